@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/credentials"
+	"gopkg.in/yaml.v3"
+)
+
+// credentialEntry is one connection's secrets in an import file
+type credentialEntry struct {
+	Username      string `yaml:"username"`
+	Password      string `yaml:"password"`
+	SecurityToken string `yaml:"security_token"`
+}
+
+// readCredentialFile reads a map of connection name to credentials from a
+// YAML or JSON file; unknown keys are refused so a typo isn't silently dropped
+func readCredentialFile(path string) (map[string]credentialEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var entries map[string]credentialEntry
+	if err := decoder.Decode(&entries); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s has no credentials", path)
+	}
+	return entries, nil
+}
+
+// checkCredentialEntry fills in the username from the config and checks the
+// server would read the credential back
+func checkCredentialEntry(cfg *config.Config, name string, entry credentialEntry) (config.Connection, credentialEntry, error) {
+	conn, exists := cfg.GetConnection(name)
+	if !exists {
+		return conn, entry, fmt.Errorf("connection '%s' not found", name)
+	}
+	if entry.Username == "" {
+		entry.Username = conn.Username
+	}
+
+	switch {
+	case conn.Type == "glue":
+		return conn, entry, fmt.Errorf("glue connections take AWS credentials, not a password")
+	case entry.Password == "":
+		return conn, entry, fmt.Errorf("password is required")
+	case entry.Username == "":
+		return conn, entry, fmt.Errorf("username is required (in the file or the connection's config)")
+	case conn.Type == "salesforce":
+		return conn, entry, nil
+	case entry.SecurityToken != "":
+		return conn, entry, fmt.Errorf("security_token is only used by salesforce connections")
+	case conn.Username == "":
+		return conn, entry, fmt.Errorf("the connection has no username in the config, so the server never reads a password for it; set one first")
+	case entry.Username != conn.Username:
+		return conn, entry, fmt.Errorf("the config uses username '%s', so the server would never read a password stored for '%s'", conn.Username, entry.Username)
+	}
+	return conn, entry, nil
+}
+
+// importCredentials stores each entry with manager, Salesforce connections'
+// with their security token, and reports one line per connection to out.
+// With dryRun everything is checked but nothing is stored. Secrets are never
+// printed or written anywhere but the credential manager.
+func importCredentials(cfg *config.Config, manager credentials.CredentialManager, entries map[string]credentialEntry, dryRun bool, out io.Writer) error {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stored, failed := 0, 0
+	for _, name := range names {
+		conn, entry, err := checkCredentialEntry(cfg, name, entries[name])
+		if err == nil && !dryRun {
+			if conn.Type == "salesforce" {
+				err = manager.StoreSalesforce(name, entry.Username, entry.Password, entry.SecurityToken)
+			} else {
+				err = manager.Store(name, entry.Username, entry.Password)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(out, "❌ %s: %v\n", name, err)
+			failed++
+			continue
+		}
+
+		action := "stored"
+		if dryRun {
+			action = "would store"
+		}
+		what := "password"
+		if entry.SecurityToken != "" {
+			what = "password and security token"
+		}
+		fmt.Fprintf(out, "✅ %s (%s): %s %s for %s\n", name, conn.Type, action, what, entry.Username)
+		stored++
+	}
+
+	if dryRun {
+		fmt.Fprintf(out, "\nDry run: %d of %d credentials would be stored.\n", stored, len(names))
+	} else {
+		fmt.Fprintf(out, "\nStored %d of %d credentials.\n", stored, len(names))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d credentials could not be imported", failed)
+	}
+	return nil
+}
+
+// importCredentialFile stores the credentials in a file with the keychain,
+// or the configured fallback
+func importCredentialFile(args []string) {
+	fs := flag.NewFlagSet("credential import", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Check the file against the config without storing anything")
+
+	// Accept the file before or after the flags
+	path := ""
+	if !strings.HasPrefix(args[0], "-") {
+		path, args = args[0], args[1:]
+	}
+	fs.Parse(args)
+	if path == "" {
+		path = fs.Arg(0)
+	}
+	if path == "" {
+		log.Fatal("Credential file is required")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	entries, err := readCredentialFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	credManager := credentials.NewProvider(cfg.Settings.CacheCredentials, cfg.Settings.CredentialFallbackFile())
+	if err := importCredentials(cfg, credManager, entries, *dryRun, os.Stdout); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if !*dryRun {
+		fmt.Printf("Delete %s now that its secrets are stored.\n", path)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func importConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Connections["orders-db"] = config.Connection{Type: "mysql", Host: "localhost", Port: 3306, Username: "app"}
+	cfg.Connections["warehouse"] = config.Connection{Type: "postgres", Host: "localhost", Port: 5432, Username: "analyst"}
+	cfg.Connections["crm"] = config.Connection{Type: "salesforce", Host: "https://login.salesforce.com"}
+	cfg.Connections["lake"] = config.Connection{Type: "glue", Region: "us-east-1"}
+	return cfg
+}
+
+func TestImportCredentials(t *testing.T) {
+	manager := testutil.NewMockCredentialManager()
+	entries := map[string]credentialEntry{
+		"orders-db": {Password: "orders-secret"},
+		"warehouse": {Username: "analyst", Password: "warehouse-secret"},
+		"crm":       {Username: "ops@example.com", Password: "crm-secret", SecurityToken: "tok123"},
+	}
+
+	var out bytes.Buffer
+	err := importCredentials(importConfig(), manager, entries, false, &out)
+	testutil.AssertNoError(t, err)
+
+	// The username comes from the config when the file leaves it out
+	cred, err := manager.Get("orders-db", "app")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "orders-secret", cred.Password)
+
+	cred, err = manager.Get("warehouse", "analyst")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "warehouse-secret", cred.Password)
+
+	// Salesforce credentials keep their security token
+	sfCred, err := manager.GetSalesforce("crm")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "ops@example.com", sfCred.Username)
+	testutil.AssertEqual(t, "crm-secret", sfCred.Password)
+	testutil.AssertEqual(t, "tok123", sfCred.SecurityToken)
+	_, err = manager.Get("crm", "ops@example.com")
+	testutil.AssertError(t, err)
+
+	testutil.AssertContains(t, out.String(), "✅ crm (salesforce): stored password and security token for ops@example.com")
+	testutil.AssertContains(t, out.String(), "✅ orders-db (mysql): stored password for app")
+	testutil.AssertContains(t, out.String(), "Stored 3 of 3 credentials.")
+	if bytes.Contains(out.Bytes(), []byte("secret")) || bytes.Contains(out.Bytes(), []byte("tok123")) {
+		t.Errorf("output shows a secret: %s", out.String())
+	}
+}
+
+func TestImportCredentialsDryRun(t *testing.T) {
+	manager := testutil.NewMockCredentialManager()
+	entries := map[string]credentialEntry{
+		"orders-db": {Password: "orders-secret"},
+		"crm":       {Username: "ops@example.com", Password: "crm-secret", SecurityToken: "tok123"},
+	}
+
+	var out bytes.Buffer
+	err := importCredentials(importConfig(), manager, entries, true, &out)
+	testutil.AssertNoError(t, err)
+
+	_, err = manager.Get("orders-db", "app")
+	testutil.AssertError(t, err)
+	_, err = manager.GetSalesforce("crm")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, out.String(), "✅ orders-db (mysql): would store password for app")
+	testutil.AssertContains(t, out.String(), "Dry run: 2 of 2 credentials would be stored.")
+}
+
+func TestImportCredentialsRejected(t *testing.T) {
+	manager := testutil.NewMockCredentialManager()
+	manager.SetError("warehouse", "analyst", errors.New("keychain locked"))
+	entries := map[string]credentialEntry{
+		"missing":   {Username: "app", Password: "secret"},
+		"orders-db": {Password: "secret", SecurityToken: "tok123"},
+		"lake":      {Username: "aws", Password: "secret"},
+		"warehouse": {Password: "secret"},
+		"crm":       {Username: "ops@example.com"},
+	}
+
+	var out bytes.Buffer
+	err := importCredentials(importConfig(), manager, entries, false, &out)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "5 credentials could not be imported")
+	testutil.AssertContains(t, out.String(), "❌ missing: connection 'missing' not found")
+	testutil.AssertContains(t, out.String(), "❌ orders-db: security_token is only used by salesforce connections")
+	testutil.AssertContains(t, out.String(), "❌ lake: glue connections take AWS credentials")
+	testutil.AssertContains(t, out.String(), "❌ warehouse: keychain locked")
+	testutil.AssertContains(t, out.String(), "❌ crm: password is required")
+
+	// A password stored for another user than the config's would never be read
+	err = importCredentials(importConfig(), manager, map[string]credentialEntry{
+		"orders-db": {Username: "admin", Password: "secret"},
+	}, false, &out)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, out.String(), "the config uses username 'app'")
+	_, err = manager.Get("orders-db", "admin")
+	testutil.AssertError(t, err)
+}
+
+func TestReadCredentialFile(t *testing.T) {
+	dir := testutil.TempDir(t)
+
+	yamlPath := filepath.Join(dir, "creds.yaml")
+	testutil.AssertNoError(t, os.WriteFile(yamlPath, []byte(`
+orders-db:
+  username: app
+  password: "p@ss: word"
+crm:
+  username: ops@example.com
+  password: crm-secret
+  security_token: tok123
+`), 0600))
+	entries, err := readCredentialFile(yamlPath)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(entries))
+	testutil.AssertEqual(t, "p@ss: word", entries["orders-db"].Password)
+	testutil.AssertEqual(t, "tok123", entries["crm"].SecurityToken)
+
+	jsonPath := filepath.Join(dir, "creds.json")
+	testutil.AssertNoError(t, os.WriteFile(jsonPath, []byte(`{"warehouse": {"username": "analyst", "password": "w"}}`), 0600))
+	entries, err = readCredentialFile(jsonPath)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "analyst", entries["warehouse"].Username)
+
+	// A misspelt key is an error rather than a silently missing password
+	typoPath := filepath.Join(dir, "typo.yaml")
+	testutil.AssertNoError(t, os.WriteFile(typoPath, []byte("orders-db:\n  pasword: secret\n"), 0600))
+	_, err = readCredentialFile(typoPath)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "pasword")
+
+	emptyPath := filepath.Join(dir, "empty.yaml")
+	testutil.AssertNoError(t, os.WriteFile(emptyPath, nil, 0600))
+	_, err = readCredentialFile(emptyPath)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "has no credentials")
+}
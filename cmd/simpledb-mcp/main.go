@@ -3,21 +3,50 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/logging"
+	"github.com/eliziario/simpledb-mcp/internal/version"
 	"github.com/eliziario/simpledb-mcp/pkg/api"
 )
 
 func main() {
 	// Parse command line flags
-	transport := flag.String("transport", "", "Transport type: stdio, http, gin (overrides config)")
+	transport := flag.String("transport", "", "Transport type: stdio, http, websocket (overrides config)")
 	address := flag.String("address", "", "Server address for HTTP/Gin transport (e.g., :8080)")
 	path := flag.String("path", "", "Endpoint path for HTTP/Gin transport (e.g., /mcp)")
+	checkConfig := flag.Bool("check-config", false, "Validate the configuration and exit without starting the server")
+	migrateConfig := flag.Bool("migrate-config", false, "Rewrite an older configuration file in the current format and exit")
+	showVersion := flag.Bool("version", false, "Print version information and exit")
+	logFile := flag.String("log-file", "", "Append logs to this file instead of stderr")
+	logLevel := flag.String("log-level", "debug", "Minimum level to log: debug, info, warn or error")
 	flag.Parse()
 
+	if *showVersion {
+		fmt.Printf("simpledb-mcp %s\n", version.String())
+		return
+	}
+
+	if *checkConfig {
+		os.Exit(runCheckConfig(os.Stdout))
+	}
+
+	if *migrateConfig {
+		os.Exit(runMigrateConfig(os.Stdout))
+	}
+
+	logOutput, err := setupLogging(*logFile, *logLevel, os.Stderr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer logOutput.Close()
+
 	// Create context that cancels on interrupt
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -45,3 +74,82 @@ func main() {
 
 	log.Println("Server stopped")
 }
+
+// setupLogging points the standard logger at logFile, or stderr when it is
+// empty, keeping lines at or above level. Logs never go to stdout, which
+// carries the JSON-RPC stream under the stdio transport. The returned closer
+// releases the log file.
+func setupLogging(logFile, level string, stderr io.Writer) (io.Closer, error) {
+	minLevel, err := logging.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	var out io.Writer = stderr
+	var closer io.Closer = io.NopCloser(nil)
+	if logFile != "" {
+		if isStdout(logFile) {
+			return nil, fmt.Errorf("--log-file cannot be stdout, which carries the MCP protocol over stdio; use stderr (the default) or a file")
+		}
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		out, closer = f, f
+	}
+
+	log.SetOutput(logging.NewLevelWriter(out, minLevel))
+	return closer, nil
+}
+
+// isStdout reports whether path names the process's standard output
+func isStdout(path string) bool {
+	if path == "-" || path == "/dev/stdout" {
+		return true
+	}
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	stdoutInfo, err := os.Stdout.Stat()
+	return err == nil && os.SameFile(pathInfo, stdoutInfo)
+}
+
+// runCheckConfig loads and validates the configuration, printing any problems.
+// It never connects to a database or reads credentials, and returns the exit code.
+func runCheckConfig(out io.Writer) int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(out, "Configuration error: %v\n", err)
+		return 1
+	}
+	return checkLoadedConfig(cfg, out)
+}
+
+// runMigrateConfig upgrades the configuration file to the current version,
+// keeping a backup of the original, and returns the exit code
+func runMigrateConfig(out io.Writer) int {
+	fromVersion, err := config.MigrateFile()
+	if err != nil {
+		fmt.Fprintf(out, "Configuration error: %v\n", err)
+		return 1
+	}
+	if fromVersion >= config.CurrentConfigVersion {
+		fmt.Fprintf(out, "Configuration is already at version %d\n", fromVersion)
+		return 0
+	}
+	fmt.Fprintf(out, "Configuration migrated from version %d to %d (original kept as config.yaml.v%d.bak)\n",
+		fromVersion, config.CurrentConfigVersion, fromVersion)
+	return 0
+}
+
+// checkLoadedConfig reports the result of validating cfg and returns the exit code
+func checkLoadedConfig(cfg *config.Config, out io.Writer) int {
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(out, "Configuration is invalid:")
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	fmt.Fprintf(out, "Configuration is valid (%d connections)\n", len(cfg.Connections))
+	return 0
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestCheckConfigInvalid(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Connections["broken"] = config.Connection{Type: "oracle", Host: "localhost"}
+	cfg.Connections["no-port"] = config.Connection{Type: "mysql", Host: "localhost"}
+
+	var out bytes.Buffer
+	code := checkLoadedConfig(cfg, &out)
+
+	testutil.AssertEqual(t, 1, code)
+	testutil.AssertContains(t, out.String(), "connection 'broken': unsupported type 'oracle'")
+	testutil.AssertContains(t, out.String(), "connection 'no-port': port 0 is out of range")
+}
+
+func TestCheckConfigValid(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Connections["local"] = config.Connection{Type: "postgres", Host: "localhost", Port: 5432, Username: "nobody"}
+
+	var out bytes.Buffer
+	code := checkLoadedConfig(cfg, &out)
+
+	testutil.AssertEqual(t, 0, code)
+	testutil.AssertContains(t, out.String(), "Configuration is valid (1 connections)")
+}
+
+func TestRunCheckConfigFromFile(t *testing.T) {
+	originalHome := os.Getenv("HOME")
+	tempDir := testutil.TempDir(t)
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	configPath, err := config.ConfigPath()
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+	badConfig := `
+connections:
+  typo:
+    type: postgress
+    host: localhost
+    port: 5432
+settings:
+  server:
+    transport: carrier-pigeon
+`
+	testutil.AssertNoError(t, os.WriteFile(configPath, []byte(badConfig), 0644))
+
+	var out bytes.Buffer
+	code := runCheckConfig(&out)
+
+	// Unknown types are refused as the config loads
+	testutil.AssertEqual(t, 1, code)
+	testutil.AssertContains(t, out.String(), "Configuration error")
+	testutil.AssertContains(t, out.String(), "unsupported type 'postgress'")
+
+	// Aliases load, leaving the rest of the config to validate
+	aliasConfig := strings.Replace(badConfig, "postgress", "postgresql", 1)
+	testutil.AssertNoError(t, os.WriteFile(configPath, []byte(aliasConfig), 0644))
+	out.Reset()
+	code = runCheckConfig(&out)
+
+	testutil.AssertEqual(t, 1, code)
+	testutil.AssertContains(t, out.String(), "Configuration is invalid")
+	testutil.AssertContains(t, out.String(), "unsupported server.transport 'carrier-pigeon'")
+}
+
+func TestRunMigrateConfig(t *testing.T) {
+	t.Setenv("HOME", testutil.TempDir(t))
+	configPath, err := config.ConfigPath()
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+	testutil.AssertNoError(t, os.WriteFile(configPath, []byte("connections: {}\n"), 0644))
+
+	var out bytes.Buffer
+	testutil.AssertEqual(t, 0, runMigrateConfig(&out))
+	testutil.AssertContains(t, out.String(), "Configuration migrated from version 0 to 1")
+	testutil.AssertFileExists(t, configPath+".v0.bak")
+
+	out.Reset()
+	testutil.AssertEqual(t, 0, runMigrateConfig(&out))
+	testutil.AssertContains(t, out.String(), "Configuration is already at version 1")
+}
+
+// captureStdout points os.Stdout at a temporary file for the rest of the test
+func captureStdout(t *testing.T) *os.File {
+	t.Helper()
+	f, err := os.Create(filepath.Join(testutil.TempDir(t), "stdout"))
+	testutil.AssertNoError(t, err)
+	original := os.Stdout
+	os.Stdout = f
+	t.Cleanup(func() {
+		os.Stdout = original
+		f.Close()
+	})
+	return f
+}
+
+// restoreLogOutput puts the standard logger back on stderr after the test
+func restoreLogOutput(t *testing.T) {
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+}
+
+func TestLoggingNeverWritesToStdout(t *testing.T) {
+	restoreLogOutput(t)
+	stdout := captureStdout(t)
+
+	var stderr bytes.Buffer
+	closer, err := setupLogging("", "info", &stderr)
+	testutil.AssertNoError(t, err)
+	defer closer.Close()
+
+	log.Printf("DEBUG query [prod] SELECT 1")
+	log.Printf("Starting MCP server with stdio transport...")
+
+	written, err := os.ReadFile(stdout.Name())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, len(written))
+	testutil.AssertContains(t, stderr.String(), "Starting MCP server with stdio transport")
+	testutil.AssertEqual(t, false, bytes.Contains(stderr.Bytes(), []byte("DEBUG")))
+
+	// Pointing the log file at stdout is refused
+	_, err = setupLogging(stdout.Name(), "info", &stderr)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--log-file cannot be stdout")
+	_, err = setupLogging("-", "info", &stderr)
+	testutil.AssertError(t, err)
+}
+
+func TestLoggingToFile(t *testing.T) {
+	restoreLogOutput(t)
+	logPath := filepath.Join(testutil.TempDir(t), "simpledb-mcp.log")
+
+	var stderr bytes.Buffer
+	closer, err := setupLogging(logPath, "warn", &stderr)
+	testutil.AssertNoError(t, err)
+	log.Printf("Created new database connection for 'prod'")
+	log.Printf("Warning: connection_pool.ping_interval is too long")
+	testutil.AssertNoError(t, closer.Close())
+
+	written, err := os.ReadFile(logPath)
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, string(written), "Warning: connection_pool.ping_interval")
+	testutil.AssertEqual(t, false, bytes.Contains(written, []byte("Created new")))
+	testutil.AssertEqual(t, 0, stderr.Len())
+
+	_, err = setupLogging("", "loud", &stderr)
+	testutil.AssertError(t, err)
+}
@@ -41,7 +41,7 @@ func main() {
 	// If password provided, store it first
 	if len(os.Args) >= 3 {
 		password := os.Args[2]
-		credManager := credentials.NewManager(cfg.Settings.CacheCredentials)
+		credManager := credentials.NewProvider(cfg.Settings.CacheCredentials, cfg.Settings.CredentialFallbackFile())
 		fmt.Printf("Storing credentials...\n")
 		if err := credManager.Store(connectionName, conn.Username, password); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to store credentials: %v\n", err)
@@ -51,14 +51,15 @@ func main() {
 	}
 
 	// Test the connection
-	credManager := credentials.NewManager(cfg.Settings.CacheCredentials)
+	credManager := credentials.NewProvider(cfg.Settings.CacheCredentials, cfg.Settings.CredentialFallbackFile())
+	credManager.SetRequireBiometric(cfg.Settings.RequireBiometric)
 	dbManager := database.NewManager(cfg, credManager)
 	defer dbManager.Close()
 
 	fmt.Printf("Testing database connection...\n")
 	if err := dbManager.TestConnection(connectionName); err != nil {
 		fmt.Printf("❌ Connection "+
-			"test failed: %v\n", err)
+			"test failed (%s): %v\n", database.ClassifyFailure(err), err)
 		os.Exit(1)
 	}
 
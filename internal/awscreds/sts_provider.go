@@ -12,6 +12,7 @@ import (
    "time"
 
    "github.com/aws/aws-sdk-go/aws"
+   awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
    "github.com/aws/aws-sdk-go/aws/session"
    "github.com/aws/aws-sdk-go/service/sts"
 )
@@ -26,36 +27,80 @@ type AWSCreds struct {
 
 // STSProvider retrieves and caches STS credentials using MFA.
 // It refreshes automatically when credentials are near expiration.
+// When SourceRoleArn is set, MFA is used to assume it and its credentials
+// then assume RoleArn (role chaining).
 type STSProvider struct {
-   RoleArn      string
-   SerialNumber string
+   RoleArn       string
+   SourceRoleArn string
+   SerialNumber  string
    SessionName  string
    Duration     int64
    UseGauth     bool
+   // MFAMode picks the MFA code source: MFAModeGauth, MFAModeDialog or
+   // MFAModeTOTP. Empty falls back to UseGauth.
+   MFAMode string
+   // TOTPSecret returns the base32 MFA secret for MFAModeTOTP
+   TOTPSecret func() (string, error)
 
    mu    sync.Mutex
    creds *AWSCreds
+
+   // assume fetches fresh credentials; replaced in tests to avoid MFA and STS
+   assume func() (*AWSCreds, error)
 }
 
+// MFA code sources
+const (
+   MFAModeGauth  = "gauth"  // the external gauth tool
+   MFAModeDialog = "dialog" // a native macOS dialog
+   MFAModeTOTP   = "totp"   // generated from a stored TOTP secret, for unattended use
+)
+
+// refreshWindow is how close to expiry credentials are refreshed rather than reused
+const refreshWindow = time.Minute
+
 // NewSTSProvider initializes a provider for the given role ARN and MFA serial.
 func NewSTSProvider(roleArn, serial string, durationSeconds int64, useGauth bool) *STSProvider {
-   return &STSProvider{
+   p := &STSProvider{
        RoleArn:      roleArn,
        SerialNumber: serial,
        SessionName:  fmt.Sprintf("simpledb-mcp-%d", time.Now().Unix()),
        Duration:     durationSeconds,
        UseGauth:     useGauth,
    }
+   p.assume = p.assumeRoleWithMFA
+   return p
 }
 
-// getMfaCode obtains the MFA TOTP code either from gauth tool or macOS dialog.
+// getMfaCode obtains the MFA TOTP code from gauth tool, macOS dialog or a stored TOTP secret.
 func (p *STSProvider) getMfaCode() (string, error) {
+   switch p.MFAMode {
+   case MFAModeTOTP:
+       return p.getMfaCodeFromSecret()
+   case MFAModeGauth:
+       return p.getMfaCodeFromGauth()
+   case MFAModeDialog:
+       return p.getMfaCodeFromDialog()
+   }
    if p.UseGauth {
        return p.getMfaCodeFromGauth()
    }
    return p.getMfaCodeFromDialog()
 }
 
+// getMfaCodeFromSecret generates the current code from the TOTP secret, so
+// automated jobs can assume the role without a human.
+func (p *STSProvider) getMfaCodeFromSecret() (string, error) {
+   if p.TOTPSecret == nil {
+       return "", fmt.Errorf("mfa_mode totp requires a TOTP secret")
+   }
+   secret, err := p.TOTPSecret()
+   if err != nil {
+       return "", fmt.Errorf("failed to get TOTP secret: %w", err)
+   }
+   return GenerateTOTP(secret, time.Now())
+}
+
 // getMfaCodeFromGauth obtains the MFA TOTP code by invoking the external gauth tool.
 // It sources the configuration file at ~/.config/.aws_menu.ini to find GAUTH_PATH and GOAUTH_PROFILE.
 func (p *STSProvider) getMfaCodeFromGauth() (string, error) {
@@ -141,37 +186,89 @@ func (p *STSProvider) getMfaCodeFromDialog() (string, error) {
 
 // Load calls STS AssumeRole with MFA, updates cached credentials and writes the shared aws_credentials file.
 func (p *STSProvider) Load() error {
-   code, err := p.getMfaCode()
+   creds, err := p.assume()
    if err != nil {
        return err
    }
-   sess, err := session.NewSession()
-   if err != nil {
-       return err
-   }
-   svc := sts.New(sess)
-   out, err := svc.AssumeRole(&sts.AssumeRoleInput{
+   p.creds = creds
+   return p.writeCredentialsFile(creds)
+}
+
+// maxChainedDuration is the AWS limit on a session obtained by role chaining.
+const maxChainedDuration = 3600
+
+// assumeRoleInputs builds the AssumeRole calls for an MFA code: one for the
+// role itself, or with role chaining, one for the source role (with MFA)
+// followed by one for the target role made with the source role's credentials.
+func (p *STSProvider) assumeRoleInputs(code string) []*sts.AssumeRoleInput {
+   first := &sts.AssumeRoleInput{
        RoleArn:         aws.String(p.RoleArn),
        RoleSessionName: aws.String(p.SessionName),
        SerialNumber:    aws.String(p.SerialNumber),
        TokenCode:       aws.String(code),
        DurationSeconds: aws.Int64(p.Duration),
-   })
+   }
+   if p.SourceRoleArn == "" {
+       return []*sts.AssumeRoleInput{first}
+   }
+   first.RoleArn = aws.String(p.SourceRoleArn)
+   duration := p.Duration
+   if duration > maxChainedDuration {
+       duration = maxChainedDuration
+   }
+   return []*sts.AssumeRoleInput{first, {
+       RoleArn:         aws.String(p.RoleArn),
+       RoleSessionName: aws.String(p.SessionName),
+       DurationSeconds: aws.Int64(duration),
+   }}
+}
+
+// assumeRoleWithMFA prompts for an MFA code and assumes the role with it.
+func (p *STSProvider) assumeRoleWithMFA() (*AWSCreds, error) {
+   code, err := p.getMfaCode()
    if err != nil {
-       return fmt.Errorf("assume role %s: %w", p.RoleArn, err)
+       return nil, err
    }
-   creds := &AWSCreds{
-       AccessKeyID:     aws.StringValue(out.Credentials.AccessKeyId),
-       SecretAccessKey: aws.StringValue(out.Credentials.SecretAccessKey),
-       SessionToken:    aws.StringValue(out.Credentials.SessionToken),
-       Expiration:      aws.TimeValue(out.Credentials.Expiration),
+   sess, err := session.NewSession()
+   if err != nil {
+       return nil, err
    }
-   p.creds = creds
-   // Write credentials file for other shells
-   awsCredsFile := os.Getenv("AWS_CREDENTIALS_FILE")
-   if awsCredsFile == "" {
-       awsCredsFile = os.ExpandEnv("$HOME/.local/bin/aws_credentials")
+   var creds *AWSCreds
+   for _, input := range p.assumeRoleInputs(code) {
+       cfg := aws.NewConfig()
+       if creds != nil {
+           // Later hops in a chain authenticate as the role assumed before them
+           cfg = cfg.WithCredentials(awscredentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken))
+       }
+       out, err := sts.New(sess, cfg).AssumeRole(input)
+       if err != nil {
+           return nil, fmt.Errorf("assume role %s: %w", aws.StringValue(input.RoleArn), err)
+       }
+       creds = &AWSCreds{
+           AccessKeyID:     aws.StringValue(out.Credentials.AccessKeyId),
+           SecretAccessKey: aws.StringValue(out.Credentials.SecretAccessKey),
+           SessionToken:    aws.StringValue(out.Credentials.SessionToken),
+           Expiration:      aws.TimeValue(out.Credentials.Expiration),
+       }
+   }
+   return creds, nil
+}
+
+// credentialsFile returns where assumed credentials are shared with other
+// shells and kept across restarts: $AWS_CREDENTIALS_FILE, or
+// ~/.local/bin/aws_credentials.
+func credentialsFile() string {
+   if path := os.Getenv("AWS_CREDENTIALS_FILE"); path != "" {
+       return path
    }
+   return os.ExpandEnv("$HOME/.local/bin/aws_credentials")
+}
+
+// writeCredentialsFile saves credentials as shell exports, readable only by
+// the owner. The role and expiration are recorded so a restarted server can
+// reuse them.
+func (p *STSProvider) writeCredentialsFile(creds *AWSCreds) error {
+   awsCredsFile := credentialsFile()
    if err := os.MkdirAll(filepath.Dir(awsCredsFile), 0700); err != nil {
        return err
    }
@@ -180,21 +277,61 @@ func (p *STSProvider) Load() error {
        return err
    }
    defer f.Close()
+   // An existing file keeps its mode on open; tighten it before writing secrets
+   if err := f.Chmod(0600); err != nil {
+       return err
+   }
    fmt.Fprintf(f, "export AWS_ACCESS_KEY_ID=%s\n", creds.AccessKeyID)
    fmt.Fprintf(f, "export AWS_SECRET_ACCESS_KEY=%s\n", creds.SecretAccessKey)
    fmt.Fprintf(f, "export AWS_SESSION_TOKEN=%s\n", creds.SessionToken)
    fmt.Fprintf(f, "# Expiration: %s\n", creds.Expiration.UTC().Format(time.RFC3339))
+   fmt.Fprintf(f, "# Role: %s\n", p.RoleArn)
    return nil
 }
 
+// readCredentialsFile returns the credentials saved by writeCredentialsFile if
+// they were assumed for this provider's role, or nil if there are none.
+func (p *STSProvider) readCredentialsFile() *AWSCreds {
+   data, err := os.ReadFile(credentialsFile())
+   if err != nil {
+       return nil
+   }
+   creds := &AWSCreds{}
+   var role string
+   for _, line := range strings.Split(string(data), "\n") {
+       line = strings.TrimSpace(line)
+       switch {
+       case strings.HasPrefix(line, "export AWS_ACCESS_KEY_ID="):
+           creds.AccessKeyID = strings.TrimPrefix(line, "export AWS_ACCESS_KEY_ID=")
+       case strings.HasPrefix(line, "export AWS_SECRET_ACCESS_KEY="):
+           creds.SecretAccessKey = strings.TrimPrefix(line, "export AWS_SECRET_ACCESS_KEY=")
+       case strings.HasPrefix(line, "export AWS_SESSION_TOKEN="):
+           creds.SessionToken = strings.TrimPrefix(line, "export AWS_SESSION_TOKEN=")
+       case strings.HasPrefix(line, "# Expiration: "):
+           creds.Expiration, _ = time.Parse(time.RFC3339, strings.TrimPrefix(line, "# Expiration: "))
+       case strings.HasPrefix(line, "# Role: "):
+           role = strings.TrimPrefix(line, "# Role: ")
+       }
+   }
+   // Files from other roles, or written before the role was recorded, aren't ours to reuse
+   if role != p.RoleArn || creds.AccessKeyID == "" || creds.SecretAccessKey == "" || creds.Expiration.IsZero() {
+       return nil
+   }
+   return creds
+}
+
 // Creds returns valid credentials, refreshing them if they are expired or within 1 minute of expiry.
+// After a restart, unexpired credentials saved to the credentials file are reused instead of prompting for MFA.
 func (p *STSProvider) Creds() (*AWSCreds, error) {
    p.mu.Lock()
    defer p.mu.Unlock()
-   if p.creds == nil || time.Until(p.creds.Expiration) < time.Minute {
+   if p.creds == nil {
+       p.creds = p.readCredentialsFile()
+   }
+   if p.creds == nil || time.Until(p.creds.Expiration) < refreshWindow {
        if err := p.Load(); err != nil {
            return nil, err
        }
    }
    return p.creds, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,117 @@
+package awscreds
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+const testRole = "arn:aws:iam::123456789012:role/Reader"
+
+// testProvider returns a provider whose MFA/STS step is counted instead of run
+func testProvider(t *testing.T, fresh *AWSCreds) (*STSProvider, *int) {
+	t.Helper()
+	t.Setenv("AWS_CREDENTIALS_FILE", filepath.Join(testutil.TempDir(t), "aws_credentials"))
+	calls := 0
+	p := NewSTSProvider(testRole, "arn:aws:iam::123456789012:mfa/alice", 3600, true)
+	p.assume = func() (*AWSCreds, error) {
+		calls++
+		return fresh, nil
+	}
+	return p, &calls
+}
+
+func TestCachedCredentialsReusedAfterRestart(t *testing.T) {
+	valid := &AWSCreds{
+		AccessKeyID:     "ASIAEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+	first, calls := testProvider(t, valid)
+	_, err := first.Creds()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, *calls)
+
+	info, err := os.Stat(credentialsFile())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A new provider, as after a restart, reads the file instead of prompting
+	restarted := NewSTSProvider(testRole, "arn:aws:iam::123456789012:mfa/alice", 3600, true)
+	restarted.assume = func() (*AWSCreds, error) {
+		t.Fatal("Expected cached credentials to be reused without MFA")
+		return nil, nil
+	}
+	creds, err := restarted.Creds()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "ASIAEXAMPLE", creds.AccessKeyID)
+	testutil.AssertEqual(t, "secret", creds.SecretAccessKey)
+	testutil.AssertEqual(t, "token", creds.SessionToken)
+	testutil.AssertEqual(t, true, creds.Expiration.Equal(valid.Expiration))
+}
+
+func TestExpiredCachedCredentialsRefreshed(t *testing.T) {
+	fresh := &AWSCreds{AccessKeyID: "ASIAFRESH", SecretAccessKey: "s", SessionToken: "t", Expiration: time.Now().Add(time.Hour)}
+	p, calls := testProvider(t, fresh)
+
+	// Near expiry counts as expired
+	expired := &AWSCreds{AccessKeyID: "ASIAOLD", SecretAccessKey: "s", SessionToken: "t", Expiration: time.Now().Add(30 * time.Second)}
+	testutil.AssertNoError(t, p.writeCredentialsFile(expired))
+
+	creds, err := p.Creds()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, *calls)
+	testutil.AssertEqual(t, "ASIAFRESH", creds.AccessKeyID)
+	testutil.AssertEqual(t, "ASIAFRESH", p.readCredentialsFile().AccessKeyID)
+}
+
+func TestCachedCredentialsForOtherRoleIgnored(t *testing.T) {
+	fresh := &AWSCreds{AccessKeyID: "ASIAFRESH", SecretAccessKey: "s", SessionToken: "t", Expiration: time.Now().Add(time.Hour)}
+	p, calls := testProvider(t, fresh)
+
+	other := NewSTSProvider("arn:aws:iam::123456789012:role/Admin", "", 3600, true)
+	testutil.AssertNoError(t, other.writeCredentialsFile(&AWSCreds{
+		AccessKeyID: "ASIAADMIN", SecretAccessKey: "s", SessionToken: "t", Expiration: time.Now().Add(time.Hour),
+	}))
+
+	creds, err := p.Creds()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, *calls)
+	testutil.AssertEqual(t, "ASIAFRESH", creds.AccessKeyID)
+}
+
+func TestAssumeRoleInputs(t *testing.T) {
+	p := NewSTSProvider(testRole, "arn:aws:iam::123456789012:mfa/alice", 7200, true)
+
+	inputs := p.assumeRoleInputs("123456")
+	testutil.AssertEqual(t, 1, len(inputs))
+	testutil.AssertEqual(t, testRole, aws.StringValue(inputs[0].RoleArn))
+	testutil.AssertEqual(t, "123456", aws.StringValue(inputs[0].TokenCode))
+	testutil.AssertEqual(t, int64(7200), aws.Int64Value(inputs[0].DurationSeconds))
+}
+
+func TestAssumeRoleInputsChained(t *testing.T) {
+	p := NewSTSProvider(testRole, "arn:aws:iam::123456789012:mfa/alice", 7200, true)
+	p.SourceRoleArn = "arn:aws:iam::999999999999:role/Jump"
+
+	inputs := p.assumeRoleInputs("123456")
+	testutil.AssertEqual(t, 2, len(inputs))
+
+	// MFA goes to the source role
+	testutil.AssertEqual(t, "arn:aws:iam::999999999999:role/Jump", aws.StringValue(inputs[0].RoleArn))
+	testutil.AssertEqual(t, "arn:aws:iam::123456789012:mfa/alice", aws.StringValue(inputs[0].SerialNumber))
+	testutil.AssertEqual(t, "123456", aws.StringValue(inputs[0].TokenCode))
+
+	// The target role is assumed with the source role's credentials, no MFA,
+	// and at most the one-hour role chaining limit
+	testutil.AssertEqual(t, testRole, aws.StringValue(inputs[1].RoleArn))
+	if inputs[1].SerialNumber != nil || inputs[1].TokenCode != nil {
+		t.Error("Expected no MFA on the chained assume-role call")
+	}
+	testutil.AssertEqual(t, int64(3600), aws.Int64Value(inputs[1].DurationSeconds))
+}
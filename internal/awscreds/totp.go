@@ -0,0 +1,48 @@
+package awscreds
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// totpStep is the RFC 6238 time step used by AWS virtual MFA devices
+const totpStep = 30 * time.Second
+
+// GenerateTOTP returns the 6-digit RFC 6238 code for a base32 secret, as
+// shown by an authenticator app set up with the same secret. Spaces and
+// missing padding in the secret are tolerated.
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(secret), " ", ""))
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(normalized, "="))
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: must be base32: %w", err)
+	}
+	if len(key) == 0 {
+		return "", fmt.Errorf("invalid TOTP secret: empty")
+	}
+	return totpCode(key, t, 6), nil
+}
+
+// totpCode computes an HMAC-SHA1 TOTP code (RFC 6238, using RFC 4226 truncation)
+func totpCode(key []byte, t time.Time, digits int) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(totpStep/time.Second)))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulus := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%modulus)
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// awsRegionPattern matches AWS region codes such as us-east-1, eu-central-2,
+// us-gov-west-1 and cn-northwest-1
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-\d+$`)
+
+// AWSRegion returns the region of a glue connection. Older configs put the
+// region in host, which is still honored when region is unset.
+func (c Connection) AWSRegion() string {
+	if c.Region != "" {
+		return c.Region
+	}
+	return c.Host
+}
+
+// UsesAWSProfile reports whether a glue connection takes its credentials from
+// a shared config profile, which covers SSO, rather than the STS-MFA flow
+func (c Connection) UsesAWSProfile() bool {
+	return c.AWSProfile != "" || c.UseSSO
+}
+
+// validateAWSRegion checks that region looks like an AWS region code
+func validateAWSRegion(region string) error {
+	if !awsRegionPattern.MatchString(region) {
+		return fmt.Errorf("'%s' is not an AWS region (e.g. us-east-1)", region)
+	}
+	return nil
+}
+
+// DeprecationWarnings describes connections relying on settings kept only
+// for backward compatibility
+func (c *Config) DeprecationWarnings() []string {
+	names := c.ListConnections()
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		conn := c.Connections[name]
+		if conn.Type == "glue" && conn.Region == "" && conn.Host != "" {
+			warnings = append(warnings, fmt.Sprintf("connection '%s' sets its AWS region in host, which is deprecated; move '%s' to region", name, conn.Host))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestAWSRegion(t *testing.T) {
+	// region wins over host
+	conn := Connection{Type: "glue", Host: "us-east-1", Region: "eu-central-1"}
+	testutil.AssertEqual(t, "eu-central-1", conn.AWSRegion())
+
+	// Older configs keep working with the region in host
+	conn = Connection{Type: "glue", Host: "us-west-2"}
+	testutil.AssertEqual(t, "us-west-2", conn.AWSRegion())
+}
+
+func TestValidateGlueRegion(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Connections["region"] = Connection{Type: "glue", Region: "us-gov-west-1", RoleArn: "arn:aws:iam::1:role/r"}
+	cfg.Connections["legacy"] = Connection{Type: "glue", Host: "ap-southeast-2", RoleArn: "arn:aws:iam::1:role/r"}
+	testutil.AssertNoError(t, cfg.Validate())
+
+	cfg.Connections["no-region"] = Connection{Type: "glue", RoleArn: "arn:aws:iam::1:role/r"}
+	cfg.Connections["bad-region"] = Connection{Type: "glue", Region: "US East", RoleArn: "arn:aws:iam::1:role/r"}
+	cfg.Connections["bad-host"] = Connection{Type: "glue", Host: "glue.us-east-1.amazonaws.com", RoleArn: "arn:aws:iam::1:role/r"}
+	cfg.Connections["mysql-region"] = Connection{Type: "mysql", Host: "localhost", Port: 3306, Region: "us-east-1"}
+	err := cfg.Validate()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "connection 'no-region': region is required for glue")
+	testutil.AssertContains(t, err.Error(), "connection 'bad-region': 'US East' is not an AWS region")
+	testutil.AssertContains(t, err.Error(), "connection 'bad-host': 'glue.us-east-1.amazonaws.com' is not an AWS region")
+	testutil.AssertContains(t, err.Error(), "connection 'mysql-region': region is only supported for glue")
+}
+
+func TestDeprecationWarningsForRegionInHost(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Connections["legacy"] = Connection{Type: "glue", Host: "us-east-1"}
+	cfg.Connections["current"] = Connection{Type: "glue", Region: "us-east-1"}
+	cfg.Connections["db"] = Connection{Type: "mysql", Host: "localhost"}
+
+	warnings := cfg.DeprecationWarnings()
+	testutil.AssertEqual(t, 1, len(warnings))
+	testutil.AssertContains(t, warnings[0], "connection 'legacy' sets its AWS region in host")
+}
+
+func TestValidateGlueProfile(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Connections["sso"] = Connection{Type: "glue", UseSSO: true}
+	cfg.Connections["profile"] = Connection{Type: "glue", AWSProfile: "analytics", Region: "us-east-1"}
+	testutil.AssertNoError(t, cfg.Validate())
+	testutil.AssertEqual(t, true, cfg.Connections["sso"].UsesAWSProfile())
+
+	cfg.Connections["profile-and-mfa"] = Connection{Type: "glue", AWSProfile: "analytics", RoleArn: "arn:aws:iam::1:role/r", MFASerial: "arn:aws:iam::1:mfa/me"}
+	cfg.Connections["mysql-profile"] = Connection{Type: "mysql", Host: "localhost", Port: 3306, AWSProfile: "analytics"}
+	err := cfg.Validate()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "connection 'profile-and-mfa': role_arn, mfa_serial")
+	testutil.AssertContains(t, err.Error(), "connection 'mysql-profile': aws_profile and use_sso are only supported for glue")
+}
@@ -1,38 +1,296 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
+	// Version is the file's structure version; see CurrentConfigVersion
+	Version int `yaml:"version"`
+	// Defaults supplies values for any field a connection leaves unset
+	Defaults    Connection            `yaml:"defaults,omitempty"`
 	Connections map[string]Connection `yaml:"connections"`
 	Settings    Settings              `yaml:"settings"`
+
+	// explicit holds the connections as the file wrote them, before the
+	// defaults block filled them in, so Save doesn't write defaults back
+	explicit map[string]Connection
 }
 
 type Connection struct {
 	Type     string `yaml:"type"`     // mysql, postgres
+	Flavor   string `yaml:"flavor,omitempty"` // mariadb for MariaDB servers behind a mysql connection; the mariadb type sets it
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
 	Database string `yaml:"database"`
 	SSLMode  string `yaml:"ssl_mode,omitempty"` // for postgres
+	Socket   string `yaml:"socket,omitempty"`   // Unix socket path (mysql) or socket directory (postgres), instead of host/port
    Username  string `yaml:"username,omitempty"` // optional, can be stored in keychain
+   PasswordFile string `yaml:"password_file,omitempty"` // fallback when no keychain credential exists (e.g. a mounted secret)
+   Proxy     string `yaml:"proxy,omitempty"` // SOCKS5 proxy for mysql/postgres, e.g. socks5://proxy.corp:1080
+   AllowSampling *bool `yaml:"allow_sampling,omitempty"` // false permits schema introspection only; unset means true
+   Enabled *bool `yaml:"enabled,omitempty"` // false takes the connection out of service without deleting it; unset means true
+   ReadReplicaHost string `yaml:"read_replica_host,omitempty"` // optional replica for mysql/postgres; see ReplicaRouting
+   ReadReplicaPort int    `yaml:"read_replica_port,omitempty"` // defaults to Port
+   ReplicaRouting  string `yaml:"replica_routing,omitempty"`   // which tools use the replica: data (default) or metadata
+   DisableKeepalive bool `yaml:"disable_keepalive,omitempty"` // skip background pings for this connection even when enable_keepalive is set
+   Environment string `yaml:"environment,omitempty"` // label such as dev, staging or prod; tools can be scoped to one
+   MaxConcurrentQueries int `yaml:"max_concurrent_queries,omitempty"` // tool calls allowed in flight at once; overrides settings.max_concurrent_queries
+   MaxRows int `yaml:"max_rows,omitempty"` // rows a tool may return from this connection; overrides settings.max_rows
+   Params map[string]string `yaml:"params,omitempty"` // extra driver parameters for mysql/postgres, e.g. readTimeout or connect_timeout
    // AWS Glue MFA/STS settings
    RoleArn   string `yaml:"role_arn,omitempty"`   // IAM role ARN for AWS Glue
    MFASerial string `yaml:"mfa_serial,omitempty"` // MFA device ARN for STS assume-role
    UseGauth  bool   `yaml:"use_gauth,omitempty"`  // Use gauth tool vs native macOS dialog
+   MFAMode   string `yaml:"mfa_mode,omitempty"`   // gauth, dialog or totp; overrides use_gauth when set
+   STSDurationSeconds int64 `yaml:"sts_duration_seconds,omitempty"` // lifetime of assumed-role credentials (default 3600)
+   SourceRoleArn string `yaml:"source_role_arn,omitempty"` // optional intermediate role assumed with MFA before RoleArn (role chaining)
+   Region    string `yaml:"region,omitempty"`      // AWS region for glue (older configs use host)
+   AWSProfile string `yaml:"aws_profile,omitempty"` // shared config profile (e.g. an SSO profile) to take glue credentials from instead of the MFA flow
+   UseSSO    bool   `yaml:"use_sso,omitempty"`     // take glue credentials from the default shared config profile (AWS_PROFILE or default)
    AthenaS3Output string `yaml:"athena_s3_output,omitempty"` // S3 bucket for Athena query results
+   SalesforceAPIVersion string `yaml:"salesforce_api_version,omitempty"` // REST API version, e.g. "62.0" (default DefaultSalesforceAPIVersion)
+   GlueSampleEnabled *bool `yaml:"glue_sample_enabled,omitempty"` // false limits a glue connection to catalog metadata (no Athena); unset means true
+}
+
+// ErrConnectionDisabled is returned for connections configured with enabled: false
+var ErrConnectionDisabled = errors.New("connection disabled")
+
+// ErrDataAccessDisabled is returned by tools that read table data on a
+// connection configured with allow_sampling: false
+var ErrDataAccessDisabled = errors.New("data access disabled for this connection")
+
+// FlavorMariaDB marks a mysql connection to a MariaDB server, which names some
+// session settings differently
+const FlavorMariaDB = "mariadb"
+
+// Replica routing modes: which kind of tool is sent to the read replica.
+// The other kind stays on the primary.
+const (
+	ReplicaRoutingData     = "data"     // table data reads (get_table_sample) go to the replica
+	ReplicaRoutingMetadata = "metadata" // schema introspection goes to the replica
+)
+
+// Null representations for sample rows
+const (
+	NullRepresentationNull        = "null"
+	NullRepresentationOmit        = "omit"
+	NullRepresentationPlaceholder = "placeholder"
+)
+
+// Concurrency limit modes: what a tool call does when its connection is at max_concurrent_queries
+const (
+	ConcurrencyLimitQueue  = "queue"
+	ConcurrencyLimitReject = "reject"
+)
+
+// Credential fallbacks: where credentials live when the system keychain is unavailable
+const (
+	CredentialFallbackNone = "none"
+	CredentialFallbackFile = "file"
+)
+
+// DefaultNullPlaceholder is shown for NULL in placeholder mode when null_placeholder is unset
+const DefaultNullPlaceholder = "NULL"
+
+// DefaultSampleMaxRows is the most rows get_table_sample returns unless
+// sample_max_rows says otherwise
+const DefaultSampleMaxRows = 100
+
+// DefaultSampleMaxBytes is the most encoded bytes a get_table_sample result
+// holds unless sample_max_bytes says otherwise
+const DefaultSampleMaxBytes = 8 << 20
+
+// HasReadReplica reports whether the connection has a read replica configured
+func (c Connection) HasReadReplica() bool {
+	return c.ReadReplicaHost != ""
+}
+
+// UseReplica reports whether a tool should run on the read replica. dataAccess
+// is true for tools that read table data and false for schema introspection.
+func (c Connection) UseReplica(dataAccess bool) bool {
+	if !c.HasReadReplica() {
+		return false
+	}
+	if c.ReplicaRouting == ReplicaRoutingMetadata {
+		return !dataAccess
+	}
+	return dataAccess
+}
+
+// ReplicaConnection returns the settings for connecting to the read replica:
+// the same connection with the replica host and port
+func (c Connection) ReplicaConnection() Connection {
+	replica := c
+	replica.Host = c.ReadReplicaHost
+	replica.Socket = ""
+	if c.ReadReplicaPort != 0 {
+		replica.Port = c.ReadReplicaPort
+	}
+	return replica
+}
+
+// AWS limits on assumed-role session durations, in seconds. Role chaining
+// caps the final session at one hour regardless of the role's maximum.
+const (
+	DefaultSTSDurationSeconds    = 3600
+	MinSTSDurationSeconds        = 900
+	MaxSTSDurationSeconds        = 43200
+	MaxChainedSTSDurationSeconds = 3600
+)
+
+// STSDuration returns how long assumed-role credentials should last, in seconds
+func (c Connection) STSDuration() int64 {
+	if c.STSDurationSeconds == 0 {
+		return DefaultSTSDurationSeconds
+	}
+	return c.STSDurationSeconds
+}
+
+// DefaultSalesforceAPIVersion is used when a salesforce connection doesn't set one
+const DefaultSalesforceAPIVersion = "62.0"
+
+// salesforceAPIVersionPattern matches versions such as 62.0, with or without a leading v
+var salesforceAPIVersionPattern = regexp.MustCompile(`^v?\d+\.\d+$`)
+
+// SalesforceVersion returns the Salesforce REST API version without the "v" prefix
+func (c Connection) SalesforceVersion() string {
+	if c.SalesforceAPIVersion == "" {
+		return DefaultSalesforceAPIVersion
+	}
+	return strings.TrimPrefix(c.SalesforceAPIVersion, "v")
+}
+
+// GlueSamplingEnabled reports whether get_table_sample may run Athena queries
+// for a glue connection; when false only catalog metadata is available
+func (c Connection) GlueSamplingEnabled() bool {
+	return c.GlueSampleEnabled == nil || *c.GlueSampleEnabled
+}
+
+// IsEnabled reports whether the connection is in service
+func (c Connection) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// CheckEnabled returns ErrConnectionDisabled, naming the connection, unless
+// it is in service
+func (c Connection) CheckEnabled(name string) error {
+	if !c.IsEnabled() {
+		return fmt.Errorf("%w: '%s' has enabled set to false; re-enable it with 'simpledb-cli connection enable %s'", ErrConnectionDisabled, name, name)
+	}
+	return nil
+}
+
+// SamplingAllowed reports whether tools may read table data, not just schema
+func (c Connection) SamplingAllowed() bool {
+	return c.AllowSampling == nil || *c.AllowSampling
+}
+
+// CheckDataAccess returns ErrDataAccessDisabled when the connection forbids data reads
+func (c Connection) CheckDataAccess(name string) error {
+	if !c.SamplingAllowed() {
+		return fmt.Errorf("%w: '%s' has allow_sampling set to false", ErrDataAccessDisabled, name)
+	}
+	return nil
 }
 
 type Settings struct {
 	QueryTimeout     time.Duration `yaml:"query_timeout"`
+	ConnectTimeout   time.Duration `yaml:"connect_timeout"` // bounds the initial dial and ping of a new connection
 	MaxRows          int           `yaml:"max_rows"`
+	SampleMaxColumns int           `yaml:"sample_max_columns"` // caps the columns get_table_sample returns (0 means no cap)
+	SampleMaxRows    int           `yaml:"sample_max_rows"`    // caps the rows get_table_sample returns, within the connection's max_rows
+	SampleMaxBytes   int           `yaml:"sample_max_bytes"`   // caps the encoded size of a MySQL or PostgreSQL sample (0 means DefaultSampleMaxBytes)
+	MaxListItems     int           `yaml:"max_list_items"`     // caps the databases, schemas, tables, columns and indexes list tools return (0 means no cap)
 	CacheCredentials time.Duration `yaml:"cache_credentials"`
 	RequireBiometric bool          `yaml:"require_biometric"`
+
+	// CredentialFallback picks where credentials live when the system keychain
+	// can't be reached, e.g. on headless Linux CI: none (the default; every
+	// credential lookup fails) or file, which reads SIMPLEDB_MCP_CREDENTIAL_*
+	// environment variables and then CredentialFile (default
+	// ~/.config/simpledb-mcp/credentials.json). Opt-in, since both are weaker
+	// than the keychain.
+	CredentialFallback string `yaml:"credential_fallback,omitempty"`
+	CredentialFile     string `yaml:"credential_file,omitempty"`
+
+	// HideSystemDatabases leaves MySQL's information_schema, performance_schema,
+	// mysql and sys and Postgres' templates and postgres database out of list_databases
+	HideSystemDatabases bool `yaml:"hide_system_databases"`
+
+	// ResolveTableSchema makes Postgres table tools called without a schema
+	// look the table up, on the search_path first and then in every schema,
+	// instead of assuming public; a name found in several schemas is refused
+	ResolveTableSchema bool `yaml:"resolve_table_schema"`
+
+	// SlowQueryThreshold logs a warning for tool calls that take longer than this (0 disables)
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+
+	// MaxConcurrentQueries bounds the tool calls in flight on one connection
+	// (0 means no limit); connections can set their own. ConcurrencyLimitMode
+	// decides what happens to calls beyond it: queue (wait for a slot, the
+	// default) or reject (fail at once).
+	MaxConcurrentQueries int    `yaml:"max_concurrent_queries"`
+	ConcurrencyLimitMode string `yaml:"concurrency_limit_mode,omitempty"`
+
+	// ResultCacheTTL keeps the results of read-only tools this long, so an
+	// identical call (same tool and arguments) within it is answered without
+	// querying again; calls can pass no_cache to bypass it (0 disables)
+	ResultCacheTTL time.Duration `yaml:"result_cache_ttl"`
+
+	// MetricsLogInterval logs pool metrics and each pooled connection's status
+	// at this interval, for long-running servers nobody scrapes (0 disables)
+	MetricsLogInterval time.Duration `yaml:"metrics_log_interval"`
+
+	// RedactConnectionDetailsInLogs masks connection hosts and usernames in all log output
+	RedactConnectionDetailsInLogs bool `yaml:"redact_connection_details_in_logs"`
+
+	// LogQueries logs every SQL, SOQL and Athena query the tools run;
+	// LogQueriesRedactLiterals replaces literal values in WHERE clauses with ?
+	LogQueries               bool `yaml:"log_queries"`
+	LogQueriesRedactLiterals bool `yaml:"log_queries_redact_literals"`
+
+	// NullRepresentation controls how SQL NULL appears in sample rows: null
+	// (JSON null, the default), omit (the key is left out of the row) or
+	// placeholder (NullPlaceholder, "NULL" if unset). Empty strings stay "".
+	NullRepresentation string `yaml:"null_representation,omitempty"`
+	NullPlaceholder    string `yaml:"null_placeholder,omitempty"`
+
+	// PIIPatterns redacts matches of these named regular expressions from
+	// string values in sample and distinct-value results, replacing each
+	// with [REDACTED:<name>]. Off unless patterns are listed.
+	PIIPatterns []PIIPattern `yaml:"pii_patterns,omitempty"`
+
+	// SafeMode guarantees a read-only deployment: tools not declared read-only
+	// are never registered and every database session is opened read-only.
+	// Unset means on for the network transports (http, websocket) and off for stdio.
+	SafeMode *bool `yaml:"safe_mode,omitempty"`
+
+	// ConnectionNamePattern is a regular expression new connection names must
+	// match (default DefaultConnectionNamePattern). Names may never contain ':'
+	// or whitespace, whatever the pattern allows, since they become keychain keys.
+	ConnectionNamePattern string `yaml:"connection_name_pattern,omitempty"`
+
+	// Instructions is advertised to MCP clients when they connect, for them to
+	// pass on to the model; empty means a generated summary of the
+	// connections and limits
+	Instructions string `yaml:"instructions,omitempty"`
+
+	// EnabledTools limits the exposed tools to these names or globs (empty means all);
+	// DisabledTools removes tools from that set
+	EnabledTools  []string `yaml:"enabled_tools,omitempty"`
+	DisabledTools []string `yaml:"disabled_tools,omitempty"`
 	
 	// Connection pool settings
 	ConnectionPool ConnectionPoolSettings `yaml:"connection_pool"`
@@ -41,34 +299,136 @@ type Settings struct {
 	Server ServerSettings `yaml:"server"`
 }
 
+// ConcurrencyLimit returns how many tool calls may run at once on a
+// connection, or 0 for no limit
+func (s Settings) ConcurrencyLimit(conn Connection) int {
+	if conn.MaxConcurrentQueries > 0 {
+		return conn.MaxConcurrentQueries
+	}
+	return s.MaxConcurrentQueries
+}
+
+// RowLimit returns the most rows a tool may return from a connection: its own
+// max_rows, or settings.max_rows
+func (s Settings) RowLimit(conn Connection) int {
+	if conn.MaxRows > 0 {
+		return conn.MaxRows
+	}
+	return s.MaxRows
+}
+
+// SampleRowLimit returns the most rows get_table_sample may return from a
+// connection, the smaller of sample_max_rows and the connection's RowLimit
+func (s Settings) SampleRowLimit(conn Connection) int {
+	sampleMax := s.SampleMaxRows
+	if sampleMax <= 0 {
+		sampleMax = DefaultSampleMaxRows
+	}
+	if limit := s.RowLimit(conn); limit < sampleMax {
+		return limit
+	}
+	return sampleMax
+}
+
+// SampleByteLimit returns the most encoded bytes a streamed get_table_sample
+// result may hold
+func (s Settings) SampleByteLimit() int {
+	if s.SampleMaxBytes <= 0 {
+		return DefaultSampleMaxBytes
+	}
+	return s.SampleMaxBytes
+}
+
+// CredentialFallbackFile returns the file credentials fall back to when the
+// keychain is unavailable, or "" when credential_fallback isn't file
+func (s Settings) CredentialFallbackFile() string {
+	if s.CredentialFallback != CredentialFallbackFile {
+		return ""
+	}
+	if s.CredentialFile != "" {
+		return s.CredentialFile
+	}
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "credentials.json"
+	}
+	return filepath.Join(configDir, "credentials.json")
+}
+
+// SafeModeEnabled reports whether safe mode is on, applying the transport default
+func (s Settings) SafeModeEnabled() bool {
+	if s.SafeMode != nil {
+		return *s.SafeMode
+	}
+	return s.Server.Transport == "http" || s.Server.Transport == "websocket"
+}
+
 type ConnectionPoolSettings struct {
 	PingInterval    time.Duration `yaml:"ping_interval"`
 	MaxIdleTime     time.Duration `yaml:"max_idle_time"`
 	MaxErrorCount   int           `yaml:"max_error_count"`
 	ReconnectDelay  time.Duration `yaml:"reconnect_delay"`
 	EnableKeepalive bool          `yaml:"enable_keepalive"`
+	// MaxPooledConnections caps how many connections stay open at once; the least
+	// recently used idle one is closed to make room (0 means no cap)
+	MaxPooledConnections int `yaml:"max_pooled_connections"`
+	// ServerIdleTimeout is how long the server, or a proxy in front of it, keeps
+	// an idle connection open (e.g. MySQL wait_timeout); 0 means unknown
+	ServerIdleTimeout time.Duration `yaml:"server_idle_timeout"`
+	// WarmPingAfter pings a connection before handing it out once it has been
+	// idle this long, so one the server dropped is replaced before the query
+	// runs instead of failing it (0 disables)
+	WarmPingAfter time.Duration `yaml:"warm_ping_after"`
+}
+
+// KeepaliveWarnings describes pool settings that let idle connections be
+// closed before anything pings them
+func (s ConnectionPoolSettings) KeepaliveWarnings() []string {
+	var warnings []string
+	if s.EnableKeepalive && s.MaxIdleTime > 0 && s.PingInterval >= s.MaxIdleTime {
+		warnings = append(warnings, fmt.Sprintf("connection_pool.ping_interval (%s) is not shorter than max_idle_time (%s); idle connections are closed before they are pinged", s.PingInterval, s.MaxIdleTime))
+	}
+	if s.ServerIdleTimeout > 0 {
+		if s.EnableKeepalive && s.PingInterval >= s.ServerIdleTimeout {
+			warnings = append(warnings, fmt.Sprintf("connection_pool.ping_interval (%s) is not shorter than server_idle_timeout (%s); the server drops idle connections before they are pinged", s.PingInterval, s.ServerIdleTimeout))
+		}
+		if s.WarmPingAfter >= s.ServerIdleTimeout {
+			warnings = append(warnings, fmt.Sprintf("connection_pool.warm_ping_after (%s) is not shorter than server_idle_timeout (%s); connections the server dropped can be handed out unchecked", s.WarmPingAfter, s.ServerIdleTimeout))
+		}
+	}
+	return warnings
 }
 
 type ServerSettings struct {
-	Transport string `yaml:"transport"` // stdio, http, gin
+	Transport string `yaml:"transport"` // stdio, http, websocket
 	Address   string `yaml:"address"`   // for http/gin transport (e.g., ":8080")
 	Path      string `yaml:"path"`      // endpoint path for http/gin (e.g., "/mcp")
 }
 
 func DefaultConfig() *Config {
 	return &Config{
+		Version:     CurrentConfigVersion,
 		Connections: make(map[string]Connection),
 		Settings: Settings{
-			QueryTimeout:     30 * time.Second,
-			MaxRows:          1000,
-			CacheCredentials: 5 * time.Minute,
-			RequireBiometric: true,
+			QueryTimeout:         30 * time.Second,
+			ConnectTimeout:       10 * time.Second,
+			MaxRows:              1000,
+			SampleMaxColumns:     50,
+			SampleMaxRows:        DefaultSampleMaxRows,
+			MaxListItems:         500,
+			MaxConcurrentQueries: 4,
+			CacheCredentials:     5 * time.Minute,
+			RequireBiometric:     true,
+			SlowQueryThreshold:   5 * time.Second,
+			HideSystemDatabases:  true,
 			ConnectionPool: ConnectionPoolSettings{
-				PingInterval:    30 * time.Second,
-				MaxIdleTime:     15 * time.Minute,
-				MaxErrorCount:   3,
-				ReconnectDelay:  5 * time.Second,
-				EnableKeepalive: true,
+				PingInterval:         30 * time.Second,
+				MaxIdleTime:          15 * time.Minute,
+				MaxErrorCount:        3,
+				ReconnectDelay:       5 * time.Second,
+				EnableKeepalive:      true,
+				MaxPooledConnections: 20,
+				WarmPingAfter:        time.Minute,
 			},
 			Server: ServerSettings{
 				Transport: "stdio",
@@ -113,14 +473,285 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	data, fileVersion, err := migrateConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	switch {
+	case fileVersion < CurrentConfigVersion:
+		log.Printf("Config file is version %d; upgraded to version %d in memory (run simpledb-mcp --migrate-config to update the file)", fileVersion, CurrentConfigVersion)
+	case fileVersion > CurrentConfigVersion:
+		log.Printf("Warning: config file is version %d but this build supports up to version %d; settings it doesn't know are ignored", fileVersion, CurrentConfigVersion)
+	}
+
 	config := DefaultConfig()
 	if err := yaml.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	config.applyDefaults()
+	if err := config.normalizeTypes(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+
+	for _, warning := range config.ConnectionNameWarnings() {
+		log.Printf("Warning: %s", warning)
+	}
+	for _, warning := range config.DeprecationWarnings() {
+		log.Printf("Warning: %s", warning)
+	}
+
 	return config, nil
 }
 
+// applyDefaults fills every zero-valued connection field from the defaults block.
+// Explicit per-connection values always win; note that this means a boolean
+// defaulted to true cannot be switched off per connection.
+func (c *Config) applyDefaults() {
+	defaults := reflect.ValueOf(c.Defaults)
+	if defaults.IsZero() {
+		return
+	}
+
+	c.explicit = make(map[string]Connection, len(c.Connections))
+	for name, conn := range c.Connections {
+		c.explicit[name] = cloneConnection(conn)
+		fillUnset(&conn, c.Defaults)
+		c.Connections[name] = conn
+	}
+}
+
+// withoutDefaults returns the connections as Save writes them: a field the
+// file left unset that still holds the default is unset again, so a later
+// change to the defaults block still reaches it. Connections added since
+// Load are written as they are.
+func (c *Config) withoutDefaults() map[string]Connection {
+	if c.explicit == nil {
+		return c.Connections
+	}
+
+	defaults := reflect.ValueOf(c.Defaults)
+	connections := make(map[string]Connection, len(c.Connections))
+	for name, conn := range c.Connections {
+		explicit, loaded := c.explicit[name]
+		if loaded {
+			conn = cloneConnection(conn)
+			source := reflect.ValueOf(explicit)
+			target := reflect.ValueOf(&conn).Elem()
+			for i := 0; i < target.NumField(); i++ {
+				field := target.Field(i)
+				if source.Field(i).IsZero() && reflect.DeepEqual(field.Interface(), defaults.Field(i).Interface()) {
+					field.Set(reflect.Zero(field.Type()))
+				}
+			}
+		}
+		connections[name] = conn
+	}
+	return connections
+}
+
+// Validate checks the configuration for problems without connecting to anything.
+// All problems found are returned together, one per line.
+func (c *Config) Validate() error {
+	var problems []error
+
+	names := c.ListConnections()
+	sort.Strings(names)
+	for _, name := range names {
+		conn := c.Connections[name]
+		switch conn.Type {
+		case "mysql", "postgres":
+			switch {
+			case conn.Socket != "" && conn.Host != "":
+				problems = append(problems, fmt.Errorf("connection '%s': socket and host cannot both be set", name))
+			case conn.Socket != "" && conn.Proxy != "":
+				problems = append(problems, fmt.Errorf("connection '%s': socket connections cannot use a proxy", name))
+			case conn.Socket == "" && conn.Host == "":
+				problems = append(problems, fmt.Errorf("connection '%s': host is required", name))
+			}
+			// A socket connection needs no port, but one that is given must still be valid
+			if (conn.Socket == "" || conn.Port != 0) && (conn.Port <= 0 || conn.Port > 65535) {
+				problems = append(problems, fmt.Errorf("connection '%s': port %d is out of range", name, conn.Port))
+			}
+			if conn.PasswordFile != "" && conn.Username == "" {
+				problems = append(problems, fmt.Errorf("connection '%s': password_file requires username", name))
+			}
+			if conn.Proxy != "" {
+				if _, err := ParseProxyURL(conn.Proxy); err != nil {
+					problems = append(problems, fmt.Errorf("connection '%s': %w", name, err))
+				}
+			}
+			if conn.Flavor != "" && (conn.Type != "mysql" || conn.Flavor != FlavorMariaDB) {
+				problems = append(problems, fmt.Errorf("connection '%s': flavor '%s' is not supported for %s (only mariadb, for mysql)", name, conn.Flavor, conn.Type))
+			}
+			if conn.HasReadReplica() {
+				replicaPort := conn.ReplicaConnection().Port
+				if replicaPort <= 0 || replicaPort > 65535 {
+					problems = append(problems, fmt.Errorf("connection '%s': read_replica_port %d is out of range", name, replicaPort))
+				}
+			}
+		case "salesforce":
+			if conn.Host == "" {
+				problems = append(problems, fmt.Errorf("connection '%s': host must be the Salesforce instance URL", name))
+			}
+			if conn.SalesforceAPIVersion != "" && !salesforceAPIVersionPattern.MatchString(conn.SalesforceAPIVersion) {
+				problems = append(problems, fmt.Errorf("connection '%s': salesforce_api_version '%s' must look like 62.0", name, conn.SalesforceAPIVersion))
+			}
+		case "glue":
+			switch {
+			case conn.AWSRegion() != "":
+				if err := validateAWSRegion(conn.AWSRegion()); err != nil {
+					problems = append(problems, fmt.Errorf("connection '%s': %w", name, err))
+				}
+			case !conn.UsesAWSProfile():
+				// A profile can supply the region instead
+				problems = append(problems, fmt.Errorf("connection '%s': region is required for glue", name))
+			}
+			if conn.UsesAWSProfile() {
+				if conn.RoleArn != "" || conn.MFASerial != "" || conn.MFAMode != "" || conn.SourceRoleArn != "" || conn.STSDurationSeconds != 0 {
+					problems = append(problems, fmt.Errorf("connection '%s': role_arn, mfa_serial, mfa_mode, source_role_arn and sts_duration_seconds configure the MFA flow and cannot be combined with aws_profile or use_sso; set role_arn in the profile instead", name))
+				}
+				break
+			}
+			if conn.RoleArn == "" {
+				problems = append(problems, fmt.Errorf("connection '%s': role_arn is required for glue", name))
+			}
+			switch conn.MFAMode {
+			case "", "gauth", "dialog", "totp":
+			default:
+				problems = append(problems, fmt.Errorf("connection '%s': mfa_mode must be gauth, dialog or totp", name))
+			}
+			if d := conn.STSDuration(); d < MinSTSDurationSeconds || d > MaxSTSDurationSeconds {
+				problems = append(problems, fmt.Errorf("connection '%s': sts_duration_seconds must be between %d and %d", name, MinSTSDurationSeconds, MaxSTSDurationSeconds))
+			} else if conn.SourceRoleArn != "" && d > MaxChainedSTSDurationSeconds {
+				problems = append(problems, fmt.Errorf("connection '%s': sts_duration_seconds cannot exceed %d with source_role_arn (AWS role chaining limit)", name, MaxChainedSTSDurationSeconds))
+			}
+		case "":
+			problems = append(problems, fmt.Errorf("connection '%s': type is required", name))
+		default:
+			problems = append(problems, fmt.Errorf("connection '%s': unsupported type '%s' (supported types: %s)", name, conn.Type, strings.Join(SupportedTypes, ", ")))
+		}
+		problems = append(problems, validateDSNParams(name, conn)...)
+		if conn.MaxConcurrentQueries < 0 {
+			problems = append(problems, fmt.Errorf("connection '%s': max_concurrent_queries cannot be negative", name))
+		}
+		if conn.MaxRows < 0 {
+			problems = append(problems, fmt.Errorf("connection '%s': max_rows cannot be negative", name))
+		}
+		if conn.Proxy != "" && conn.Type != "mysql" && conn.Type != "postgres" {
+			problems = append(problems, fmt.Errorf("connection '%s': proxy is only supported for mysql and postgres", name))
+		}
+		if (conn.STSDurationSeconds != 0 || conn.SourceRoleArn != "" || conn.MFAMode != "") && conn.Type != "glue" {
+			problems = append(problems, fmt.Errorf("connection '%s': sts_duration_seconds, source_role_arn and mfa_mode are only supported for glue", name))
+		}
+		if conn.SalesforceAPIVersion != "" && conn.Type != "salesforce" {
+			problems = append(problems, fmt.Errorf("connection '%s': salesforce_api_version is only supported for salesforce", name))
+		}
+		if conn.Region != "" && conn.Type != "glue" {
+			problems = append(problems, fmt.Errorf("connection '%s': region is only supported for glue", name))
+		}
+		if conn.UsesAWSProfile() && conn.Type != "glue" {
+			problems = append(problems, fmt.Errorf("connection '%s': aws_profile and use_sso are only supported for glue", name))
+		}
+		if conn.GlueSampleEnabled != nil && conn.Type != "glue" {
+			problems = append(problems, fmt.Errorf("connection '%s': glue_sample_enabled is only supported for glue", name))
+		}
+		if conn.HasReadReplica() && conn.Type != "mysql" && conn.Type != "postgres" {
+			problems = append(problems, fmt.Errorf("connection '%s': read_replica_host is only supported for mysql and postgres", name))
+		}
+		switch conn.ReplicaRouting {
+		case "", ReplicaRoutingData, ReplicaRoutingMetadata:
+			if conn.ReplicaRouting != "" && !conn.HasReadReplica() {
+				problems = append(problems, fmt.Errorf("connection '%s': replica_routing requires read_replica_host", name))
+			}
+		default:
+			problems = append(problems, fmt.Errorf("connection '%s': replica_routing must be '%s' or '%s'", name, ReplicaRoutingData, ReplicaRoutingMetadata))
+		}
+	}
+
+	if c.Settings.QueryTimeout <= 0 {
+		problems = append(problems, fmt.Errorf("settings: query_timeout must be positive"))
+	}
+	if c.Settings.ConnectTimeout <= 0 {
+		problems = append(problems, fmt.Errorf("settings: connect_timeout must be positive"))
+	}
+	if c.Settings.MaxRows <= 0 {
+		problems = append(problems, fmt.Errorf("settings: max_rows must be positive"))
+	}
+	if c.Settings.SampleMaxRows <= 0 {
+		problems = append(problems, fmt.Errorf("settings: sample_max_rows must be positive"))
+	}
+	if c.Settings.SampleMaxBytes < 0 {
+		problems = append(problems, fmt.Errorf("settings: sample_max_bytes cannot be negative"))
+	}
+	if c.Settings.SampleMaxColumns < 0 {
+		problems = append(problems, fmt.Errorf("settings: sample_max_columns cannot be negative"))
+	}
+	if c.Settings.MaxListItems < 0 {
+		problems = append(problems, fmt.Errorf("settings: max_list_items cannot be negative"))
+	}
+	if c.Settings.MaxConcurrentQueries < 0 {
+		problems = append(problems, fmt.Errorf("settings: max_concurrent_queries cannot be negative"))
+	}
+	switch c.Settings.ConcurrencyLimitMode {
+	case "", ConcurrencyLimitQueue, ConcurrencyLimitReject:
+	default:
+		problems = append(problems, fmt.Errorf("settings: concurrency_limit_mode must be '%s' or '%s'", ConcurrencyLimitQueue, ConcurrencyLimitReject))
+	}
+	if c.Settings.ResultCacheTTL < 0 {
+		problems = append(problems, fmt.Errorf("settings: result_cache_ttl cannot be negative"))
+	}
+	if c.Settings.MetricsLogInterval < 0 {
+		problems = append(problems, fmt.Errorf("settings: metrics_log_interval cannot be negative"))
+	}
+	if c.Settings.ConnectionNamePattern != "" {
+		if _, err := regexp.Compile(c.Settings.ConnectionNamePattern); err != nil {
+			problems = append(problems, fmt.Errorf("settings: connection_name_pattern is not a valid regular expression: %w", err))
+		}
+	}
+	problems = append(problems, validatePIIPatterns(c.Settings.PIIPatterns)...)
+	switch c.Settings.CredentialFallback {
+	case "", CredentialFallbackNone, CredentialFallbackFile:
+	default:
+		problems = append(problems, fmt.Errorf("settings: credential_fallback must be '%s' or '%s'", CredentialFallbackNone, CredentialFallbackFile))
+	}
+	if c.Settings.CredentialFile != "" && c.Settings.CredentialFallback != CredentialFallbackFile {
+		problems = append(problems, fmt.Errorf("settings: credential_file is only used with credential_fallback: %s", CredentialFallbackFile))
+	}
+	switch c.Settings.NullRepresentation {
+	case "", NullRepresentationNull, NullRepresentationOmit, NullRepresentationPlaceholder:
+	default:
+		problems = append(problems, fmt.Errorf("settings: null_representation must be '%s', '%s' or '%s'", NullRepresentationNull, NullRepresentationOmit, NullRepresentationPlaceholder))
+	}
+	if c.Settings.ConnectionPool.MaxPooledConnections < 0 {
+		problems = append(problems, fmt.Errorf("settings: connection_pool.max_pooled_connections cannot be negative"))
+	}
+	if c.Settings.ConnectionPool.ServerIdleTimeout < 0 {
+		problems = append(problems, fmt.Errorf("settings: connection_pool.server_idle_timeout cannot be negative"))
+	}
+	if c.Settings.ConnectionPool.WarmPingAfter < 0 {
+		problems = append(problems, fmt.Errorf("settings: connection_pool.warm_ping_after cannot be negative"))
+	}
+	if c.Settings.ConnectionPool.EnableKeepalive && c.Settings.ConnectionPool.PingInterval <= 0 {
+		problems = append(problems, fmt.Errorf("settings: connection_pool.ping_interval must be positive when keepalive is enabled"))
+	}
+
+	switch c.Settings.Server.Transport {
+	case "stdio":
+	case "http", "websocket":
+		if c.Settings.Server.Address == "" {
+			problems = append(problems, fmt.Errorf("settings: server.address is required for %s transport", c.Settings.Server.Transport))
+		}
+		if c.Settings.Server.Path == "" {
+			problems = append(problems, fmt.Errorf("settings: server.path is required for %s transport", c.Settings.Server.Transport))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("settings: unsupported server.transport '%s'", c.Settings.Server.Transport))
+	}
+
+	return errors.Join(problems...)
+}
+
 func (c *Config) Save() error {
 	configDir, err := ConfigDir()
 	if err != nil {
@@ -137,7 +768,9 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	data, err := yaml.Marshal(c)
+	saved := *c
+	saved.Connections = c.withoutDefaults()
+	data, err := yaml.Marshal(&saved)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -149,7 +782,14 @@ func (c *Config) Save() error {
 	return nil
 }
 
+// AddConnection saves a connection. New names must pass ValidateConnectionName;
+// an existing connection is updated in place whatever its name.
 func (c *Config) AddConnection(name string, conn Connection) error {
+	if _, exists := c.Connections[name]; !exists {
+		if err := c.ValidateConnectionName(name); err != nil {
+			return err
+		}
+	}
 	if c.Connections == nil {
 		c.Connections = make(map[string]Connection)
 	}
@@ -157,6 +797,21 @@ func (c *Config) AddConnection(name string, conn Connection) error {
 	return c.Save()
 }
 
+// SetConnectionEnabled takes a connection in or out of service and saves the
+// config. Enabling clears the setting, since connections are enabled by default.
+func (c *Config) SetConnectionEnabled(name string, enabled bool) error {
+	conn, exists := c.Connections[name]
+	if !exists {
+		return fmt.Errorf("connection '%s' not found", name)
+	}
+	conn.Enabled = nil
+	if !enabled {
+		conn.Enabled = &enabled
+	}
+	c.Connections[name] = conn
+	return c.Save()
+}
+
 func (c *Config) RemoveConnection(name string) error {
 	delete(c.Connections, name)
 	return c.Save()
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DiscoveredConnection is a candidate connection found in another tool's config file.
+// Passwords are never carried over; they are stored separately in the keychain.
+type DiscoveredConnection struct {
+	Name       string
+	Source     string
+	Connection Connection
+}
+
+// DiscoverConnections looks for connections in ~/.pgpass (or $PGPASSFILE) and ~/.my.cnf.
+// Files that don't exist are skipped.
+func DiscoverConnections(homeDir string) ([]DiscoveredConnection, error) {
+	var discovered []DiscoveredConnection
+
+	pgpassPath := os.Getenv("PGPASSFILE")
+	if pgpassPath == "" {
+		pgpassPath = filepath.Join(homeDir, ".pgpass")
+	}
+	found, err := discoverFromFile(pgpassPath, ParsePgpass)
+	if err != nil {
+		return nil, err
+	}
+	discovered = append(discovered, found...)
+
+	found, err = discoverFromFile(filepath.Join(homeDir, ".my.cnf"), ParseMyCnf)
+	if err != nil {
+		return nil, err
+	}
+	discovered = append(discovered, found...)
+
+	return discovered, nil
+}
+
+func discoverFromFile(path string, parse func(io.Reader, string) ([]DiscoveredConnection, error)) ([]DiscoveredConnection, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	return parse(file, path)
+}
+
+// ParsePgpass parses a PostgreSQL password file (hostname:port:database:username:password).
+// Wildcard hosts are skipped since they don't name a server; a wildcard port or
+// database falls back to the PostgreSQL defaults.
+func ParsePgpass(r io.Reader, source string) ([]DiscoveredConnection, error) {
+	var discovered []DiscoveredConnection
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := splitPgpassLine(line)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("%s:%d: expected 5 fields, got %d", source, lineNumber, len(fields))
+		}
+		host, portField, database, username := fields[0], fields[1], fields[2], fields[3]
+		if host == "*" {
+			continue
+		}
+
+		port := 5432
+		if portField != "*" {
+			p, err := strconv.Atoi(portField)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid port '%s'", source, lineNumber, portField)
+			}
+			port = p
+		}
+		if database == "*" {
+			database = "postgres"
+		}
+		if username == "*" {
+			username = ""
+		}
+
+		name := discoveredName("pg", host, database)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		discovered = append(discovered, DiscoveredConnection{
+			Name:   name,
+			Source: source,
+			Connection: Connection{
+				Type:     "postgres",
+				Host:     host,
+				Port:     port,
+				Database: database,
+				Username: username,
+			},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+
+	return discovered, nil
+}
+
+// splitPgpassLine splits on ':' while honouring the '\:' and '\\' escapes
+func splitPgpassLine(line string) []string {
+	var fields []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			fields = append(fields, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(fields, current.String())
+}
+
+// ParseMyCnf parses a MySQL option file. The [client] and [mysql] groups make up
+// the default connection; suffixed groups such as [client_prod] each become a
+// separate connection that inherits from the defaults.
+func ParseMyCnf(r io.Reader, source string) ([]DiscoveredConnection, error) {
+	groups := make(map[string]map[string]string)
+	var order []string
+	current := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			if _, exists := groups[current]; !exists {
+				groups[current] = make(map[string]string)
+				order = append(order, current)
+			}
+			continue
+		}
+		if current == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		key = strings.ReplaceAll(strings.TrimSpace(key), "-", "_")
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		groups[current][key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+
+	base := make(map[string]string)
+	for _, group := range []string{"client", "mysql"} {
+		for key, value := range groups[group] {
+			base[key] = value
+		}
+	}
+
+	var discovered []DiscoveredConnection
+	if len(base) > 0 {
+		conn, err := myCnfConnection(base)
+		if err != nil {
+			return nil, fmt.Errorf("%s: [client]: %w", source, err)
+		}
+		discovered = append(discovered, DiscoveredConnection{
+			Name:       discoveredName("mysql", conn.Host, conn.Database),
+			Source:     source,
+			Connection: conn,
+		})
+	}
+
+	for _, group := range order {
+		var suffix string
+		switch {
+		case strings.HasPrefix(group, "client_"):
+			suffix = strings.TrimPrefix(group, "client_")
+		case strings.HasPrefix(group, "mysql_"):
+			suffix = strings.TrimPrefix(group, "mysql_")
+		default:
+			continue
+		}
+
+		options := make(map[string]string)
+		for key, value := range base {
+			options[key] = value
+		}
+		for key, value := range groups[group] {
+			options[key] = value
+		}
+
+		conn, err := myCnfConnection(options)
+		if err != nil {
+			return nil, fmt.Errorf("%s: [%s]: %w", source, group, err)
+		}
+		discovered = append(discovered, DiscoveredConnection{
+			Name:       discoveredName("mysql", suffix),
+			Source:     source,
+			Connection: conn,
+		})
+	}
+
+	return discovered, nil
+}
+
+func myCnfConnection(options map[string]string) (Connection, error) {
+	conn := Connection{
+		Type:     "mysql",
+		Host:     options["host"],
+		Port:     3306,
+		Database: options["database"],
+		Username: options["user"],
+	}
+	if conn.Host == "" {
+		conn.Host = "localhost"
+	}
+	if port, exists := options["port"]; exists {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return Connection{}, fmt.Errorf("invalid port '%s'", port)
+		}
+		conn.Port = p
+	}
+	return conn, nil
+}
+
+var nameUnsafeChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// discoveredName builds a connection name like "pg-db-example-com-analytics"
+func discoveredName(prefix string, parts ...string) string {
+	name := prefix
+	for _, part := range parts {
+		part = strings.Trim(nameUnsafeChars.ReplaceAllString(strings.ToLower(part), "-"), "-")
+		if part != "" {
+			name += "-" + part
+		}
+	}
+	return name
+}
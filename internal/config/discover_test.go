@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestParsePgpass(t *testing.T) {
+	pgpass := `# production analytics
+db.example.com:5432:analytics:readonly:s3cret
+localhost:*:*:postgres:pw
+*:5432:*:fallback:pw
+odd\:host:6543:app:user:pa\:ss
+db.example.com:5432:analytics:other:dup
+`
+
+	discovered, err := ParsePgpass(strings.NewReader(pgpass), ".pgpass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, len(discovered))
+
+	first := discovered[0]
+	testutil.AssertEqual(t, "pg-db-example-com-analytics", first.Name)
+	testutil.AssertEqual(t, ".pgpass", first.Source)
+	testutil.AssertEqual(t, Connection{
+		Type:     "postgres",
+		Host:     "db.example.com",
+		Port:     5432,
+		Database: "analytics",
+		Username: "readonly",
+	}, first.Connection)
+
+	// Wildcard port and database fall back to defaults
+	local := discovered[1].Connection
+	testutil.AssertEqual(t, 5432, local.Port)
+	testutil.AssertEqual(t, "postgres", local.Database)
+
+	// Escaped colons are part of the field
+	testutil.AssertEqual(t, "odd:host", discovered[2].Connection.Host)
+	testutil.AssertEqual(t, 6543, discovered[2].Connection.Port)
+}
+
+func TestParsePgpassInvalid(t *testing.T) {
+	_, err := ParsePgpass(strings.NewReader("host:5432:db\n"), ".pgpass")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), ".pgpass:1")
+
+	_, err = ParsePgpass(strings.NewReader("host:abc:db:user:pw\n"), ".pgpass")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid port")
+}
+
+func TestParseMyCnf(t *testing.T) {
+	mycnf := `[client]
+user = dev
+password = secret
+host = 127.0.0.1
+
+[mysqld]
+port = 3307
+
+[mysql]
+database = app
+
+[client_prod]
+host = "prod-db.example.com"
+port = 3310
+user = deploy
+`
+
+	discovered, err := ParseMyCnf(strings.NewReader(mycnf), ".my.cnf")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(discovered))
+
+	testutil.AssertEqual(t, "mysql-127-0-0-1-app", discovered[0].Name)
+	testutil.AssertEqual(t, Connection{
+		Type:     "mysql",
+		Host:     "127.0.0.1",
+		Port:     3306,
+		Database: "app",
+		Username: "dev",
+	}, discovered[0].Connection)
+
+	// Suffixed groups inherit the defaults and override them
+	testutil.AssertEqual(t, "mysql-prod", discovered[1].Name)
+	testutil.AssertEqual(t, Connection{
+		Type:     "mysql",
+		Host:     "prod-db.example.com",
+		Port:     3310,
+		Database: "app",
+		Username: "deploy",
+	}, discovered[1].Connection)
+}
+
+func TestDiscoverConnections(t *testing.T) {
+	home := testutil.TempDir(t)
+	t.Setenv("PGPASSFILE", "")
+
+	// No files is not an error
+	discovered, err := DiscoverConnections(home)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, len(discovered))
+
+	testutil.AssertNoError(t, os.WriteFile(filepath.Join(home, ".pgpass"), []byte("pg.local:5432:app:app:pw\n"), 0600))
+	testutil.AssertNoError(t, os.WriteFile(filepath.Join(home, ".my.cnf"), []byte("[client]\nuser=root\n"), 0600))
+
+	discovered, err = DiscoverConnections(home)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(discovered))
+	testutil.AssertEqual(t, "postgres", discovered[0].Connection.Type)
+	testutil.AssertEqual(t, "mysql", discovered[1].Connection.Type)
+	testutil.AssertEqual(t, "localhost", discovered[1].Connection.Host)
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Effective returns a copy of the configuration with the defaults the server
+// applies implicitly written out, such as Postgres' sslmode=prefer, so it can
+// be shown to a user. Proxy passwords are masked; credentials themselves never
+// live in the config file.
+func (c *Config) Effective() *Config {
+	effective := *c
+	effective.Defaults.Proxy = redactProxy(c.Defaults.Proxy)
+	effective.Connections = make(map[string]Connection, len(c.Connections))
+	for name, conn := range c.Connections {
+		effective.Connections[name] = conn.effective()
+	}
+
+	if effective.Settings.NullRepresentation == "" {
+		effective.Settings.NullRepresentation = NullRepresentationNull
+	}
+	if effective.Settings.NullPlaceholder == "" {
+		effective.Settings.NullPlaceholder = DefaultNullPlaceholder
+	}
+	if effective.Settings.CredentialFallback == "" {
+		effective.Settings.CredentialFallback = CredentialFallbackNone
+	}
+	effective.Settings.CredentialFile = effective.Settings.CredentialFallbackFile()
+	if effective.Settings.ConcurrencyLimitMode == "" {
+		effective.Settings.ConcurrencyLimitMode = ConcurrencyLimitQueue
+	}
+	if effective.Settings.ConnectionNamePattern == "" {
+		effective.Settings.ConnectionNamePattern = DefaultConnectionNamePattern
+	}
+	safeMode := effective.Settings.SafeModeEnabled()
+	effective.Settings.SafeMode = &safeMode
+	return &effective
+}
+
+// effective fills in the values a connection gets when it leaves them unset
+func (c Connection) effective() Connection {
+	c.Proxy = redactProxy(c.Proxy)
+
+	switch c.Type {
+	case "mysql", "postgres":
+		if c.Type == "postgres" && c.SSLMode == "" {
+			c.SSLMode = "prefer"
+		}
+		if c.HasReadReplica() {
+			if c.ReplicaRouting == "" {
+				c.ReplicaRouting = ReplicaRoutingData
+			}
+			if c.ReadReplicaPort == 0 {
+				c.ReadReplicaPort = c.Port
+			}
+		}
+	case "glue":
+		if !c.UsesAWSProfile() {
+			c.STSDurationSeconds = c.STSDuration()
+			if c.MFAMode == "" {
+				c.MFAMode = "dialog"
+				if c.UseGauth {
+					c.MFAMode = "gauth"
+				}
+			}
+		}
+		sampling := c.GlueSamplingEnabled()
+		c.GlueSampleEnabled = &sampling
+	case "salesforce":
+		c.SalesforceAPIVersion = c.SalesforceVersion()
+	}
+
+	allowed := c.SamplingAllowed()
+	c.AllowSampling = &allowed
+	enabled := c.IsEnabled()
+	c.Enabled = &enabled
+	return c
+}
+
+// redactProxy masks the password in a proxy URL
+func redactProxy(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "<invalid proxy URL redacted>"
+	}
+	return u.Redacted()
+}
+
+// MarshalEffective renders the effective configuration as YAML
+func (c *Config) MarshalEffective() ([]byte, error) {
+	return yaml.Marshal(c.Effective())
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentConfigVersion is the config file structure this build reads and writes.
+// Files without a version field are version 0.
+const CurrentConfigVersion = 1
+
+// configMigrations[v] upgrades a parsed config document from version v to v+1
+var configMigrations = []func(doc map[string]interface{}){
+	migrateV0,
+}
+
+// migrateV0 upgrades files written before the version field: on glue
+// connections use_gauth: true becomes mfa_mode: gauth, which supersedes it. A
+// use_gauth inherited from the defaults block keeps working as a fallback.
+func migrateV0(doc map[string]interface{}) {
+	connections, _ := doc["connections"].(map[string]interface{})
+	for _, raw := range connections {
+		conn, ok := raw.(map[string]interface{})
+		if !ok || conn["type"] != "glue" {
+			continue
+		}
+		if useGauth, _ := conn["use_gauth"].(bool); useGauth {
+			if _, set := conn["mfa_mode"]; !set {
+				conn["mfa_mode"] = "gauth"
+			}
+		}
+		delete(conn, "use_gauth")
+	}
+}
+
+// migrateConfig upgrades a config file's contents to CurrentConfigVersion and
+// returns them with the version the file was written as. Files already at the
+// current version, or at a newer one this build doesn't know, are returned
+// unchanged.
+func migrateConfig(data []byte) ([]byte, int, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, 0, err
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+
+	version := 0
+	if raw, set := doc["version"]; set {
+		v, ok := raw.(int)
+		if !ok || v < 0 {
+			return nil, 0, fmt.Errorf("version must be a non-negative whole number")
+		}
+		version = v
+	}
+	if version >= CurrentConfigVersion {
+		return data, version, nil
+	}
+
+	for v := version; v < CurrentConfigVersion; v++ {
+		configMigrations[v](doc)
+	}
+	doc["version"] = CurrentConfigVersion
+
+	migrated, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal migrated config: %w", err)
+	}
+	return migrated, version, nil
+}
+
+// MigrateFile rewrites an older config file in the current structure, keeping
+// the original alongside it as config.yaml.v<N>.bak. It returns the version
+// the file had; a missing or current file is left alone.
+func MigrateFile() (int, error) {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return CurrentConfigVersion, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	migrated, version, err := migrateConfig(data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if version >= CurrentConfigVersion {
+		return version, nil
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", configPath, version)
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		return 0, fmt.Errorf("failed to back up config file: %w", err)
+	}
+	if err := os.WriteFile(configPath, migrated, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write config file: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+const versionlessConfig = `
+connections:
+  warehouse:
+    type: glue
+    host: us-east-1
+    role_arn: arn:aws:iam::123456789012:role/Reader
+    use_gauth: true
+  analytics:
+    type: postgres
+    host: db.example.com
+    port: 5432
+settings:
+  max_rows: 250
+`
+
+func TestLoadMigratesVersionlessConfig(t *testing.T) {
+	testutil.WriteConfigFile(t, versionlessConfig)
+
+	cfg, err := Load()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, CurrentConfigVersion, cfg.Version)
+
+	// use_gauth: true is carried over as mfa_mode: gauth
+	warehouse, _ := cfg.GetConnection("warehouse")
+	testutil.AssertEqual(t, "gauth", warehouse.MFAMode)
+	testutil.AssertEqual(t, false, warehouse.UseGauth)
+
+	// Explicit settings are kept and missing ones take their defaults
+	testutil.AssertEqual(t, 250, cfg.Settings.MaxRows)
+	testutil.AssertEqual(t, 30*time.Second, cfg.Settings.QueryTimeout)
+	testutil.AssertEqual(t, 30*time.Second, cfg.Settings.ConnectionPool.PingInterval)
+	testutil.AssertEqual(t, "stdio", cfg.Settings.Server.Transport)
+	testutil.AssertNoError(t, cfg.Validate())
+}
+
+func TestLoadDoesNotRewriteFile(t *testing.T) {
+	configPath := testutil.WriteConfigFile(t, versionlessConfig)
+
+	_, err := Load()
+	testutil.AssertNoError(t, err)
+	data, err := os.ReadFile(configPath)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, versionlessConfig, string(data))
+}
+
+func TestMigrateFile(t *testing.T) {
+	configPath := testutil.WriteConfigFile(t, versionlessConfig)
+
+	fromVersion, err := MigrateFile()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, fromVersion)
+
+	backup, err := os.ReadFile(configPath + ".v0.bak")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, versionlessConfig, string(backup))
+
+	data, err := os.ReadFile(configPath)
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, string(data), "version: 1")
+	testutil.AssertContains(t, string(data), "mfa_mode: gauth")
+
+	// A second run finds nothing to do
+	fromVersion, err = MigrateFile()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, CurrentConfigVersion, fromVersion)
+}
+
+func TestLoadWarnsOnNewerVersion(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+version: 99
+connections:
+  local:
+    type: mysql
+    host: localhost
+    port: 3306
+    shiny_new_setting: true
+`)
+	var logs bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&logs)
+	defer log.SetOutput(original)
+
+	cfg, err := Load()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 99, cfg.Version)
+	testutil.AssertEqual(t, 1, len(cfg.Connections))
+	testutil.AssertContains(t, logs.String(), "config file is version 99 but this build supports up to version 1")
+}
+
+func TestLoadRejectsInvalidVersion(t *testing.T) {
+	testutil.WriteConfigFile(t, "version: latest\n")
+
+	_, err := Load()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "version must be a non-negative whole number")
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultConnectionNamePattern allows letters, digits, '.', '_' and '-',
+// starting with a letter or digit
+const DefaultConnectionNamePattern = `^[A-Za-z0-9][A-Za-z0-9._-]*$`
+
+// MaxConnectionNameLength bounds connection names, which are embedded in
+// keychain keys and pool entry names
+const MaxConnectionNameLength = 64
+
+// ValidateConnectionName checks a name for a new connection. Names become
+// keychain keys of the form <name>:<user>, so ':' and whitespace are refused
+// outright; the rest is up to connection_name_pattern.
+func (c *Config) ValidateConnectionName(name string) error {
+	if name == "" {
+		return fmt.Errorf("connection name cannot be empty")
+	}
+	if len(name) > MaxConnectionNameLength {
+		return fmt.Errorf("connection name '%s' is longer than %d characters", name, MaxConnectionNameLength)
+	}
+	for _, r := range name {
+		switch {
+		case r == ':':
+			return fmt.Errorf("connection name '%s' cannot contain ':', which separates the name from the username in keychain entries", name)
+		case unicode.IsSpace(r) || unicode.IsControl(r):
+			return fmt.Errorf("connection name '%s' cannot contain whitespace or control characters", name)
+		}
+	}
+
+	pattern := c.Settings.ConnectionNamePattern
+	if pattern == "" {
+		pattern = DefaultConnectionNamePattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid connection_name_pattern: %w", err)
+	}
+	if !re.MatchString(name) {
+		return fmt.Errorf("connection name '%s' must match %s", name, pattern)
+	}
+	return nil
+}
+
+// ConnectionNameWarnings describes configured connections whose names new
+// connections could not use. They keep working, since renaming one would
+// orphan its stored credentials, but are reported so they can be renamed.
+func (c *Config) ConnectionNameWarnings() []string {
+	names := c.ListConnections()
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		if err := c.ValidateConnectionName(name); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%v; rename it (e.g. to '%s') and store its credentials again", err, SuggestConnectionName(name)))
+		}
+	}
+	return warnings
+}
+
+// SuggestConnectionName replaces each run of characters the default pattern
+// doesn't allow with '-'
+func SuggestConnectionName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	suggestion := strings.Trim(b.String(), "-._")
+	if len(suggestion) > MaxConnectionNameLength {
+		suggestion = suggestion[:MaxConnectionNameLength]
+	}
+	if suggestion == "" {
+		return "connection"
+	}
+	return suggestion
+}
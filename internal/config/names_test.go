@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestValidateConnectionName(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, name := range []string{"prod-db", "analytics_replica", "crm.eu", "db2", "A"} {
+		testutil.AssertNoError(t, cfg.ValidateConnectionName(name))
+	}
+
+	rejected := map[string]string{
+		"":             "cannot be empty",
+		"prod:db":      "cannot contain ':'",
+		"prod db":      "cannot contain whitespace",
+		"prod\tdb":     "cannot contain whitespace",
+		"-prod":        "must match",
+		"prod/db":      "must match",
+		"prod@replica": "must match",
+	}
+	for name, message := range rejected {
+		err := cfg.ValidateConnectionName(name)
+		testutil.AssertError(t, err)
+		testutil.AssertContains(t, err.Error(), message)
+	}
+
+	long := make([]byte, MaxConnectionNameLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	testutil.AssertError(t, cfg.ValidateConnectionName(string(long)))
+}
+
+func TestConnectionNamePatternSetting(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Settings.ConnectionNamePattern = `^[a-z]+-(dev|prod)$`
+	testutil.AssertNoError(t, cfg.ValidateConnectionName("crm-prod"))
+	testutil.AssertError(t, cfg.ValidateConnectionName("crm"))
+
+	// A pattern can't let separators back in
+	cfg.Settings.ConnectionNamePattern = `.*`
+	testutil.AssertError(t, cfg.ValidateConnectionName("crm:prod"))
+
+	cfg.Settings.ConnectionNamePattern = `[`
+	err := cfg.Validate()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "settings: connection_name_pattern is not a valid regular expression")
+}
+
+func TestConnectionNameWarnings(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Connections["good"] = Connection{Type: "mysql"}
+	cfg.Connections["old db:eu"] = Connection{Type: "mysql"}
+
+	warnings := cfg.ConnectionNameWarnings()
+	testutil.AssertEqual(t, 1, len(warnings))
+	testutil.AssertContains(t, warnings[0], "connection name 'old db:eu'")
+	testutil.AssertContains(t, warnings[0], "e.g. to 'old-db-eu'")
+}
+
+func TestAddConnectionRejectsBadName(t *testing.T) {
+	t.Setenv("HOME", testutil.TempDir(t))
+	cfg := DefaultConfig()
+
+	err := cfg.AddConnection("bad name", Connection{Type: "mysql", Host: "localhost", Port: 3306})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "cannot contain whitespace")
+	testutil.AssertNoError(t, cfg.AddConnection("good-name", Connection{Type: "mysql", Host: "localhost", Port: 3306}))
+
+	// Existing connections can still be updated under their old names
+	cfg.Connections["legacy name"] = Connection{Type: "mysql"}
+	testutil.AssertNoError(t, cfg.AddConnection("legacy name", Connection{Type: "mysql", Host: "db"}))
+}
+
+func TestSuggestConnectionName(t *testing.T) {
+	testutil.AssertEqual(t, "prod-db", SuggestConnectionName("prod db"))
+	testutil.AssertEqual(t, "crm-eu", SuggestConnectionName("crm: eu"))
+	testutil.AssertEqual(t, "connection", SuggestConnectionName("::"))
+}
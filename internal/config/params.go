@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// dsnParamNamePattern matches driver parameter names
+var dsnParamNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// reservedDSNParams are parameters the DSN already sets from other connection
+// fields or for safe mode, which params can't override
+var reservedDSNParams = map[string][]string{
+	"mysql":    {"parseTime", "loc", "charset", "allowNativePasswords", "transaction_read_only", "tx_read_only"},
+	"postgres": {"host", "port", "dbname", "user", "password", "sslmode", "default_transaction_read_only"},
+}
+
+// readOnlySettings are the session settings safe mode uses to open read-only
+// sessions. Params can reach them other ways than by name: Postgres' options
+// runs "-c default_transaction_read_only=off" at startup, and MySQL sends a
+// value like "100,transaction_read_only=0" verbatim in a SET statement.
+var readOnlySettings = []string{"transaction_read_only", "tx_read_only"}
+
+// setsReadOnly reports whether a param name or value mentions a read-only
+// setting, ignoring case, backslash escapes and dashes written for
+// underscores, as Postgres accepts them in options
+func setsReadOnly(text string) bool {
+	normalized := strings.NewReplacer(`\`, "", "-", "_").Replace(strings.ToLower(text))
+	for _, setting := range readOnlySettings {
+		if strings.Contains(normalized, setting) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDSNParams checks a connection's params for the connection's type
+func validateDSNParams(name string, conn Connection) []error {
+	if len(conn.Params) == 0 {
+		return nil
+	}
+	reserved, ok := reservedDSNParams[conn.Type]
+	if !ok {
+		return []error{fmt.Errorf("connection '%s': params are only supported for mysql and postgres", name)}
+	}
+
+	keys := make([]string, 0, len(conn.Params))
+	for key := range conn.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []error
+	for _, key := range keys {
+		if !dsnParamNamePattern.MatchString(key) {
+			problems = append(problems, fmt.Errorf("connection '%s': params: '%s' is not a valid parameter name", name, key))
+			continue
+		}
+		if setsReadOnly(key) || setsReadOnly(conn.Params[key]) {
+			problems = append(problems, fmt.Errorf("connection '%s': params: '%s' changes whether sessions are read-only, which safe mode controls", name, key))
+			continue
+		}
+		for _, r := range reserved {
+			if key == r {
+				problems = append(problems, fmt.Errorf("connection '%s': params: '%s' is set from the connection's other fields and cannot be overridden", name, key))
+				break
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestValidateDSNParams(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Connections["mysql"] = Connection{Type: "mysql", Host: "localhost", Port: 3306, Params: map[string]string{"readTimeout": "30s", "timeout": "5s"}}
+	cfg.Connections["postgres"] = Connection{Type: "postgres", Host: "localhost", Port: 5432, Params: map[string]string{"connect_timeout": "10", "application_name": "reports", "options": "-c search_path=reporting"}}
+	testutil.AssertNoError(t, cfg.Validate())
+
+	cfg.Connections["reserved"] = Connection{Type: "postgres", Host: "localhost", Port: 5432, Params: map[string]string{"sslmode": "disable"}}
+	cfg.Connections["read-write"] = Connection{Type: "mysql", Host: "localhost", Port: 3306, Params: map[string]string{"transaction_read_only": "0"}}
+	cfg.Connections["bad-name"] = Connection{Type: "mysql", Host: "localhost", Port: 3306, Params: map[string]string{"read timeout": "30s"}}
+	cfg.Connections["glue-params"] = Connection{Type: "glue", Region: "us-east-1", RoleArn: "arn:aws:iam::1:role/r", Params: map[string]string{"timeout": "5s"}}
+	err := cfg.Validate()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "connection 'reserved': params: 'sslmode' is set from the connection's other fields")
+	testutil.AssertContains(t, err.Error(), "connection 'read-write': params: 'transaction_read_only'")
+	testutil.AssertContains(t, err.Error(), "connection 'bad-name': params: 'read timeout' is not a valid parameter name")
+	testutil.AssertContains(t, err.Error(), "connection 'glue-params': params are only supported for mysql and postgres")
+}
+
+func TestDSNParamsCannotTurnOffReadOnlySessions(t *testing.T) {
+	for name, params := range map[string]map[string]string{
+		"options":          {"options": "-c default_transaction_read_only=off"},
+		"options-dashes":   {"options": "--default-transaction-read-only=off"},
+		"options-escaped":  {"options": `-c default_transaction\_read_only=off`},
+		"mixed-case":       {"Default_Transaction_Read_Only": "off"},
+		"mysql-set-value":  {"wait_timeout": "100,transaction_read_only=0"},
+		"mysql-tx-setting": {"tx_read_only": "0"},
+	} {
+		connType := "postgres"
+		if name == "mysql-set-value" || name == "mysql-tx-setting" {
+			connType = "mysql"
+		}
+		cfg := DefaultConfig()
+		cfg.Connections["db"] = Connection{Type: connType, Host: "localhost", Port: 5432, Params: params}
+		err := cfg.Validate()
+		testutil.AssertError(t, err)
+		testutil.AssertContains(t, err.Error(), "changes whether sessions are read-only")
+	}
+}
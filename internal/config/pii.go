@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// PIIPattern is a named regular expression whose matches are redacted from
+// sampled values. Pattern may be left empty to use the built-in pattern of
+// the same name (see BuiltinPIIPatterns).
+type PIIPattern struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern,omitempty"`
+}
+
+// BuiltinPIIPatterns are the patterns a pii_patterns entry gets by name alone
+var BuiltinPIIPatterns = map[string]string{
+	"email":       `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	"credit_card": `\b(?:\d[ -]?){12,18}\d\b`,
+	"ssn":         `\b\d{3}-\d{2}-\d{4}\b`,
+}
+
+// MaxPIIPatterns bounds pii_patterns, since every string value sampled is
+// checked against all of them
+const MaxPIIPatterns = 16
+
+// piiNamePattern keeps names usable inside the [REDACTED:<name>] token
+var piiNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Expression returns the entry's regular expression, falling back to the
+// built-in pattern of its name
+func (p PIIPattern) Expression() string {
+	if p.Pattern != "" {
+		return p.Pattern
+	}
+	return BuiltinPIIPatterns[p.Name]
+}
+
+// validatePIIPatterns checks names and expressions of pii_patterns entries
+func validatePIIPatterns(patterns []PIIPattern) []error {
+	var problems []error
+	if len(patterns) > MaxPIIPatterns {
+		problems = append(problems, fmt.Errorf("settings: pii_patterns has %d entries; at most %d are allowed", len(patterns), MaxPIIPatterns))
+	}
+	seen := make(map[string]bool)
+	for i, p := range patterns {
+		if !piiNamePattern.MatchString(p.Name) {
+			problems = append(problems, fmt.Errorf("settings: pii_patterns[%d]: name '%s' must be letters, digits, '_' or '-'", i, p.Name))
+			continue
+		}
+		if seen[p.Name] {
+			problems = append(problems, fmt.Errorf("settings: pii_patterns: '%s' is listed twice", p.Name))
+		}
+		seen[p.Name] = true
+
+		expression := p.Expression()
+		if expression == "" {
+			problems = append(problems, fmt.Errorf("settings: pii_patterns: '%s' needs a pattern (built-in names are email, credit_card and ssn)", p.Name))
+			continue
+		}
+		if _, err := regexp.Compile(expression); err != nil {
+			problems = append(problems, fmt.Errorf("settings: pii_patterns: '%s' is not a valid regular expression: %w", p.Name, err))
+		}
+	}
+	return problems
+}
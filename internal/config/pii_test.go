@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestValidatePIIPatterns(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Settings.PIIPatterns = []PIIPattern{{Name: "email"}, {Name: "badge", Pattern: `B-\d{6}`}}
+	testutil.AssertNoError(t, cfg.Validate())
+
+	cfg.Settings.PIIPatterns = []PIIPattern{
+		{Name: "phone"},
+		{Name: "bad", Pattern: `(`},
+		{Name: "has space", Pattern: `x`},
+		{Name: "email"},
+		{Name: "email"},
+	}
+	err := cfg.Validate()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "'phone' needs a pattern")
+	testutil.AssertContains(t, err.Error(), "'bad' is not a valid regular expression")
+	testutil.AssertContains(t, err.Error(), "name 'has space' must be")
+	testutil.AssertContains(t, err.Error(), "'email' is listed twice")
+
+	cfg.Settings.PIIPatterns = make([]PIIPattern, MaxPIIPatterns+1)
+	for i := range cfg.Settings.PIIPatterns {
+		cfg.Settings.PIIPatterns[i] = PIIPattern{Name: string(rune('a' + i)), Pattern: "x"}
+	}
+	err = cfg.Validate()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "at most 16 are allowed")
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// ParseProxyURL parses a connection's proxy setting. Only SOCKS5 proxies are
+// supported, written as socks5://[user:password@]host:port; socks5h is accepted
+// as an alias since the proxy always resolves the database host name.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if u.Scheme != "socks5" && u.Scheme != "socks5h" {
+		return nil, fmt.Errorf("unsupported proxy scheme '%s' (expected socks5)", u.Scheme)
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil || host == "" || port == "" {
+		return nil, fmt.Errorf("proxy URL must include host and port")
+	}
+	return u, nil
+}
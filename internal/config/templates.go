@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// connectionTemplates are presets for managed database services. They only
+// set what the service fixes or requires; host, database and username always
+// come from the user.
+var connectionTemplates = map[string]Connection{
+	// Amazon RDS and Aurora. RDS Postgres enforces TLS by default since 15.
+	"rds-postgres":    {Type: "postgres", Port: 5432, SSLMode: "require"},
+	"rds-mysql":       {Type: "mysql", Port: 3306},
+	"aurora-postgres": {Type: "postgres", Port: 5432, SSLMode: "require"},
+	"aurora-mysql":    {Type: "mysql", Port: 3306},
+	// Google Cloud SQL, reached over its public IP with server certificates
+	"cloudsql-postgres": {Type: "postgres", Port: 5432, SSLMode: "require"},
+	"cloudsql-mysql":    {Type: "mysql", Port: 3306},
+	// Azure Database for PostgreSQL requires TLS
+	"azure-postgres": {Type: "postgres", Port: 5432, SSLMode: "require"},
+}
+
+// TemplateNames lists the connection templates in alphabetical order
+func TemplateNames() []string {
+	names := make([]string, 0, len(connectionTemplates))
+	for name := range connectionTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyTemplate fills every field conn leaves unset from the named template.
+// Values already set on conn win, the same rule the defaults block follows.
+func ApplyTemplate(name string, conn Connection) (Connection, error) {
+	template, ok := connectionTemplates[name]
+	if !ok {
+		return conn, fmt.Errorf("unknown connection template '%s' (available: %s)", name, strings.Join(TemplateNames(), ", "))
+	}
+	fillUnset(&conn, template)
+	return conn, nil
+}
+
+// DescribeTemplate summarizes what a template sets, e.g. "postgres, port 5432, ssl_mode require"
+func DescribeTemplate(name string) string {
+	template, ok := connectionTemplates[name]
+	if !ok {
+		return ""
+	}
+	parts := []string{template.Type}
+	if template.Port != 0 {
+		parts = append(parts, fmt.Sprintf("port %d", template.Port))
+	}
+	if template.SSLMode != "" {
+		parts = append(parts, "ssl_mode "+template.SSLMode)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fillUnset copies each field of from into the matching zero-valued field of
+// conn. Maps and pointers are copied too, so conn never shares them with from.
+func fillUnset(conn *Connection, from Connection) {
+	source := reflect.ValueOf(from)
+	target := reflect.ValueOf(conn).Elem()
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Field(i)
+		if field.IsZero() && !source.Field(i).IsZero() {
+			field.Set(cloneValue(source.Field(i)))
+		}
+	}
+}
+
+// cloneConnection copies a connection along with its maps and pointers
+func cloneConnection(conn Connection) Connection {
+	source := reflect.ValueOf(conn)
+	target := reflect.ValueOf(&conn).Elem()
+	for i := 0; i < target.NumField(); i++ {
+		target.Field(i).Set(cloneValue(source.Field(i)))
+	}
+	return conn
+}
+
+// cloneValue copies a map, pointer or slice one level deep; other values are
+// returned as they are
+func cloneValue(value reflect.Value) reflect.Value {
+	if value.IsZero() {
+		return value
+	}
+	switch value.Kind() {
+	case reflect.Map:
+		clone := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			clone.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return clone
+	case reflect.Ptr:
+		clone := reflect.New(value.Type().Elem())
+		clone.Elem().Set(value.Elem())
+		return clone
+	case reflect.Slice:
+		clone := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(clone, value)
+		return clone
+	}
+	return value
+}
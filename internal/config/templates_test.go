@@ -0,0 +1,49 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestApplyTemplateDefaults(t *testing.T) {
+	conn, err := ApplyTemplate("rds-postgres", Connection{Host: "db.abc.us-east-1.rds.amazonaws.com", Database: "app"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "postgres", conn.Type)
+	testutil.AssertEqual(t, 5432, conn.Port)
+	testutil.AssertEqual(t, "require", conn.SSLMode)
+	testutil.AssertEqual(t, "db.abc.us-east-1.rds.amazonaws.com", conn.Host)
+	testutil.AssertEqual(t, "app", conn.Database)
+
+	conn, err = ApplyTemplate("cloudsql-mysql", Connection{})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "mysql", conn.Type)
+	testutil.AssertEqual(t, 3306, conn.Port)
+}
+
+func TestApplyTemplateUserValuesWin(t *testing.T) {
+	conn, err := ApplyTemplate("rds-postgres", Connection{Port: 6432, SSLMode: "verify-full"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 6432, conn.Port)
+	testutil.AssertEqual(t, "verify-full", conn.SSLMode)
+	testutil.AssertEqual(t, "postgres", conn.Type)
+}
+
+func TestApplyTemplateUnknown(t *testing.T) {
+	_, err := ApplyTemplate("rds-oracle", Connection{})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "unknown connection template 'rds-oracle'")
+	testutil.AssertContains(t, err.Error(), "rds-postgres")
+}
+
+func TestTemplatesAreValid(t *testing.T) {
+	for _, name := range TemplateNames() {
+		conn, err := ApplyTemplate(name, Connection{Host: "db.example.com", Username: "app"})
+		testutil.AssertNoError(t, err)
+		cfg := DefaultConfig()
+		cfg.Connections[name] = conn
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Template %s produces an invalid connection: %v", name, err)
+		}
+	}
+}
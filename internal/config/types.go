@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SupportedTypes are the canonical connection types
+var SupportedTypes = []string{"mysql", "postgres", "salesforce", "glue"}
+
+// typeAliases map other common names for a connection type, lowercased, to
+// the canonical one
+var typeAliases = map[string]string{
+	"postgresql": "postgres",
+	"pg":         "postgres",
+	"pgsql":      "postgres",
+	"psql":       "postgres",
+	"mariadb":    "mysql",
+	"maria":      "mysql",
+	"sfdc":       "salesforce",
+	"sf":         "salesforce",
+	"aws-glue":   "glue",
+	"awsglue":    "glue",
+}
+
+// mariaDBTypes are the type names, lowercased, that mean a MariaDB server
+var mariaDBTypes = map[string]bool{"mariadb": true, "maria": true}
+
+// NormalizeType returns the canonical name of a connection type, accepting
+// common aliases in any case. "" stays "" for Validate to report.
+func NormalizeType(connType string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(connType))
+	if normalized == "" {
+		return "", nil
+	}
+	if canonical, ok := typeAliases[normalized]; ok {
+		return canonical, nil
+	}
+	for _, supported := range SupportedTypes {
+		if normalized == supported {
+			return normalized, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported type '%s' (supported types: %s)", connType, strings.Join(SupportedTypes, ", "))
+}
+
+// normalizeTypes rewrites the type of the defaults block and of every
+// connection to its canonical name, returning one error per unknown type.
+// A MariaDB type becomes mysql with the mariadb flavor.
+func (c *Config) normalizeTypes() error {
+	names := c.ListConnections()
+	sort.Strings(names)
+
+	var problems []error
+	if connType, err := NormalizeType(c.Defaults.Type); err != nil {
+		problems = append(problems, fmt.Errorf("defaults: %w", err))
+	} else {
+		c.Defaults.Type = connType
+	}
+	for _, name := range names {
+		conn := c.Connections[name]
+		connType, err := NormalizeType(conn.Type)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("connection '%s': %w", name, err))
+			continue
+		}
+		if mariaDBTypes[strings.ToLower(strings.TrimSpace(conn.Type))] && conn.Flavor == "" {
+			conn.Flavor = FlavorMariaDB
+		}
+		conn.Type = connType
+		c.Connections[name] = conn
+	}
+	return errors.Join(problems...)
+}
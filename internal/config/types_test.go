@@ -0,0 +1,88 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestNormalizeType(t *testing.T) {
+	normalized := map[string]string{
+		"mysql":      "mysql",
+		"postgres":   "postgres",
+		"PostgreSQL": "postgres",
+		" psql ":     "postgres",
+		"pg":         "postgres",
+		"MariaDB":    "mysql",
+		"maria":      "mysql",
+		"sfdc":       "salesforce",
+		"aws-glue":   "glue",
+		"":           "",
+	}
+	for connType, want := range normalized {
+		got, err := NormalizeType(connType)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, want, got)
+	}
+
+	for _, connType := range []string{"mssqlserver", "oracle", "postgress"} {
+		_, err := NormalizeType(connType)
+		testutil.AssertError(t, err)
+		testutil.AssertContains(t, err.Error(), "unsupported type '"+connType+"' (supported types: mysql, postgres, salesforce, glue)")
+	}
+}
+
+func TestLoadNormalizesTypes(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+defaults:
+  type: PostgreSQL
+connections:
+  warehouse:
+    host: localhost
+    port: 5432
+  orders-db:
+    type: mariadb
+    host: localhost
+    port: 3306
+  crm:
+    type: sfdc
+    host: https://example.my.salesforce.com
+`)
+	cfg, err := Load()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "postgres", cfg.Connections["warehouse"].Type)
+	testutil.AssertEqual(t, "mysql", cfg.Connections["orders-db"].Type)
+	testutil.AssertEqual(t, "salesforce", cfg.Connections["crm"].Type)
+	testutil.AssertNoError(t, cfg.Validate())
+
+	// MariaDB names some session settings differently, so the alias is kept as a flavor
+	testutil.AssertEqual(t, FlavorMariaDB, cfg.Connections["orders-db"].Flavor)
+	testutil.AssertEqual(t, "", cfg.Connections["warehouse"].Flavor)
+
+	warehouse := cfg.Connections["warehouse"]
+	warehouse.Flavor = FlavorMariaDB
+	cfg.Connections["warehouse"] = warehouse
+	testutil.AssertError(t, cfg.Validate())
+}
+
+func TestLoadRejectsUnknownTypes(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  legacy:
+    type: mssqlserver
+    host: localhost
+    port: 1433
+  warehouse:
+    type: postgresql
+    host: localhost
+    port: 5432
+  reports:
+    type: oracle
+    host: localhost
+    port: 1521
+`)
+	_, err := Load()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "connection 'legacy': unsupported type 'mssqlserver' (supported types: mysql, postgres, salesforce, glue)")
+	testutil.AssertContains(t, err.Error(), "connection 'reports': unsupported type 'oracle'")
+}
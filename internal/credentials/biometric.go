@@ -0,0 +1,57 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// authReason is shown in the system authentication dialog
+const authReason = "SimpleDB MCP needs to access your database credentials"
+
+// ErrBiometricUnavailable is returned when biometrics are required but neither
+// biometric hardware nor the system password prompt can be used.
+var ErrBiometricUnavailable = errors.New("biometric authentication is unavailable on this device")
+
+// BiometricAuthenticator prompts the user to approve access to stored credentials.
+// It returns an error, rather than false, when the device can't prompt at all.
+type BiometricAuthenticator interface {
+	Authenticate(reason string) (bool, error)
+}
+
+// PlatformAuthenticators returns this platform's biometric prompt and the
+// system password prompt it falls back to; both are nil where there are none
+func PlatformAuthenticators() (biometric, passcode BiometricAuthenticator) {
+	return platformAuthenticators()
+}
+
+// authenticate gates keychain reads on the RequireBiometric setting. When the
+// device has no usable biometrics (no Touch ID, none enrolled, or disabled) it
+// falls back to the system password prompt instead of locking the user out.
+func (m *Manager) authenticate() error {
+	if !m.requireBiometric || m.biometric == nil {
+		return nil
+	}
+
+	authenticated, err := m.biometric.Authenticate(authReason)
+	if err == nil {
+		if !authenticated {
+			return fmt.Errorf("biometric authentication was cancelled or failed")
+		}
+		return nil
+	}
+
+	if m.passcode != nil {
+		log.Printf("Biometric authentication unavailable (%v), falling back to system password prompt", err)
+		authenticated, passcodeErr := m.passcode.Authenticate(authReason)
+		if passcodeErr == nil {
+			if !authenticated {
+				return fmt.Errorf("password authentication was cancelled or failed")
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w (%v); to read credentials without it, set 'require_biometric: false' under settings in ~/.config/simpledb-mcp/config.yaml",
+		ErrBiometricUnavailable, err)
+}
@@ -4,31 +4,19 @@
 package credentials
 
 import (
-	"fmt"
-
 	"github.com/ansxuman/go-touchid"
-	"github.com/zalando/go-keyring"
 )
 
-func (m *Manager) getMacOSWithBiometric(key string) (string, error) {
-	authenticated, err := touchid.Auth(touchid.DeviceTypeBiometrics, "SimpleDB MCP needs to access your database credentials")
-	if err != nil {
-		return "", fmt.Errorf("biometric authentication failed: %w", err)
-	}
-
-	if !authenticated {
-		return "", fmt.Errorf("biometric authentication was cancelled or failed")
-	}
-
-	password, err := keyring.Get(ServiceName, key)
-	if err != nil {
-		return "", fmt.Errorf("failed to retrieve password from keychain: %w", err)
-	}
+// touchIDAuthenticator prompts through LocalAuthentication. Auth returns an
+// error, rather than false, when the device can't evaluate the policy.
+type touchIDAuthenticator struct {
+	deviceType touchid.DeviceType
+}
 
-	return password, nil
+func (a touchIDAuthenticator) Authenticate(reason string) (bool, error) {
+	return touchid.Auth(a.deviceType, reason)
 }
 
-func (m *Manager) getWindowsWithBiometric(key string) (string, error) {
-	// Not supported on macOS
-	return keyring.Get(ServiceName, key)
+func platformAuthenticators() (biometric, passcode BiometricAuthenticator) {
+	return touchIDAuthenticator{touchid.DeviceTypeBiometrics}, touchIDAuthenticator{touchid.DeviceTypeAny}
 }
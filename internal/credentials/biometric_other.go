@@ -2,16 +2,7 @@
 
 package credentials
 
-import (
-	"github.com/zalando/go-keyring"
-)
-
-func (m *Manager) getMacOSWithBiometric(key string) (string, error) {
-	// Not supported on this platform
-	return keyring.Get(ServiceName, key)
+func platformAuthenticators() (biometric, passcode BiometricAuthenticator) {
+	// No biometric prompt on this platform
+	return nil, nil
 }
-
-func (m *Manager) getWindowsWithBiometric(key string) (string, error) {
-	// Not supported on this platform
-	return keyring.Get(ServiceName, key)
-}
\ No newline at end of file
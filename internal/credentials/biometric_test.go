@@ -0,0 +1,170 @@
+package credentials
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errNoBiometrics = errors.New("error occurred accessing biometrics")
+
+func newFakeManager(biometric, passcode *FakeAuthenticator) (*Manager, *FakeKeyStore) {
+	// Avoid storing typed nil pointers in the interfaces
+	var primary, fallback BiometricAuthenticator
+	if biometric != nil {
+		primary = biometric
+	}
+	if passcode != nil {
+		fallback = passcode
+	}
+	store := NewFakeKeyStore()
+	return NewManagerWithBackends(5*time.Minute, primary, fallback, store), store
+}
+
+func TestAuthenticateWithBiometrics(t *testing.T) {
+	biometric := &FakeAuthenticator{Authenticated: true}
+	passcode := &FakeAuthenticator{Authenticated: true}
+	manager, _ := newFakeManager(biometric, passcode)
+
+	if err := manager.authenticate(); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	assertEqual(t, 1, biometric.Calls())
+	assertEqual(t, 0, passcode.Calls())
+}
+
+func TestAuthenticateBiometricDenied(t *testing.T) {
+	biometric := &FakeAuthenticator{Authenticated: false}
+	passcode := &FakeAuthenticator{Authenticated: true}
+	manager, _ := newFakeManager(biometric, passcode)
+
+	// A denial is not a missing capability, so there's no password fallback
+	err := manager.authenticate()
+	assertError(t, err)
+	assertEqual(t, 0, passcode.Calls())
+}
+
+func TestAuthenticateFallsBackToPassword(t *testing.T) {
+	biometric := &FakeAuthenticator{Err: errNoBiometrics}
+	passcode := &FakeAuthenticator{Authenticated: true}
+	manager, _ := newFakeManager(biometric, passcode)
+
+	if err := manager.authenticate(); err != nil {
+		t.Fatalf("Expected password fallback to succeed, got %v", err)
+	}
+	assertEqual(t, 1, passcode.Calls())
+}
+
+func TestAuthenticateFallbackDenied(t *testing.T) {
+	biometric := &FakeAuthenticator{Err: errNoBiometrics}
+	passcode := &FakeAuthenticator{Authenticated: false}
+	manager, _ := newFakeManager(biometric, passcode)
+
+	err := manager.authenticate()
+	assertError(t, err)
+	if errors.Is(err, ErrBiometricUnavailable) {
+		t.Error("A cancelled password prompt should not be reported as unavailable biometrics")
+	}
+}
+
+func TestAuthenticateUnavailableGivesActionableError(t *testing.T) {
+	biometric := &FakeAuthenticator{Err: errNoBiometrics}
+	passcode := &FakeAuthenticator{Err: errors.New("no passcode set")}
+	manager, _ := newFakeManager(biometric, passcode)
+
+	err := manager.authenticate()
+	assertError(t, err)
+	if !errors.Is(err, ErrBiometricUnavailable) {
+		t.Errorf("Expected ErrBiometricUnavailable, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "require_biometric: false") {
+		t.Errorf("Expected error to explain how to disable the requirement, got %v", err)
+	}
+}
+
+func TestAuthenticateNotRequired(t *testing.T) {
+	biometric := &FakeAuthenticator{Err: errNoBiometrics}
+	manager, _ := newFakeManager(biometric, nil)
+	manager.SetRequireBiometric(false)
+
+	if err := manager.authenticate(); err != nil {
+		t.Fatalf("Expected no prompt when biometrics aren't required, got %v", err)
+	}
+	assertEqual(t, 0, biometric.Calls())
+}
+
+func TestGetPromptsOnceThenUsesCache(t *testing.T) {
+	biometric := &FakeAuthenticator{Authenticated: true}
+	manager, store := newFakeManager(biometric, nil)
+	store.Set("prod:admin", "s3cret")
+
+	for i := 0; i < 3; i++ {
+		cred, err := manager.Get("prod", "admin")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		assertEqual(t, "s3cret", cred.Password)
+	}
+
+	// Only the first read hits the keychain and prompts; the rest come from the cache
+	assertEqual(t, 1, biometric.Calls())
+	assertEqual(t, 1, store.Gets())
+
+	// Once the cache is cleared the user is prompted again
+	manager.ClearCache()
+	_, err := manager.Get("prod", "admin")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	assertEqual(t, 2, biometric.Calls())
+}
+
+func TestGetBiometricDenied(t *testing.T) {
+	biometric := &FakeAuthenticator{Authenticated: false}
+	manager, store := newFakeManager(biometric, nil)
+	store.Set("prod:admin", "s3cret")
+
+	_, err := manager.Get("prod", "admin")
+	assertError(t, err)
+
+	// The keychain is never read and nothing is cached
+	assertEqual(t, 0, store.Gets())
+	assertEqual(t, 0, len(manager.cache))
+}
+
+func TestGetKeyStoreMiss(t *testing.T) {
+	biometric := &FakeAuthenticator{Authenticated: true}
+	manager, _ := newFakeManager(biometric, nil)
+
+	_, err := manager.Get("prod", "nobody")
+	assertError(t, err)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStoreAndDeleteUseKeyStore(t *testing.T) {
+	manager, store := newFakeManager(nil, nil)
+
+	if err := manager.Store("prod", "admin", "s3cret"); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	secret, _ := store.Get("prod:admin")
+	assertEqual(t, "s3cret", secret)
+
+	if err := manager.StoreSalesforce("sf", "user@example.com", "pw", "token"); err != nil {
+		t.Fatalf("StoreSalesforce failed: %v", err)
+	}
+	sfCred, err := manager.GetSalesforce("sf")
+	if err != nil {
+		t.Fatalf("GetSalesforce failed: %v", err)
+	}
+	assertEqual(t, "token", sfCred.SecurityToken)
+
+	if err := manager.Delete("prod", "admin"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	_, err = store.Get("prod:admin")
+	assertError(t, err)
+}
@@ -4,12 +4,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"runtime"
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/zalando/go-keyring"
 )
 
 const (
@@ -20,6 +17,13 @@ type Manager struct {
 	cache      map[string]cachedCredential
 	cacheMutex sync.RWMutex
 	cacheTime  time.Duration
+
+	// Biometric prompt before keychain reads, with the system password prompt as fallback
+	requireBiometric bool
+	biometric        BiometricAuthenticator
+	passcode         BiometricAuthenticator
+
+	keyStore KeyStore
 }
 
 type cachedCredential struct {
@@ -39,16 +43,32 @@ type SalesforceCredential struct {
 }
 
 func NewManager(cacheTime time.Duration) *Manager {
+	biometric, passcode := platformAuthenticators()
+	return NewManagerWithBackends(cacheTime, biometric, passcode, NewKeyringStore(ServiceName))
+}
+
+// NewManagerWithBackends creates a manager with explicit authentication and
+// storage backends. biometric and fallback may be nil to skip prompting.
+func NewManagerWithBackends(cacheTime time.Duration, biometric, fallback BiometricAuthenticator, store KeyStore) *Manager {
 	return &Manager{
-		cache:     make(map[string]cachedCredential),
-		cacheTime: cacheTime,
+		cache:            make(map[string]cachedCredential),
+		cacheTime:        cacheTime,
+		requireBiometric: true,
+		biometric:        biometric,
+		passcode:         fallback,
+		keyStore:         store,
 	}
 }
 
+// SetRequireBiometric controls whether keychain reads prompt for biometrics first
+func (m *Manager) SetRequireBiometric(require bool) {
+	m.requireBiometric = require
+}
+
 func (m *Manager) Store(connectionName, username, password string) error {
 	key := fmt.Sprintf("%s:%s", connectionName, username)
 
-	if err := keyring.Set(ServiceName, key, password); err != nil {
+	if err := m.keyStore.Set(key, password); err != nil {
 		return fmt.Errorf("failed to store credential in keychain: %w", err)
 	}
 
@@ -77,7 +97,7 @@ func (m *Manager) StoreSalesforce(connectionName, username, password, securityTo
 	}
 	
 	key := fmt.Sprintf("%s:salesforce", connectionName)
-	if err := keyring.Set(ServiceName, key, string(credJSON)); err != nil {
+	if err := m.keyStore.Set(key, string(credJSON)); err != nil {
 		return fmt.Errorf("failed to store Salesforce credential in keychain: %w", err)
 	}
 
@@ -153,7 +173,7 @@ func (m *Manager) Get(connectionName, username string) (*Credential, error) {
 func (m *Manager) Delete(connectionName, username string) error {
 	key := fmt.Sprintf("%s:%s", connectionName, username)
 
-	if err := keyring.Delete(ServiceName, key); err != nil {
+	if err := m.keyStore.Delete(key); err != nil {
 		return fmt.Errorf("failed to delete credential from keychain: %w", err)
 	}
 
@@ -165,16 +185,18 @@ func (m *Manager) Delete(connectionName, username string) error {
 	return nil
 }
 
+// getWithBiometric reads a secret from the keystore after the platform's
+// authentication prompt, if any (Touch ID on macOS; none elsewhere yet).
 func (m *Manager) getWithBiometric(key string) (string, error) {
-	switch runtime.GOOS {
-	case "darwin":
-		return m.getMacOSWithBiometric(key)
-	case "windows":
-		return m.getWindowsWithBiometric(key)
-	default:
-		// Fallback to regular keyring for Linux/other systems
-		return keyring.Get(ServiceName, key)
+	if err := m.authenticate(); err != nil {
+		return "", err
+	}
+
+	secret, err := m.keyStore.Get(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve password from keychain: %w", err)
 	}
+	return secret, nil
 }
 
 func (m *Manager) ClearCache() {
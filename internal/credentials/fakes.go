@@ -0,0 +1,72 @@
+package credentials
+
+import "sync"
+
+// FakeAuthenticator is a BiometricAuthenticator for tests that returns a fixed outcome
+type FakeAuthenticator struct {
+	Authenticated bool
+	Err           error
+
+	mutex sync.Mutex
+	calls int
+}
+
+func (f *FakeAuthenticator) Authenticate(reason string) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.calls++
+	return f.Authenticated, f.Err
+}
+
+// Calls returns how many times the user was prompted
+func (f *FakeAuthenticator) Calls() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.calls
+}
+
+// FakeKeyStore is an in-memory KeyStore for tests
+type FakeKeyStore struct {
+	mutex   sync.Mutex
+	secrets map[string]string
+	gets    int
+}
+
+func NewFakeKeyStore() *FakeKeyStore {
+	return &FakeKeyStore{secrets: make(map[string]string)}
+}
+
+func (f *FakeKeyStore) Get(key string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.gets++
+	secret, exists := f.secrets[key]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (f *FakeKeyStore) Set(key, secret string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.secrets[key] = secret
+	return nil
+}
+
+func (f *FakeKeyStore) Delete(key string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, exists := f.secrets[key]; !exists {
+		return ErrNotFound
+	}
+	delete(f.secrets, key)
+	return nil
+}
+
+// Gets returns how many times the store was read
+func (f *FakeKeyStore) Gets() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.gets
+}
@@ -0,0 +1,176 @@
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrKeychainUnavailable is returned by every credential call when the system
+// keychain can't be reached (e.g. headless Linux without a D-Bus secret
+// service) and no fallback backend is configured
+var ErrKeychainUnavailable = errors.New("system keychain is unavailable")
+
+// CredentialEnvPrefix starts the environment variables the file backend reads
+// secrets from, e.g. SIMPLEDB_MCP_CREDENTIAL_PROD_READONLY for prod:readonly
+const CredentialEnvPrefix = "SIMPLEDB_MCP_CREDENTIAL_"
+
+// probeKey is looked up to check the keychain answers; it is never stored
+const probeKey = "simpledb-mcp-keychain-probe"
+
+// NewProvider creates a manager backed by the system keychain, checking up
+// front that the keychain can be reached. When it can't, and fallbackFile is
+// set, it logs a warning and keeps credentials in environment variables and
+// that file instead. The fallback is opt-in because those are weaker than
+// the keychain; without it every credential call fails with
+// ErrKeychainUnavailable.
+func NewProvider(cacheTime time.Duration, fallbackFile string) *Manager {
+	biometric, passcode := platformAuthenticators()
+	store := selectKeyStore(NewKeyringStore(ServiceName), fallbackFile)
+	return NewManagerWithBackends(cacheTime, biometric, passcode, store)
+}
+
+// selectKeyStore returns keychain if it answers, otherwise the file backend
+// when fallbackFile is set, otherwise a store that explains the failure
+func selectKeyStore(keychain KeyStore, fallbackFile string) KeyStore {
+	err := ProbeKeyStore(keychain)
+	if err == nil {
+		return keychain
+	}
+	if fallbackFile == "" {
+		log.Printf("Warning: %v (%v); set 'credential_fallback: file' under settings to use environment variables and a credentials file instead", ErrKeychainUnavailable, err)
+		return unavailableStore{err: err}
+	}
+	log.Printf("Warning: %v (%v); falling back to %s* environment variables and %s", ErrKeychainUnavailable, err, CredentialEnvPrefix, fallbackFile)
+	return NewFileKeyStore(fallbackFile)
+}
+
+// ProbeKeyStore reports whether the store can be read; a missing key is fine
+func ProbeKeyStore(store KeyStore) error {
+	_, err := store.Get(probeKey)
+	if err == nil || errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// unavailableStore fails every call with ErrKeychainUnavailable and the
+// keychain's own error, instead of the keychain's cryptic error alone
+type unavailableStore struct {
+	err error
+}
+
+func (s unavailableStore) failure() error {
+	return fmt.Errorf("%w (%v); set 'credential_fallback: file' under settings in ~/.config/simpledb-mcp/config.yaml to use environment variables and a credentials file instead",
+		ErrKeychainUnavailable, s.err)
+}
+
+func (s unavailableStore) Get(key string) (string, error) { return "", s.failure() }
+func (s unavailableStore) Set(key, secret string) error   { return s.failure() }
+func (s unavailableStore) Delete(key string) error        { return s.failure() }
+
+// fileKeyStore reads secrets from environment variables first and then from
+// a JSON file of key to secret, written with owner-only permissions
+type fileKeyStore struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// NewFileKeyStore returns a KeyStore that uses environment variables and the
+// JSON file at path
+func NewFileKeyStore(path string) KeyStore {
+	return &fileKeyStore{path: path}
+}
+
+// credentialEnvVar names the environment variable holding the secret for key,
+// upper-casing it and replacing anything but letters and digits with '_'
+func credentialEnvVar(key string) string {
+	return CredentialEnvPrefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+func (s *fileKeyStore) Get(key string) (string, error) {
+	if secret, ok := os.LookupEnv(credentialEnvVar(key)); ok {
+		return secret, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	secret, exists := secrets[key]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (s *fileKeyStore) Set(key, secret string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	secrets[key] = secret
+	return s.save(secrets)
+}
+
+func (s *fileKeyStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, exists := secrets[key]; !exists {
+		return ErrNotFound
+	}
+	delete(secrets, key)
+	return s.save(secrets)
+}
+
+// load reads the secrets file; a missing file holds no secrets
+func (s *fileKeyStore) load() (map[string]string, error) {
+	secrets := make(map[string]string)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", s.path, err)
+	}
+	return secrets, nil
+}
+
+func (s *fileKeyStore) save(secrets map[string]string) error {
+	data, err := json.MarshalIndent(secrets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
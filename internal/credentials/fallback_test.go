@@ -0,0 +1,86 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errNoDBus = errors.New("The name org.freedesktop.secrets was not provided by any .service files")
+
+// brokenKeyStore simulates a keychain that can't be reached at all
+type brokenKeyStore struct{}
+
+func (brokenKeyStore) Get(key string) (string, error) { return "", errNoDBus }
+func (brokenKeyStore) Set(key, secret string) error   { return errNoDBus }
+func (brokenKeyStore) Delete(key string) error        { return errNoDBus }
+
+func TestSelectKeyStoreKeepsWorkingKeychain(t *testing.T) {
+	keychain := NewFakeKeyStore()
+	store := selectKeyStore(keychain, filepath.Join(t.TempDir(), "credentials.json"))
+	assertEqual(t, KeyStore(keychain), store)
+}
+
+func TestSelectKeyStoreUnavailableWithoutFallback(t *testing.T) {
+	manager := NewManagerWithBackends(5*time.Minute, nil, nil, selectKeyStore(brokenKeyStore{}, ""))
+
+	_, err := manager.Get("prod", "admin")
+	if !errors.Is(err, ErrKeychainUnavailable) {
+		t.Fatalf("Expected ErrKeychainUnavailable, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "credential_fallback: file") {
+		t.Errorf("Expected the error to name the fallback setting, got %v", err)
+	}
+	if err := manager.Store("prod", "admin", "s3cret"); !errors.Is(err, ErrKeychainUnavailable) {
+		t.Errorf("Expected ErrKeychainUnavailable, got %v", err)
+	}
+}
+
+func TestSelectKeyStoreFallsBackToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "simpledb-mcp", "credentials.json")
+	manager := NewManagerWithBackends(5*time.Minute, nil, nil, selectKeyStore(brokenKeyStore{}, path))
+
+	if err := manager.Store("prod", "admin", "s3cret"); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected credentials file: %v", err)
+	}
+	assertEqual(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A fresh manager reads what the first one stored
+	manager = NewManagerWithBackends(5*time.Minute, nil, nil, selectKeyStore(brokenKeyStore{}, path))
+	cred, err := manager.Get("prod", "admin")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	assertEqual(t, "s3cret", cred.Password)
+
+	if err := manager.Delete("prod", "admin"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	_, err = manager.Get("prod", "admin")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFileKeyStorePrefersEnvironment(t *testing.T) {
+	store := NewFileKeyStore(filepath.Join(t.TempDir(), "credentials.json"))
+	if err := store.Set("prod-db:read.only", "from-file"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	assertEqual(t, "SIMPLEDB_MCP_CREDENTIAL_PROD_DB_READ_ONLY", credentialEnvVar("prod-db:read.only"))
+	t.Setenv("SIMPLEDB_MCP_CREDENTIAL_PROD_DB_READ_ONLY", "from-env")
+
+	secret, err := store.Get("prod-db:read.only")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	assertEqual(t, "from-env", secret)
+}
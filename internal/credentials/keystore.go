@@ -0,0 +1,37 @@
+package credentials
+
+import (
+	"github.com/zalando/go-keyring"
+)
+
+// ErrNotFound is returned by a KeyStore when no secret exists for a key
+var ErrNotFound = keyring.ErrNotFound
+
+// KeyStore stores secrets in the OS keychain (or a fake in tests)
+type KeyStore interface {
+	Get(key string) (string, error)
+	Set(key, secret string) error
+	Delete(key string) error
+}
+
+// keyringStore is the KeyStore backed by go-keyring under a single service name
+type keyringStore struct {
+	service string
+}
+
+// NewKeyringStore returns a KeyStore that uses the system keychain
+func NewKeyringStore(service string) KeyStore {
+	return keyringStore{service: service}
+}
+
+func (s keyringStore) Get(key string) (string, error) {
+	return keyring.Get(s.service, key)
+}
+
+func (s keyringStore) Set(key, secret string) error {
+	return keyring.Set(s.service, key, secret)
+}
+
+func (s keyringStore) Delete(key string) error {
+	return keyring.Delete(s.service, key)
+}
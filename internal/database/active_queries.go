@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// maxActiveQueryLength caps the query text returned for each session
+const maxActiveQueryLength = 2000
+
+// postgresHiddenQuery is what pg_stat_activity shows for other users'
+// sessions to a user without pg_read_all_stats
+const postgresHiddenQuery = "<insufficient privilege>"
+
+// ActiveQuery is a statement running on the server when it was listed
+type ActiveQuery struct {
+	PID             int64   `json:"pid"`
+	User            string  `json:"user,omitempty"`
+	Database        string  `json:"database,omitempty"`
+	State           string  `json:"state,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Query           string  `json:"query"`
+	Truncated       bool    `json:"truncated,omitempty"`
+}
+
+// ActiveQueriesReport is the result of listing running queries. A user who
+// may not read the process list gets an empty report with Available false
+// and the reason, rather than an error.
+type ActiveQueriesReport struct {
+	Available bool          `json:"available"`
+	Reason    string        `json:"reason,omitempty"`
+	Queries   []ActiveQuery `json:"queries"`
+}
+
+// The tool's own session and idle sessions are left out, longest running first
+const mysqlActiveQueriesQuery = `
+		SELECT ID, USER, DB, COALESCE(NULLIF(STATE, ''), COMMAND), TIME, INFO
+		FROM INFORMATION_SCHEMA.PROCESSLIST
+		WHERE ID <> CONNECTION_ID() AND COMMAND NOT IN ('Sleep', 'Daemon', 'Binlog Dump')`
+
+const postgresActiveQueriesQuery = `
+		SELECT pid, usename, datname, state, EXTRACT(EPOCH FROM (now() - query_start)), query
+		FROM pg_stat_activity
+		WHERE pid <> pg_backend_pid() AND state IS NOT NULL AND state <> 'idle'`
+
+// activeQueriesQuery builds the process list query of an engine, limited to
+// statements running at least minDuration
+func activeQueriesQuery(engine string, minDuration time.Duration, limit int) (string, []interface{}) {
+	var query string
+	var args []interface{}
+	switch engine {
+	case "mysql":
+		query = mysqlActiveQueriesQuery
+		if minDuration > 0 {
+			query += " AND TIME >= ?"
+			args = append(args, int64(minDuration.Seconds()))
+		}
+		query += "\n\t\tORDER BY TIME DESC LIMIT ?"
+	case "postgres":
+		query = postgresActiveQueriesQuery
+		if minDuration > 0 {
+			query += " AND query_start <= now() - make_interval(secs => $1)"
+			args = append(args, minDuration.Seconds())
+		}
+		query += fmt.Sprintf("\n\t\tORDER BY query_start LIMIT $%d", len(args)+1)
+	}
+	args = append(args, limit)
+	return query, args
+}
+
+// redactActiveQueries replaces the literals in WHERE clauses of each query
+// with ?, then caps the text at maxActiveQueryLength
+func redactActiveQueries(engine string, queries []ActiveQuery, redactLiterals bool) {
+	for i := range queries {
+		if redactLiterals && queries[i].Query != postgresHiddenQuery {
+			queries[i].Query = RedactWhereLiterals(engine, queries[i].Query)
+		}
+		if len(queries[i].Query) > maxActiveQueryLength {
+			cut := maxActiveQueryLength
+			for cut > 0 && !utf8.RuneStart(queries[i].Query[cut]) {
+				cut--
+			}
+			queries[i].Query = queries[i].Query[:cut]
+			queries[i].Truncated = true
+		}
+	}
+}
+
+// GetActiveQueries lists the statements running on a MySQL or PostgreSQL
+// server, from INFORMATION_SCHEMA.PROCESSLIST or pg_stat_activity. Users
+// without the PROCESS privilege (MySQL) or pg_read_all_stats (PostgreSQL)
+// only see their own sessions' queries.
+func (m *Manager) GetActiveQueries(ctx context.Context, connectionName string, minDuration time.Duration, limit int, redactLiterals bool) (*ActiveQueriesReport, error) {
+	engine := m.engineOf(connectionName)
+	if engine != "mysql" && engine != "postgres" {
+		return nil, fmt.Errorf("active queries are only available for MySQL and PostgreSQL connections")
+	}
+
+	// Always the primary: a replica's process list says nothing about the
+	// queries troubling the primary
+	db, err := m.pool.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	query, args := activeQueriesQuery(engine, minDuration, limit)
+	rows, err := m.query(ctx, db, connectionName, query, args...)
+	if err != nil {
+		if ClassifyFailure(err) == FailurePermission {
+			return &ActiveQueriesReport{Reason: fmt.Sprintf("the connection's user can't read the process list: %v", err), Queries: []ActiveQuery{}}, nil
+		}
+		return nil, fmt.Errorf("failed to list active queries: %w", err)
+	}
+	defer rows.Close()
+
+	queries := []ActiveQuery{}
+	hidden := 0
+	for rows.Next() {
+		var q ActiveQuery
+		var user, dbName, state, text sql.NullString
+		var duration sql.NullFloat64
+		if err := rows.Scan(&q.PID, &user, &dbName, &state, &duration, &text); err != nil {
+			return nil, fmt.Errorf("failed to scan active query: %w", err)
+		}
+		q.User, q.Database, q.State, q.Query = user.String, dbName.String, state.String, text.String
+		q.DurationSeconds = duration.Float64
+		if q.Query == postgresHiddenQuery {
+			hidden++
+		}
+		queries = append(queries, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list active queries: %w", err)
+	}
+
+	m.pool.recordQuery(connectionName, len(queries), time.Since(start))
+
+	redactActiveQueries(engine, queries, redactLiterals)
+	report := &ActiveQueriesReport{Available: true, Queries: queries}
+	if hidden > 0 {
+		report.Reason = fmt.Sprintf("%d queries belong to other users and are hidden; grant pg_read_all_stats to see them", hidden)
+	}
+	return report, nil
+}
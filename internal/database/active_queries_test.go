@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestActiveQueriesQuery(t *testing.T) {
+	query, args := activeQueriesQuery("mysql", 0, 50)
+	testutil.AssertContains(t, query, "FROM INFORMATION_SCHEMA.PROCESSLIST")
+	testutil.AssertContains(t, query, "ID <> CONNECTION_ID()")
+	testutil.AssertContains(t, query, "COMMAND NOT IN ('Sleep'")
+	testutil.AssertContains(t, query, "ORDER BY TIME DESC LIMIT ?")
+	testutil.AssertEqual(t, false, strings.Contains(query, "TIME >="))
+	testutil.AssertEqual(t, 1, len(args))
+	testutil.AssertEqual(t, 50, args[0])
+
+	query, args = activeQueriesQuery("mysql", 90*time.Second, 10)
+	testutil.AssertContains(t, query, "AND TIME >= ?\n\t\tORDER BY")
+	testutil.AssertEqual(t, 2, len(args))
+	testutil.AssertEqual(t, int64(90), args[0])
+	testutil.AssertEqual(t, 10, args[1])
+
+	query, args = activeQueriesQuery("postgres", 0, 50)
+	testutil.AssertContains(t, query, "FROM pg_stat_activity")
+	testutil.AssertContains(t, query, "pid <> pg_backend_pid()")
+	testutil.AssertContains(t, query, "ORDER BY query_start LIMIT $1")
+	testutil.AssertEqual(t, 1, len(args))
+
+	query, args = activeQueriesQuery("postgres", 1500*time.Millisecond, 50)
+	testutil.AssertContains(t, query, "make_interval(secs => $1)\n\t\tORDER BY query_start LIMIT $2")
+	testutil.AssertEqual(t, 1.5, args[0])
+}
+
+func TestRedactActiveQueries(t *testing.T) {
+	long := "SELECT * FROM events WHERE note = '" + strings.Repeat("é", maxActiveQueryLength) + "'"
+	queries := []ActiveQuery{
+		{Query: "SELECT * FROM users WHERE email = 'alice@example.com' AND age > 30"},
+		{Query: postgresHiddenQuery},
+		{Query: long},
+	}
+	redactActiveQueries("postgres", queries, true)
+	testutil.AssertEqual(t, "SELECT * FROM users WHERE email = ? AND age > ?", queries[0].Query)
+	testutil.AssertEqual(t, postgresHiddenQuery, queries[1].Query)
+	testutil.AssertEqual(t, "SELECT * FROM events WHERE note = ?", queries[2].Query)
+	testutil.AssertEqual(t, false, queries[2].Truncated)
+
+	// Without redaction long text is cut on a character boundary
+	queries = []ActiveQuery{{Query: long}}
+	redactActiveQueries("postgres", queries, false)
+	testutil.AssertEqual(t, true, queries[0].Truncated)
+	testutil.AssertEqual(t, true, len(queries[0].Query) <= maxActiveQueryLength)
+	testutil.AssertEqual(t, true, strings.HasSuffix(queries[0].Query, "é"))
+}
+
+func TestGetActiveQueriesMySQL(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	query, args := activeQueriesQuery("mysql", 0, 20)
+	db.SetQueryResultForArgs(query, args, []string{"ID", "USER", "DB", "STATE", "TIME", "INFO"}, [][]interface{}{
+		{int64(812), "report", "shop", "Sending data", int64(95), "SELECT * FROM orders WHERE customer_id = 42"},
+		{int64(15), "event_scheduler", nil, "Waiting on empty queue", int64(3), nil},
+	})
+
+	report, err := manager.GetActiveQueries(context.Background(), "test-mysql", 0, 20, true)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, report.Available)
+	testutil.AssertEqual(t, 2, len(report.Queries))
+	testutil.AssertEqual(t, int64(812), report.Queries[0].PID)
+	testutil.AssertEqual(t, "Sending data", report.Queries[0].State)
+	testutil.AssertEqual(t, 95.0, report.Queries[0].DurationSeconds)
+	testutil.AssertEqual(t, "SELECT * FROM orders WHERE customer_id = ?", report.Queries[0].Query)
+	testutil.AssertEqual(t, "", report.Queries[1].Database)
+	testutil.AssertEqual(t, "", report.Queries[1].Query)
+}
+
+func TestGetActiveQueriesWithoutPermission(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	db.SetQueryFails(true, &mysql.MySQLError{Number: 1227, Message: "Access denied; you need (at least one of) the PROCESS privilege(s) for this operation"})
+
+	report, err := manager.GetActiveQueries(context.Background(), "test-mysql", 0, 20, true)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, false, report.Available)
+	testutil.AssertEqual(t, 0, len(report.Queries))
+	testutil.AssertContains(t, report.Reason, "can't read the process list")
+}
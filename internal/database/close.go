@@ -0,0 +1,45 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CloseConnection frees what a connection holds open: its pooled database
+// handles, its read replica's included, and its cached Salesforce or Glue
+// clients. The configuration is left alone, so the next tool call simply
+// reconnects. It reports whether anything was open.
+func (m *Manager) CloseConnection(connectionName string) (bool, error) {
+	if _, exists := m.config.GetConnection(connectionName); !exists {
+		return false, fmt.Errorf("connection '%s' not found", connectionName)
+	}
+
+	closed := m.pool.CloseConnection(connectionName)
+	if m.pool.CloseConnection(replicaPoolKey(connectionName)) {
+		closed = true
+	}
+	if m.sfCache.client(connectionName) != nil {
+		m.sfCache.invalidate(connectionName)
+		closed = true
+	}
+	if m.glueCache.get(connectionName) != nil {
+		m.glueCache.invalidate(connectionName)
+		closed = true
+	}
+	return closed, nil
+}
+
+// CloseAllConnections closes every configured connection, returning the
+// names of those that had something open
+func (m *Manager) CloseAllConnections() []string {
+	names := m.config.ListConnections()
+	sort.Strings(names)
+
+	closed := []string{}
+	for _, name := range names {
+		if ok, _ := m.CloseConnection(name); ok {
+			closed = append(closed, name)
+		}
+	}
+	return closed
+}
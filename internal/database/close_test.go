@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestCloseConnectionKeepsConfig(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	addMockConnection(t, manager.pool, "test-postgres")
+	addMockConnection(t, manager.pool, replicaPoolKey("test-postgres"))
+
+	closed, err := manager.CloseConnection("test-postgres")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, closed)
+
+	manager.pool.mutex.RLock()
+	_, primary := manager.pool.connections["test-postgres"]
+	_, replica := manager.pool.connections[replicaPoolKey("test-postgres")]
+	manager.pool.mutex.RUnlock()
+	testutil.AssertEqual(t, false, primary)
+	testutil.AssertEqual(t, false, replica)
+
+	_, configured := manager.config.GetConnection("test-postgres")
+	testutil.AssertEqual(t, true, configured)
+
+	// Closing again finds nothing open
+	closed, err = manager.CloseConnection("test-postgres")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, false, closed)
+}
+
+func TestCloseConnectionUnknown(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	_, err := manager.CloseConnection("missing")
+	testutil.AssertError(t, err)
+}
+
+func TestCloseAllConnections(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	addMockConnection(t, manager.pool, "test-postgres")
+	addMockConnection(t, manager.pool, "test-mysql")
+
+	closed := manager.CloseAllConnections()
+	testutil.AssertEqual(t, 2, len(closed))
+	testutil.AssertEqual(t, "test-mysql", closed[0])
+	testutil.AssertEqual(t, "test-postgres", closed[1])
+
+	manager.pool.mutex.RLock()
+	open := len(manager.pool.connections)
+	manager.pool.mutex.RUnlock()
+	testutil.AssertEqual(t, 0, open)
+	testutil.AssertEqual(t, 0, len(manager.CloseAllConnections()))
+}
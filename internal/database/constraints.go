@@ -0,0 +1,209 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConstraintInfo describes the values a column is allowed to hold: either a
+// CHECK constraint expression or the labels of an ENUM (or MySQL SET) type.
+type ConstraintInfo struct {
+	Table      string   `json:"table"`
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"` // check, enum or set
+	Column     string   `json:"column,omitempty"`
+	Expression string   `json:"expression,omitempty"`
+	Values     []string `json:"values,omitempty"`
+}
+
+// An empty table argument matches every table in the schema
+const postgresCheckConstraintsQuery = `
+		SELECT cl.relname, con.conname, pg_get_constraintdef(con.oid)
+		FROM pg_constraint con
+		JOIN pg_class cl ON cl.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = cl.relnamespace
+		WHERE con.contype = 'c' AND n.nspname = $1 AND ($2 = '' OR cl.relname = $2)
+		ORDER BY cl.relname, con.conname`
+
+const postgresEnumValuesQuery = `
+		SELECT c.table_name, c.column_name, t.typname, e.enumlabel
+		FROM information_schema.columns c
+		JOIN pg_namespace tn ON tn.nspname = c.udt_schema
+		JOIN pg_type t ON t.typname = c.udt_name AND t.typnamespace = tn.oid
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		WHERE c.table_schema = $1 AND ($2 = '' OR c.table_name = $2)
+		ORDER BY c.table_name, c.ordinal_position, e.enumsortorder`
+
+const mysqlCheckConstraintsQuery = `
+		SELECT tc.TABLE_NAME, cc.CONSTRAINT_NAME, cc.CHECK_CLAUSE
+		FROM INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc
+		JOIN INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+			ON tc.CONSTRAINT_SCHEMA = cc.CONSTRAINT_SCHEMA
+			AND tc.CONSTRAINT_NAME = cc.CONSTRAINT_NAME
+		WHERE tc.CONSTRAINT_TYPE = 'CHECK' AND cc.CONSTRAINT_SCHEMA = ? AND (? = '' OR tc.TABLE_NAME = ?)
+		ORDER BY tc.TABLE_NAME, cc.CONSTRAINT_NAME`
+
+const mysqlEnumColumnsQuery = `
+		SELECT TABLE_NAME, COLUMN_NAME, DATA_TYPE, COLUMN_TYPE
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND (? = '' OR TABLE_NAME = ?) AND DATA_TYPE IN ('enum', 'set')
+		ORDER BY TABLE_NAME, ORDINAL_POSITION`
+
+// ListConstraintsPostgres returns the check constraints and enum-typed columns
+// of a schema, optionally limited to one table
+func (m *Manager) ListConstraintsPostgres(connectionName, database, schema, tableName string) ([]ConstraintInfo, error) {
+	if err := validateIdentifiers("postgres", database, schema, tableName); err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	if schema == "" {
+		schema = "public"
+	}
+
+	var constraints []ConstraintInfo
+
+	rows, err := m.query(context.Background(), db, connectionName, postgresCheckConstraintsQuery, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check constraints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		constraint := ConstraintInfo{Kind: "check"}
+		if err := rows.Scan(&constraint.Table, &constraint.Name, &constraint.Expression); err != nil {
+			return nil, fmt.Errorf("failed to scan check constraint: %w", err)
+		}
+		constraints = append(constraints, constraint)
+	}
+
+	enumRows, err := m.query(context.Background(), db, connectionName, postgresEnumValuesQuery, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list enum values: %w", err)
+	}
+	defer enumRows.Close()
+
+	// One row per label; consecutive rows for the same column are merged
+	for enumRows.Next() {
+		var table, column, typeName, label string
+		if err := enumRows.Scan(&table, &column, &typeName, &label); err != nil {
+			return nil, fmt.Errorf("failed to scan enum value: %w", err)
+		}
+		last := len(constraints) - 1
+		if last >= 0 && constraints[last].Kind == "enum" && constraints[last].Table == table && constraints[last].Column == column {
+			constraints[last].Values = append(constraints[last].Values, label)
+			continue
+		}
+		constraints = append(constraints, ConstraintInfo{
+			Table:  table,
+			Name:   typeName,
+			Kind:   "enum",
+			Column: column,
+			Values: []string{label},
+		})
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(constraints), time.Since(start))
+
+	return constraints, nil
+}
+
+// ListConstraintsMySQL returns the CHECK constraints (MySQL 8.0.16+) and
+// ENUM/SET columns of a database, optionally limited to one table
+func (m *Manager) ListConstraintsMySQL(connectionName, database, tableName string) ([]ConstraintInfo, error) {
+	if err := validateIdentifiers("mysql", database, tableName); err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	var constraints []ConstraintInfo
+
+	rows, err := m.query(context.Background(), db, connectionName, mysqlCheckConstraintsQuery, database, tableName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check constraints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		constraint := ConstraintInfo{Kind: "check"}
+		if err := rows.Scan(&constraint.Table, &constraint.Name, &constraint.Expression); err != nil {
+			return nil, fmt.Errorf("failed to scan check constraint: %w", err)
+		}
+		constraints = append(constraints, constraint)
+	}
+
+	enumRows, err := m.query(context.Background(), db, connectionName, mysqlEnumColumnsQuery, database, tableName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list enum columns: %w", err)
+	}
+	defer enumRows.Close()
+
+	for enumRows.Next() {
+		var table, column, dataType, columnType string
+		if err := enumRows.Scan(&table, &column, &dataType, &columnType); err != nil {
+			return nil, fmt.Errorf("failed to scan enum column: %w", err)
+		}
+		constraints = append(constraints, ConstraintInfo{
+			Table:  table,
+			Name:   column,
+			Kind:   strings.ToLower(dataType),
+			Column: column,
+			Values: parseMySQLEnumValues(columnType),
+		})
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(constraints), time.Since(start))
+
+	return constraints, nil
+}
+
+// parseMySQLEnumValues extracts the labels from a COLUMN_TYPE such as
+// enum('small','it”s big'). Quotes inside a label are doubled or backslash-escaped.
+func parseMySQLEnumValues(columnType string) []string {
+	open := strings.Index(columnType, "(")
+	close := strings.LastIndex(columnType, ")")
+	if open < 0 || close < open {
+		return nil
+	}
+	body := columnType[open+1 : close]
+
+	var values []string
+	var current strings.Builder
+	inQuote := false
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case !inQuote:
+			if c == '\'' {
+				inQuote = true
+				current.Reset()
+			}
+		case c == '\\' && i+1 < len(body):
+			i++
+			current.WriteByte(body[i])
+		case c == '\'' && i+1 < len(body) && body[i+1] == '\'':
+			i++
+			current.WriteByte('\'')
+		case c == '\'':
+			inQuote = false
+			values = append(values, current.String())
+		default:
+			current.WriteByte(c)
+		}
+	}
+	return values
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestParseMySQLEnumValues(t *testing.T) {
+	tests := []struct {
+		columnType string
+		expected   string
+	}{
+		{"enum('small','medium','large')", "small|medium|large"},
+		{"set('read','write')", "read|write"},
+		{"enum('it''s','a\\\\b','x,y')", "it's|a\\b|x,y"},
+		{"enum('')", ""},
+		{"varchar(20)", ""},
+	}
+
+	for _, tt := range tests {
+		values := parseMySQLEnumValues(tt.columnType)
+		testutil.AssertEqual(t, tt.expected, strings.Join(values, "|"))
+	}
+}
+
+func TestListConstraintsPostgres(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-postgres")
+	db.SetQueryResult(postgresCheckConstraintsQuery, []string{"relname", "conname", "def"}, [][]interface{}{
+		{"orders", "orders_qty_check", "CHECK ((quantity > 0))"},
+	})
+	db.SetQueryResult(postgresEnumValuesQuery, []string{"table_name", "column_name", "typname", "enumlabel"}, [][]interface{}{
+		{"orders", "status", "order_status", "pending"},
+		{"orders", "status", "order_status", "shipped"},
+		{"orders", "priority", "priority_level", "low"},
+		{"returns", "status", "order_status", "pending"},
+	})
+
+	constraints, err := manager.ListConstraintsPostgres("test-postgres", "testdb", "", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 4, len(constraints))
+
+	testutil.AssertEqual(t, "check", constraints[0].Kind)
+	testutil.AssertEqual(t, "orders_qty_check", constraints[0].Name)
+	testutil.AssertEqual(t, "CHECK ((quantity > 0))", constraints[0].Expression)
+
+	// Labels for the same column are grouped, in enum sort order
+	testutil.AssertEqual(t, "enum", constraints[1].Kind)
+	testutil.AssertEqual(t, "order_status", constraints[1].Name)
+	testutil.AssertEqual(t, "status", constraints[1].Column)
+	testutil.AssertEqual(t, "pending,shipped", strings.Join(constraints[1].Values, ","))
+	testutil.AssertEqual(t, "priority", constraints[2].Column)
+	testutil.AssertEqual(t, "returns", constraints[3].Table)
+	testutil.AssertEqual(t, "pending", strings.Join(constraints[3].Values, ","))
+}
+
+func TestListConstraintsMySQL(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	db.SetQueryResult(mysqlCheckConstraintsQuery, []string{"TABLE_NAME", "CONSTRAINT_NAME", "CHECK_CLAUSE"}, [][]interface{}{
+		{"orders", "qty_positive", "(`quantity` > 0)"},
+	})
+	db.SetQueryResult(mysqlEnumColumnsQuery, []string{"TABLE_NAME", "COLUMN_NAME", "DATA_TYPE", "COLUMN_TYPE"}, [][]interface{}{
+		{"orders", "status", "enum", "enum('pending','shipped')"},
+		{"users", "roles", "set", "set('admin','editor')"},
+	})
+
+	constraints, err := manager.ListConstraintsMySQL("test-mysql", "testdb", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, len(constraints))
+	testutil.AssertEqual(t, "(`quantity` > 0)", constraints[0].Expression)
+	testutil.AssertEqual(t, "enum", constraints[1].Kind)
+	testutil.AssertEqual(t, "pending,shipped", strings.Join(constraints[1].Values, ","))
+	testutil.AssertEqual(t, "set", constraints[2].Kind)
+	testutil.AssertEqual(t, "admin,editor", strings.Join(constraints[2].Values, ","))
+}
+
+func TestListConstraintsRejectsBadTable(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	_, err := manager.ListConstraintsPostgres("test-postgres", "testdb", "public", "orders; DROP TABLE x")
+	testutil.AssertError(t, err)
+	_, err = manager.ListConstraintsMySQL("test-mysql", "testdb", "orders`")
+	testutil.AssertError(t, err)
+}
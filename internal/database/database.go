@@ -1,12 +1,16 @@
 package database
 
 import (
+   "context"
    "database/sql"
    "fmt"
+   "os"
+   "path/filepath"
+   "strconv"
+   "strings"
+   "sync"
+   "time"
    
-   "github.com/aws/aws-sdk-go/aws"
-   awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
-   "github.com/aws/aws-sdk-go/aws/session"
    "github.com/eliziario/simpledb-mcp/internal/config"
    "github.com/eliziario/simpledb-mcp/internal/credentials"
    "github.com/eliziario/simpledb-mcp/internal/awscreds"
@@ -15,35 +19,50 @@ import (
 )
 
 type Manager struct {
-   pool          *ConnectionPool
-   config        *config.Config
-   credManager   credentials.CredentialManager
-   // STS providers per-connection for AWS Glue
-   awsProviders  map[string]*awscreds.STSProvider
-}
-
-// glueSession returns an AWS session for the Glue connection, refreshing STS credentials via MFA.
-func (m *Manager) glueSession(connectionName string) (*session.Session, error) {
-   connCfg, exists := m.config.GetConnection(connectionName)
-   if !exists {
-       return nil, fmt.Errorf("connection '%s' not found", connectionName)
-   }
-   if m.awsProviders == nil {
-       m.awsProviders = make(map[string]*awscreds.STSProvider)
-   }
-   prov, ok := m.awsProviders[connectionName]
-   if !ok {
-       prov = awscreds.NewSTSProvider(connCfg.RoleArn, connCfg.MFASerial, 3600, connCfg.UseGauth)
-       m.awsProviders[connectionName] = prov
-   }
-   creds, err := prov.Creds()
-   if err != nil {
-       return nil, fmt.Errorf("get STS creds: %w", err)
+   pool             *ConnectionPool
+   config           *config.Config
+   credManager      credentials.CredentialManager
+   // STS providers per-connection for AWS Glue, guarded by awsMu
+   awsProviders     map[string]*awscreds.STSProvider
+   awsMu            sync.Mutex
+   // Glue sessions and service clients per connection
+   glueCache        *glueCache
+   // glueCredentials returns STS credentials for a Glue connection; replaced in tests
+   glueCredentials  func(connectionName string, connCfg config.Connection) (*awscreds.AWSCreds, error)
+   // openDB opens a database handle; replaced in tests
+   openDB           func(driverName, dsn string) (*sql.DB, error)
+   // Logged-in Salesforce clients and describe results per connection
+   sfCache          *salesforceCache
+   // newSalesforceAPI creates a Salesforce client before login; replaced in tests
+   newSalesforceAPI func(instanceURL, apiVersion string) salesforceAPI
+}
+
+// defaultConnectTimeout applies when no connect_timeout is configured
+const defaultConnectTimeout = 10 * time.Second
+
+// TOTPSecretEnv holds a base32 MFA secret for glue connections with mfa_mode: totp
+const TOTPSecretEnv = "AWS_MFA_TOTP_SECRET"
+
+// TOTPSecretUsername is the keychain username the MFA secret is stored under
+// for a glue connection when TOTPSecretEnv is not set
+const TOTPSecretUsername = "mfa-totp"
+
+// newSTSProvider builds the credential provider for a Glue connection
+func (m *Manager) newSTSProvider(connectionName string, connCfg config.Connection) *awscreds.STSProvider {
+   prov := awscreds.NewSTSProvider(connCfg.RoleArn, connCfg.MFASerial, connCfg.STSDuration(), connCfg.UseGauth)
+   prov.SourceRoleArn = connCfg.SourceRoleArn
+   prov.MFAMode = connCfg.MFAMode
+   prov.TOTPSecret = func() (string, error) {
+       if secret := os.Getenv(TOTPSecretEnv); secret != "" {
+           return secret, nil
+       }
+       cred, err := m.credManager.Get(connectionName, TOTPSecretUsername)
+       if err != nil {
+           return "", fmt.Errorf("set %s or store the secret for '%s' under username '%s': %w", TOTPSecretEnv, connectionName, TOTPSecretUsername, err)
+       }
+       return cred.Password, nil
    }
-   return session.NewSession(&aws.Config{
-       Region:      aws.String(connCfg.Host),
-       Credentials: awscredentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
-   })
+   return prov
 }
 
 type TableInfo struct {
@@ -52,12 +71,30 @@ type TableInfo struct {
 	RowCount *int64 `json:"row_count,omitempty"`
 }
 
+// ColumnInfo describes one column. Describe functions return columns in a
+// stable order: ordinal position for MySQL and PostgreSQL, the describe
+// call's field order for Salesforce, and storage columns followed by
+// partition keys for Glue (the order of SELECT * in Athena).
 type ColumnInfo struct {
+	// Ordinal is the column's 1-based position in that order
+	Ordinal      int     `json:"ordinal"`
 	Name         string  `json:"name"`
 	Type         string  `json:"type"`
 	Nullable     bool    `json:"nullable"`
 	DefaultValue *string `json:"default_value"`
 	IsPrimaryKey bool    `json:"is_primary_key"`
+	// Comment is the column's description, where the catalog provides one
+	Comment string `json:"comment,omitempty"`
+	// IsPartitionKey marks Glue partition columns; filtering on them lets Athena prune partitions
+	IsPartitionKey bool `json:"is_partition_key,omitempty"`
+}
+
+// numberColumns sets each column's Ordinal from its position in columns
+func numberColumns(columns []ColumnInfo) []ColumnInfo {
+	for i := range columns {
+		columns[i].Ordinal = i + 1
+	}
+	return columns
 }
 
 type IndexInfo struct {
@@ -65,9 +102,12 @@ type IndexInfo struct {
 	Columns []string `json:"columns"`
 	Type    string   `json:"type"`
 	Unique  bool     `json:"unique"`
+	// Definition is the CREATE INDEX statement, where the catalog provides one
+	Definition string `json:"definition,omitempty"`
 }
 
 type ForeignKeyInfo struct {
+	Table              string   `json:"table,omitempty"`
 	Name               string   `json:"name"`
 	Columns            []string `json:"columns"`
 	ReferencedTable    string   `json:"referenced_table"`
@@ -76,28 +116,44 @@ type ForeignKeyInfo struct {
 
 func NewManager(config *config.Config, credManager credentials.CredentialManager) *Manager {
 	manager := &Manager{
-		config:      config,
-		credManager: credManager,
+		config:           config,
+		credManager:      credManager,
+		openDB:           sql.Open,
+		sfCache:          newSalesforceCache(salesforceCacheTTL),
+		newSalesforceAPI: newSimpleforceAPI,
+		glueCache:        newGlueCache(),
 	}
+	manager.glueCredentials = manager.stsCredentials
 	manager.pool = NewConnectionPool(manager)
 	return manager
 }
 
+// GetConnection returns the handle for schema introspection, which runs on
+// the read replica only when the connection routes metadata there
 func (m *Manager) GetConnection(connectionName string) (*sql.DB, error) {
-	return m.pool.GetConnection(connectionName)
+	return m.pool.GetConnection(m.routeConnection(connectionName, false))
+}
+
+// GetDataConnection returns the handle for reading table data, which runs on
+// the read replica unless the connection routes only metadata there
+func (m *Manager) GetDataConnection(connectionName string) (*sql.DB, error) {
+	return m.pool.GetConnection(m.routeConnection(connectionName, true))
+}
+
+// routeConnection returns the pool entry a tool should use: the connection
+// itself, or its read replica's separate entry
+func (m *Manager) routeConnection(connectionName string, dataAccess bool) string {
+	if connConfig, exists := m.config.GetConnection(connectionName); exists && connConfig.UseReplica(dataAccess) {
+		return replicaPoolKey(connectionName)
+	}
+	return connectionName
 }
 
 func (m *Manager) createRawConnection(connConfig config.Connection, connectionName string) (*sql.DB, error) {
 	// Get credentials
-	var username, password string
-	if connConfig.Username != "" {
-		cred, err := m.credManager.Get(connectionName, connConfig.Username)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get credentials for connection '%s': %w", connectionName, err)
-		}
-		username = cred.Username
-		password = cred.Password
-		
+	username, password, err := m.resolveCredentials(connConfig, connectionName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build connection string
@@ -107,13 +163,15 @@ func (m *Manager) createRawConnection(connConfig config.Connection, connectionNa
 	}
 
 	// Open connection
-	db, err := sql.Open(connConfig.Type, dsn)
+	db, err := m.open(connConfig, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
-	// Test the connection immediately
-	if err := db.Ping(); err != nil {
+	// Test the connection immediately, bounded so an unreachable host can't hang
+	ctx, cancel := context.WithTimeout(context.Background(), m.connectTimeout())
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
 		if closeErr := db.Close(); closeErr != nil {
 			return nil, fmt.Errorf("failed to ping database: %w (and failed to close: %v)", err, closeErr)
 		}
@@ -123,16 +181,85 @@ func (m *Manager) createRawConnection(connConfig config.Connection, connectionNa
 	return db, nil
 }
 
+// open returns a handle for the DSN. Postgres connections behind a proxy need
+// a custom connector; MySQL proxies are selected by the network in the DSN.
+func (m *Manager) open(connConfig config.Connection, dsn string) (*sql.DB, error) {
+	if connConfig.Type == "postgres" {
+		dialer, err := proxyDialer(connConfig)
+		if err != nil {
+			return nil, err
+		}
+		if dialer != nil {
+			return openPostgresViaProxy(dsn, dialer)
+		}
+	}
+	return m.openDB(connConfig.Type, dsn)
+}
+
+// connectTimeout returns how long a new connection may take to dial and ping
+func (m *Manager) connectTimeout() time.Duration {
+	if m.config.Settings.ConnectTimeout > 0 {
+		return m.config.Settings.ConnectTimeout
+	}
+	return defaultConnectTimeout
+}
+
+// resolveCredentials looks up the username and password for a connection,
+// preferring the keychain and falling back to PasswordFile. The file is read
+// on every call so rotated secrets take effect on the next reconnect.
+func (m *Manager) resolveCredentials(connConfig config.Connection, connectionName string) (string, string, error) {
+	if connConfig.Username == "" {
+		return "", "", nil
+	}
+
+	cred, err := m.credManager.Get(connectionName, connConfig.Username)
+	if err == nil {
+		return cred.Username, cred.Password, nil
+	}
+	if connConfig.PasswordFile == "" {
+		return "", "", fmt.Errorf("failed to get credentials for connection '%s': %w", connectionName, err)
+	}
+
+	data, readErr := os.ReadFile(connConfig.PasswordFile)
+	if readErr != nil {
+		return "", "", fmt.Errorf("failed to read password file for connection '%s': %w", connectionName, readErr)
+	}
+	return connConfig.Username, strings.TrimRight(string(data), "\r\n"), nil
+}
+
 func (m *Manager) buildDSN(conn config.Connection, username, password string) (string, error) {
 	switch conn.Type {
 	case "mysql":
+		network, err := mysqlNetwork(conn)
+		if err != nil {
+			return "", err
+		}
+		address := fmt.Sprintf("%s:%d", conn.Host, conn.Port)
+		if conn.Socket != "" {
+			network, address = "unix", conn.Socket
+		}
+		// Safe mode opens every session read-only; the driver sends unknown
+		// parameters as SET statements when it connects. MariaDB before 11.1
+		// only knows the setting as tx_read_only, which MySQL 8.0 removed.
+		readOnly := ""
+		if m.config.Settings.SafeModeEnabled() {
+			readOnly = "&transaction_read_only=1"
+			if conn.Flavor == config.FlavorMariaDB {
+				readOnly = "&tx_read_only=1"
+			}
+		}
+		params := readOnly + mysqlDSNParams(conn.Params)
 		if username == "" {
-			return fmt.Sprintf("tcp(%s:%d)/%s?parseTime=true&loc=Local", conn.Host, conn.Port, conn.Database), nil
+			return fmt.Sprintf("%s(%s)/%s?parseTime=true&loc=Local%s", network, address, conn.Database, params), nil
 		}
-		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&loc=Local&charset=utf8mb4&allowNativePasswords=true", username, password, conn.Host, conn.Port, conn.Database), nil
+		return fmt.Sprintf("%s:%s@%s(%s)/%s?parseTime=true&loc=Local&charset=utf8mb4&allowNativePasswords=true%s", username, password, network, address, conn.Database, params), nil
 	
 	case "postgres":
-		dsn := fmt.Sprintf("host=%s port=%d dbname=%s", conn.Host, conn.Port, conn.Database)
+		host, port := conn.Host, conn.Port
+		if conn.Socket != "" {
+			host, port = postgresSocket(conn.Socket, conn.Port)
+		}
+		dsn := fmt.Sprintf("host=%s port=%d dbname=%s", host, port, conn.Database)
 		if username != "" {
 			dsn += fmt.Sprintf(" user=%s password=%s", username, password)
 		}
@@ -141,6 +268,11 @@ func (m *Manager) buildDSN(conn config.Connection, username, password string) (s
 		} else {
 			dsn += " sslmode=prefer"
 		}
+		if m.config.Settings.SafeModeEnabled() {
+			// lib/pq passes unknown keys to the server as session settings
+			dsn += " default_transaction_read_only=on"
+		}
+		dsn += postgresDSNParams(conn.Params)
 		return dsn, nil
 	
 	default:
@@ -148,13 +280,36 @@ func (m *Manager) buildDSN(conn config.Connection, username, password string) (s
 	}
 }
 
+// postgresSocket turns a socket setting into the directory and port libpq
+// expects. Postgres names its socket <dir>/.s.PGSQL.<port>, so a full socket
+// file path is split back into those parts; a directory keeps the given port
+// (default 5432).
+func postgresSocket(socket string, port int) (string, int) {
+	dir, file := filepath.Split(socket)
+	if suffix, ok := strings.CutPrefix(file, ".s.PGSQL."); ok {
+		if socketPort, err := strconv.Atoi(suffix); err == nil {
+			return filepath.Clean(dir), socketPort
+		}
+	}
+	if port == 0 {
+		port = 5432
+	}
+	return socket, port
+}
+
 func (m *Manager) Close() error {
 	return m.pool.Close()
 }
 
 func (m *Manager) TestConnection(connectionName string) error {
+	connCfg, exists := m.config.GetConnection(connectionName)
+	if exists {
+		if err := connCfg.CheckEnabled(connectionName); err != nil {
+			return err
+		}
+	}
 	// For AWS Glue connections, verify via AWS Catalog
-	if connCfg, exists := m.config.GetConnection(connectionName); exists && connCfg.Type == "glue" {
+	if exists && connCfg.Type == "glue" {
 		_, err := m.ListDatabasesGlue(connectionName)
 		return err
 	}
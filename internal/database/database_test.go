@@ -1,6 +1,9 @@
 package database
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -89,7 +92,7 @@ func TestBuildDSN(t *testing.T) {
 			},
 			username: "user",
 			password: "pass",
-			expected: "user:pass@tcp(localhost:3306)/testdb",
+			expected: "user:pass@tcp(localhost:3306)/testdb?parseTime=true&loc=Local&charset=utf8mb4&allowNativePasswords=true&connectionAttributes=program_name%3Asimpledb-mcp",
 		},
 		{
 			name: "MySQL without credentials",
@@ -101,7 +104,7 @@ func TestBuildDSN(t *testing.T) {
 			},
 			username: "",
 			password: "",
-			expected: "tcp(db.example.com:3306)/myapp",
+			expected: "tcp(db.example.com:3306)/myapp?parseTime=true&loc=Local&connectionAttributes=program_name%3Asimpledb-mcp",
 		},
 		{
 			name: "Postgres with credentials",
@@ -114,7 +117,7 @@ func TestBuildDSN(t *testing.T) {
 			},
 			username: "user",
 			password: "pass",
-			expected: "host=localhost port=5432 dbname=testdb user=user password=pass sslmode=disable",
+			expected: "host=localhost port=5432 dbname=testdb user=user password=pass sslmode=disable application_name=simpledb-mcp",
 		},
 		{
 			name: "Postgres without credentials",
@@ -126,7 +129,7 @@ func TestBuildDSN(t *testing.T) {
 			},
 			username: "",
 			password: "",
-			expected: "host=pg.example.com port=5432 dbname=myapp sslmode=prefer",
+			expected: "host=pg.example.com port=5432 dbname=myapp sslmode=prefer application_name=simpledb-mcp",
 		},
 		{
 			name: "Postgres with SSL mode",
@@ -139,7 +142,47 @@ func TestBuildDSN(t *testing.T) {
 			},
 			username: "admin",
 			password: "secret",
-			expected: "host=secure-db.com port=5432 dbname=prod user=admin password=secret sslmode=require",
+			expected: "host=secure-db.com port=5432 dbname=prod user=admin password=secret sslmode=require application_name=simpledb-mcp",
+		},
+		{
+			name: "MySQL over Unix socket",
+			conn: config.Connection{
+				Type:     "mysql",
+				Socket:   "/var/run/mysqld/mysqld.sock",
+				Database: "app",
+			},
+			username: "user",
+			password: "pass",
+			expected: "user:pass@unix(/var/run/mysqld/mysqld.sock)/app?parseTime=true&loc=Local&charset=utf8mb4&allowNativePasswords=true&connectionAttributes=program_name%3Asimpledb-mcp",
+		},
+		{
+			name: "Postgres socket directory",
+			conn: config.Connection{
+				Type:     "postgres",
+				Socket:   "/var/run/postgresql",
+				Database: "app",
+			},
+			expected: "host=/var/run/postgresql port=5432 dbname=app sslmode=prefer application_name=simpledb-mcp",
+		},
+		{
+			name: "Postgres socket directory with port",
+			conn: config.Connection{
+				Type:     "postgres",
+				Socket:   "/tmp",
+				Port:     5433,
+				Database: "app",
+				SSLMode:  "disable",
+			},
+			expected: "host=/tmp port=5433 dbname=app sslmode=disable application_name=simpledb-mcp",
+		},
+		{
+			name: "Postgres socket file",
+			conn: config.Connection{
+				Type:     "postgres",
+				Socket:   "/var/run/postgresql/.s.PGSQL.5434",
+				Database: "app",
+			},
+			expected: "host=/var/run/postgresql port=5434 dbname=app sslmode=prefer application_name=simpledb-mcp",
 		},
 	}
 	
@@ -152,6 +195,34 @@ func TestBuildDSN(t *testing.T) {
 	}
 }
 
+func TestBuildDSNSafeModeReadOnly(t *testing.T) {
+	cfg := testConfig()
+	safe := true
+	cfg.Settings.SafeMode = &safe
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	dsn, err := manager.buildDSN(config.Connection{Type: "mysql", Host: "localhost", Port: 3306, Database: "app"}, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, dsn, "&transaction_read_only=1")
+
+	dsn, err = manager.buildDSN(config.Connection{Type: "mysql", Host: "localhost", Port: 3306, Database: "app"}, "", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, dsn, "&transaction_read_only=1")
+
+	// MariaDB before 11.1 only accepts the older name
+	dsn, err = manager.buildDSN(config.Connection{Type: "mysql", Flavor: config.FlavorMariaDB, Host: "localhost", Port: 3306, Database: "app"}, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, dsn, "&tx_read_only=1")
+	if strings.Contains(dsn, "transaction_read_only") {
+		t.Errorf("Expected no transaction_read_only for MariaDB, got %s", dsn)
+	}
+
+	dsn, err = manager.buildDSN(config.Connection{Type: "postgres", Host: "localhost", Port: 5432, Database: "app"}, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, dsn, " default_transaction_read_only=on")
+}
+
 func TestBuildDSNUnsupportedType(t *testing.T) {
 	cfg := testConfig()
 	credManager := testutil.NewMockCredentialManager()
@@ -239,6 +310,62 @@ func TestCreateRawConnectionNoUsername(t *testing.T) {
 	}
 }
 
+func TestResolveCredentialsFromPasswordFile(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	
+	passwordFile := filepath.Join(testutil.TempDir(t), "password")
+	testutil.AssertNoError(t, os.WriteFile(passwordFile, []byte("s3cret\n"), 0600))
+	
+	conn := config.Connection{
+		Type:         "mysql",
+		Host:         "localhost",
+		Port:         3306,
+		Username:     "fileuser",
+		PasswordFile: passwordFile,
+	}
+	
+	username, password, err := manager.resolveCredentials(conn, "file-conn")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "fileuser", username)
+	testutil.AssertEqual(t, "s3cret", password)
+	
+	// A rotated secret is picked up on the next connection attempt
+	testutil.AssertNoError(t, os.WriteFile(passwordFile, []byte("rotated\r\n"), 0600))
+	_, password, err = manager.resolveCredentials(conn, "file-conn")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "rotated", password)
+}
+
+func TestResolveCredentialsPrefersKeychain(t *testing.T) {
+	credManager := testutil.NewMockCredentialManager()
+	credManager.Store("file-conn", "fileuser", "from-keychain")
+	manager := NewManager(testConfig(), credManager)
+	
+	conn := config.Connection{
+		Type:         "postgres",
+		Username:     "fileuser",
+		PasswordFile: filepath.Join(testutil.TempDir(t), "missing"),
+	}
+	
+	_, password, err := manager.resolveCredentials(conn, "file-conn")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "from-keychain", password)
+}
+
+func TestResolveCredentialsMissingPasswordFile(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	
+	conn := config.Connection{
+		Type:         "mysql",
+		Username:     "fileuser",
+		PasswordFile: filepath.Join(testutil.TempDir(t), "missing"),
+	}
+	
+	_, _, err := manager.resolveCredentials(conn, "file-conn")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "failed to read password file")
+}
+
 func TestGetConnection(t *testing.T) {
 	cfg := testConfig()
 	credManager := testutil.NewMockCredentialManager()
@@ -346,4 +473,35 @@ func TestForeignKeyInfo(t *testing.T) {
 	testutil.AssertEqual(t, "users", fk.ReferencedTable)
 	testutil.AssertEqual(t, 1, len(fk.ReferencedColumns))
 	testutil.AssertEqual(t, "id", fk.ReferencedColumns[0])
-}
\ No newline at end of file
+}
+func TestListDatabasesHidesSystemDatabases(t *testing.T) {
+	for _, hide := range []bool{true, false} {
+		cfg := testConfig()
+		cfg.Settings.HideSystemDatabases = hide
+		manager := NewManager(cfg, testutil.NewMockCredentialManager())
+		
+		mysqlDB := addMockConnection(t, manager.pool, "test-mysql")
+		mysqlDB.SetQueryResult("SHOW DATABASES", []string{"Database"}, [][]interface{}{
+			{"app"}, {"information_schema"}, {"mysql"}, {"performance_schema"}, {"sys"},
+		})
+		postgresDB := addMockConnection(t, manager.pool, "test-postgres")
+		postgresDB.SetQueryResult(postgresListDatabasesQuery, []string{"datname", "datistemplate"}, [][]interface{}{
+			{"analytics", false}, {"postgres", false}, {"template0", true}, {"template1", true}, {"templates_app", false},
+		})
+		
+		mysqlDatabases, err := manager.ListDatabasesMySQL("test-mysql")
+		testutil.AssertNoError(t, err)
+		postgresDatabases, err := manager.ListDatabasesPostgres("test-postgres")
+		testutil.AssertNoError(t, err)
+		
+		if hide {
+			testutil.AssertEqual(t, "app", strings.Join(mysqlDatabases, ","))
+			// Only real templates are hidden, not databases whose names merely start with "template"
+			testutil.AssertEqual(t, "analytics,templates_app", strings.Join(postgresDatabases, ","))
+		} else {
+			testutil.AssertEqual(t, 5, len(mysqlDatabases))
+			testutil.AssertEqual(t, 5, len(postgresDatabases))
+		}
+		manager.Close()
+	}
+}
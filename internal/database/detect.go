@@ -0,0 +1,141 @@
+package database
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// DetectTimeout bounds a whole connection type probe
+const DetectTimeout = 3 * time.Second
+
+// mysqlGreetingWait is how long to wait for a server to speak first. MySQL
+// sends its greeting immediately; Postgres waits for the client.
+const mysqlGreetingWait = 500 * time.Millisecond
+
+// postgresSSLRequestCode is the request code of a Postgres SSLRequest message
+const postgresSSLRequestCode = 80877103
+
+// mysqlClientSSL is the capability flag a MySQL server sets when it accepts TLS
+const mysqlClientSSL = 0x0800
+
+// DetectedType is the result of probing a host for its database type
+type DetectedType struct {
+	Type string // mysql, postgres, or "" when neither answered
+	// Method is "handshake" when the server answered the protocol probe and
+	// "port" when the type is only a guess from the port number
+	Method        string
+	ServerVersion string // MySQL only; Postgres doesn't reveal it before login
+	TLS           bool   // the server offered TLS
+	SSLMode       string // suggested ssl_mode for postgres connections
+}
+
+// GuessTypeFromPort maps well-known ports to a connection type, or "" if the
+// port isn't one of them
+func GuessTypeFromPort(port int) string {
+	switch port {
+	case 3306, 3307, 33060, 4000, 6033:
+		// MySQL, a second local instance, X Protocol, TiDB and ProxySQL
+		return "mysql"
+	case 5432, 5433, 6432, 26257:
+		// PostgreSQL, a second local instance, PgBouncer and CockroachDB
+		return "postgres"
+	}
+	return ""
+}
+
+// DetectConnectionType probes host:port for a MySQL or Postgres server by
+// starting each protocol's handshake, without logging in. It gives up after
+// timeout and falls back to the port number when the server doesn't answer
+// either probe.
+func DetectConnectionType(host string, port int, timeout time.Duration) (*DetectedType, error) {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	deadline := time.Now().Add(timeout)
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	// MySQL speaks first
+	wait := time.Now().Add(mysqlGreetingWait)
+	if wait.After(deadline) {
+		wait = deadline
+	}
+	conn.SetReadDeadline(wait)
+	greeting := make([]byte, 512)
+	if n, err := conn.Read(greeting); err == nil {
+		if detected := parseMySQLGreeting(greeting[:n]); detected != nil {
+			return detected, nil
+		}
+		return portGuess(port), nil
+	}
+
+	// Silence suggests Postgres: ask whether it accepts TLS and expect S or N
+	conn.SetDeadline(deadline)
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(request); err == nil {
+		reply := make([]byte, 1)
+		if _, err := conn.Read(reply); err == nil {
+			if detected := parsePostgresSSLReply(reply[0]); detected != nil {
+				return detected, nil
+			}
+		}
+	}
+	return portGuess(port), nil
+}
+
+// parseMySQLGreeting recognizes the initial handshake packet of a MySQL
+// server, or the error packet it sends to hosts it won't talk to
+func parseMySQLGreeting(packet []byte) *DetectedType {
+	// 3-byte payload length, 1-byte sequence id, then the payload
+	if len(packet) < 5 || packet[3] != 0 {
+		return nil
+	}
+	payload := packet[4:]
+	switch payload[0] {
+	case 0xff:
+		// Error packet, e.g. "Host is not allowed to connect"
+		return &DetectedType{Type: "mysql", Method: "handshake"}
+	case 0x0a:
+		// Protocol 10: NUL-terminated version, connection id, 8-byte salt,
+		// filler, then the lower capability flags
+		end := bytes.IndexByte(payload[1:], 0)
+		if end < 0 {
+			return nil
+		}
+		detected := &DetectedType{Type: "mysql", Method: "handshake", ServerVersion: string(payload[1 : 1+end])}
+		caps := 1 + end + 1 + 4 + 8 + 1
+		if len(payload) >= caps+2 {
+			detected.TLS = binary.LittleEndian.Uint16(payload[caps:caps+2])&mysqlClientSSL != 0
+		}
+		return detected
+	}
+	return nil
+}
+
+// parsePostgresSSLReply interprets the one-byte answer to an SSLRequest
+func parsePostgresSSLReply(reply byte) *DetectedType {
+	switch reply {
+	case 'S':
+		return &DetectedType{Type: "postgres", Method: "handshake", TLS: true, SSLMode: "require"}
+	case 'N':
+		return &DetectedType{Type: "postgres", Method: "handshake", SSLMode: "disable"}
+	}
+	return nil
+}
+
+// portGuess is the fallback result when no handshake was recognized
+func portGuess(port int) *DetectedType {
+	detected := &DetectedType{Type: GuessTypeFromPort(port), Method: "port"}
+	if detected.Type == "postgres" {
+		detected.SSLMode = "prefer"
+	}
+	return detected
+}
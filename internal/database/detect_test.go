@@ -0,0 +1,119 @@
+package database
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestGuessTypeFromPort(t *testing.T) {
+	tests := []struct {
+		port     int
+		expected string
+	}{
+		{3306, "mysql"},
+		{3307, "mysql"},
+		{33060, "mysql"},
+		{6033, "mysql"},
+		{5432, "postgres"},
+		{5433, "postgres"},
+		{6432, "postgres"},
+		{26257, "postgres"},
+		{1433, ""},
+		{0, ""},
+	}
+
+	for _, tt := range tests {
+		testutil.AssertEqual(t, tt.expected, GuessTypeFromPort(tt.port))
+	}
+}
+
+func TestPortGuessSuggestsSSLMode(t *testing.T) {
+	testutil.AssertEqual(t, "prefer", portGuess(5432).SSLMode)
+	testutil.AssertEqual(t, "", portGuess(3306).SSLMode)
+	testutil.AssertEqual(t, "port", portGuess(1433).Method)
+}
+
+// mysqlGreeting builds a protocol 10 handshake packet
+func mysqlGreeting(version string, capabilities uint16) []byte {
+	payload := []byte{0x0a}
+	payload = append(payload, version...)
+	payload = append(payload, 0)
+	payload = append(payload, 1, 0, 0, 0)            // connection id
+	payload = append(payload, []byte("12345678")...) // salt
+	payload = append(payload, 0)                     // filler
+	payload = binary.LittleEndian.AppendUint16(payload, capabilities)
+	header := []byte{byte(len(payload)), 0, 0, 0}
+	return append(header, payload...)
+}
+
+func TestParseMySQLGreeting(t *testing.T) {
+	detected := parseMySQLGreeting(mysqlGreeting("8.0.36", mysqlClientSSL))
+	testutil.AssertEqual(t, "mysql", detected.Type)
+	testutil.AssertEqual(t, "8.0.36", detected.ServerVersion)
+	testutil.AssertEqual(t, true, detected.TLS)
+
+	detected = parseMySQLGreeting(mysqlGreeting("5.7.44", 0))
+	testutil.AssertEqual(t, false, detected.TLS)
+
+	// A host the server refuses still gets an error packet
+	detected = parseMySQLGreeting([]byte{5, 0, 0, 0, 0xff, 0x6a, 0x04, 'H', 'o'})
+	testutil.AssertEqual(t, "mysql", detected.Type)
+
+	if parseMySQLGreeting([]byte("SSH-2.0-OpenSSH_9.6\r\n")) != nil {
+		t.Error("Expected an SSH banner not to be taken for MySQL")
+	}
+}
+
+// serve accepts one connection on a local port and runs handle on it
+func serve(t *testing.T, handle func(net.Conn)) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.AssertNoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handle(conn)
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestDetectConnectionTypeHandshakes(t *testing.T) {
+	mysqlPort := serve(t, func(conn net.Conn) {
+		conn.Write(mysqlGreeting("8.0.36", 0))
+	})
+	detected, err := DetectConnectionType("127.0.0.1", mysqlPort, time.Second)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "mysql", detected.Type)
+	testutil.AssertEqual(t, "handshake", detected.Method)
+
+	postgresPort := serve(t, func(conn net.Conn) {
+		request := make([]byte, 8)
+		if _, err := conn.Read(request); err == nil && binary.BigEndian.Uint32(request[4:]) == postgresSSLRequestCode {
+			conn.Write([]byte{'S'})
+		}
+	})
+	detected, err = DetectConnectionType("127.0.0.1", postgresPort, time.Second)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "postgres", detected.Type)
+	testutil.AssertEqual(t, "require", detected.SSLMode)
+}
+
+func TestDetectConnectionTypeRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.AssertNoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	_, err = DetectConnectionType("127.0.0.1", port, time.Second)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), strconv.Itoa(port))
+}
@@ -0,0 +1,217 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/simpleforce/simpleforce"
+)
+
+// distinctCountColumn names the per-value count in distinct value queries
+const distinctCountColumn = "value_count"
+
+// DistinctValue is one value of a column and, when counted, how many rows hold it
+type DistinctValue struct {
+	Value interface{} `json:"value"`
+	Count *int64      `json:"count,omitempty"`
+}
+
+// findColumn returns a column's name as the table declares it. Only real
+// columns are accepted, so a name never reaches a query unless the catalog
+// knows it; an exact match wins over a case-insensitive one.
+func findColumn(columns []ColumnInfo, tableName, name string) (string, error) {
+	match := ""
+	for _, col := range columns {
+		if col.Name == name {
+			return col.Name, nil
+		}
+		if match == "" && strings.EqualFold(col.Name, name) {
+			match = col.Name
+		}
+	}
+	if match == "" {
+		return "", fmt.Errorf("column '%s' not found in table '%s'", name, tableName)
+	}
+	return match, nil
+}
+
+// distinctQuery builds the SQL that lists a column's distinct values. With
+// counts the most common values come first.
+func distinctQuery(table, column string, limit int, withCounts bool) string {
+	if withCounts {
+		return fmt.Sprintf("SELECT %s, COUNT(*) AS %s FROM %s GROUP BY %s ORDER BY COUNT(*) DESC LIMIT %d",
+			column, distinctCountColumn, table, column, limit)
+	}
+	return fmt.Sprintf("SELECT DISTINCT %s FROM %s LIMIT %d", column, table, limit)
+}
+
+// salesforceDistinctQuery builds the SOQL that lists a field's distinct
+// values; SOQL has no DISTINCT, so values are grouped instead
+func salesforceDistinctQuery(objectName, field string, limit int, withCounts bool) (string, error) {
+	object, err := quoteIdentifier("salesforce", objectName)
+	if err != nil {
+		return "", err
+	}
+	field, err = quoteIdentifier("salesforce", field)
+	if err != nil {
+		return "", err
+	}
+	if withCounts {
+		return fmt.Sprintf("SELECT %s, COUNT(Id) %s FROM %s GROUP BY %s ORDER BY COUNT(Id) DESC LIMIT %d",
+			field, distinctCountColumn, object, field, limit), nil
+	}
+	return fmt.Sprintf("SELECT %s FROM %s GROUP BY %s LIMIT %d", field, object, field, limit), nil
+}
+
+// GetDistinctValuesMySQL lists up to limit distinct values of a column
+func (m *Manager) GetDistinctValuesMySQL(ctx context.Context, connectionName, database, tableName, columnName string, limit int, withCounts bool) ([]DistinctValue, error) {
+	columns, err := m.DescribeTableMySQL(connectionName, database, tableName)
+	if err != nil {
+		return nil, err
+	}
+	column, err := findColumn(columns, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := qualifiedName("mysql", database, tableName)
+	if err != nil {
+		return nil, err
+	}
+	quoted, err := quoteIdentifier("mysql", column)
+	if err != nil {
+		return nil, err
+	}
+	return m.queryDistinctValues(ctx, connectionName, distinctQuery(table, quoted, limit, withCounts), withCounts)
+}
+
+// GetDistinctValuesPostgres lists up to limit distinct values of a column
+func (m *Manager) GetDistinctValuesPostgres(ctx context.Context, connectionName, database, tableName, schema, columnName string, limit int, withCounts bool) ([]DistinctValue, error) {
+	columns, err := m.DescribeTablePostgres(connectionName, database, tableName, schema)
+	if err != nil {
+		return nil, err
+	}
+	column, err := findColumn(columns, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema == "" {
+		schema = "public"
+	}
+	table, err := qualifiedName("postgres", schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	quoted, err := quoteIdentifier("postgres", column)
+	if err != nil {
+		return nil, err
+	}
+	return m.queryDistinctValues(ctx, connectionName, distinctQuery(table, quoted, limit, withCounts), withCounts)
+}
+
+// queryDistinctValues runs a distinct value query built by distinctQuery
+func (m *Manager) queryDistinctValues(ctx context.Context, connectionName, query string, withCounts bool) ([]DistinctValue, error) {
+	db, err := m.GetDataConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	rows, err := m.query(ctx, db, connectionName, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distinct values: %w", err)
+	}
+	defer rows.Close()
+
+	values := []DistinctValue{}
+	for rows.Next() {
+		var value interface{}
+		var count int64
+		dest := []interface{}{&value}
+		if withCounts {
+			dest = append(dest, &count)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan distinct value: %w", err)
+		}
+		if b, ok := value.([]byte); ok {
+			value = cleanTextForJSON(string(b))
+		}
+
+		distinct := DistinctValue{Value: value}
+		if withCounts {
+			distinct.Count = &count
+		}
+		values = append(values, distinct)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read distinct values: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, true), len(values), time.Since(start))
+
+	return values, nil
+}
+
+// GetDistinctValuesSalesforce lists up to limit distinct values of an object's field
+func (m *Manager) GetDistinctValuesSalesforce(ctx context.Context, connectionName, objectName, fieldName string, limit int, withCounts bool) ([]DistinctValue, error) {
+	columns, err := m.DescribeTableSalesforce(connectionName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	field, err := findColumn(columns, objectName, fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := salesforceDistinctQuery(objectName, field, limit, withCounts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The Salesforce client has no context support, so check before issuing the query
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var result *simpleforce.QueryResult
+	err = m.withSalesforceClient(connectionName, func(sfClient *SalesforceClient) error {
+		if result, err = m.soql(connectionName, sfClient, query); err != nil {
+			return fmt.Errorf("failed to get distinct values of %s.%s: %w", objectName, field, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseSalesforceDistinct(result, field, withCounts), nil
+}
+
+// parseSalesforceDistinct reads the records of a salesforceDistinctQuery
+func parseSalesforceDistinct(result *simpleforce.QueryResult, field string, withCounts bool) []DistinctValue {
+	values := []DistinctValue{}
+	if result == nil {
+		return values
+	}
+	for _, record := range result.Records {
+		value := record[field]
+		if str, ok := value.(string); ok {
+			value = cleanTextForJSON(str)
+		}
+
+		distinct := DistinctValue{Value: value}
+		if withCounts {
+			// Aggregate results are decoded from JSON, so counts arrive as float64
+			if n, ok := record[distinctCountColumn].(float64); ok {
+				count := int64(n)
+				distinct.Count = &count
+			}
+		}
+		values = append(values, distinct)
+	}
+	return values
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/simpleforce/simpleforce"
+)
+
+func TestFindColumn(t *testing.T) {
+	columns := []ColumnInfo{{Name: "id"}, {Name: "Status"}, {Name: "status"}}
+
+	name, err := findColumn(columns, "orders", "status")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "status", name)
+
+	// Case-insensitive matches resolve to the declared name
+	name, err = findColumn(columns, "orders", "ID")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "id", name)
+
+	_, err = findColumn(columns, "orders", "status; DROP TABLE orders")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "column 'status; DROP TABLE orders' not found in table 'orders'")
+}
+
+func TestDistinctQuery(t *testing.T) {
+	testutil.AssertEqual(t, "SELECT DISTINCT `status` FROM `shop`.`orders` LIMIT 20",
+		distinctQuery("`shop`.`orders`", "`status`", 20, false))
+	testutil.AssertEqual(t, `SELECT "status", COUNT(*) AS value_count FROM "public"."orders" GROUP BY "status" ORDER BY COUNT(*) DESC LIMIT 5`,
+		distinctQuery(`"public"."orders"`, `"status"`, 5, true))
+}
+
+func TestSalesforceDistinctQuery(t *testing.T) {
+	query, err := salesforceDistinctQuery("Opportunity", "StageName", 10, false)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "SELECT StageName FROM Opportunity GROUP BY StageName LIMIT 10", query)
+
+	query, err = salesforceDistinctQuery("Opportunity", "StageName", 10, true)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "SELECT StageName, COUNT(Id) value_count FROM Opportunity GROUP BY StageName ORDER BY COUNT(Id) DESC LIMIT 10", query)
+
+	_, err = salesforceDistinctQuery("Opportunity", "Stage Name", 10, false)
+	testutil.AssertError(t, err)
+}
+
+func TestDistinctValuesRejectUnknownColumn(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+	addMockConnection(t, manager.pool, "test-mysql")
+
+	// The table describes with no such column, so no query is built
+	_, err := manager.GetDistinctValuesMySQL(context.Background(), "test-mysql", "shop", "orders", "status", 10, false)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "column 'status' not found in table 'orders'")
+}
+
+func TestDistinctValuesSalesforce(t *testing.T) {
+	fake := &fakeSalesforce{
+		describes: map[string]string{"Opportunity": `{"fields": [{"name": "Id", "type": "id"}, {"name": "StageName", "type": "picklist"}]}`},
+		records: []simpleforce.SObject{
+			{"StageName": "Prospecting", "value_count": float64(12)},
+			{"StageName": "Closed Won", "value_count": float64(3)},
+		},
+	}
+	manager := salesforceManager(t, fake)
+
+	values, err := manager.GetDistinctValuesSalesforce(context.Background(), "test-sf", "Opportunity", "stagename", 10, true)
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, fake.lastQuery, "GROUP BY StageName")
+	testutil.AssertEqual(t, 2, len(values))
+	testutil.AssertEqual(t, "Prospecting", values[0].Value)
+	testutil.AssertEqual(t, int64(12), *values[0].Count)
+
+	_, err = manager.GetDistinctValuesSalesforce(context.Background(), "test-sf", "Opportunity", "Amount", 10, false)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "column 'Amount' not found in table 'Opportunity'")
+}
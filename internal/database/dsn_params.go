@@ -0,0 +1,64 @@
+package database
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// applicationName identifies the server's sessions to the database, as
+// Postgres application_name and MySQL program_name, unless params sets one
+const applicationName = "simpledb-mcp"
+
+// sortedParamKeys keeps built DSNs stable
+func sortedParamKeys(params map[string]string) []string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mysqlDSNParams renders params as &key=value pairs for the query string of a
+// MySQL DSN. program_name travels in connectionAttributes, so it is added
+// there unless the configured attributes already name a program.
+func mysqlDSNParams(params map[string]string) string {
+	merged := map[string]string{"connectionAttributes": "program_name:" + applicationName}
+	for key, value := range params {
+		if key == "connectionAttributes" && !strings.Contains(value, "program_name:") {
+			value = merged[key] + "," + value
+		}
+		merged[key] = value
+	}
+
+	var b strings.Builder
+	for _, key := range sortedParamKeys(merged) {
+		b.WriteString("&" + key + "=" + url.QueryEscape(merged[key]))
+	}
+	return b.String()
+}
+
+// postgresDSNParams renders params as space-separated key=value pairs for a
+// Postgres DSN, application_name included
+func postgresDSNParams(params map[string]string) string {
+	merged := map[string]string{"application_name": applicationName}
+	for key, value := range params {
+		merged[key] = value
+	}
+
+	var b strings.Builder
+	for _, key := range sortedParamKeys(merged) {
+		b.WriteString(" " + key + "=" + postgresDSNValue(merged[key]))
+	}
+	return b.String()
+}
+
+// postgresDSNValue quotes a value that is empty or contains spaces, quotes or
+// backslashes, the way lib/pq parses key=value strings
+func postgresDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " '\\") {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
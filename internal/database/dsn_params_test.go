@@ -0,0 +1,54 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestBuildDSNCustomParamsMySQL(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	conn := config.Connection{Type: "mysql", Host: "localhost", Port: 3306, Database: "app", Params: map[string]string{
+		"timeout":     "5s",
+		"readTimeout": "30s",
+	}}
+	dsn, err := manager.buildDSN(conn, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "user:pass@tcp(localhost:3306)/app?parseTime=true&loc=Local&charset=utf8mb4&allowNativePasswords=true&connectionAttributes=program_name%3Asimpledb-mcp&readTimeout=30s&timeout=5s", dsn)
+
+	// Configured attributes keep the default program name unless they set one
+	conn.Params = map[string]string{"connectionAttributes": "team:data"}
+	dsn, err = manager.buildDSN(conn, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, dsn, "&connectionAttributes=program_name%3Asimpledb-mcp%2Cteam%3Adata")
+
+	conn.Params = map[string]string{"connectionAttributes": "program_name:etl"}
+	dsn, err = manager.buildDSN(conn, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, dsn, "&connectionAttributes=program_name%3Aetl")
+	if strings.Contains(dsn, "simpledb-mcp") {
+		t.Errorf("Default program name should be replaced: %s", dsn)
+	}
+}
+
+func TestBuildDSNCustomParamsPostgres(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	conn := config.Connection{Type: "postgres", Host: "localhost", Port: 5432, Database: "app", SSLMode: "disable", Params: map[string]string{
+		"connect_timeout": "10",
+		"options":         "-c search_path=analytics",
+	}}
+	dsn, err := manager.buildDSN(conn, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "host=localhost port=5432 dbname=app user=user password=pass sslmode=disable application_name=simpledb-mcp connect_timeout=10 options='-c search_path=analytics'", dsn)
+
+	conn.Params = map[string]string{"application_name": "it's reports"}
+	dsn, err = manager.buildDSN(conn, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "host=localhost port=5432 dbname=app user=user password=pass sslmode=disable application_name='it\\'s reports'", dsn)
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// FailureKind classifies why a connection attempt or query failed, so users
+// can tell a wrong password from an unreachable host or a missing grant
+type FailureKind string
+
+const (
+	FailureAuth        FailureKind = "auth_failed"
+	FailureUnreachable FailureKind = "unreachable"
+	FailurePermission  FailureKind = "permission_denied"
+	FailureTimeout     FailureKind = "timeout"
+	FailureUnknown     FailureKind = "unknown"
+)
+
+// MySQL server error numbers
+var mysqlFailureKinds = map[uint16]FailureKind{
+	1045: FailureAuth,        // ER_ACCESS_DENIED_ERROR: bad user or password
+	1698: FailureAuth,        // ER_ACCESS_DENIED_NO_PASSWORD_ERROR
+	1862: FailureAuth,        // ER_MUST_CHANGE_PASSWORD_LOGIN
+	1044: FailurePermission,  // ER_DBACCESS_DENIED_ERROR
+	1142: FailurePermission,  // ER_TABLEACCESS_DENIED_ERROR
+	1143: FailurePermission,  // ER_COLUMNACCESS_DENIED_ERROR
+	1227: FailurePermission,  // ER_SPECIFIC_ACCESS_DENIED_ERROR
+	1129: FailureUnreachable, // ER_HOST_IS_BLOCKED
+	1130: FailureUnreachable, // ER_HOST_NOT_PRIVILEGED: host may not connect
+	1040: FailureUnreachable, // ER_CON_COUNT_ERROR: too many connections
+	3024: FailureTimeout,     // ER_QUERY_TIMEOUT
+}
+
+// Postgres SQLSTATE codes; class 08 (connection exception) is handled separately
+var postgresFailureKinds = map[pq.ErrorCode]FailureKind{
+	"28P01": FailureAuth,        // invalid_password
+	"28000": FailureAuth,        // invalid_authorization_specification
+	"42501": FailurePermission,  // insufficient_privilege
+	"53300": FailureUnreachable, // too_many_connections
+	"57P03": FailureUnreachable, // cannot_connect_now
+	"57014": FailureTimeout,     // query_canceled (statement_timeout)
+}
+
+// Fallbacks for errors that only carry a message, e.g. wrapped or from other drivers
+var failureMessages = []struct {
+	substring string
+	kind      FailureKind
+}{
+	{"password authentication failed", FailureAuth},
+	{"access denied for user", FailureAuth},
+	{"credential not found", FailureAuth},
+	{"permission denied", FailurePermission},
+	{"command denied", FailurePermission},
+	{"connection refused", FailureUnreachable},
+	{"no such host", FailureUnreachable},
+	{"network is unreachable", FailureUnreachable},
+	{"no route to host", FailureUnreachable},
+	{"i/o timeout", FailureTimeout},
+	{"deadline exceeded", FailureTimeout},
+}
+
+// ClassifyFailure maps a connection or query error to a FailureKind using
+// driver error codes where available, then network errors, then the message.
+// A nil error has no kind.
+func ClassifyFailure(err error) FailureKind {
+	if err == nil {
+		return ""
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if kind, ok := mysqlFailureKinds[mysqlErr.Number]; ok {
+			return kind
+		}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if kind, ok := postgresFailureKinds[pqErr.Code]; ok {
+			return kind
+		}
+		if pqErr.Code.Class() == "08" {
+			return FailureUnreachable
+		}
+	}
+
+	if isSalesforceAuthError(err) {
+		return FailureAuth
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return FailureTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return FailureTimeout
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return FailureUnreachable
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, m := range failureMessages {
+		if strings.Contains(message, m.substring) {
+			return m.kind
+		}
+	}
+	return FailureUnknown
+}
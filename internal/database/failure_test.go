@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected FailureKind
+	}{
+		{"nil", nil, ""},
+
+		// MySQL error numbers, wrapped the way createRawConnection wraps them
+		{"mysql bad password", fmt.Errorf("failed to ping database: %w", &mysql.MySQLError{Number: 1045, Message: "Access denied for user 'app'@'10.0.0.1' (using password: YES)"}), FailureAuth},
+		{"mysql no db grant", &mysql.MySQLError{Number: 1044, Message: "Access denied for user 'app'@'%' to database 'billing'"}, FailurePermission},
+		{"mysql no select grant", &mysql.MySQLError{Number: 1142, Message: "SELECT command denied to user 'app'@'%' for table 'users'"}, FailurePermission},
+		{"mysql host blocked", &mysql.MySQLError{Number: 1130, Message: "Host '10.0.0.1' is not allowed to connect to this MySQL server"}, FailureUnreachable},
+		{"mysql unknown db", &mysql.MySQLError{Number: 1049, Message: "Unknown database 'nope'"}, FailureUnknown},
+
+		// Postgres SQLSTATE codes
+		{"postgres bad password", fmt.Errorf("failed to ping database: %w", &pq.Error{Code: "28P01", Message: `password authentication failed for user "app"`}), FailureAuth},
+		{"postgres no role", &pq.Error{Code: "28000", Message: `role "ghost" does not exist`}, FailureAuth},
+		{"postgres no grant", &pq.Error{Code: "42501", Message: "permission denied for table users"}, FailurePermission},
+		{"postgres starting up", &pq.Error{Code: "57P03", Message: "the database system is starting up"}, FailureUnreachable},
+		{"postgres connection exception", &pq.Error{Code: "08006", Message: "connection failure"}, FailureUnreachable},
+		{"postgres statement timeout", &pq.Error{Code: "57014", Message: "canceling statement due to statement timeout"}, FailureTimeout},
+
+		// Network and context errors
+		{"connect timeout", fmt.Errorf("failed to ping database: %w", context.DeadlineExceeded), FailureTimeout},
+		{"refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}, FailureUnreachable},
+		{"dns", &net.DNSError{Err: "no such host", Name: "db.invalid"}, FailureUnreachable},
+
+		// Message fallbacks for errors without codes
+		{"refused text", errors.New("dial tcp 127.0.0.1:3306: connect: connection refused"), FailureUnreachable},
+		{"pq auth text", errors.New(`pq: password authentication failed for user "app"`), FailureAuth},
+		{"missing credential", errors.New("failed to get credentials for connection 'prod': credential not found"), FailureAuth},
+		{"other", errors.New("something else broke"), FailureUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.AssertEqual(t, tt.expected, ClassifyFailure(tt.err))
+		})
+	}
+}
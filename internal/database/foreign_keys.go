@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// One row per key column; an empty table argument matches every table
+const mysqlForeignKeysQuery = `
+		SELECT TABLE_NAME, CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_SCHEMA, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND (? = '' OR TABLE_NAME = ?) AND REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION`
+
+const postgresForeignKeysQuery = `
+		SELECT cl.relname, con.conname, a.attname, fn.nspname, fcl.relname, fa.attname
+		FROM pg_constraint con
+		JOIN pg_class cl ON cl.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = cl.relnamespace
+		JOIN pg_class fcl ON fcl.oid = con.confrelid
+		JOIN pg_namespace fn ON fn.oid = fcl.relnamespace
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, fattnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute fa ON fa.attrelid = con.confrelid AND fa.attnum = k.fattnum
+		WHERE con.contype = 'f' AND n.nspname = $1 AND ($2 = '' OR cl.relname = $2)
+		ORDER BY cl.relname, con.conname, k.ord`
+
+// ListForeignKeysMySQL returns the foreign keys of a database, optionally limited to one table
+func (m *Manager) ListForeignKeysMySQL(connectionName, database, tableName string) ([]ForeignKeyInfo, error) {
+	if err := validateIdentifiers("mysql", database, tableName); err != nil {
+		return nil, err
+	}
+	return m.listForeignKeys(connectionName, database, mysqlForeignKeysQuery, database, tableName, tableName)
+}
+
+// ListForeignKeysPostgres returns the foreign keys of a schema, optionally limited to one table
+func (m *Manager) ListForeignKeysPostgres(connectionName, database, schema, tableName string) ([]ForeignKeyInfo, error) {
+	if err := validateIdentifiers("postgres", database, schema, tableName); err != nil {
+		return nil, err
+	}
+	if schema == "" {
+		schema = "public"
+	}
+	return m.listForeignKeys(connectionName, schema, postgresForeignKeysQuery, schema, tableName)
+}
+
+// listForeignKeys runs a foreign key query, merging the rows of each
+// constraint. Referenced tables outside namespace are qualified with theirs.
+func (m *Manager) listForeignKeys(connectionName, namespace, query string, args ...interface{}) ([]ForeignKeyInfo, error) {
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	rows, err := m.query(context.Background(), db, connectionName, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	foreignKeys := []ForeignKeyInfo{}
+	for rows.Next() {
+		var table, name, column, refNamespace, refTable, refColumn string
+		if err := rows.Scan(&table, &name, &column, &refNamespace, &refTable, &refColumn); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+		if refNamespace != namespace {
+			refTable = refNamespace + "." + refTable
+		}
+
+		last := len(foreignKeys) - 1
+		if last >= 0 && foreignKeys[last].Table == table && foreignKeys[last].Name == name {
+			foreignKeys[last].Columns = append(foreignKeys[last].Columns, column)
+			foreignKeys[last].ReferencedColumns = append(foreignKeys[last].ReferencedColumns, refColumn)
+			continue
+		}
+		foreignKeys = append(foreignKeys, ForeignKeyInfo{
+			Table:             table,
+			Name:              name,
+			Columns:           []string{column},
+			ReferencedTable:   refTable,
+			ReferencedColumns: []string{refColumn},
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read foreign keys: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(foreignKeys), time.Since(start))
+
+	return foreignKeys, nil
+}
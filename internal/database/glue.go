@@ -1,6 +1,8 @@
 package database
 
 import (
+   "context"
+   "errors"
    "fmt"
    "os"
    "time"
@@ -8,21 +10,23 @@ import (
    "github.com/aws/aws-sdk-go/aws"
    "github.com/aws/aws-sdk-go/service/athena"
    "github.com/aws/aws-sdk-go/service/glue"
+   "github.com/eliziario/simpledb-mcp/internal/config"
 )
 
 
 // ListDatabasesGlue lists all Glue Catalog databases.
 func (m *Manager) ListDatabasesGlue(connectionName string) ([]string, error) {
-   sess, err := m.glueSession(connectionName)
+   clients, err := m.glueClients(connectionName)
    if err != nil {
        return nil, err
    }
-   svc := glue.New(sess)
+   svc := clients.glue
    input := &glue.GetDatabasesInput{}
    var outNames []string
    for {
        resp, err := svc.GetDatabases(input)
        if err != nil {
+           m.glueFailed(connectionName, err)
            return nil, err
        }
        for _, db := range resp.DatabaseList {
@@ -43,16 +47,17 @@ func (m *Manager) ListSchemasGlue(connectionName, database string) ([]string, er
 
 // ListTablesGlue lists tables in a Glue database.
 func (m *Manager) ListTablesGlue(connectionName, database, _ string) ([]TableInfo, error) {
-   sess, err := m.glueSession(connectionName)
+   clients, err := m.glueClients(connectionName)
    if err != nil {
        return nil, err
    }
-   svc := glue.New(sess)
+   svc := clients.glue
    input := &glue.GetTablesInput{DatabaseName: aws.String(database)}
    var tables []TableInfo
    for {
        resp, err := svc.GetTables(input)
        if err != nil {
+           m.glueFailed(connectionName, err)
            return nil, err
        }
        for _, t := range resp.TableList {
@@ -72,30 +77,59 @@ func (m *Manager) ListTablesGlue(connectionName, database, _ string) ([]TableInf
 
 // DescribeTableGlue retrieves column definitions for a Glue table.
 func (m *Manager) DescribeTableGlue(connectionName, database, tableName, _ string) ([]ColumnInfo, error) {
-   sess, err := m.glueSession(connectionName)
+   if err := validateIdentifiers("glue", database, tableName); err != nil {
+       return nil, err
+   }
+   clients, err := m.glueClients(connectionName)
    if err != nil {
        return nil, err
    }
-   svc := glue.New(sess)
+   svc := clients.glue
    resp, err := svc.GetTable(&glue.GetTableInput{
        DatabaseName: aws.String(database),
        Name:         aws.String(tableName),
    })
    if err != nil {
+       m.glueFailed(connectionName, err)
        return nil, err
    }
-   sd := resp.Table.StorageDescriptor
+   return glueColumns(resp.Table), nil
+}
+
+// glueColumns lists a Glue table's columns followed by its partition keys,
+// which Glue keeps apart from the storage descriptor. Glue has no
+// nullability, defaults or primary keys.
+func glueColumns(table *glue.TableData) []ColumnInfo {
    var cols []ColumnInfo
-   for _, c := range sd.Columns {
+   add := func(c *glue.Column, partitionKey bool) {
        cols = append(cols, ColumnInfo{
-           Name:         aws.StringValue(c.Name),
-           Type:         aws.StringValue(c.Type),
-           Nullable:     true,
-           DefaultValue: nil,
-           IsPrimaryKey: false,
+           Name:           aws.StringValue(c.Name),
+           Type:           aws.StringValue(c.Type),
+           Nullable:       true,
+           DefaultValue:   nil,
+           IsPrimaryKey:   false,
+           Comment:        aws.StringValue(c.Comment),
+           IsPartitionKey: partitionKey,
        })
    }
-   return cols, nil
+   if table.StorageDescriptor != nil {
+       for _, c := range table.StorageDescriptor.Columns {
+           add(c, false)
+       }
+   }
+   for _, c := range table.PartitionKeys {
+       add(c, true)
+   }
+   return numberColumns(cols)
+}
+
+// DescribeDatabaseGlue summarizes a Glue database from its table listing; size is not available
+func (m *Manager) DescribeDatabaseGlue(connectionName, database string, maxTables int) (*DatabaseSummary, error) {
+   tables, err := m.ListTablesGlue(connectionName, database, "")
+   if err != nil {
+       return nil, err
+   }
+   return summarizeTables(tables, maxTables), nil
 }
 
 // ListIndexesGlue always returns nil since Glue has no indexes.
@@ -103,41 +137,103 @@ func (m *Manager) ListIndexesGlue(connectionName, database, tableName string) ([
    return nil, nil
 }
 
+// ErrGlueSamplingDisabled is returned by GetTableSampleGlue for connections
+// configured with glue_sample_enabled: false
+var ErrGlueSamplingDisabled = errors.New("sampling disabled for this Glue connection")
+
 // GetTableSampleGlue runs an Athena query to sample rows.
-func (m *Manager) GetTableSampleGlue(connectionName, database, tableName string, limit int) (map[string]interface{}, error) {
-   sess, err := m.glueSession(connectionName)
-   if err != nil {
+func (m *Manager) GetTableSampleGlue(ctx context.Context, connectionName, database, tableName string, limit int) (map[string]interface{}, error) {
+   if err := validateIdentifiers("glue", database, tableName); err != nil {
        return nil, err
    }
    
-   // Get Athena S3 output location from config, fallback to environment variable
+   // Check the sampling configuration before prompting for MFA
    conn, exists := m.config.GetConnection(connectionName)
    if !exists {
        return nil, fmt.Errorf("connection %s not found", connectionName)
    }
+   if !conn.GlueSamplingEnabled() {
+       return nil, fmt.Errorf("%w: '%s' has glue_sample_enabled set to false; list and describe tools still work", ErrGlueSamplingDisabled, connectionName)
+   }
+   
+   outLoc, err := athenaOutputLocation(conn)
+   if err != nil {
+       return nil, err
+   }
    
+   table, err := qualifiedName("glue", database, tableName)
+   if err != nil {
+       return nil, err
+   }
+   query := fmt.Sprintf("SELECT * FROM %s LIMIT %d", table, limit)
+   rows, err := m.runAthenaQuery(ctx, connectionName, database, outLoc, query)
+   if err != nil {
+       return nil, err
+   }
+   if len(rows) < 1 {
+       return map[string]interface{}{"columns": []string{}, "rows": []map[string]interface{}{}, "total_sampled": 0}, nil
+   }
+   header := rows[0].Data
+   var cols []string
+   for _, d := range header {
+       cols = append(cols, aws.StringValue(d.VarCharValue))
+   }
+   var outRows []map[string]interface{}
+   for _, r := range rows[1:] {
+       row := make(map[string]interface{}, len(cols))
+       for i, d := range r.Data {
+           if i >= len(cols) {
+               continue
+           }
+           // Athena leaves VarCharValue unset for NULL and sets it to "" for empty strings
+           if d.VarCharValue == nil {
+               row[cols[i]] = nil
+           } else {
+               row[cols[i]] = *d.VarCharValue
+           }
+       }
+       outRows = append(outRows, row)
+   }
+   return m.sampleResult(cols, outRows, nil), nil
+}
+
+// athenaOutputLocation returns where Athena writes a connection's query
+// results: athena_s3_output, or the AWS_ATHENA_S3_OUTPUT environment variable
+func athenaOutputLocation(conn config.Connection) (string, error) {
    outLoc := conn.AthenaS3Output
    if outLoc == "" {
        outLoc = os.Getenv("AWS_ATHENA_S3_OUTPUT")
    }
    if outLoc == "" {
-       return nil, fmt.Errorf("athena_s3_output must be set in connection config or AWS_ATHENA_S3_OUTPUT environment variable for Athena results")
+       return "", fmt.Errorf("athena_s3_output must be set in connection config or AWS_ATHENA_S3_OUTPUT environment variable for Athena results (set glue_sample_enabled: false for metadata-only use)")
+   }
+   return outLoc, nil
+}
+
+// runAthenaQuery runs a query in Athena, waits for it within the query
+// timeout and returns the first page of results, header row first. The query
+// is stopped if ctx is cancelled while it runs.
+func (m *Manager) runAthenaQuery(ctx context.Context, connectionName, database, outLoc, query string) ([]*athena.Row, error) {
+   clients, err := m.glueClients(connectionName)
+   if err != nil {
+       return nil, err
    }
    
-   ath := athena.New(sess)
-   query := fmt.Sprintf("SELECT * FROM \"%s\".\"%s\" LIMIT %d", database, tableName, limit)
-   si, err := ath.StartQueryExecution(&athena.StartQueryExecutionInput{
+   ath := clients.athena
+   m.logQuery(connectionName, "athena", query)
+   si, err := ath.StartQueryExecutionWithContext(ctx, &athena.StartQueryExecutionInput{
        QueryString: aws.String(query),
        QueryExecutionContext: &athena.QueryExecutionContext{Database: aws.String(database)},
        ResultConfiguration:  &athena.ResultConfiguration{OutputLocation: aws.String(outLoc)},
    })
    if err != nil {
+       m.glueFailed(connectionName, err)
        return nil, err
    }
    qid := aws.StringValue(si.QueryExecutionId)
    deadline := time.Now().Add(m.config.Settings.QueryTimeout)
    for {
-       ge, err := ath.GetQueryExecution(&athena.GetQueryExecutionInput{QueryExecutionId: aws.String(qid)})
+       ge, err := ath.GetQueryExecutionWithContext(ctx, &athena.GetQueryExecutionInput{QueryExecutionId: aws.String(qid)})
        if err != nil {
            return nil, err
        }
@@ -151,34 +247,19 @@ func (m *Manager) GetTableSampleGlue(connectionName, database, tableName string,
        if time.Now().After(deadline) {
            return nil, fmt.Errorf("Athena query timed out after %s", m.config.Settings.QueryTimeout)
        }
-       time.Sleep(time.Second)
+       select {
+       case <-ctx.Done():
+           // Stop the Athena query so it doesn't keep scanning after the caller gave up
+           if _, stopErr := ath.StopQueryExecution(&athena.StopQueryExecutionInput{QueryExecutionId: aws.String(qid)}); stopErr != nil {
+               return nil, fmt.Errorf("%w (and failed to stop Athena query %s: %v)", ctx.Err(), qid, stopErr)
+           }
+           return nil, ctx.Err()
+       case <-time.After(time.Second):
+       }
    }
-   gr, err := ath.GetQueryResults(&athena.GetQueryResultsInput{QueryExecutionId: aws.String(qid)})
+   gr, err := ath.GetQueryResultsWithContext(ctx, &athena.GetQueryResultsInput{QueryExecutionId: aws.String(qid)})
    if err != nil {
        return nil, err
    }
-   rows := gr.ResultSet.Rows
-   if len(rows) < 1 {
-       return map[string]interface{}{"columns": []string{}, "rows": []map[string]interface{}{}, "total_sampled": 0}, nil
-   }
-   header := rows[0].Data
-   var cols []string
-   for _, d := range header {
-       cols = append(cols, aws.StringValue(d.VarCharValue))
-   }
-   var outRows []map[string]interface{}
-   for _, r := range rows[1:] {
-       m := make(map[string]interface{}, len(cols))
-       for i, d := range r.Data {
-           if i < len(cols) {
-               m[cols[i]] = aws.StringValue(d.VarCharValue)
-           }
-       }
-       outRows = append(outRows, m)
-   }
-   return map[string]interface{}{
-       "columns":       cols,
-       "rows":          outRows,
-       "total_sampled": len(outRows),
-   }, nil
+   return gr.ResultSet.Rows, nil
 }
\ No newline at end of file
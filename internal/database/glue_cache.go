@@ -0,0 +1,171 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/eliziario/simpledb-mcp/internal/awscreds"
+	"github.com/eliziario/simpledb-mcp/internal/config"
+)
+
+// glueRefreshMargin is how long before its STS credentials expire a cached
+// Glue session is rebuilt, so no call starts on credentials about to lapse
+const glueRefreshMargin = 5 * time.Minute
+
+// glueProfileSessionLifetime is how long a session built from a shared
+// config profile is reused
+const glueProfileSessionLifetime = time.Hour
+
+// glueSessionOptions builds the options of a session that takes credentials,
+// and the region unless the connection sets one, from a shared config
+// profile. An empty profile means AWS_PROFILE or the default profile.
+func glueSessionOptions(conn config.Connection) session.Options {
+	opts := session.Options{
+		Profile:           conn.AWSProfile,
+		SharedConfigState: session.SharedConfigEnable,
+	}
+	if region := conn.AWSRegion(); region != "" {
+		opts.Config.Region = aws.String(region)
+	}
+	return opts
+}
+
+// glueClients is an AWS session for a Glue connection and the service clients built on it
+type glueClients struct {
+	expires time.Time
+	session *session.Session
+	glue    *glue.Glue
+	athena  *athena.Athena
+}
+
+// glueCache keeps one set of clients per connection so repeated tool calls
+// skip rebuilding the session and clients until the credentials near expiry
+type glueCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	clients map[string]*glueClients
+}
+
+func newGlueCache() *glueCache {
+	return &glueCache{
+		now:     time.Now,
+		clients: make(map[string]*glueClients),
+	}
+}
+
+// get returns a connection's clients unless their credentials are near expiry
+func (c *glueCache) get(connectionName string) *glueClients {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clients, ok := c.clients[connectionName]
+	if !ok {
+		return nil
+	}
+	if !c.now().Before(clients.expires.Add(-glueRefreshMargin)) {
+		delete(c.clients, connectionName)
+		return nil
+	}
+	return clients
+}
+
+func (c *glueCache) store(connectionName string, clients *glueClients) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clients[connectionName] = clients
+}
+
+// invalidate drops a connection's clients so the next call rebuilds them
+func (c *glueCache) invalidate(connectionName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clients, connectionName)
+}
+
+// glueClients returns the session and service clients for a Glue connection,
+// reusing them while their STS credentials stay valid. Connections with
+// aws_profile or use_sso build the session from the shared AWS config
+// instead of assuming role_arn with MFA.
+func (m *Manager) glueClients(connectionName string) (*glueClients, error) {
+	if clients := m.glueCache.get(connectionName); clients != nil {
+		return clients, nil
+	}
+
+	connCfg, exists := m.config.GetConnection(connectionName)
+	if !exists {
+		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+	}
+
+	var sess *session.Session
+	var expires time.Time
+	var err error
+	if connCfg.UsesAWSProfile() {
+		if sess, err = session.NewSessionWithOptions(glueSessionOptions(connCfg)); err != nil {
+			return nil, fmt.Errorf("load AWS profile: %w", err)
+		}
+		// The SDK refreshes profile and SSO credentials itself; the session is
+		// only rebuilt now and then to pick up changes to the shared config
+		expires = m.glueCache.now().Add(glueProfileSessionLifetime)
+	} else {
+		creds, err := m.glueCredentials(connectionName, connCfg)
+		if err != nil {
+			return nil, fmt.Errorf("get STS creds: %w", err)
+		}
+		sess, err = session.NewSession(&aws.Config{
+			Region:      aws.String(connCfg.AWSRegion()),
+			Credentials: awscredentials.NewStaticCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
+		})
+		if err != nil {
+			return nil, err
+		}
+		expires = creds.Expiration
+	}
+
+	clients := &glueClients{
+		expires: expires,
+		session: sess,
+		glue:    glue.New(sess),
+		athena:  athena.New(sess),
+	}
+	m.glueCache.store(connectionName, clients)
+	return clients, nil
+}
+
+// stsCredentials returns STS credentials for a Glue connection, refreshing
+// them via MFA when needed. Each connection keeps one provider.
+func (m *Manager) stsCredentials(connectionName string, connCfg config.Connection) (*awscreds.AWSCreds, error) {
+	m.awsMu.Lock()
+	if m.awsProviders == nil {
+		m.awsProviders = make(map[string]*awscreds.STSProvider)
+	}
+	prov, ok := m.awsProviders[connectionName]
+	if !ok {
+		prov = m.newSTSProvider(connectionName, connCfg)
+		m.awsProviders[connectionName] = prov
+	}
+	m.awsMu.Unlock()
+	return prov.Creds()
+}
+
+// glueExpiredCodes are the AWS error codes for credentials that are no longer accepted
+var glueExpiredCodes = map[string]bool{
+	"ExpiredToken":                true,
+	"ExpiredTokenException":       true,
+	"InvalidClientTokenId":        true,
+	"UnrecognizedClientException": true,
+}
+
+// glueFailed forgets the cached clients after a credentials error
+func (m *Manager) glueFailed(connectionName string, err error) {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && glueExpiredCodes[awsErr.Code()] {
+		m.glueCache.invalidate(connectionName)
+	}
+}
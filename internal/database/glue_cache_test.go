@@ -0,0 +1,155 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/eliziario/simpledb-mcp/internal/awscreds"
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+// glueManager returns a manager whose Glue credentials are counted instead of
+// fetched from STS; each fetch returns credentials valid for an hour
+func glueManager(t *testing.T) (*Manager, *int64) {
+	t.Helper()
+	manager := NewManager(glueConfig(nil), testutil.NewMockCredentialManager())
+	t.Cleanup(func() { manager.Close() })
+
+	var fetches int64
+	manager.glueCredentials = func(connectionName string, connCfg config.Connection) (*awscreds.AWSCreds, error) {
+		n := atomic.AddInt64(&fetches, 1)
+		return &awscreds.AWSCreds{
+			AccessKeyID:     fmt.Sprintf("ASIA%d", n),
+			SecretAccessKey: "secret",
+			SessionToken:    "token",
+			Expiration:      manager.glueCache.now().Add(time.Hour),
+		}, nil
+	}
+	return manager, &fetches
+}
+
+func TestGlueClientsReusedWhileCredentialsValid(t *testing.T) {
+	manager, fetches := glueManager(t)
+
+	first, err := manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+	second, err := manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+
+	testutil.AssertEqual(t, int64(1), *fetches)
+	testutil.AssertEqual(t, true, first == second)
+	testutil.AssertEqual(t, "us-east-1", *second.session.Config.Region)
+}
+
+func TestGlueClientsRebuiltNearExpiry(t *testing.T) {
+	manager, fetches := glueManager(t)
+	start := time.Now()
+	manager.glueCache.now = func() time.Time { return start }
+
+	first, err := manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+
+	// Still comfortably valid
+	manager.glueCache.now = func() time.Time { return start.Add(50 * time.Minute) }
+	same, err := manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, first == same)
+
+	// Inside the refresh margin
+	manager.glueCache.now = func() time.Time { return start.Add(56 * time.Minute) }
+	refreshed, err := manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, int64(2), *fetches)
+	testutil.AssertEqual(t, false, first == refreshed)
+}
+
+func TestGlueClientsConcurrentCallers(t *testing.T) {
+	manager, fetches := glueManager(t)
+	_, err := manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := manager.glueClients("test-glue"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	testutil.AssertEqual(t, int64(1), atomic.LoadInt64(fetches))
+}
+
+func TestGlueExpiredTokenInvalidatesClients(t *testing.T) {
+	manager, fetches := glueManager(t)
+	_, err := manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+
+	// Other failures keep the clients
+	manager.glueFailed("test-glue", awserr.New("EntityNotFoundException", "table not found", nil))
+	_, err = manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, int64(1), *fetches)
+
+	manager.glueFailed("test-glue", awserr.New("ExpiredTokenException", "The security token included in the request is expired", nil))
+	_, err = manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, int64(2), *fetches)
+}
+
+func TestGlueClientsUseRegionOverHost(t *testing.T) {
+	manager, _ := glueManager(t)
+	conn := manager.config.Connections["test-glue"]
+	conn.Region = "eu-west-2"
+	manager.config.Connections["test-glue"] = conn
+
+	clients, err := manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "eu-west-2", *clients.session.Config.Region)
+}
+
+func TestGlueSessionOptions(t *testing.T) {
+	opts := glueSessionOptions(config.Connection{Type: "glue", AWSProfile: "analytics-sso", Region: "eu-west-1"})
+	testutil.AssertEqual(t, "analytics-sso", opts.Profile)
+	testutil.AssertEqual(t, session.SharedConfigEnable, opts.SharedConfigState)
+	testutil.AssertEqual(t, "eu-west-1", aws.StringValue(opts.Config.Region))
+
+	// use_sso alone takes the default profile, and the profile's region
+	opts = glueSessionOptions(config.Connection{Type: "glue", UseSSO: true})
+	testutil.AssertEqual(t, "", opts.Profile)
+	testutil.AssertEqual(t, session.SharedConfigEnable, opts.SharedConfigState)
+	testutil.AssertEqual(t, true, opts.Config.Region == nil)
+}
+
+func TestGlueClientsFromProfile(t *testing.T) {
+	dir := testutil.TempDir(t)
+	configFile := filepath.Join(dir, "config")
+	err := os.WriteFile(configFile, []byte("[profile analytics]\nregion = eu-north-1\naws_access_key_id = AKIDEXAMPLE\naws_secret_access_key = secret\n"), 0600)
+	testutil.AssertNoError(t, err)
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+
+	manager, fetches := glueManager(t)
+	manager.config.Connections["test-glue"] = config.Connection{Type: "glue", AWSProfile: "analytics"}
+
+	clients, err := manager.glueClients("test-glue")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "eu-north-1", aws.StringValue(clients.session.Config.Region))
+	creds, err := clients.session.Config.Credentials.Get()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "AKIDEXAMPLE", creds.AccessKeyID)
+
+	// The MFA flow is never used
+	testutil.AssertEqual(t, int64(0), *fetches)
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func glueConfig(sampleEnabled *bool) *config.Config {
+	cfg := testConfig()
+	cfg.Connections["test-glue"] = config.Connection{
+		Type:              "glue",
+		Host:              "us-east-1",
+		RoleArn:           "arn:aws:iam::123456789012:role/Reader",
+		GlueSampleEnabled: sampleEnabled,
+	}
+	return cfg
+}
+
+func TestGlueSamplingDisabled(t *testing.T) {
+	disabled := false
+	manager := NewManager(glueConfig(&disabled), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	// Fails before any AWS call or MFA prompt, and names the setting rather than S3
+	_, err := manager.GetTableSampleGlue(context.Background(), "test-glue", "events", "clicks", 10)
+	testutil.AssertError(t, err)
+	if !errors.Is(err, ErrGlueSamplingDisabled) {
+		t.Errorf("Expected ErrGlueSamplingDisabled, got %v", err)
+	}
+	testutil.AssertContains(t, err.Error(), "sampling disabled for this Glue connection")
+	testutil.AssertContains(t, err.Error(), "glue_sample_enabled")
+}
+
+func TestGlueSamplingWithoutS3Output(t *testing.T) {
+	t.Setenv("AWS_ATHENA_S3_OUTPUT", "")
+	manager := NewManager(glueConfig(nil), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	// Sampling is on by default; a missing result location is reported before MFA
+	_, err := manager.GetTableSampleGlue(context.Background(), "test-glue", "events", "clicks", 10)
+	testutil.AssertError(t, err)
+	if errors.Is(err, ErrGlueSamplingDisabled) {
+		t.Errorf("Expected the S3 configuration error, got %v", err)
+	}
+	testutil.AssertContains(t, err.Error(), "athena_s3_output")
+}
+
+func TestGlueSTSProviderSettings(t *testing.T) {
+	manager := NewManager(glueConfig(nil), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	conn := glueConfig(nil).Connections["test-glue"]
+	prov := manager.newSTSProvider("test-glue", conn)
+	testutil.AssertEqual(t, int64(config.DefaultSTSDurationSeconds), prov.Duration)
+	testutil.AssertEqual(t, "", prov.SourceRoleArn)
+
+	conn.STSDurationSeconds = 1800
+	conn.SourceRoleArn = "arn:aws:iam::999999999999:role/Jump"
+	prov = manager.newSTSProvider("test-glue", conn)
+	testutil.AssertEqual(t, int64(1800), prov.Duration)
+	testutil.AssertEqual(t, conn.RoleArn, prov.RoleArn)
+	testutil.AssertEqual(t, "arn:aws:iam::999999999999:role/Jump", prov.SourceRoleArn)
+}
+
+func TestGlueTOTPSecretSources(t *testing.T) {
+	credManager := testutil.NewMockCredentialManager()
+	manager := NewManager(glueConfig(nil), credManager)
+	defer manager.Close()
+
+	conn := glueConfig(nil).Connections["test-glue"]
+	conn.MFAMode = "totp"
+	prov := manager.newSTSProvider("test-glue", conn)
+	testutil.AssertEqual(t, "totp", prov.MFAMode)
+
+	// Nothing stored yet
+	t.Setenv(TOTPSecretEnv, "")
+	_, err := prov.TOTPSecret()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), TOTPSecretEnv)
+
+	// The keychain entry is used when the environment variable is unset
+	testutil.AssertNoError(t, credManager.Store("test-glue", TOTPSecretUsername, "KEYCHAINSECRET"))
+	secret, err := prov.TOTPSecret()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "KEYCHAINSECRET", secret)
+
+	// The environment variable wins
+	t.Setenv(TOTPSecretEnv, "ENVSECRET")
+	secret, err = prov.TOTPSecret()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "ENVSECRET", secret)
+}
+
+func TestGlueColumnsIncludeCommentsAndPartitionKeys(t *testing.T) {
+	// Shaped like a GetTable response for a table partitioned by date
+	resp := &glue.GetTableOutput{Table: &glue.TableData{
+		Name: aws.String("clicks"),
+		StorageDescriptor: &glue.StorageDescriptor{Columns: []*glue.Column{
+			{Name: aws.String("user_id"), Type: aws.String("bigint"), Comment: aws.String("Account that clicked")},
+			{Name: aws.String("url"), Type: aws.String("string")},
+		}},
+		PartitionKeys: []*glue.Column{
+			{Name: aws.String("dt"), Type: aws.String("string"), Comment: aws.String("Partition date, yyyy-mm-dd")},
+		},
+	}}
+
+	cols := glueColumns(resp.Table)
+	testutil.AssertEqual(t, 3, len(cols))
+
+	testutil.AssertEqual(t, "user_id", cols[0].Name)
+	testutil.AssertEqual(t, "Account that clicked", cols[0].Comment)
+	testutil.AssertEqual(t, false, cols[0].IsPartitionKey)
+
+	testutil.AssertEqual(t, "url", cols[1].Name)
+	testutil.AssertEqual(t, "", cols[1].Comment)
+	testutil.AssertEqual(t, false, cols[1].IsPartitionKey)
+
+	testutil.AssertEqual(t, "dt", cols[2].Name)
+	testutil.AssertEqual(t, "string", cols[2].Type)
+	testutil.AssertEqual(t, "Partition date, yyyy-mm-dd", cols[2].Comment)
+	testutil.AssertEqual(t, true, cols[2].IsPartitionKey)
+
+	// Partition keys come last, as in SELECT *
+	testutil.AssertEqual(t, 1, cols[0].Ordinal)
+	testutil.AssertEqual(t, 2, cols[1].Ordinal)
+	testutil.AssertEqual(t, 3, cols[2].Ordinal)
+
+	// Views and some external tables have no storage descriptor
+	cols = glueColumns(&glue.TableData{PartitionKeys: resp.Table.PartitionKeys})
+	testutil.AssertEqual(t, 1, len(cols))
+	testutil.AssertEqual(t, 1, cols[0].Ordinal)
+}
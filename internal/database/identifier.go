@@ -0,0 +1,104 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidIdentifier is wrapped by the errors for database, schema and table
+// names that fail validation
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+// identifierQuotes maps each engine to the character it uses to quote identifiers.
+// Salesforce SOQL has no quoted identifiers, so names are used as-is.
+var identifierQuotes = map[string]string{
+	"mysql":      "`",
+	"postgres":   `"`,
+	"glue":       `"`,
+	"salesforce": "",
+}
+
+// identifierRule describes the names an engine accepts for databases, schemas and tables
+type identifierRule struct {
+	pattern   *regexp.Regexp
+	maxLength int
+}
+
+// identifierRules are deliberately stricter than what each engine allows with
+// quoting: letters, digits, underscores and the few characters commonly found
+// in real object names (single inner spaces and hyphens, MySQL/Postgres '$').
+var identifierRules = map[string]identifierRule{
+	"mysql":      {regexp.MustCompile(`^[\p{L}\p{N}_$]+([ -][\p{L}\p{N}_$]+)*$`), 64},
+	"postgres":   {regexp.MustCompile(`^[\p{L}\p{N}_$]+([ -][\p{L}\p{N}_$]+)*$`), 63},
+	"glue":       {regexp.MustCompile(`^[A-Za-z0-9_-]+$`), 255},
+	"salesforce": {regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`), 80},
+}
+
+// validateIdentifier checks that a name matches the engine's allowed pattern
+func validateIdentifier(engine, name string) error {
+	rule, supported := identifierRules[engine]
+	if !supported {
+		return fmt.Errorf("unsupported database type: %s", engine)
+	}
+	if name == "" {
+		return fmt.Errorf("%w: cannot be empty", ErrInvalidIdentifier)
+	}
+	if utf8.RuneCountInString(name) > rule.maxLength {
+		return fmt.Errorf("%w %q: longer than %d characters", ErrInvalidIdentifier, name, rule.maxLength)
+	}
+	if !rule.pattern.MatchString(name) {
+		return fmt.Errorf("%w %q: contains characters not allowed for %s", ErrInvalidIdentifier, name, engine)
+	}
+	return nil
+}
+
+// validateIdentifiers validates every non-empty name; empty names are
+// optional arguments the caller fills in with its own default.
+func validateIdentifiers(engine string, names ...string) error {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if err := validateIdentifier(engine, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quoteIdentifier quotes a table, schema or database name for the given engine.
+// Names containing quote characters are rejected rather than escaped so a
+// crafted name can never break out of the quoted identifier, and names that
+// fail validateIdentifier are rejected as well.
+func quoteIdentifier(engine, name string) (string, error) {
+	quote, supported := identifierQuotes[engine]
+	if !supported {
+		return "", fmt.Errorf("unsupported database type: %s", engine)
+	}
+	if name == "" {
+		return "", fmt.Errorf("%w: cannot be empty", ErrInvalidIdentifier)
+	}
+	if strings.ContainsAny(name, "`\"'\x00") {
+		return "", fmt.Errorf("%w %q: quote characters are not allowed", ErrInvalidIdentifier, name)
+	}
+	if err := validateIdentifier(engine, name); err != nil {
+		return "", err
+	}
+	return quote + name + quote, nil
+}
+
+// qualifiedName quotes each part of a dotted object reference, e.g. schema.table
+func qualifiedName(engine string, parts ...string) (string, error) {
+	quoted := make([]string, len(parts))
+	for i, part := range parts {
+		q, err := quoteIdentifier(engine, part)
+		if err != nil {
+			return "", err
+		}
+		quoted[i] = q
+	}
+	return strings.Join(quoted, "."), nil
+}
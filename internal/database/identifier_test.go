@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		engine   string
+		name     string
+		expected string
+	}{
+		{"mysql", "users", "`users`"},
+		{"mysql", "order items", "`order items`"},
+		{"mysql", "select", "`select`"},
+		{"postgres", "users", `"users"`},
+		{"postgres", "Order Items", `"Order Items"`},
+		{"postgres", "table", `"table"`},
+		{"glue", "events", `"events"`},
+		{"salesforce", "Account", "Account"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engine+"/"+tt.name, func(t *testing.T) {
+			quoted, err := quoteIdentifier(tt.engine, tt.name)
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, tt.expected, quoted)
+		})
+	}
+}
+
+func TestQuoteIdentifierRejectsQuotes(t *testing.T) {
+	malicious := []string{
+		"users`; DROP TABLE users; --",
+		`users"; DROP TABLE users; --`,
+		"users' OR '1'='1",
+		"users\x00",
+		"",
+	}
+
+	for _, engine := range []string{"mysql", "postgres", "glue", "salesforce"} {
+		for _, name := range malicious {
+			_, err := quoteIdentifier(engine, name)
+			testutil.AssertError(t, err)
+		}
+	}
+}
+
+func TestQuoteIdentifierUnsupportedEngine(t *testing.T) {
+	_, err := quoteIdentifier("oracle", "users")
+	testutil.AssertError(t, err)
+}
+
+func TestQualifiedName(t *testing.T) {
+	name, err := qualifiedName("mysql", "sales db", "order")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "`sales db`.`order`", name)
+
+	name, err = qualifiedName("postgres", "public", "user")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, `"public"."user"`, name)
+
+	_, err = qualifiedName("postgres", "public", `x"."y`)
+	testutil.AssertError(t, err)
+}
+
+func TestValidateIdentifier(t *testing.T) {
+	valid := map[string][]string{
+		"mysql":      {"users", "order_items", "order items", "sales-2024", "tbl$1", "café"},
+		"postgres":   {"users", "Order Items", "public", "tbl$1"},
+		"glue":       {"events", "raw_events", "raw-events"},
+		"salesforce": {"Account", "Custom_Object__c"},
+	}
+	for engine, names := range valid {
+		for _, name := range names {
+			testutil.AssertNoError(t, validateIdentifier(engine, name))
+		}
+	}
+
+	invalid := map[string][]string{
+		"mysql":      {"users`; DROP TABLE ", "users;", "a.b", " users", "users ", "a  b", "users--", "users/*"},
+		"postgres":   {`users"; DROP TABLE x; --`, "users;", "a.b", "users\n", "x'"},
+		"glue":       {`events"`, "events;", "events table", "a.b"},
+		"salesforce": {"Account WHERE Id != null", "Account;", "1Account", "../limits"},
+	}
+	for engine, names := range invalid {
+		for _, name := range names {
+			testutil.AssertError(t, validateIdentifier(engine, name))
+		}
+	}
+
+	testutil.AssertError(t, validateIdentifier("mysql", strings.Repeat("a", 65)))
+	testutil.AssertError(t, validateIdentifier("postgres", strings.Repeat("a", 64)))
+}
+
+func TestSampleAndDescribeRejectMaliciousIdentifiers(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	ctx := context.Background()
+	malicious := "users`; DROP TABLE "
+
+	_, err := manager.GetTableSampleMySQL(ctx, "test-mysql", "testdb", malicious, 10)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid identifier")
+
+	_, err = manager.GetTableSampleMySQL(ctx, "test-mysql", malicious, "users", 10)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid identifier")
+
+	_, err = manager.DescribeTableMySQL("test-mysql", "testdb", malicious)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid identifier")
+
+	_, err = manager.GetTableSamplePostgres(ctx, "test-postgres", "testdb", `users"; DROP TABLE users; --`, "public", 10)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid identifier")
+
+	_, err = manager.DescribeTablePostgres("test-postgres", "testdb", "users", `public"; --`)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid identifier")
+
+	_, err = manager.GetTableSampleGlue(ctx, "test-glue", "db", malicious, 10)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid identifier")
+
+	_, err = manager.GetTableSampleSalesforce(ctx, "test-sf", "Account LIMIT 1; --", 10)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid identifier")
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IndexUsage is how often an index has been read since the server's
+// statistics were last reset
+type IndexUsage struct {
+	Table     string `json:"table"`
+	Index     string `json:"index"`
+	Scans     int64  `json:"scans"`
+	RowsRead  int64  `json:"rows_read"`
+	SizeBytes int64  `json:"size_bytes,omitempty"` // Postgres only
+	Unique    bool   `json:"unique,omitempty"`
+	Primary   bool   `json:"primary,omitempty"`
+	Unused    bool   `json:"unused,omitempty"`
+	Hint      string `json:"hint,omitempty"`
+}
+
+// IndexUsageReport is the result of an index usage lookup. Servers that
+// don't expose statistics to the connection's user give an empty report
+// with StatsAvailable false and the reason, rather than an error.
+type IndexUsageReport struct {
+	StatsAvailable bool         `json:"stats_available"`
+	Reason         string       `json:"reason,omitempty"`
+	Indexes        []IndexUsage `json:"indexes"`
+}
+
+const postgresIndexUsageQuery = `
+		SELECT s.relname, s.indexrelname, s.idx_scan, s.idx_tup_read, pg_relation_size(s.indexrelid), i.indisunique, i.indisprimary
+		FROM pg_stat_user_indexes s
+		JOIN pg_index i ON i.indexrelid = s.indexrelid
+		WHERE s.schemaname = $1`
+
+// Rows without INDEX_NAME count full table scans, not index reads
+const mysqlIndexUsageQuery = `
+		SELECT u.OBJECT_NAME, u.INDEX_NAME, u.COUNT_READ, u.COUNT_FETCH, 0,
+			MIN(st.NON_UNIQUE) = 0, u.INDEX_NAME = 'PRIMARY'
+		FROM performance_schema.table_io_waits_summary_by_index_usage u
+		JOIN INFORMATION_SCHEMA.STATISTICS st
+			ON st.TABLE_SCHEMA = u.OBJECT_SCHEMA AND st.TABLE_NAME = u.OBJECT_NAME AND st.INDEX_NAME = u.INDEX_NAME
+		WHERE u.OBJECT_SCHEMA = ? AND u.INDEX_NAME IS NOT NULL`
+
+const mysqlIndexUsageGroupBy = `
+		GROUP BY u.OBJECT_NAME, u.INDEX_NAME, u.COUNT_READ, u.COUNT_FETCH`
+
+const mysqlPerformanceSchemaQuery = "SELECT @@performance_schema"
+
+// indexUsageQuery builds the usage query of an engine for a schema (Postgres)
+// or database (MySQL), limited to one table unless table is empty
+func indexUsageQuery(engine, namespace, table string) (string, []interface{}) {
+	var query string
+	args := []interface{}{namespace}
+	switch engine {
+	case "postgres":
+		query = postgresIndexUsageQuery
+		if table != "" {
+			query += " AND s.relname = $2"
+			args = append(args, table)
+		}
+		query += "\n\t\tORDER BY s.relname, s.indexrelname"
+	case "mysql":
+		query = mysqlIndexUsageQuery
+		if table != "" {
+			query += " AND u.OBJECT_NAME = ?"
+			args = append(args, table)
+		}
+		query += mysqlIndexUsageGroupBy + "\n\t\tORDER BY u.OBJECT_NAME, u.INDEX_NAME"
+	}
+	return query, args
+}
+
+// flagUnusedIndexes marks indexes that have never been scanned. Indexes
+// backing a primary key or unique constraint are flagged too, since nothing
+// reads them, but the hint says they can't simply be dropped.
+func flagUnusedIndexes(usage []IndexUsage) {
+	for i := range usage {
+		if usage[i].Scans > 0 {
+			continue
+		}
+		usage[i].Unused = true
+		switch {
+		case usage[i].Primary:
+			usage[i].Hint = "never scanned, but it backs the primary key; keep it"
+		case usage[i].Unique:
+			usage[i].Hint = "never scanned, but it enforces uniqueness; drop it only if that constraint isn't needed"
+		default:
+			usage[i].Hint = "never scanned since statistics were last reset; a candidate for dropping once replicas and the reset time are checked"
+		}
+	}
+}
+
+// GetIndexUsagePostgres reports index scans from pg_stat_user_indexes for a
+// schema (default public), optionally limited to one table
+func (m *Manager) GetIndexUsagePostgres(ctx context.Context, connectionName, database, schema, tableName string) (*IndexUsageReport, error) {
+	if err := validateIdentifiers("postgres", database, schema, tableName); err != nil {
+		return nil, err
+	}
+	if schema == "" {
+		schema = "public"
+	}
+	query, args := indexUsageQuery("postgres", schema, tableName)
+	return m.indexUsage(ctx, connectionName, query, args)
+}
+
+// GetIndexUsageMySQL reports index reads from performance_schema for a
+// database, optionally limited to one table
+func (m *Manager) GetIndexUsageMySQL(ctx context.Context, connectionName, database, tableName string) (*IndexUsageReport, error) {
+	if err := validateIdentifiers("mysql", database, tableName); err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+	var enabled int
+	if err := m.queryRow(ctx, db, connectionName, mysqlPerformanceSchemaQuery).Scan(&enabled); err != nil {
+		return nil, fmt.Errorf("failed to check performance_schema: %w", err)
+	}
+	if enabled == 0 {
+		return &IndexUsageReport{Reason: "performance_schema is disabled on this server", Indexes: []IndexUsage{}}, nil
+	}
+
+	query, args := indexUsageQuery("mysql", database, tableName)
+	return m.indexUsage(ctx, connectionName, query, args)
+}
+
+// indexUsage runs a usage query and flags unused indexes. A permission error
+// yields an empty report instead of failing.
+func (m *Manager) indexUsage(ctx context.Context, connectionName, query string, args []interface{}) (*IndexUsageReport, error) {
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	rows, err := m.query(ctx, db, connectionName, query, args...)
+	if err != nil {
+		if ClassifyFailure(err) == FailurePermission {
+			return &IndexUsageReport{Reason: fmt.Sprintf("the connection's user can't read index statistics: %v", err), Indexes: []IndexUsage{}}, nil
+		}
+		return nil, fmt.Errorf("failed to read index usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []IndexUsage{}
+	for rows.Next() {
+		var u IndexUsage
+		if err := rows.Scan(&u.Table, &u.Index, &u.Scans, &u.RowsRead, &u.SizeBytes, &u.Unique, &u.Primary); err != nil {
+			return nil, fmt.Errorf("failed to scan index usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read index usage: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(usage), time.Since(start))
+
+	flagUnusedIndexes(usage)
+	return &IndexUsageReport{StatsAvailable: true, Indexes: usage}, nil
+}
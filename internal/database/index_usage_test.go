@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/lib/pq"
+)
+
+func TestIndexUsageQuery(t *testing.T) {
+	query, args := indexUsageQuery("postgres", "public", "")
+	testutil.AssertEqual(t, 1, len(args))
+	testutil.AssertContains(t, query, "FROM pg_stat_user_indexes s")
+	testutil.AssertEqual(t, false, strings.Contains(query, "$2"))
+
+	query, args = indexUsageQuery("postgres", "billing", "invoices")
+	testutil.AssertEqual(t, "billing,invoices", joinArgs(args))
+	testutil.AssertContains(t, query, "WHERE s.schemaname = $1 AND s.relname = $2\n\t\tORDER BY")
+
+	query, args = indexUsageQuery("mysql", "shop", "orders")
+	testutil.AssertEqual(t, "shop,orders", joinArgs(args))
+	testutil.AssertContains(t, query, "FROM performance_schema.table_io_waits_summary_by_index_usage u")
+	testutil.AssertContains(t, query, "u.INDEX_NAME IS NOT NULL AND u.OBJECT_NAME = ?\n\t\tGROUP BY")
+	testutil.AssertContains(t, query, "ORDER BY u.OBJECT_NAME, u.INDEX_NAME")
+}
+
+func joinArgs(args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = arg.(string)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestFlagUnusedIndexes(t *testing.T) {
+	usage := []IndexUsage{
+		{Index: "orders_pkey", Primary: true, Unique: true},
+		{Index: "orders_ref_key", Unique: true},
+		{Index: "orders_created", Scans: 0},
+		{Index: "orders_customer", Scans: 42},
+	}
+	flagUnusedIndexes(usage)
+
+	testutil.AssertEqual(t, true, usage[0].Unused)
+	testutil.AssertContains(t, usage[0].Hint, "primary key")
+	testutil.AssertContains(t, usage[1].Hint, "uniqueness")
+	testutil.AssertEqual(t, true, usage[2].Unused)
+	testutil.AssertContains(t, usage[2].Hint, "candidate for dropping")
+	testutil.AssertEqual(t, false, usage[3].Unused)
+	testutil.AssertEqual(t, "", usage[3].Hint)
+}
+
+func TestGetIndexUsagePostgres(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-postgres")
+	query, args := indexUsageQuery("postgres", "public", "orders")
+	db.SetQueryResultForArgs(query, args, []string{"relname", "indexrelname", "idx_scan", "idx_tup_read", "size", "indisunique", "indisprimary"}, [][]interface{}{
+		{"orders", "orders_created", int64(0), int64(0), int64(8192), false, false},
+		{"orders", "orders_pkey", int64(310), int64(512), int64(16384), true, true},
+	})
+
+	report, err := manager.GetIndexUsagePostgres(context.Background(), "test-postgres", "testdb", "", "orders")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, report.StatsAvailable)
+	testutil.AssertEqual(t, 2, len(report.Indexes))
+	testutil.AssertEqual(t, true, report.Indexes[0].Unused)
+	testutil.AssertEqual(t, int64(8192), report.Indexes[0].SizeBytes)
+	testutil.AssertEqual(t, false, report.Indexes[1].Unused)
+	testutil.AssertEqual(t, int64(310), report.Indexes[1].Scans)
+}
+
+func TestGetIndexUsageWithoutStats(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	// A user who can't read the statistics gets an empty report
+	pg := addMockConnection(t, manager.pool, "test-postgres")
+	pg.SetQueryFails(true, &pq.Error{Code: "42501", Message: "permission denied for view pg_stat_user_indexes"})
+	report, err := manager.GetIndexUsagePostgres(context.Background(), "test-postgres", "testdb", "", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, false, report.StatsAvailable)
+	testutil.AssertEqual(t, 0, len(report.Indexes))
+	testutil.AssertContains(t, report.Reason, "can't read index statistics")
+
+	// So does a MySQL server with performance_schema off
+	my := addMockConnection(t, manager.pool, "test-mysql")
+	my.SetQueryResult(mysqlPerformanceSchemaQuery, []string{"@@performance_schema"}, [][]interface{}{{int64(0)}})
+	report, err = manager.GetIndexUsageMySQL(context.Background(), "test-mysql", "shop", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, false, report.StatsAvailable)
+	testutil.AssertContains(t, report.Reason, "performance_schema is disabled")
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sqlToken is a significant piece of a query: a word, number or symbol.
+// Whitespace, comments and quoted strings/identifiers are skipped over.
+type sqlToken struct {
+	text  string
+	start int
+	end   int
+	depth int // parenthesis nesting level; 0 is the outer query
+}
+
+// tokenizeSQL splits a query into tokens, tracking parenthesis depth so
+// clauses in subqueries can be told apart from the outer query's. '#' starts
+// a comment only in MySQL; in Postgres it is an operator.
+func tokenizeSQL(engine, query string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	depth := 0
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(query[i:], "--"), c == '#' && engine == "mysql":
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 1
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			// Quoted strings and identifiers; a doubled quote or backslash escapes
+			j := i + 1
+			for ; j < len(query); j++ {
+				if query[j] == '\\' && c == '\'' {
+					j++
+				} else if query[j] == c {
+					if j+1 < len(query) && query[j+1] == c {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(query) {
+				return nil, fmt.Errorf("unterminated quoted string")
+			}
+			tokens = append(tokens, sqlToken{text: query[i : j+1], start: i, end: j + 1, depth: depth})
+			i = j + 1
+		case c == '(':
+			tokens = append(tokens, sqlToken{text: "(", start: i, end: i + 1, depth: depth})
+			depth++
+			i++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+			tokens = append(tokens, sqlToken{text: ")", start: i, end: i + 1, depth: depth})
+			i++
+		case isWordByte(c):
+			j := i
+			for j < len(query) && isWordByte(query[j]) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{text: query[i:j], start: i, end: j, depth: depth})
+			i = j
+		default:
+			tokens = append(tokens, sqlToken{text: query[i : i+1], start: i, end: i + 1, depth: depth})
+			i++
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	return tokens, nil
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// LimitQuery makes sure a read query returns at most maxRows rows. A query
+// without a LIMIT on its outer SELECT gets one appended; an existing outer
+// LIMIT (or Postgres FETCH FIRST) larger than maxRows is lowered. LIMITs inside
+// subqueries are left alone. Statements other than SELECT/WITH, and a
+// maxRows of 0 or less, leave the query unchanged. MySQL, Postgres and Athena
+// all use LIMIT; the engine only affects how comments are recognized.
+func LimitQuery(engine, query string, maxRows int) (string, error) {
+	tokens, err := tokenizeSQL(engine, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse query: %w", err)
+	}
+	if maxRows <= 0 || len(tokens) == 0 {
+		return query, nil
+	}
+	switch strings.ToUpper(tokens[0].text) {
+	case "SELECT", "WITH", "(":
+	default:
+		return query, nil
+	}
+
+	// Ignore a trailing semicolon; anything after it is a second statement
+	last := len(tokens) - 1
+	for i, tok := range tokens {
+		if tok.text == ";" {
+			if i != len(tokens)-1 {
+				return "", fmt.Errorf("multiple statements are not allowed")
+			}
+			last = i - 1
+		}
+	}
+
+	for i := 0; i <= last; i++ {
+		tok := tokens[i]
+		if tok.depth != 0 {
+			continue
+		}
+		switch strings.ToUpper(tok.text) {
+		case "LIMIT":
+			// MySQL's LIMIT offset, count puts the row count second
+			count := i + 1
+			if count+1 <= last && tokens[count+1].text == "," {
+				count += 2
+			}
+			return capLimit(query, tokens, count, last, maxRows)
+		case "FETCH":
+			// FETCH { FIRST | NEXT } [ count ] { ROW | ROWS } ONLY
+			count := i + 2
+			if count <= last && !strings.HasPrefix(strings.ToUpper(tokens[count].text), "ROW") {
+				return capLimit(query, tokens, count, last, maxRows)
+			}
+			// No count means one row
+			return query, nil
+		}
+	}
+
+	// No outer limit: add one after the last significant token so a trailing
+	// comment or semicolon can't swallow it, or before a locking clause
+	// (FOR UPDATE/SHARE, LOCK IN SHARE MODE), which must come last
+	end := tokens[last].end
+	for i := 0; i <= last; i++ {
+		word := strings.ToUpper(tokens[i].text)
+		if tokens[i].depth == 0 && (word == "FOR" || word == "LOCK") && i > 0 {
+			end = tokens[i-1].end
+			break
+		}
+	}
+	return query[:end] + fmt.Sprintf(" LIMIT %d", maxRows) + query[end:], nil
+}
+
+// capLimit lowers the row count token at index count to maxRows if it is larger
+func capLimit(query string, tokens []sqlToken, count, last, maxRows int) (string, error) {
+	if count > last {
+		return "", fmt.Errorf("LIMIT is missing a row count")
+	}
+	tok := tokens[count]
+	if strings.EqualFold(tok.text, "ALL") {
+		return query[:tok.start] + strconv.Itoa(maxRows) + query[tok.end:], nil
+	}
+	n, err := strconv.Atoi(tok.text)
+	if err != nil {
+		return "", fmt.Errorf("LIMIT must be a number, got '%s'", tok.text)
+	}
+	if n <= maxRows {
+		return query, nil
+	}
+	return query[:tok.start] + strconv.Itoa(maxRows) + query[tok.end:], nil
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestLimitQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		engine   string
+		query    string
+		expected string
+	}{
+		// Injection
+		{"no limit", "mysql", "SELECT * FROM users", "SELECT * FROM users LIMIT 100"},
+		{"trailing semicolon", "postgres", "SELECT * FROM users;", "SELECT * FROM users LIMIT 100;"},
+		{"trailing line comment", "postgres", "SELECT * FROM users -- all of them", "SELECT * FROM users LIMIT 100 -- all of them"},
+		{"trailing hash comment", "mysql", "SELECT * FROM users # all", "SELECT * FROM users LIMIT 100 # all"},
+		{"limit in string", "mysql", "SELECT * FROM logs WHERE msg = 'LIMIT 5'", "SELECT * FROM logs WHERE msg = 'LIMIT 5' LIMIT 100"},
+		{"limit in comment", "postgres", "SELECT /* LIMIT 5 */ id FROM t", "SELECT /* LIMIT 5 */ id FROM t LIMIT 100"},
+		{"before locking clause", "mysql", "SELECT * FROM t WHERE id > 1 FOR UPDATE", "SELECT * FROM t WHERE id > 1 LIMIT 100 FOR UPDATE"},
+		{"union", "postgres", "SELECT a FROM x UNION ALL SELECT a FROM y", "SELECT a FROM x UNION ALL SELECT a FROM y LIMIT 100"},
+
+		// Subquery limits are left alone and the outer query still gets one
+		{"subquery limit", "mysql",
+			"SELECT * FROM (SELECT * FROM users ORDER BY id LIMIT 5000) u",
+			"SELECT * FROM (SELECT * FROM users ORDER BY id LIMIT 5000) u LIMIT 100"},
+		{"cte limit", "postgres",
+			"WITH recent AS (SELECT * FROM orders LIMIT 10) SELECT * FROM recent",
+			"WITH recent AS (SELECT * FROM orders LIMIT 10) SELECT * FROM recent LIMIT 100"},
+		{"in subquery", "mysql",
+			"SELECT * FROM a WHERE id IN (SELECT a_id FROM b LIMIT 3) LIMIT 20",
+			"SELECT * FROM a WHERE id IN (SELECT a_id FROM b LIMIT 3) LIMIT 20"},
+
+		// Capping
+		{"smaller limit kept", "mysql", "SELECT * FROM users LIMIT 10", "SELECT * FROM users LIMIT 10"},
+		{"larger limit capped", "postgres", "SELECT * FROM users LIMIT 50000 OFFSET 10", "SELECT * FROM users LIMIT 100 OFFSET 10"},
+		{"mysql offset, count", "mysql", "SELECT * FROM users LIMIT 20, 5000", "SELECT * FROM users LIMIT 20, 100"},
+		{"limit all", "postgres", "SELECT * FROM users LIMIT ALL", "SELECT * FROM users LIMIT 100"},
+		{"fetch first", "postgres", "SELECT * FROM users FETCH FIRST 500 ROWS ONLY", "SELECT * FROM users FETCH FIRST 100 ROWS ONLY"},
+		{"lowercase", "glue", "select * from events limit 999", "select * from events limit 100"},
+
+		// Not a SELECT
+		{"show", "mysql", "SHOW TABLES", "SHOW TABLES"},
+		{"explain", "postgres", "EXPLAIN SELECT * FROM users", "EXPLAIN SELECT * FROM users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limited, err := LimitQuery(tt.engine, tt.query, 100)
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, tt.expected, limited)
+		})
+	}
+}
+
+func TestLimitQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"SELECT * FROM users LIMIT $1",
+		"SELECT * FROM users LIMIT",
+		"SELECT * FROM users; DELETE FROM users",
+		"SELECT * FROM (SELECT 1",
+		"SELECT 'unterminated",
+	} {
+		if _, err := LimitQuery("postgres", query, 100); err == nil {
+			t.Errorf("Expected error for %q", query)
+		}
+	}
+}
+
+func TestLimitQueryDisabled(t *testing.T) {
+	limited, err := LimitQuery("mysql", "SELECT * FROM users", 0)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "SELECT * FROM users", limited)
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"log"
+	"sort"
+	"time"
+)
+
+// startMetricsLogger logs a metrics snapshot every interval until the pool is closed
+func (p *ConnectionPool) startMetricsLogger(interval time.Duration) {
+	p.metricsDone = make(chan struct{})
+	go func() {
+		defer close(p.metricsDone)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		log.Printf("Logging pool metrics every %s", interval)
+
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+				p.logMetrics()
+			}
+		}
+	}()
+}
+
+// logMetrics logs the pool totals and then one line per pooled connection
+func (p *ConnectionPool) logMetrics() {
+	metrics := p.GetPoolMetrics()
+	log.Printf("Pool metrics: %d pooled (%d connected, %d in error), %d opened, pings %d ok/%d failed, %d queries, %d rows, %s query time",
+		metrics.ActiveConnections, metrics.ConnectedCount, metrics.ErrorCount, metrics.TotalConnections,
+		metrics.SuccessfulPings, metrics.FailedPings,
+		metrics.TotalQueries, metrics.TotalRowsReturned, metrics.TotalQueryTime.Round(time.Millisecond))
+
+	statuses := p.GetAllConnectionStatus()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	for _, status := range statuses {
+		line := "Connection '%s': %s, idle %s, %d queries, %d rows, %d errors"
+		args := []interface{}{status.Name, status.State, status.IdleTime.Round(time.Second),
+			status.QueriesRun, status.RowsReturned, status.ErrorCount}
+		if status.LastErrorKind != "" {
+			line += ", last error %s"
+			args = append(args, status.LastErrorKind)
+		}
+		log.Printf(line, args...)
+	}
+}
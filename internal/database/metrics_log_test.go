@@ -0,0 +1,64 @@
+package database
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+// syncBuffer is a log destination that can be read while a goroutine writes to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestMetricsLoggerEmitsAndStopsOnClose(t *testing.T) {
+	logs := &syncBuffer{}
+	original := log.Writer()
+	log.SetOutput(logs)
+	t.Cleanup(func() {
+		log.SetOutput(original)
+	})
+
+	cfg := testConfig()
+	cfg.Settings.ConnectionPool.EnableKeepalive = false
+	cfg.Settings.MetricsLogInterval = 10 * time.Millisecond
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	addMockConnection(t, manager.pool, "test-mysql")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(logs.String(), "Connection 'test-mysql'") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	testutil.AssertContains(t, logs.String(), "Pool metrics: 1 pooled (1 connected, 0 in error)")
+	testutil.AssertContains(t, logs.String(), "Connection 'test-mysql': connected")
+
+	// Close waits for the logger, so nothing is logged afterwards
+	testutil.AssertNoError(t, manager.Close())
+	snapshots := strings.Count(logs.String(), "Pool metrics:")
+	time.Sleep(50 * time.Millisecond)
+	testutil.AssertEqual(t, snapshots, strings.Count(logs.String(), "Pool metrics:"))
+}
+
+func TestMetricsLoggerOffByDefault(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+	testutil.AssertEqual(t, true, manager.pool.metricsDone == nil)
+}
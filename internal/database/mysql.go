@@ -1,9 +1,11 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -31,13 +33,23 @@ func cleanTextForJSON(text string) string {
 	return text
 }
 
+// mysqlSystemDatabases are the schemas MySQL creates for itself
+var mysqlSystemDatabases = map[string]bool{
+	"information_schema": true,
+	"performance_schema": true,
+	"mysql":              true,
+	"sys":                true,
+}
+
 func (m *Manager) ListDatabasesMySQL(connectionName string) ([]string, error) {
 	db, err := m.GetConnection(connectionName)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := db.Query("SHOW DATABASES")
+	start := time.Now()
+
+	rows, err := m.query(context.Background(), db, connectionName, "SHOW DATABASES")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list databases: %w", err)
 	}
@@ -49,25 +61,32 @@ func (m *Manager) ListDatabasesMySQL(connectionName string) ([]string, error) {
 		if err := rows.Scan(&dbName); err != nil {
 			return nil, fmt.Errorf("failed to scan database name: %w", err)
 		}
+		if m.config.Settings.HideSystemDatabases && mysqlSystemDatabases[strings.ToLower(dbName)] {
+			continue
+		}
 		databases = append(databases, dbName)
 	}
 
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(databases), time.Since(start))
+
 	return databases, nil
 }
 
+const mysqlListTablesQuery = `
+		SELECT TABLE_NAME, TABLE_TYPE, IFNULL(TABLE_ROWS, 0) as TABLE_ROWS
+		FROM INFORMATION_SCHEMA.TABLES 
+		WHERE TABLE_SCHEMA = ? 
+		ORDER BY TABLE_NAME`
+
 func (m *Manager) ListTablesMySQL(connectionName, database string) ([]TableInfo, error) {
 	db, err := m.GetConnection(connectionName)
 	if err != nil {
 		return nil, err
 	}
 
-	query := `
-		SELECT TABLE_NAME, TABLE_TYPE, IFNULL(TABLE_ROWS, 0) as TABLE_ROWS
-		FROM INFORMATION_SCHEMA.TABLES 
-		WHERE TABLE_SCHEMA = ? 
-		ORDER BY TABLE_NAME`
+	start := time.Now()
 
-	rows, err := db.Query(query, database)
+	rows, err := m.query(context.Background(), db, connectionName, mysqlListTablesQuery, database)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tables: %w", err)
 	}
@@ -86,16 +105,44 @@ func (m *Manager) ListTablesMySQL(connectionName, database string) ([]TableInfo,
 		tables = append(tables, table)
 	}
 
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(tables), time.Since(start))
+
 	return tables, nil
 }
 
-func (m *Manager) DescribeTableMySQL(connectionName, database, tableName string) ([]ColumnInfo, error) {
+// DescribeDatabaseMySQL summarizes a database's tables, views, row estimates and size
+func (m *Manager) DescribeDatabaseMySQL(connectionName, database string, maxTables int) (*DatabaseSummary, error) {
+	tables, err := m.ListTablesMySQL(connectionName, database)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := m.GetConnection(connectionName)
 	if err != nil {
 		return nil, err
 	}
 
+	start := time.Now()
+
+	// Total data and index size across the database in one aggregate query
 	query := `
+		SELECT COALESCE(SUM(DATA_LENGTH + INDEX_LENGTH), 0)
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = ?`
+
+	var totalSize int64
+	if err := m.queryRow(context.Background(), db, connectionName, query, database).Scan(&totalSize); err != nil {
+		return nil, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), 1, time.Since(start))
+
+	summary := summarizeTables(tables, maxTables)
+	summary.TotalSizeBytes = &totalSize
+	return summary, nil
+}
+
+const mysqlDescribeTableQuery = `
 		SELECT 
 			COLUMN_NAME,
 			COLUMN_TYPE,
@@ -106,7 +153,19 @@ func (m *Manager) DescribeTableMySQL(connectionName, database, tableName string)
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 		ORDER BY ORDINAL_POSITION`
 
-	rows, err := db.Query(query, database, tableName)
+func (m *Manager) DescribeTableMySQL(connectionName, database, tableName string) ([]ColumnInfo, error) {
+	if err := validateIdentifiers("mysql", database, tableName); err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	rows, err := m.query(context.Background(), db, connectionName, mysqlDescribeTableQuery, database, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe table: %w", err)
 	}
@@ -125,16 +184,12 @@ func (m *Manager) DescribeTableMySQL(connectionName, database, tableName string)
 		columns = append(columns, col)
 	}
 
-	return columns, nil
-}
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(columns), time.Since(start))
 
-func (m *Manager) ListIndexesMySQL(connectionName, database, tableName string) ([]IndexInfo, error) {
-	db, err := m.GetConnection(connectionName)
-	if err != nil {
-		return nil, err
-	}
+	return numberColumns(columns), nil
+}
 
-	query := `
+const mysqlListIndexesQuery = `
 		SELECT 
 			INDEX_NAME,
 			COLUMN_NAME,
@@ -144,7 +199,19 @@ func (m *Manager) ListIndexesMySQL(connectionName, database, tableName string) (
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 		ORDER BY INDEX_NAME, SEQ_IN_INDEX`
 
-	rows, err := db.Query(query, database, tableName)
+func (m *Manager) ListIndexesMySQL(connectionName, database, tableName string) ([]IndexInfo, error) {
+	if err := validateIdentifiers("mysql", database, tableName); err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	rows, err := m.query(context.Background(), db, connectionName, mysqlListIndexesQuery, database, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list indexes: %w", err)
 	}
@@ -175,17 +242,41 @@ func (m *Manager) ListIndexesMySQL(connectionName, database, tableName string) (
 		indexes = append(indexes, *idx)
 	}
 
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(indexes), time.Since(start))
+
 	return indexes, nil
 }
 
-func (m *Manager) GetTableSampleMySQL(connectionName, database, tableName string, limit int) (map[string]interface{}, error) {
-	db, err := m.GetConnection(connectionName)
+func (m *Manager) GetTableSampleMySQL(ctx context.Context, connectionName, database, tableName string, limit int) (map[string]interface{}, error) {
+	collector := &sampleCollector{}
+	summary, err := m.StreamTableSampleMySQL(ctx, connectionName, database, tableName, limit, collector)
+	if err != nil {
+		return nil, err
+	}
+	return collector.result(summary.ScanErrors), nil
+}
+
+// StreamTableSampleMySQL reads up to limit rows of a table into w one row at a
+// time, without holding the sample in memory
+func (m *Manager) StreamTableSampleMySQL(ctx context.Context, connectionName, database, tableName string, limit int, w SampleWriter) (*SampleSummary, error) {
+	if err := validateIdentifiers("mysql", database, tableName); err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetDataConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	table, err := qualifiedName("mysql", database, tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	query := fmt.Sprintf("SELECT * FROM `%s`.`%s` LIMIT %d", database, tableName, limit)
-	rows, err := db.Query(query)
+	query := fmt.Sprintf("SELECT * FROM %s LIMIT %d", table, limit)
+	rows, err := m.query(ctx, db, connectionName, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get table sample: %w", err)
 	}
@@ -196,38 +287,25 @@ func (m *Manager) GetTableSampleMySQL(connectionName, database, tableName string
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
-	var results []map[string]interface{}
-	for rows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
-
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
+	if err := w.WriteColumns(columns); err != nil {
+		return nil, err
+	}
 
-		row := make(map[string]interface{})
-		for i, col := range columns {
-			val := values[i]
-			if val == nil {
-				row[col] = nil
-			} else if b, ok := val.([]byte); ok {
-				// Handle byte arrays (TEXT, VARCHAR, etc.)
-				text := string(b)
-				// Escape and clean text for JSON safety
-				row[col] = cleanTextForJSON(text)
-			} else {
-				row[col] = val
-			}
+	summary, err := scanSampleRows(rows, columns, func(i int, val interface{}) interface{} {
+		if b, ok := val.([]byte); ok {
+			// Handle byte arrays (TEXT, VARCHAR, etc.)
+			// Escape and clean text for JSON safety
+			return cleanTextForJSON(string(b))
 		}
-		results = append(results, row)
+		return val
+	}, func(row map[string]interface{}) error {
+		return w.WriteRow(m.withNullRepresentation(row))
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return map[string]interface{}{
-		"columns":       columns,
-		"rows":          results,
-		"total_sampled": len(results),
-	}, nil
+	m.pool.recordQuery(m.routeConnection(connectionName, true), summary.Rows, time.Since(start))
+
+	return summary, nil
 }
\ No newline at end of file
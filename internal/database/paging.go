@@ -0,0 +1,35 @@
+package database
+
+import "sort"
+
+// TablePage is one page of a table listing
+type TablePage struct {
+	Tables     []TableInfo `json:"tables"`
+	Total      int         `json:"total"`
+	NextOffset *int        `json:"next_offset,omitempty"` // nil on the last page
+}
+
+// PageTables sorts tables by name, so pages stay stable between calls, and
+// returns up to limit of them starting at offset. A limit of 0 or less returns
+// everything from offset on.
+func PageTables(tables []TableInfo, offset, limit int) TablePage {
+	sorted := make([]TableInfo, len(tables))
+	copy(sorted, tables)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	page := TablePage{Tables: []TableInfo{}, Total: len(sorted)}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(sorted) {
+		return page
+	}
+
+	end := len(sorted)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+		page.NextOffset = &end
+	}
+	page.Tables = sorted[offset:end]
+	return page
+}
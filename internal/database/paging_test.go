@@ -0,0 +1,73 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+// unsortedObjects returns n objects named Object_0000.. in reverse order
+func unsortedObjects(n int) []TableInfo {
+	tables := make([]TableInfo, 0, n)
+	for i := n - 1; i >= 0; i-- {
+		tables = append(tables, TableInfo{Name: fmt.Sprintf("Object_%04d", i), Type: "CUSTOM"})
+	}
+	return tables
+}
+
+func TestPageTablesWalksLargeListing(t *testing.T) {
+	tables := unsortedObjects(2500)
+
+	var seen []string
+	offset := 0
+	pages := 0
+	for {
+		page := PageTables(tables, offset, 1000)
+		testutil.AssertEqual(t, 2500, page.Total)
+		for _, table := range page.Tables {
+			seen = append(seen, table.Name)
+		}
+		pages++
+		if page.NextOffset == nil {
+			break
+		}
+		offset = *page.NextOffset
+	}
+
+	testutil.AssertEqual(t, 3, pages)
+	testutil.AssertEqual(t, 2500, len(seen))
+	for i, name := range seen {
+		if name != fmt.Sprintf("Object_%04d", i) {
+			t.Fatalf("Expected sorted, gap-free pages; got %s at position %d", name, i)
+		}
+	}
+
+	// The input is left in its original order
+	testutil.AssertEqual(t, "Object_2499", tables[0].Name)
+}
+
+func TestPageTablesBounds(t *testing.T) {
+	tables := unsortedObjects(5)
+
+	page := PageTables(tables, 0, 0)
+	testutil.AssertEqual(t, 5, len(page.Tables))
+	if page.NextOffset != nil {
+		t.Error("Expected no next_offset without a limit")
+	}
+
+	page = PageTables(tables, 3, 2)
+	testutil.AssertEqual(t, 2, len(page.Tables))
+	testutil.AssertEqual(t, "Object_0003", page.Tables[0].Name)
+	if page.NextOffset != nil {
+		t.Error("Expected no next_offset on an exactly filled last page")
+	}
+
+	page = PageTables(tables, 10, 2)
+	testutil.AssertEqual(t, 0, len(page.Tables))
+	testutil.AssertEqual(t, 5, page.Total)
+
+	page = PageTables(tables, -1, 2)
+	testutil.AssertEqual(t, "Object_0000", page.Tables[0].Name)
+	testutil.AssertEqual(t, 2, *page.NextOffset)
+}
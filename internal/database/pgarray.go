@@ -0,0 +1,147 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// isPostgresArrayType reports whether a lib/pq column type name is an array
+// (pq reports them with a leading underscore, e.g. "_INT4", "_TEXT")
+func isPostgresArrayType(typeName string) bool {
+	return strings.HasPrefix(typeName, "_")
+}
+
+// parsePostgresArray converts an array literal such as {1,2,3} or
+// {{"a b",NULL},{c,"d\"e"}} into nested []interface{} values. Elements are
+// converted according to the array's element type; NULL becomes nil.
+func parsePostgresArray(typeName, literal string) (interface{}, error) {
+	// Arrays with non-default bounds are prefixed with their dimensions, e.g. [0:2]={1,2,3}
+	if strings.HasPrefix(literal, "[") {
+		if i := strings.Index(literal, "="); i >= 0 {
+			literal = literal[i+1:]
+		}
+	}
+
+	p := &pgArrayParser{input: literal, elementType: strings.TrimPrefix(typeName, "_")}
+	value, err := p.parseArray()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("unexpected trailing data in array literal at position %d", p.pos)
+	}
+	return value, nil
+}
+
+type pgArrayParser struct {
+	input       string
+	pos         int
+	elementType string
+}
+
+func (p *pgArrayParser) parseArray() ([]interface{}, error) {
+	if p.pos >= len(p.input) || p.input[p.pos] != '{' {
+		return nil, fmt.Errorf("expected '{' at position %d", p.pos)
+	}
+	p.pos++
+
+	elements := []interface{}{}
+	if p.pos < len(p.input) && p.input[p.pos] == '}' {
+		p.pos++
+		return elements, nil
+	}
+
+	for {
+		if p.pos >= len(p.input) {
+			return nil, fmt.Errorf("unterminated array literal")
+		}
+
+		var element interface{}
+		var err error
+		switch p.input[p.pos] {
+		case '{':
+			element, err = p.parseArray()
+		case '"':
+			element, err = p.parseQuoted()
+		default:
+			element, err = p.parseUnquoted()
+		}
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+
+		if p.pos >= len(p.input) {
+			return nil, fmt.Errorf("unterminated array literal")
+		}
+		switch p.input[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return elements, nil
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+		}
+	}
+}
+
+func (p *pgArrayParser) parseQuoted() (interface{}, error) {
+	p.pos++ // opening quote
+	var b strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		switch c {
+		case '\\':
+			if p.pos+1 >= len(p.input) {
+				return nil, fmt.Errorf("unterminated escape in array literal")
+			}
+			b.WriteByte(p.input[p.pos+1])
+			p.pos += 2
+		case '"':
+			p.pos++
+			// Quoted elements are never NULL, even if they spell it
+			return p.convert(b.String()), nil
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return nil, fmt.Errorf("unterminated quoted element in array literal")
+}
+
+func (p *pgArrayParser) parseUnquoted() (interface{}, error) {
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] != ',' && p.input[p.pos] != '}' {
+		p.pos++
+	}
+	text := strings.TrimSpace(p.input[start:p.pos])
+	if strings.EqualFold(text, "NULL") {
+		return nil, nil
+	}
+	return p.convert(text), nil
+}
+
+// convert turns an element into a JSON-friendly value. Numeric and other
+// types that could lose precision stay strings, matching scalar columns.
+func (p *pgArrayParser) convert(text string) interface{} {
+	switch p.elementType {
+	case "INT2", "INT4", "INT8":
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n
+		}
+	case "FLOAT4", "FLOAT8":
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	case "BOOL":
+		switch text {
+		case "t":
+			return true
+		case "f":
+			return false
+		}
+	}
+	return cleanTextForJSON(text)
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func arrayJSON(t *testing.T, typeName, literal string) string {
+	t.Helper()
+	value, err := parsePostgresArray(typeName, literal)
+	testutil.AssertNoError(t, err)
+	data, err := json.Marshal(value)
+	testutil.AssertNoError(t, err)
+	return string(data)
+}
+
+func TestParsePostgresIntArray(t *testing.T) {
+	testutil.AssertEqual(t, `[1,2,3]`, arrayJSON(t, "_INT4", "{1,2,3}"))
+	testutil.AssertEqual(t, `[-9000000000,null]`, arrayJSON(t, "_INT8", "{-9000000000,NULL}"))
+	testutil.AssertEqual(t, `[]`, arrayJSON(t, "_INT4", "{}"))
+	testutil.AssertEqual(t, `[[1,2],[3,4]]`, arrayJSON(t, "_INT4", "{{1,2},{3,4}}"))
+	testutil.AssertEqual(t, `[7,8]`, arrayJSON(t, "_INT2", "[0:1]={7,8}"))
+}
+
+func TestParsePostgresTextArray(t *testing.T) {
+	testutil.AssertEqual(t, `["apple","banana split"]`, arrayJSON(t, "_TEXT", `{apple,"banana split"}`))
+	testutil.AssertEqual(t, `["a,b","say \"hi\"","back\\slash"]`, arrayJSON(t, "_TEXT", `{"a,b","say \"hi\"","back\\slash"}`))
+
+	// Unquoted NULL is null; a quoted "NULL" is the string
+	testutil.AssertEqual(t, `[null,"NULL"]`, arrayJSON(t, "_VARCHAR", `{NULL,"NULL"}`))
+	testutil.AssertEqual(t, `[["a","{b}"],["c",""]]`, arrayJSON(t, "_TEXT", `{{a,"{b}"},{c,""}}`))
+}
+
+func TestParsePostgresOtherElementTypes(t *testing.T) {
+	testutil.AssertEqual(t, `[true,false]`, arrayJSON(t, "_BOOL", "{t,f}"))
+	testutil.AssertEqual(t, `[1.5,-2]`, arrayJSON(t, "_FLOAT8", "{1.5,-2}"))
+	// Numeric stays a string, as scalar numeric columns do
+	testutil.AssertEqual(t, `["12345678901234567890.123"]`, arrayJSON(t, "_NUMERIC", "{12345678901234567890.123}"))
+}
+
+func TestParsePostgresArrayInvalid(t *testing.T) {
+	for _, literal := range []string{"1,2,3", "{1,2", `{"abc}`, "{1,2}x", "{{1,2}"} {
+		_, err := parsePostgresArray("_INT4", literal)
+		testutil.AssertError(t, err)
+	}
+}
+
+func TestIsPostgresArrayType(t *testing.T) {
+	testutil.AssertEqual(t, true, isPostgresArrayType("_INT4"))
+	testutil.AssertEqual(t, true, isPostgresArrayType("_TEXT"))
+	testutil.AssertEqual(t, false, isPostgresArrayType("INT4"))
+	testutil.AssertEqual(t, false, isPostgresArrayType(""))
+}
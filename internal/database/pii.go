@@ -0,0 +1,133 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+)
+
+// piiPattern is one compiled pii_patterns entry
+type piiPattern struct {
+	regexp *regexp.Regexp
+	token  string
+}
+
+// PIIRedactor replaces matches of the configured PII patterns in string
+// values with [REDACTED:<name>]. A nil PIIRedactor leaves values unchanged.
+type PIIRedactor struct {
+	// any matches if some pattern does, so values without PII, the common
+	// case, cost one linear-time scan however many patterns are configured
+	any      *regexp.Regexp
+	patterns []piiPattern
+}
+
+// NewPIIRedactor compiles pii_patterns entries, returning nil when there are none
+func NewPIIRedactor(patterns []config.PIIPattern) (*PIIRedactor, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	r := &PIIRedactor{}
+	alternatives := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		expression := p.Expression()
+		if expression == "" {
+			return nil, fmt.Errorf("pii pattern '%s' has no pattern", p.Name)
+		}
+		compiled, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("pii pattern '%s': %w", p.Name, err)
+		}
+		r.patterns = append(r.patterns, piiPattern{regexp: compiled, token: "[REDACTED:" + p.Name + "]"})
+		alternatives = append(alternatives, "(?:"+expression+")")
+	}
+	r.any = regexp.MustCompile(strings.Join(alternatives, "|"))
+	return r, nil
+}
+
+// RedactString replaces every pattern match in text
+func (r *PIIRedactor) RedactString(text string) string {
+	if r == nil || !r.any.MatchString(text) {
+		return text
+	}
+	for _, p := range r.patterns {
+		text = p.regexp.ReplaceAllLiteralString(text, p.token)
+	}
+	return text
+}
+
+// RedactValue redacts string and byte cells; other types can't hold PII
+// patterns in any form worth matching and are returned unchanged
+func (r *PIIRedactor) RedactValue(value interface{}) interface{} {
+	if r == nil {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		return r.RedactString(v)
+	case []byte:
+		if redacted := r.RedactString(string(v)); redacted != string(v) {
+			return redacted
+		}
+	case json.RawMessage:
+		// Tokens contain no quotes, so JSON stays valid unless a pattern spans one
+		if redacted := r.RedactString(string(v)); redacted != string(v) {
+			if json.Valid([]byte(redacted)) {
+				return json.RawMessage(redacted)
+			}
+			return redacted
+		}
+	}
+	return value
+}
+
+// redactRow redacts every cell of a row in place
+func (r *PIIRedactor) redactRow(row map[string]interface{}) map[string]interface{} {
+	for column, value := range row {
+		row[column] = r.RedactValue(value)
+	}
+	return row
+}
+
+// RedactSample redacts the rows of a table sample in place
+func (r *PIIRedactor) RedactSample(sample map[string]interface{}) {
+	if r == nil {
+		return
+	}
+	rows, _ := sample["rows"].([]map[string]interface{})
+	for _, row := range rows {
+		r.redactRow(row)
+	}
+}
+
+// RedactDistinctValues redacts distinct values in place
+func (r *PIIRedactor) RedactDistinctValues(values []DistinctValue) {
+	if r == nil {
+		return
+	}
+	for i := range values {
+		values[i].Value = r.RedactValue(values[i].Value)
+	}
+}
+
+// piiSampleWriter redacts each row before passing it on
+type piiSampleWriter struct {
+	SampleWriter
+	redactor *PIIRedactor
+}
+
+// NewPIISampleWriter wraps w so that streamed rows are redacted. With a nil
+// redactor w is returned as is.
+func NewPIISampleWriter(w SampleWriter, r *PIIRedactor) SampleWriter {
+	if r == nil {
+		return w
+	}
+	return &piiSampleWriter{SampleWriter: w, redactor: r}
+}
+
+func (w *piiSampleWriter) WriteRow(row map[string]interface{}) error {
+	return w.SampleWriter.WriteRow(w.redactor.redactRow(row))
+}
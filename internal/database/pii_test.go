@@ -0,0 +1,64 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func testRedactor(t *testing.T) *PIIRedactor {
+	t.Helper()
+	r, err := NewPIIRedactor([]config.PIIPattern{{Name: "email"}, {Name: "ssn"}, {Name: "badge", Pattern: `B-\d{6}`}})
+	testutil.AssertNoError(t, err)
+	return r
+}
+
+func TestPIIRedactorRedactsEmail(t *testing.T) {
+	r := testRedactor(t)
+	testutil.AssertEqual(t, "[REDACTED:email]", r.RedactValue("jane.doe@example.com"))
+	testutil.AssertEqual(t, "contact [REDACTED:email] or [REDACTED:badge], ssn [REDACTED:ssn]",
+		r.RedactValue("contact jane@example.com or B-123456, ssn 123-45-6789"))
+}
+
+func TestPIIRedactorLeavesOtherValues(t *testing.T) {
+	r := testRedactor(t)
+	testutil.AssertEqual(t, "Jane Doe", r.RedactValue("Jane Doe"))
+	testutil.AssertEqual(t, int64(123456789), r.RedactValue(int64(123456789)))
+	testutil.AssertEqual(t, nil, r.RedactValue(nil))
+
+	// No patterns means no redactor, and a nil redactor changes nothing
+	none, err := NewPIIRedactor(nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, none == nil)
+	testutil.AssertEqual(t, "jane@example.com", none.RedactValue("jane@example.com"))
+}
+
+func TestPIIRedactorJSONAndBytes(t *testing.T) {
+	r := testRedactor(t)
+	raw, ok := r.RedactValue(json.RawMessage(`{"email":"jane@example.com"}`)).(json.RawMessage)
+	testutil.AssertEqual(t, true, ok)
+	testutil.AssertEqual(t, `{"email":"[REDACTED:email]"}`, string(raw))
+	testutil.AssertEqual(t, "[REDACTED:email]", r.RedactValue([]byte("jane@example.com")))
+}
+
+func TestPIIRedactorSampleAndWriter(t *testing.T) {
+	r := testRedactor(t)
+	sample := map[string]interface{}{
+		"columns": []string{"id", "email"},
+		"rows":    []map[string]interface{}{{"id": int64(1), "email": "jane@example.com"}},
+	}
+	r.RedactSample(sample)
+	testutil.AssertEqual(t, "[REDACTED:email]", sample["rows"].([]map[string]interface{})[0]["email"])
+
+	collector := &sampleCollector{}
+	w := NewPIISampleWriter(collector, r)
+	testutil.AssertNoError(t, w.WriteRow(map[string]interface{}{"email": "bob@example.org"}))
+	testutil.AssertEqual(t, "[REDACTED:email]", collector.rows[0]["email"])
+
+	values := []DistinctValue{{Value: "ann@example.net"}, {Value: "gold"}}
+	r.RedactDistinctValues(values)
+	testutil.AssertEqual(t, "[REDACTED:email]", values[0].Value)
+	testutil.AssertEqual(t, "gold", values[1].Value)
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// pingAllConcurrency bounds how many connections PingAll pings at once
+const pingAllConcurrency = 4
+
+// Ping statuses reported by PingAll
+const (
+	PingOK      = "ok"
+	PingTimeout = "timeout" // the database did not answer within the timeout
+	PingError   = "error"   // connecting or pinging failed outright
+	PingSkipped = "skipped" // the connection type has no SQL ping
+)
+
+// PingResult is the outcome of pinging one connection
+type PingResult struct {
+	Connection string      `json:"connection"`
+	Type       string      `json:"type"`
+	Status     string      `json:"status"`
+	Pings      int         `json:"pings"`
+	RTTMillis  *float64    `json:"rtt_ms,omitempty"` // the last round trip, when every ping succeeded
+	MinMillis  *float64    `json:"min_ms,omitempty"` // min/avg/max are set when more than one ping was taken
+	AvgMillis  *float64    `json:"avg_ms,omitempty"`
+	MaxMillis  *float64    `json:"max_ms,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Failure    FailureKind `json:"failure,omitempty"`
+}
+
+// PingAll pings every enabled MySQL and PostgreSQL connection count times, a few
+// connections at a time, and reports round-trip latencies. Each ping gets its
+// own timeout; a ping that runs out of time is reported as a timeout rather
+// than an error. Results are sorted by connection name.
+func (m *Manager) PingAll(ctx context.Context, count int, timeout time.Duration) []PingResult {
+	if count < 1 {
+		count = 1
+	}
+
+	names := make([]string, 0, len(m.config.Connections))
+	for name, conn := range m.config.Connections {
+		if conn.IsEnabled() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	results := make([]PingResult, len(names))
+	sem := make(chan struct{}, pingAllConcurrency)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = m.pingConnection(ctx, name, count, timeout)
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
+
+// pingConnection measures the round trip to one connection
+func (m *Manager) pingConnection(ctx context.Context, connectionName string, count int, timeout time.Duration) PingResult {
+	conn, _ := m.config.GetConnection(connectionName)
+	result := PingResult{Connection: connectionName, Type: conn.Type}
+	if conn.Type != "mysql" && conn.Type != "postgres" {
+		result.Status = PingSkipped
+		return result
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return pingFailed(result, err)
+	}
+
+	var rtts []time.Duration
+	for i := 0; i < count; i++ {
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := db.PingContext(pingCtx)
+		rtt := time.Since(start)
+		cancel()
+		if err != nil {
+			return pingFailed(result, err)
+		}
+		rtts = append(rtts, rtt)
+		result.Pings++
+	}
+
+	result.Status = PingOK
+	result.RTTMillis = millis(rtts[len(rtts)-1])
+	if len(rtts) > 1 {
+		min, max, total := rtts[0], rtts[0], time.Duration(0)
+		for _, rtt := range rtts {
+			if rtt < min {
+				min = rtt
+			}
+			if rtt > max {
+				max = rtt
+			}
+			total += rtt
+		}
+		result.MinMillis = millis(min)
+		result.AvgMillis = millis(total / time.Duration(len(rtts)))
+		result.MaxMillis = millis(max)
+	}
+	return result
+}
+
+// pingFailed records err on result, telling timeouts apart from other failures
+func pingFailed(result PingResult, err error) PingResult {
+	result.Error = err.Error()
+	result.Failure = ClassifyFailure(err)
+	if errors.Is(err, context.DeadlineExceeded) || result.Failure == FailureTimeout {
+		result.Status = PingTimeout
+	} else {
+		result.Status = PingError
+	}
+	return result
+}
+
+// millis converts a duration to fractional milliseconds
+func millis(d time.Duration) *float64 {
+	ms := float64(d.Microseconds()) / 1000
+	return &ms
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestPingAllReportsLatencies(t *testing.T) {
+	cfg := testConfig()
+	cfg.Connections["test-sf"] = config.Connection{Type: "salesforce", Host: "https://example.my.salesforce.com"}
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	defer manager.Close()
+	addMockConnection(t, manager.pool, "test-mysql")
+	addMockConnection(t, manager.pool, "test-postgres").SetPingDelay(2 * time.Millisecond)
+
+	results := manager.PingAll(context.Background(), 3, time.Second)
+	testutil.AssertEqual(t, 3, len(results))
+
+	mysqlResult := results[0]
+	testutil.AssertEqual(t, "test-mysql", mysqlResult.Connection)
+	testutil.AssertEqual(t, PingOK, mysqlResult.Status)
+	testutil.AssertEqual(t, 3, mysqlResult.Pings)
+	if mysqlResult.RTTMillis == nil || mysqlResult.MinMillis == nil || mysqlResult.AvgMillis == nil || mysqlResult.MaxMillis == nil {
+		t.Fatalf("Expected rtt, min, avg and max for several pings, got %+v", mysqlResult)
+	}
+
+	postgresResult := results[1]
+	testutil.AssertEqual(t, PingOK, postgresResult.Status)
+	if *postgresResult.MinMillis < 2 || *postgresResult.AvgMillis < *postgresResult.MinMillis || *postgresResult.MaxMillis < *postgresResult.AvgMillis {
+		t.Errorf("Expected min <= avg <= max of at least 2ms, got %v/%v/%v",
+			*postgresResult.MinMillis, *postgresResult.AvgMillis, *postgresResult.MaxMillis)
+	}
+
+	testutil.AssertEqual(t, "test-sf", results[2].Connection)
+	testutil.AssertEqual(t, PingSkipped, results[2].Status)
+
+	// A single ping reports its round trip without min/avg/max
+	single := manager.PingAll(context.Background(), 1, time.Second)[0]
+	if single.RTTMillis == nil || single.MinMillis != nil {
+		t.Errorf("Expected only rtt_ms for a single ping, got %+v", single)
+	}
+}
+
+func TestPingAllSeparatesTimeoutsFromFailures(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+	addMockConnection(t, manager.pool, "test-mysql").SetPingFails(true, fmt.Errorf("dial tcp 10.0.0.5:3306: connection refused"))
+	addMockConnection(t, manager.pool, "test-postgres").SetPingDelay(time.Second)
+
+	results := manager.PingAll(context.Background(), 2, 20*time.Millisecond)
+
+	failed := results[0]
+	testutil.AssertEqual(t, PingError, failed.Status)
+	testutil.AssertEqual(t, FailureUnreachable, failed.Failure)
+	testutil.AssertContains(t, failed.Error, "connection refused")
+	if failed.RTTMillis != nil {
+		t.Error("Expected no latency for a failed ping")
+	}
+
+	slow := results[1]
+	testutil.AssertEqual(t, PingTimeout, slow.Status)
+	testutil.AssertEqual(t, FailureTimeout, slow.Failure)
+	testutil.AssertEqual(t, 0, slow.Pings)
+}
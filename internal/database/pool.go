@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/eliziario/simpledb-mcp/internal/config"
+	"golang.org/x/sync/singleflight"
 )
 
 // ConnectionState represents the current state of a database connection
@@ -49,12 +52,37 @@ type PooledConnection struct {
 	LastPing     time.Time
 	ErrorCount   int
 	CreatedAt    time.Time
+	
+	// Per-connection ping metrics
+	SuccessfulPings int64
+	FailedPings     int64
+	LastError       string
+	LastErrorKind   FailureKind
+	LastErrorAt     time.Time
+	
+	// Per-connection usage statistics
+	QueriesRun     int64
+	RowsReturned   int64
+	TotalQueryTime time.Duration
+	LastQueryAt    time.Time // last tool-driven query; keepalive pings don't touch it
+	
+	configName   string // configured connection this entry dials for; differs from Name for replicas
 	mutex        sync.RWMutex
 }
 
+// replicaSuffix marks pool entries that connect to a connection's read replica
+const replicaSuffix = "@replica"
+
+// replicaPoolKey names the pool entry for a connection's read replica
+func replicaPoolKey(connectionName string) string {
+	return connectionName + replicaSuffix
+}
+
 // ConnectionPool manages database connections with keep-alive functionality
 type ConnectionPool struct {
 	connections map[string]*PooledConnection
+	// connecting collapses concurrent first-time connects to the same name into one dial
+	connecting  singleflight.Group
 	manager     *Manager
 	ctx         context.Context
 	cancel      context.CancelFunc
@@ -65,11 +93,23 @@ type ConnectionPool struct {
 	maxIdleTime     time.Duration
 	maxErrorCount   int
 	reconnectDelay  time.Duration
+	keepalive       bool
+	maxPooled       int
+	warmPingAfter   time.Duration
 	
-	// Metrics
+	// metricsDone is closed when the metrics logger, if started, has stopped
+	metricsDone chan struct{}
+	
+	// Metrics; the ping counts are updated atomically, since warm pings and
+	// health checks hold only their own connection's mutex
 	totalConnections int64
 	successfulPings  int64
 	failedPings      int64
+	
+	// Usage totals across all connections, updated atomically
+	totalQueries   int64
+	totalRows      int64
+	totalQueryTime int64 // nanoseconds
 }
 
 // NewConnectionPool creates a new connection pool
@@ -87,6 +127,13 @@ func NewConnectionPool(manager *Manager) *ConnectionPool {
 		maxIdleTime:     poolConfig.MaxIdleTime,
 		maxErrorCount:   poolConfig.MaxErrorCount,
 		reconnectDelay:  poolConfig.ReconnectDelay,
+		keepalive:       poolConfig.EnableKeepalive,
+		maxPooled:       poolConfig.MaxPooledConnections,
+		warmPingAfter:   poolConfig.WarmPingAfter,
+	}
+	
+	for _, warning := range poolConfig.KeepaliveWarnings() {
+		log.Printf("Warning: %s", warning)
 	}
 	
 	// Start background monitoring if enabled
@@ -94,77 +141,220 @@ func NewConnectionPool(manager *Manager) *ConnectionPool {
 		go pool.backgroundMonitor()
 	}
 	
+	if interval := manager.config.Settings.MetricsLogInterval; interval > 0 {
+		pool.startMetricsLogger(interval)
+	}
+	
 	return pool
 }
 
 // GetConnection gets or creates a pooled connection
 func (p *ConnectionPool) GetConnection(connectionName string) (*sql.DB, error) {
 	p.mutex.Lock()
-	defer p.mutex.Unlock()
 	
 	// Check if connection exists and is healthy
 	if conn, exists := p.connections[connectionName]; exists {
 		conn.mutex.Lock()
+		idle := time.Since(conn.lastActive())
 		conn.LastUsed = time.Now()
 		
 		// If connection is healthy, return it
 		if conn.State == StateConnected && conn.DB != nil {
+			db := conn.DB
 			conn.mutex.Unlock()
-			return conn.DB, nil
+			p.mutex.Unlock()
+			if p.warmPingAfter > 0 && idle >= p.warmPingAfter {
+				if err := p.warmPing(conn, db); err != nil {
+					return nil, err
+				}
+			}
+			return db, nil
 		}
 		conn.mutex.Unlock()
 	}
+	p.mutex.Unlock()
+	
+	// Callers arriving while a connect is in flight wait for it and share its result
+	result, err, _ := p.connecting.Do(connectionName, func() (interface{}, error) {
+		p.mutex.Lock()
+		// A flight that finished just before this one started may have connected already
+		if conn, exists := p.connections[connectionName]; exists {
+			conn.mutex.Lock()
+			if conn.State == StateConnected && conn.DB != nil {
+				conn.mutex.Unlock()
+				p.mutex.Unlock()
+				return conn.DB, nil
+			}
+			conn.mutex.Unlock()
+		}
+		
+		p.evictLRU(connectionName)
+		
+		// Register the new entry while locked, then dial without holding the pool
+		// lock so a slow or unreachable host doesn't block other connections
+		pooledConn, err := p.addConnecting(connectionName)
+		p.mutex.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return p.createConnection(pooledConn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*sql.DB), nil
+}
+
+// lastActive returns when the connection was last handed out or pinged.
+// The caller must hold the connection mutex.
+func (conn *PooledConnection) lastActive() time.Time {
+	if conn.LastPing.After(conn.LastUsed) {
+		return conn.LastPing
+	}
+	return conn.LastUsed
+}
+
+// warmPing pings a connection that has sat idle before it is handed out.
+// database/sql discards pooled connections the server has closed and dials a
+// fresh one while pinging, so the caller's query doesn't hit the dead one.
+// It must be called without the pool mutex held.
+func (p *ConnectionPool) warmPing(conn *PooledConnection, db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(p.ctx, 5*time.Second)
+	defer cancel()
+	err := db.PingContext(ctx)
 	
-	// Create or recreate connection
-	return p.createConnection(connectionName)
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	if err != nil {
+		conn.State = StateError
+		conn.ErrorCount++
+		conn.FailedPings++
+		conn.recordError(err)
+		atomic.AddInt64(&p.failedPings, 1)
+		return fmt.Errorf("connection '%s' failed after being idle: %w", conn.Name, err)
+	}
+	conn.LastPing = time.Now()
+	conn.SuccessfulPings++
+	atomic.AddInt64(&p.successfulPings, 1)
+	return nil
 }
 
-// createConnection creates a new database connection
-func (p *ConnectionPool) createConnection(connectionName string) (*sql.DB, error) {
-	// Get connection config
-	connConfig, exists := p.manager.config.GetConnection(connectionName)
+// evictGracePeriod keeps a connection out of eviction for a while after it was
+// handed out. Callers of GetConnection don't give the handle back, and until
+// their query starts database/sql reports nothing in use, so a connection
+// just returned would otherwise look idle and be closed under its caller.
+const evictGracePeriod = 30 * time.Second
+
+// evictLRU makes room for a new entry when the pool is at its cap by closing
+// the least recently used connection that has no queries in flight and was
+// not handed out within evictGracePeriod. If every connection is busy the pool
+// is allowed to grow past the cap.
+// The caller must hold the pool mutex.
+func (p *ConnectionPool) evictLRU(incoming string) {
+	if p.maxPooled <= 0 || len(p.connections) < p.maxPooled {
+		return
+	}
+	// Reconnecting replaces the existing entry, so the pool doesn't grow
+	if _, exists := p.connections[incoming]; exists {
+		return
+	}
+	
+	var victim *PooledConnection
+	var victimLastUsed time.Time
+	for _, conn := range p.connections {
+		conn.mutex.RLock()
+		lastUsed := conn.LastUsed
+		busy := conn.State == StateConnecting || time.Since(lastUsed) < evictGracePeriod ||
+			(conn.DB != nil && conn.DB.Stats().InUse > 0)
+		conn.mutex.RUnlock()
+		
+		if !busy && (victim == nil || lastUsed.Before(victimLastUsed)) {
+			victim = conn
+			victimLastUsed = lastUsed
+		}
+	}
+	
+	if victim == nil {
+		log.Printf("Connection pool is at its cap of %d and every connection is busy; opening '%s' anyway", p.maxPooled, incoming)
+		return
+	}
+	
+	victim.mutex.Lock()
+	if victim.DB != nil {
+		victim.DB.Close()
+	}
+	victim.mutex.Unlock()
+	delete(p.connections, victim.Name)
+	log.Printf("Evicted least recently used connection '%s' to make room for '%s'", victim.Name, incoming)
+}
+
+// entryConfig resolves a pool entry name to the settings it dials and the
+// configured connection it belongs to. Replica entries dial the replica host
+// with their primary's settings and credentials.
+func (p *ConnectionPool) entryConfig(connectionName string) (config.Connection, string, bool) {
+	if connConfig, exists := p.manager.config.GetConnection(connectionName); exists {
+		return connConfig, connectionName, true
+	}
+	if configName, ok := strings.CutSuffix(connectionName, replicaSuffix); ok {
+		if connConfig, exists := p.manager.config.GetConnection(configName); exists && connConfig.HasReadReplica() {
+			return connConfig.ReplicaConnection(), configName, true
+		}
+	}
+	return config.Connection{}, "", false
+}
+
+// addConnecting stores a new entry in the connecting state.
+// The caller must hold the pool mutex.
+func (p *ConnectionPool) addConnecting(connectionName string) (*PooledConnection, error) {
+	connConfig, configName, exists := p.entryConfig(connectionName)
 	if !exists {
 		return nil, fmt.Errorf("connection '%s' not found in configuration", connectionName)
 	}
 	
 	// Create new pooled connection
 	pooledConn := &PooledConnection{
-		Name:      connectionName,
-		Config:    connConfig,
-		State:     StateConnecting,
-		CreatedAt: time.Now(),
-		LastUsed:  time.Now(),
+		Name:       connectionName,
+		Config:     connConfig,
+		configName: configName,
+		State:      StateConnecting,
+		CreatedAt:  time.Now(),
+		LastUsed:   time.Now(),
 	}
 	
 	// Store in pool
 	p.connections[connectionName] = pooledConn
+	return pooledConn, nil
+}
+
+// createConnection dials the database for an entry added by addConnecting.
+// It must be called without the pool mutex held.
+func (p *ConnectionPool) createConnection(pooledConn *PooledConnection) (*sql.DB, error) {
+	connectionName := pooledConn.Name
 	
-	// Create actual database connection
-	db, err := p.manager.createRawConnection(connConfig, connectionName)
+	// Create actual database connection; a replica shares its primary's credentials
+	db, err := p.manager.createRawConnection(pooledConn.Config, pooledConn.configName)
 	if err != nil {
-		pooledConn.mutex.Lock()
-		pooledConn.State = StateError
-		pooledConn.ErrorCount++
-		pooledConn.mutex.Unlock()
+		p.mutex.Lock()
+		p.connectionFailed(pooledConn, err)
+		p.mutex.Unlock()
 		return nil, err
 	}
 	
+	p.mutex.Lock()
+	// CloseConnection may have removed the entry while it was dialing; storing
+	// the handle on it then would leak an open *sql.DB nothing can close
+	if p.connections[connectionName] != pooledConn {
+		p.mutex.Unlock()
+		db.Close()
+		return nil, fmt.Errorf("connection '%s' was closed while it was being opened", connectionName)
+	}
+	
 	// Configure connection pool settings
 	db.SetMaxOpenConns(10)
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(time.Hour)
 	db.SetConnMaxIdleTime(p.maxIdleTime)
 	
-	// Test connection
-	if err := db.Ping(); err != nil {
-		db.Close()
-		pooledConn.mutex.Lock()
-		pooledConn.State = StateError
-		pooledConn.ErrorCount++
-		pooledConn.mutex.Unlock()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-	
 	// Update pooled connection
 	pooledConn.mutex.Lock()
 	pooledConn.DB = db
@@ -174,11 +364,67 @@ func (p *ConnectionPool) createConnection(connectionName string) (*sql.DB, error
 	pooledConn.mutex.Unlock()
 	
 	p.totalConnections++
+	p.mutex.Unlock()
 	log.Printf("Created new database connection for '%s'", connectionName)
 	
 	return db, nil
 }
 
+// keepaliveEnabled reports whether the background monitor pings a pool entry:
+// keepalive must be on globally and not disabled for the connection, and the
+// connection must be in service
+func (p *ConnectionPool) keepaliveEnabled(connectionName string) bool {
+	if !p.keepalive {
+		return false
+	}
+	connConfig, _, exists := p.entryConfig(connectionName)
+	return !exists || (!connConfig.DisableKeepalive && connConfig.IsEnabled())
+}
+
+// connectionFailed handles a connection that could not be created. With
+// keepalive enabled the entry stays in the pool in the error state so its
+// last error remains visible in the pool status; otherwise it is dropped so a
+// dead entry doesn't linger in the pool metrics. Either way nothing redials
+// it in the background: the monitor only pings entries that have a DB, so
+// the connection is retried when a caller next asks for it, and an error
+// entry nobody asks for is removed once it has been idle for max_idle_time.
+// The caller must hold the pool mutex.
+func (p *ConnectionPool) connectionFailed(pooledConn *PooledConnection, err error) {
+	if !p.keepaliveEnabled(pooledConn.Name) {
+		if p.connections[pooledConn.Name] == pooledConn {
+			delete(p.connections, pooledConn.Name)
+		}
+		return
+	}
+	
+	pooledConn.mutex.Lock()
+	pooledConn.State = StateError
+	pooledConn.ErrorCount++
+	pooledConn.recordError(err)
+	pooledConn.mutex.Unlock()
+}
+
+// recordQuery adds a completed query to the usage statistics of a connection
+func (p *ConnectionPool) recordQuery(connectionName string, rows int, duration time.Duration) {
+	atomic.AddInt64(&p.totalQueries, 1)
+	atomic.AddInt64(&p.totalRows, int64(rows))
+	atomic.AddInt64(&p.totalQueryTime, int64(duration))
+	
+	p.mutex.RLock()
+	conn, exists := p.connections[connectionName]
+	p.mutex.RUnlock()
+	if !exists {
+		return
+	}
+	
+	conn.mutex.Lock()
+	conn.QueriesRun++
+	conn.RowsReturned += int64(rows)
+	conn.TotalQueryTime += duration
+	conn.LastQueryAt = time.Now()
+	conn.mutex.Unlock()
+}
+
 // backgroundMonitor runs the background connection monitoring
 func (p *ConnectionPool) backgroundMonitor() {
 	ticker := time.NewTicker(p.pingInterval)
@@ -197,11 +443,15 @@ func (p *ConnectionPool) backgroundMonitor() {
 	}
 }
 
-// healthCheck performs health checks on all connections
+// healthCheck performs health checks on all connections that have keepalive
+// enabled; the others are only used on demand
 func (p *ConnectionPool) healthCheck() {
 	p.mutex.RLock()
 	connections := make([]*PooledConnection, 0, len(p.connections))
-	for _, conn := range p.connections {
+	for name, conn := range p.connections {
+		if !p.keepaliveEnabled(name) {
+			continue
+		}
 		connections = append(connections, conn)
 	}
 	p.mutex.RUnlock()
@@ -219,7 +469,7 @@ func (p *ConnectionPool) checkConnection(conn *PooledConnection) {
 	conn.mutex.Lock()
 	defer conn.mutex.Unlock()
 	
-	if conn.DB == nil || conn.State == StateError {
+	if conn.DB == nil {
 		return
 	}
 	
@@ -230,7 +480,9 @@ func (p *ConnectionPool) checkConnection(conn *PooledConnection) {
 	if err := conn.DB.PingContext(ctx); err != nil {
 		conn.State = StateError
 		conn.ErrorCount++
-		p.failedPings++
+		conn.FailedPings++
+		conn.recordError(err)
+		atomic.AddInt64(&p.failedPings, 1)
 		
 		log.Printf("Connection '%s' ping failed (errors: %d): %v", 
 			conn.Name, conn.ErrorCount, err)
@@ -250,7 +502,11 @@ func (p *ConnectionPool) checkConnection(conn *PooledConnection) {
 			log.Printf("Connection '%s' recovered", conn.Name)
 		}
 		conn.ErrorCount = 0
-		p.successfulPings++
+		conn.LastError = ""
+		conn.LastErrorKind = ""
+		conn.LastErrorAt = time.Time{}
+		conn.SuccessfulPings++
+		atomic.AddInt64(&p.successfulPings, 1)
 	}
 }
 
@@ -285,6 +541,28 @@ func (p *ConnectionPool) cleanupIdleConnections() {
 	}
 }
 
+// CloseConnection closes and removes a pool entry, reporting whether there
+// was one. The next GetConnection for it opens a new one.
+func (p *ConnectionPool) CloseConnection(connectionName string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	conn, exists := p.connections[connectionName]
+	if !exists {
+		return false
+	}
+	conn.mutex.Lock()
+	if conn.DB != nil {
+		if err := conn.DB.Close(); err != nil {
+			log.Printf("Error closing connection '%s': %v", connectionName, err)
+		}
+	}
+	conn.mutex.Unlock()
+	delete(p.connections, connectionName)
+	log.Printf("Closed connection '%s' on request", connectionName)
+	return true
+}
+
 // GetConnectionStatus returns the status of a specific connection
 func (p *ConnectionPool) GetConnectionStatus(connectionName string) *ConnectionStatus {
 	p.mutex.RLock()
@@ -301,16 +579,7 @@ func (p *ConnectionPool) GetConnectionStatus(connectionName string) *ConnectionS
 	conn.mutex.RLock()
 	defer conn.mutex.RUnlock()
 	
-	return &ConnectionStatus{
-		Name:        conn.Name,
-		State:       conn.State,
-		LastUsed:    conn.LastUsed,
-		LastPing:    conn.LastPing,
-		ErrorCount:  conn.ErrorCount,
-		CreatedAt:   conn.CreatedAt,
-		IdleTime:    time.Since(conn.LastUsed),
-		ConnectedFor: time.Since(conn.CreatedAt),
-	}
+	return conn.status()
 }
 
 // GetAllConnectionStatus returns status for all connections
@@ -321,22 +590,43 @@ func (p *ConnectionPool) GetAllConnectionStatus() []*ConnectionStatus {
 	statuses := make([]*ConnectionStatus, 0, len(p.connections))
 	for _, conn := range p.connections {
 		conn.mutex.RLock()
-		statuses = append(statuses, &ConnectionStatus{
-			Name:         conn.Name,
-			State:        conn.State,
-			LastUsed:     conn.LastUsed,
-			LastPing:     conn.LastPing,
-			ErrorCount:   conn.ErrorCount,
-			CreatedAt:    conn.CreatedAt,
-			IdleTime:     time.Since(conn.LastUsed),
-			ConnectedFor: time.Since(conn.CreatedAt),
-		})
+		statuses = append(statuses, conn.status())
 		conn.mutex.RUnlock()
 	}
 	
 	return statuses
 }
 
+// recordError stores the most recent failure; the caller must hold conn.mutex
+func (conn *PooledConnection) recordError(err error) {
+	conn.LastError = err.Error()
+	conn.LastErrorKind = ClassifyFailure(err)
+	conn.LastErrorAt = time.Now()
+}
+
+// status builds a ConnectionStatus snapshot; the caller must hold conn.mutex
+func (conn *PooledConnection) status() *ConnectionStatus {
+	return &ConnectionStatus{
+		Name:            conn.Name,
+		State:           conn.State,
+		LastUsed:        conn.LastUsed,
+		LastPing:        conn.LastPing,
+		ErrorCount:      conn.ErrorCount,
+		CreatedAt:       conn.CreatedAt,
+		IdleTime:        time.Since(conn.LastUsed),
+		ConnectedFor:    time.Since(conn.CreatedAt),
+		SuccessfulPings: conn.SuccessfulPings,
+		FailedPings:     conn.FailedPings,
+		LastError:       conn.LastError,
+		LastErrorKind:   conn.LastErrorKind,
+		LastErrorAt:     conn.LastErrorAt,
+		QueriesRun:      conn.QueriesRun,
+		RowsReturned:    conn.RowsReturned,
+		TotalQueryTime:  conn.TotalQueryTime,
+		LastQueryAt:     conn.LastQueryAt,
+	}
+}
+
 // GetPoolMetrics returns overall pool metrics
 func (p *ConnectionPool) GetPoolMetrics() *PoolMetrics {
 	p.mutex.RLock()
@@ -360,10 +650,13 @@ func (p *ConnectionPool) GetPoolMetrics() *PoolMetrics {
 		ActiveConnections:  int64(len(p.connections)),
 		ConnectedCount:     int64(connected),
 		ErrorCount:         int64(errors),
-		SuccessfulPings:    p.successfulPings,
-		FailedPings:        p.failedPings,
+		SuccessfulPings:    atomic.LoadInt64(&p.successfulPings),
+		FailedPings:        atomic.LoadInt64(&p.failedPings),
 		PingInterval:       p.pingInterval,
 		MaxIdleTime:        p.maxIdleTime,
+		TotalQueries:       atomic.LoadInt64(&p.totalQueries),
+		TotalRowsReturned:  atomic.LoadInt64(&p.totalRows),
+		TotalQueryTime:     time.Duration(atomic.LoadInt64(&p.totalQueryTime)),
 	}
 }
 
@@ -374,6 +667,11 @@ func (p *ConnectionPool) Close() error {
 	// Stop background monitor
 	p.cancel()
 	
+	// The metrics logger reads the pool, so let it finish before tearing it down
+	if p.metricsDone != nil {
+		<-p.metricsDone
+	}
+	
 	// Close all connections
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -406,6 +704,17 @@ type ConnectionStatus struct {
 	CreatedAt    time.Time         `json:"created_at"`
 	IdleTime     time.Duration     `json:"idle_time"`
 	ConnectedFor time.Duration     `json:"connected_for"`
+	
+	SuccessfulPings int64     `json:"successful_pings"`
+	FailedPings     int64     `json:"failed_pings"`
+	LastError       string      `json:"last_error,omitempty"`
+	LastErrorKind   FailureKind `json:"last_error_kind,omitempty"`
+	LastErrorAt     time.Time   `json:"last_error_at,omitempty"`
+	
+	QueriesRun     int64         `json:"queries_run"`
+	RowsReturned   int64         `json:"rows_returned"`
+	TotalQueryTime time.Duration `json:"total_query_time"`
+	LastQueryAt    time.Time     `json:"last_query_at,omitempty"` // zero until a tool runs a query
 }
 
 // PoolMetrics represents overall connection pool metrics
@@ -418,4 +727,7 @@ type PoolMetrics struct {
 	FailedPings        int64         `json:"failed_pings"`
 	PingInterval       time.Duration `json:"ping_interval"`
 	MaxIdleTime        time.Duration `json:"max_idle_time"`
+	TotalQueries       int64         `json:"total_queries"`
+	TotalRowsReturned  int64         `json:"total_rows_returned"`
+	TotalQueryTime     time.Duration `json:"total_query_time"`
 }
\ No newline at end of file
@@ -1,9 +1,15 @@
 package database
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/eliziario/simpledb-mcp/internal/config"
 	"github.com/eliziario/simpledb-mcp/internal/testutil"
 )
 
@@ -417,4 +423,632 @@ func TestPoolConcurrentAccess(t *testing.T) {
 			t.Error("Goroutine timed out")
 		}
 	}
-}
\ No newline at end of file
+}
+func TestCheckConnectionPerConnectionPingMetrics(t *testing.T) {
+	cfg := testConfig()
+	credManager := testutil.NewMockCredentialManager()
+	manager := NewManager(cfg, credManager)
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	driver := testutil.NewMockDriver()
+	healthyDB := testutil.OpenMockDB(t, driver, "healthy")
+	flakyDB := testutil.OpenMockDB(t, driver, "flaky")
+	testutil.AssertNoError(t, healthyDB.Ping())
+	testutil.AssertNoError(t, flakyDB.Ping())
+	driver.GetConnection("flaky").SetPingFails(true, fmt.Errorf("connection reset by peer"))
+	
+	healthy := &PooledConnection{Name: "healthy", DB: healthyDB, State: StateConnected}
+	flaky := &PooledConnection{Name: "flaky", DB: flakyDB, State: StateConnected}
+	
+	pool.checkConnection(healthy)
+	pool.checkConnection(healthy)
+	pool.checkConnection(flaky)
+	
+	testutil.AssertEqual(t, int64(2), healthy.SuccessfulPings)
+	testutil.AssertEqual(t, int64(0), healthy.FailedPings)
+	testutil.AssertEqual(t, "", healthy.LastError)
+	
+	testutil.AssertEqual(t, int64(0), flaky.SuccessfulPings)
+	testutil.AssertEqual(t, int64(1), flaky.FailedPings)
+	testutil.AssertContains(t, flaky.LastError, "connection reset by peer")
+	
+	// Pool-wide counters still aggregate across connections
+	testutil.AssertEqual(t, int64(2), pool.successfulPings)
+	testutil.AssertEqual(t, int64(1), pool.failedPings)
+	
+	// Per-connection counters are exposed through ConnectionStatus
+	pool.connections["flaky"] = flaky
+	status := pool.GetConnectionStatus("flaky")
+	testutil.AssertEqual(t, int64(1), status.FailedPings)
+	testutil.AssertContains(t, status.LastError, "connection reset by peer")
+}
+
+func TestCheckConnectionLastErrorSetAndCleared(t *testing.T) {
+	cfg := testConfig()
+	credManager := testutil.NewMockCredentialManager()
+	manager := NewManager(cfg, credManager)
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	driver := testutil.NewMockDriver()
+	db := testutil.OpenMockDB(t, driver, "recovering")
+	testutil.AssertNoError(t, db.Ping())
+	mockDB := driver.GetConnection("recovering")
+	
+	conn := &PooledConnection{Name: "recovering", DB: db, State: StateConnected}
+	
+	// A failed ping records the error and when it happened
+	mockDB.SetPingFails(true, fmt.Errorf("i/o timeout"))
+	before := time.Now()
+	pool.checkConnection(conn)
+	testutil.AssertEqual(t, StateError, conn.State)
+	testutil.AssertContains(t, conn.LastError, "i/o timeout")
+	if conn.LastErrorAt.Before(before) {
+		t.Errorf("Expected LastErrorAt to be set, got %v", conn.LastErrorAt)
+	}
+	
+	// A successful ping recovers the connection and clears the error
+	mockDB.SetPingFails(false, nil)
+	pool.checkConnection(conn)
+	testutil.AssertEqual(t, StateConnected, conn.State)
+	testutil.AssertEqual(t, "", conn.LastError)
+	testutil.AssertEqual(t, true, conn.LastErrorAt.IsZero())
+}
+
+func TestCreateConnectionFailureSetsLastError(t *testing.T) {
+	cfg := testConfig()
+	credManager := testutil.NewMockCredentialManager()
+	manager := NewManager(cfg, credManager)
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	// No credential stored for test-mysql, so creation fails before dialing
+	_, err := pool.GetConnection("test-mysql")
+	testutil.AssertError(t, err)
+	
+	status := pool.GetConnectionStatus("test-mysql")
+	testutil.AssertEqual(t, StateError, status.State)
+	testutil.AssertContains(t, status.LastError, "credential not found")
+	testutil.AssertEqual(t, FailureAuth, status.LastErrorKind)
+	if status.LastErrorAt.IsZero() {
+		t.Error("Expected LastErrorAt to be set")
+	}
+}
+
+func TestFailedConnectDoesNotLinger(t *testing.T) {
+	cfg := testConfig()
+	cfg.Settings.ConnectionPool.EnableKeepalive = false
+	credManager := testutil.NewMockCredentialManager()
+	manager := NewManager(cfg, credManager)
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	// No credential stored for test-mysql, so creation fails before dialing
+	for i := 0; i < 3; i++ {
+		_, err := pool.GetConnection("test-mysql")
+		testutil.AssertError(t, err)
+	}
+	
+	metrics := pool.GetPoolMetrics()
+	testutil.AssertEqual(t, int64(0), metrics.ActiveConnections)
+	testutil.AssertEqual(t, int64(0), metrics.ErrorCount)
+	testutil.AssertEqual(t, int64(0), metrics.TotalConnections)
+	
+	// With no entry in the pool the connection simply reports as disconnected
+	status := pool.GetConnectionStatus("test-mysql")
+	testutil.AssertEqual(t, StateDisconnected, status.State)
+}
+
+func TestFailedConnectKeptWhenKeepaliveEnabled(t *testing.T) {
+	cfg := testConfig()
+	credManager := testutil.NewMockCredentialManager()
+	manager := NewManager(cfg, credManager)
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	_, err := pool.GetConnection("test-mysql")
+	testutil.AssertError(t, err)
+	
+	// Keepalive manages the entry, so it stays in the error state
+	metrics := pool.GetPoolMetrics()
+	testutil.AssertEqual(t, int64(1), metrics.ActiveConnections)
+	testutil.AssertEqual(t, int64(1), metrics.ErrorCount)
+}
+
+// addMockConnection puts a connected mock database into the pool under the given name
+func addMockConnection(t *testing.T, pool *ConnectionPool, name string) *testutil.MockDB {
+	t.Helper()
+	driver := testutil.NewMockDriver()
+	db := testutil.OpenMockDB(t, driver, name)
+	testutil.AssertNoError(t, db.Ping())
+	
+	pool.mutex.Lock()
+	pool.connections[name] = &PooledConnection{
+		Name:      name,
+		DB:        db,
+		State:     StateConnected,
+		CreatedAt: time.Now(),
+		LastUsed:  time.Now(),
+	}
+	pool.mutex.Unlock()
+	return driver.GetConnection(name)
+}
+
+func TestQueryUsageStatistics(t *testing.T) {
+	cfg := testConfig()
+	credManager := testutil.NewMockCredentialManager()
+	manager := NewManager(cfg, credManager)
+	defer manager.Close()
+	
+	mysqlDB := addMockConnection(t, manager.pool, "test-mysql")
+	mysqlDB.SetQueryResult("SHOW DATABASES", []string{"Database"}, [][]interface{}{
+		{"app"}, {"analytics"}, {"audit"},
+	})
+	addMockConnection(t, manager.pool, "test-postgres")
+	
+	for i := 0; i < 2; i++ {
+		databases, err := manager.ListDatabasesMySQL("test-mysql")
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 3, len(databases))
+	}
+	
+	mysqlStatus := manager.GetConnectionStatus("test-mysql")
+	testutil.AssertEqual(t, int64(2), mysqlStatus.QueriesRun)
+	testutil.AssertEqual(t, int64(6), mysqlStatus.RowsReturned)
+	
+	// Counters are tracked per connection
+	postgresStatus := manager.GetConnectionStatus("test-postgres")
+	testutil.AssertEqual(t, int64(0), postgresStatus.QueriesRun)
+	
+	metrics := manager.GetPoolMetrics()
+	testutil.AssertEqual(t, int64(2), metrics.TotalQueries)
+	testutil.AssertEqual(t, int64(6), metrics.TotalRowsReturned)
+}
+
+func TestRecordQueryConcurrent(t *testing.T) {
+	cfg := testConfig()
+	credManager := testutil.NewMockCredentialManager()
+	manager := NewManager(cfg, credManager)
+	defer manager.Close()
+	
+	manager.pool.connections["busy"] = &PooledConnection{Name: "busy", State: StateConnected}
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			manager.pool.recordQuery("busy", 2, time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	
+	status := manager.GetConnectionStatus("busy")
+	testutil.AssertEqual(t, int64(50), status.QueriesRun)
+	testutil.AssertEqual(t, int64(100), status.RowsReturned)
+	testutil.AssertEqual(t, 50*time.Millisecond, status.TotalQueryTime)
+}
+
+// slowConnectManager returns a manager whose connections dial a mock driver
+// that takes pingDelay to answer the initial ping
+func slowConnectManager(t *testing.T, connectTimeout, pingDelay time.Duration) *Manager {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Settings.ConnectTimeout = connectTimeout
+	credManager := testutil.NewMockCredentialManager()
+	credManager.SetCredential("test-mysql", "testuser", "secret")
+	manager := NewManager(cfg, credManager)
+	
+	driver := testutil.NewMockDriver()
+	driver.SetPingDelay(pingDelay)
+	driverName := testutil.RegisterMockDriver(driver)
+	manager.openDB = func(_, dsn string) (*sql.DB, error) {
+		return sql.Open(driverName, dsn)
+	}
+	return manager
+}
+
+func TestSlowConnectTimesOut(t *testing.T) {
+	manager := slowConnectManager(t, 50*time.Millisecond, 5*time.Second)
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	start := time.Now()
+	_, err := pool.GetConnection("test-mysql")
+	elapsed := time.Since(start)
+	
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "context deadline exceeded")
+	if elapsed > time.Second {
+		t.Errorf("Expected connect to give up near the 50ms timeout, took %v", elapsed)
+	}
+}
+
+func TestSlowConnectDoesNotBlockStatus(t *testing.T) {
+	manager := slowConnectManager(t, 2*time.Second, 500*time.Millisecond)
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.GetConnection("test-mysql")
+		done <- err
+	}()
+	
+	// Wait until the connect is in flight
+	deadline := time.Now().Add(time.Second)
+	for pool.GetConnectionStatus("test-mysql").State != StateConnecting {
+		if time.Now().After(deadline) {
+			t.Fatal("Connection never entered the connecting state")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	
+	start := time.Now()
+	pool.GetPoolMetrics()
+	pool.GetAllConnectionStatus()
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Status calls blocked behind the connect for %v", elapsed)
+	}
+	
+	testutil.AssertNoError(t, <-done)
+	testutil.AssertEqual(t, StateConnected, pool.GetConnectionStatus("test-mysql").State)
+}
+
+func TestSlowConnectDoesNotBlockOtherConnections(t *testing.T) {
+	manager := slowConnectManager(t, 2*time.Second, time.Second)
+	manager.credManager.(*testutil.MockCredentialManager).SetCredential("test-postgres", "testuser", "secret")
+	
+	// Only the mysql connection is slow; postgres dials a driver that answers at once
+	slowOpen := manager.openDB
+	fastDriver := testutil.RegisterMockDriver(testutil.NewMockDriver())
+	manager.openDB = func(driverName, dsn string) (*sql.DB, error) {
+		if driverName == "postgres" {
+			return sql.Open(fastDriver, dsn)
+		}
+		return slowOpen(driverName, dsn)
+	}
+	
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	go pool.GetConnection("test-mysql")
+	
+	deadline := time.Now().Add(time.Second)
+	for pool.GetConnectionStatus("test-mysql").State != StateConnecting {
+		if time.Now().After(deadline) {
+			t.Fatal("Connection never entered the connecting state")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	
+	start := time.Now()
+	_, err := pool.GetConnection("test-postgres")
+	testutil.AssertNoError(t, err)
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Connect to another connection blocked behind the slow one for %v", elapsed)
+	}
+}
+
+func TestCloseConnectionWhileConnecting(t *testing.T) {
+	manager := slowConnectManager(t, 2*time.Second, 200*time.Millisecond)
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.GetConnection("test-mysql")
+		done <- err
+	}()
+	
+	deadline := time.Now().Add(time.Second)
+	for pool.GetConnectionStatus("test-mysql").State != StateConnecting {
+		if time.Now().After(deadline) {
+			t.Fatal("Connection never entered the connecting state")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	testutil.AssertEqual(t, true, pool.CloseConnection("test-mysql"))
+	
+	// The dial finishes after the entry is gone, so its handle is closed, not stored
+	err := <-done
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "was closed while it was being opened")
+	testutil.AssertEqual(t, 0, len(pool.GetAllConnectionStatus()))
+	testutil.AssertEqual(t, int64(0), pool.GetPoolMetrics().TotalConnections)
+	
+	// The next request opens a fresh connection
+	_, err = pool.GetConnection("test-mysql")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, StateConnected, pool.GetConnectionStatus("test-mysql").State)
+}
+
+func TestConcurrentConnectsShareAttempt(t *testing.T) {
+	manager := slowConnectManager(t, 2*time.Second, 100*time.Millisecond)
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	const callers = 5
+	dbs := make([]*sql.DB, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			db, err := pool.GetConnection("test-mysql")
+			testutil.AssertNoError(t, err)
+			dbs[i] = db
+		}(i)
+	}
+	wg.Wait()
+	
+	for i := 1; i < callers; i++ {
+		if dbs[i] != dbs[0] {
+			t.Fatalf("Caller %d got a different *sql.DB than caller 0", i)
+		}
+	}
+	testutil.AssertEqual(t, int64(1), pool.GetPoolMetrics().TotalConnections)
+}
+
+func TestConcurrentConnectsDialOnce(t *testing.T) {
+	manager := slowConnectManager(t, 2*time.Second, 100*time.Millisecond)
+	
+	var dials int64
+	open := manager.openDB
+	manager.openDB = func(driverName, dsn string) (*sql.DB, error) {
+		atomic.AddInt64(&dials, 1)
+		return open(driverName, dsn)
+	}
+	
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	const callers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := pool.GetConnection("test-mysql")
+			testutil.AssertNoError(t, err)
+		}()
+	}
+	wg.Wait()
+	
+	testutil.AssertEqual(t, int64(1), atomic.LoadInt64(&dials))
+}
+
+func TestPoolCapEvictsLeastRecentlyUsed(t *testing.T) {
+	manager := slowConnectManager(t, time.Second, 0)
+	manager.config.Settings.ConnectionPool.MaxPooledConnections = 2
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	addMockConnection(t, pool, "old")
+	addMockConnection(t, pool, "recent")
+	pool.connections["old"].LastUsed = time.Now().Add(-time.Hour)
+	oldDB := pool.connections["old"].DB
+	
+	_, err := pool.GetConnection("test-mysql")
+	testutil.AssertNoError(t, err)
+	
+	testutil.AssertEqual(t, int64(2), pool.GetPoolMetrics().ActiveConnections)
+	testutil.AssertEqual(t, StateDisconnected, pool.GetConnectionStatus("old").State)
+	testutil.AssertEqual(t, StateConnected, pool.GetConnectionStatus("recent").State)
+	testutil.AssertError(t, oldDB.Ping())
+}
+
+func TestPoolCapKeepsConnectionsWithInFlightWork(t *testing.T) {
+	manager := slowConnectManager(t, time.Second, 0)
+	manager.config.Settings.ConnectionPool.MaxPooledConnections = 2
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	addMockConnection(t, pool, "busy")
+	addMockConnection(t, pool, "idle")
+	pool.connections["busy"].LastUsed = time.Now().Add(-2 * time.Hour)
+	pool.connections["idle"].LastUsed = time.Now().Add(-time.Hour)
+	
+	// An open result set keeps the busy connection's only conn checked out
+	rows, err := pool.connections["busy"].DB.Query("SELECT 1")
+	testutil.AssertNoError(t, err)
+	defer rows.Close()
+	
+	_, err = pool.GetConnection("test-mysql")
+	testutil.AssertNoError(t, err)
+	
+	testutil.AssertEqual(t, StateConnected, pool.GetConnectionStatus("busy").State)
+	testutil.AssertEqual(t, StateDisconnected, pool.GetConnectionStatus("idle").State)
+	
+	// With everything busy the pool grows past the cap rather than failing
+	idleRows, err := pool.connections["test-mysql"].DB.Query("SELECT 1")
+	testutil.AssertNoError(t, err)
+	defer idleRows.Close()
+	manager.credManager.(*testutil.MockCredentialManager).SetCredential("test-postgres", "testuser", "secret")
+	_, err = pool.GetConnection("test-postgres")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, int64(3), pool.GetPoolMetrics().ActiveConnections)
+}
+
+func TestPoolCapKeepsConnectionsJustHandedOut(t *testing.T) {
+	manager := slowConnectManager(t, time.Second, 0)
+	manager.config.Settings.ConnectionPool.MaxPooledConnections = 1
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	addMockConnection(t, pool, "borrowed")
+	pool.connections["borrowed"].LastUsed = time.Now().Add(-time.Hour)
+	
+	// Handed out but no query started yet, so nothing is in use
+	db, err := pool.GetConnection("borrowed")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, db.Stats().InUse)
+	
+	_, err = pool.GetConnection("test-mysql")
+	testutil.AssertNoError(t, err)
+	
+	// The caller's handle stays open and the pool grows past the cap instead
+	testutil.AssertNoError(t, db.Ping())
+	testutil.AssertEqual(t, StateConnected, pool.GetConnectionStatus("borrowed").State)
+	testutil.AssertEqual(t, int64(2), pool.GetPoolMetrics().ActiveConnections)
+}
+
+func TestHealthCheckSkipsDisabledKeepalive(t *testing.T) {
+	cfg := testConfig()
+	warehouse := cfg.Connections["test-postgres"]
+	warehouse.DisableKeepalive = true
+	cfg.Connections["test-postgres"] = warehouse
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	addMockConnection(t, pool, "test-mysql")
+	addMockConnection(t, pool, "test-postgres")
+	
+	pool.healthCheck()
+	
+	testutil.AssertEqual(t, int64(1), pool.connections["test-mysql"].SuccessfulPings)
+	testutil.AssertEqual(t, int64(0), pool.connections["test-postgres"].SuccessfulPings)
+	testutil.AssertEqual(t, int64(1), pool.successfulPings)
+	
+	// The skipped connection is still usable on demand
+	db, err := pool.GetConnection("test-postgres")
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, db.Ping())
+}
+
+func TestHealthCheckSkipsDisabledConnections(t *testing.T) {
+	cfg := testConfig()
+	warehouse := cfg.Connections["test-postgres"]
+	warehouse.Enabled = new(bool)
+	cfg.Connections["test-postgres"] = warehouse
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	pool := NewConnectionPool(manager)
+	defer pool.Close()
+	
+	addMockConnection(t, pool, "test-mysql")
+	addMockConnection(t, pool, "test-postgres")
+	
+	pool.healthCheck()
+	
+	testutil.AssertEqual(t, int64(1), pool.connections["test-mysql"].SuccessfulPings)
+	testutil.AssertEqual(t, int64(0), pool.connections["test-postgres"].SuccessfulPings)
+	
+	err := manager.TestConnection("test-postgres")
+	if !errors.Is(err, config.ErrConnectionDisabled) {
+		t.Errorf("Expected ErrConnectionDisabled, got %v", err)
+	}
+}
+
+func TestLastQueryAtIgnoresPings(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+	
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	db.SetQueryResult("SHOW DATABASES", []string{"Database"}, [][]interface{}{{"app"}})
+	conn := manager.pool.connections["test-mysql"]
+	
+	// A background ping refreshes LastPing only
+	manager.pool.checkConnection(conn)
+	status := manager.GetConnectionStatus("test-mysql")
+	testutil.AssertEqual(t, false, status.LastPing.IsZero())
+	testutil.AssertEqual(t, true, status.LastQueryAt.IsZero())
+	
+	before := time.Now()
+	_, err := manager.ListDatabasesMySQL("test-mysql")
+	testutil.AssertNoError(t, err)
+	status = manager.GetConnectionStatus("test-mysql")
+	testutil.AssertEqual(t, false, status.LastQueryAt.Before(before))
+	
+	// Later pings leave it alone
+	queried := status.LastQueryAt
+	manager.pool.checkConnection(conn)
+	testutil.AssertEqual(t, true, manager.GetConnectionStatus("test-mysql").LastQueryAt.Equal(queried))
+}
+
+func TestWarmPingBeforeReturningIdleConnection(t *testing.T) {
+	cfg := testConfig()
+	cfg.Settings.ConnectionPool.WarmPingAfter = time.Minute
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	defer manager.Close()
+	
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	conn := manager.pool.connections["test-mysql"]
+	pings := db.PingCount()
+	
+	// Recently used: handed out without a ping
+	_, err := manager.pool.GetConnection("test-mysql")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, pings, db.PingCount())
+	
+	// Idle past the threshold: pinged first
+	conn.LastUsed = time.Now().Add(-2 * time.Minute)
+	_, err = manager.pool.GetConnection("test-mysql")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, pings+1, db.PingCount())
+	testutil.AssertEqual(t, int64(1), conn.SuccessfulPings)
+	
+	// A recent keepalive ping counts as activity
+	conn.LastUsed = time.Now().Add(-2 * time.Minute)
+	conn.LastPing = time.Now()
+	_, err = manager.pool.GetConnection("test-mysql")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, pings+1, db.PingCount())
+}
+
+func TestWarmPingFailureMarksConnection(t *testing.T) {
+	cfg := testConfig()
+	cfg.Settings.ConnectionPool.WarmPingAfter = time.Minute
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	defer manager.Close()
+	
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	conn := manager.pool.connections["test-mysql"]
+	conn.LastUsed = time.Now().Add(-2 * time.Minute)
+	db.SetPingFails(true, fmt.Errorf("connection reset by peer"))
+	
+	_, err := manager.pool.GetConnection("test-mysql")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "connection 'test-mysql' failed after being idle")
+	testutil.AssertEqual(t, StateError, conn.State)
+	testutil.AssertEqual(t, int64(1), conn.FailedPings)
+}
+
+func TestPingCountsConcurrentWarmPingsAndHealthChecks(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+	pool := manager.pool
+	
+	addMockConnection(t, pool, "test-mysql")
+	addMockConnection(t, pool, "test-postgres")
+	checked := pool.connections["test-mysql"]
+	warmed := pool.connections["test-postgres"]
+	
+	// Warm pings and health checks hold different connection mutexes, so the
+	// pool-wide counts must not depend on either
+	const rounds = 50
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			pool.checkConnection(checked)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			testutil.AssertNoError(t, pool.warmPing(warmed, warmed.DB))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			pool.GetPoolMetrics()
+		}
+	}()
+	wg.Wait()
+	
+	testutil.AssertEqual(t, int64(2*rounds), pool.GetPoolMetrics().SuccessfulPings)
+}
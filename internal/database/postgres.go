@@ -1,23 +1,27 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 )
 
+const postgresListDatabasesQuery = `
+		SELECT datname, datistemplate
+		FROM pg_database
+		ORDER BY datname`
+
 func (m *Manager) ListDatabasesPostgres(connectionName string) ([]string, error) {
 	db, err := m.GetConnection(connectionName)
 	if err != nil {
 		return nil, err
 	}
 
-	query := `
-		SELECT datname 
-		FROM pg_database 
-		WHERE datistemplate = false 
-		ORDER BY datname`
+	start := time.Now()
 
-	rows, err := db.Query(query)
+	rows, err := m.query(context.Background(), db, connectionName, postgresListDatabasesQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list databases: %w", err)
 	}
@@ -26,12 +30,19 @@ func (m *Manager) ListDatabasesPostgres(connectionName string) ([]string, error)
 	var databases []string
 	for rows.Next() {
 		var dbName string
-		if err := rows.Scan(&dbName); err != nil {
+		var isTemplate bool
+		if err := rows.Scan(&dbName, &isTemplate); err != nil {
 			return nil, fmt.Errorf("failed to scan database name: %w", err)
 		}
+		// template0/template1 and the default postgres maintenance database
+		if m.config.Settings.HideSystemDatabases && (isTemplate || dbName == "postgres") {
+			continue
+		}
 		databases = append(databases, dbName)
 	}
 
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(databases), time.Since(start))
+
 	return databases, nil
 }
 
@@ -41,13 +52,15 @@ func (m *Manager) ListSchemasPostgres(connectionName, database string) ([]string
 		return nil, err
 	}
 
+	start := time.Now()
+
 	query := `
 		SELECT schema_name 
 		FROM information_schema.schemata 
 		WHERE schema_name NOT IN ('information_schema', 'pg_catalog', 'pg_toast')
 		ORDER BY schema_name`
 
-	rows, err := db.Query(query)
+	rows, err := m.query(context.Background(), db, connectionName, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list schemas: %w", err)
 	}
@@ -62,6 +75,8 @@ func (m *Manager) ListSchemasPostgres(connectionName, database string) ([]string
 		schemas = append(schemas, schemaName)
 	}
 
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(schemas), time.Since(start))
+
 	return schemas, nil
 }
 
@@ -71,6 +86,8 @@ func (m *Manager) ListTablesPostgres(connectionName, database, schema string) ([
 		return nil, err
 	}
 
+	start := time.Now()
+
 	if schema == "" {
 		schema = "public"
 	}
@@ -86,7 +103,7 @@ func (m *Manager) ListTablesPostgres(connectionName, database, schema string) ([
 		WHERE t.table_schema = $1
 		ORDER BY t.table_name`
 
-	rows, err := db.Query(query, schema)
+	rows, err := m.query(context.Background(), db, connectionName, query, schema)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tables: %w", err)
 	}
@@ -105,15 +122,60 @@ func (m *Manager) ListTablesPostgres(connectionName, database, schema string) ([
 		tables = append(tables, table)
 	}
 
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(tables), time.Since(start))
+
 	return tables, nil
 }
 
+// DescribeDatabasePostgres summarizes a schema's tables, views, row estimates and size
+func (m *Manager) DescribeDatabasePostgres(connectionName, database, schema string, maxTables int) (*DatabaseSummary, error) {
+	tables, err := m.ListTablesPostgres(connectionName, database, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	if schema == "" {
+		schema = "public"
+	}
+
+	// Total on-disk size, including indexes and TOAST, in one aggregate query
+	query := `
+		SELECT COALESCE(SUM(pg_total_relation_size(c.oid)), 0)::bigint
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'm', 'p')`
+
+	var totalSize int64
+	if err := m.queryRow(context.Background(), db, connectionName, query, schema).Scan(&totalSize); err != nil {
+		return nil, fmt.Errorf("failed to get schema size: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), 1, time.Since(start))
+
+	summary := summarizeTables(tables, maxTables)
+	summary.TotalSizeBytes = &totalSize
+	return summary, nil
+}
+
 func (m *Manager) DescribeTablePostgres(connectionName, database, tableName, schema string) ([]ColumnInfo, error) {
+	if err := validateIdentifiers("postgres", database, schema, tableName); err != nil {
+		return nil, err
+	}
+
 	db, err := m.GetConnection(connectionName)
 	if err != nil {
 		return nil, err
 	}
 
+	start := time.Now()
+
 	if schema == "" {
 		schema = "public"
 	}
@@ -124,19 +186,24 @@ func (m *Manager) DescribeTablePostgres(connectionName, database, tableName, sch
 			c.data_type,
 			c.is_nullable = 'YES' as is_nullable,
 			c.column_default,
-			COALESCE(tc.constraint_type = 'PRIMARY KEY', false) as is_primary_key
+			pk.column_name IS NOT NULL as is_primary_key
 		FROM information_schema.columns c
-		LEFT JOIN information_schema.key_column_usage kcu 
-			ON c.table_name = kcu.table_name 
-			AND c.column_name = kcu.column_name
-			AND c.table_schema = kcu.table_schema
-		LEFT JOIN information_schema.table_constraints tc
-			ON kcu.constraint_name = tc.constraint_name
-			AND tc.constraint_type = 'PRIMARY KEY'
+		LEFT JOIN (
+			-- Only the primary key, so a column that is also in a foreign
+			-- key or unique constraint is listed once
+			SELECT kcu.column_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu
+				ON kcu.constraint_name = tc.constraint_name
+				AND kcu.table_schema = tc.table_schema
+				AND kcu.table_name = tc.table_name
+			WHERE tc.constraint_type = 'PRIMARY KEY'
+				AND tc.table_schema = $1 AND tc.table_name = $2
+		) pk ON pk.column_name = c.column_name
 		WHERE c.table_schema = $1 AND c.table_name = $2
 		ORDER BY c.ordinal_position`
 
-	rows, err := db.Query(query, schema, tableName)
+	rows, err := m.query(context.Background(), db, connectionName, query, schema, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe table: %w", err)
 	}
@@ -155,15 +222,23 @@ func (m *Manager) DescribeTablePostgres(connectionName, database, tableName, sch
 		columns = append(columns, col)
 	}
 
-	return columns, nil
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(columns), time.Since(start))
+
+	return numberColumns(columns), nil
 }
 
 func (m *Manager) ListIndexesPostgres(connectionName, database, tableName, schema string) ([]IndexInfo, error) {
+	if err := validateIdentifiers("postgres", database, schema, tableName); err != nil {
+		return nil, err
+	}
+
 	db, err := m.GetConnection(connectionName)
 	if err != nil {
 		return nil, err
 	}
 
+	start := time.Now()
+
 	if schema == "" {
 		schema = "public"
 	}
@@ -182,7 +257,7 @@ func (m *Manager) ListIndexesPostgres(connectionName, database, tableName, schem
 		WHERE i.schemaname = $1 AND i.tablename = $2
 		ORDER BY i.indexname`
 
-	rows, err := db.Query(query, schema, tableName)
+	rows, err := m.query(context.Background(), db, connectionName, query, schema, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list indexes: %w", err)
 	}
@@ -196,31 +271,55 @@ func (m *Manager) ListIndexesPostgres(connectionName, database, tableName, schem
 			return nil, fmt.Errorf("failed to scan index info: %w", err)
 		}
 
-		// Parse column names from index definition (simplified)
-		// In a production system, you'd want more robust parsing
+		method, columns := parseIndexDef(indexDef)
 		indexes = append(indexes, IndexInfo{
-			Name:    indexName,
-			Columns: []string{}, // TODO: Parse from indexdef
-			Type:    "btree",    // Default for Postgres
-			Unique:  isUnique,
+			Name:       indexName,
+			Columns:    columns,
+			Type:       method,
+			Unique:     isUnique,
+			Definition: indexDef,
 		})
 	}
 
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(indexes), time.Since(start))
+
 	return indexes, nil
 }
 
-func (m *Manager) GetTableSamplePostgres(connectionName, database, tableName, schema string, limit int) (map[string]interface{}, error) {
-	db, err := m.GetConnection(connectionName)
+func (m *Manager) GetTableSamplePostgres(ctx context.Context, connectionName, database, tableName, schema string, limit int) (map[string]interface{}, error) {
+	collector := &sampleCollector{}
+	summary, err := m.StreamTableSamplePostgres(ctx, connectionName, database, tableName, schema, limit, collector)
 	if err != nil {
 		return nil, err
 	}
+	return collector.result(summary.ScanErrors), nil
+}
+
+// StreamTableSamplePostgres reads up to limit rows of a table into w one row at a
+// time, without holding the sample in memory
+func (m *Manager) StreamTableSamplePostgres(ctx context.Context, connectionName, database, tableName, schema string, limit int, w SampleWriter) (*SampleSummary, error) {
+	if err := validateIdentifiers("postgres", database, schema, tableName); err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetDataConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
 
 	if schema == "" {
 		schema = "public"
 	}
 
-	query := fmt.Sprintf(`SELECT * FROM "%s"."%s" LIMIT %d`, schema, tableName, limit)
-	rows, err := db.Query(query)
+	table, err := qualifiedName("postgres", schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s LIMIT %d", table, limit)
+	rows, err := m.query(ctx, db, connectionName, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get table sample: %w", err)
 	}
@@ -231,38 +330,81 @@ func (m *Manager) GetTableSamplePostgres(connectionName, database, tableName, sc
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
-	var results []map[string]interface{}
-	for rows.Next() {
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
-		}
+	// Column types from the result set tell us which columns are arrays
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column types: %w", err)
+	}
+
+	if err := w.WriteColumns(columns); err != nil {
+		return nil, err
+	}
 
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+	summary, err := scanSampleRows(rows, columns, func(i int, val interface{}) interface{} {
+		b, ok := val.([]byte)
+		if !ok {
+			return val
 		}
+		typeName := columnTypes[i].DatabaseTypeName()
+		if isPostgresArrayType(typeName) {
+			// Arrays become JSON arrays; fall back to the raw literal if it can't be parsed
+			if parsed, err := parsePostgresArray(typeName, string(b)); err == nil {
+				return parsed
+			}
+		}
+		// Handle byte arrays (TEXT, VARCHAR, etc.)
+		// Escape and clean text for JSON safety
+		return cleanTextForJSON(string(b))
+	}, func(row map[string]interface{}) error {
+		return w.WriteRow(m.withNullRepresentation(row))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, true), summary.Rows, time.Since(start))
 
-		row := make(map[string]interface{})
-		for i, col := range columns {
-			val := values[i]
-			if val == nil {
-				row[col] = nil
-			} else if b, ok := val.([]byte); ok {
-				// Handle byte arrays (TEXT, VARCHAR, etc.)
-				text := string(b)
-				// Escape and clean text for JSON safety
-				row[col] = cleanTextForJSON(text)
-			} else {
-				row[col] = val
+	return summary, nil
+}
+
+// parseIndexDef reads the access method and key columns from a pg_indexes
+// definition such as CREATE UNIQUE INDEX users_email ON public.users USING btree (lower(email), id).
+// Expressions are kept as written; quoted column names are unquoted.
+func parseIndexDef(indexDef string) (string, []string) {
+	using := strings.Index(indexDef, " USING ")
+	if using < 0 {
+		return "btree", []string{}
+	}
+	rest := indexDef[using+len(" USING "):]
+	open := strings.IndexByte(rest, '(')
+	if open < 0 {
+		return strings.TrimSpace(rest), []string{}
+	}
+	method := strings.TrimSpace(rest[:open])
+
+	// Find the parenthesis closing the key list
+	depth, end := 0, -1
+	for i := open; i < len(rest) && end < 0; i++ {
+		switch rest[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				end = i
 			}
 		}
-		results = append(results, row)
+	}
+	if end < 0 {
+		return method, []string{}
 	}
 
-	return map[string]interface{}{
-		"columns":       columns,
-		"rows":          results,
-		"total_sampled": len(results),
-	}, nil
+	columns := []string{}
+	for _, column := range splitTopLevel(rest[open+1 : end]) {
+		if len(column) > 1 && column[0] == '"' && column[len(column)-1] == '"' {
+			column = strings.ReplaceAll(column[1:len(column)-1], `""`, `"`)
+		}
+		columns = append(columns, column)
+	}
+	return method, columns
 }
@@ -0,0 +1,208 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Grant is a set of privileges the connection's user holds on one object,
+// or, for MySQL role grants, the roles granted to it
+type Grant struct {
+	Privileges []string `json:"privileges,omitempty"`
+	Roles      []string `json:"roles,omitempty"`
+	Object     string   `json:"object,omitempty"` // db.table, schema.table or *.* for global privileges
+	Grantee    string   `json:"grantee"`
+	Grantable  bool     `json:"grantable,omitempty"`
+	Statement  string   `json:"statement,omitempty"` // the SHOW GRANTS line a MySQL grant was read from
+}
+
+// Only rows granted to the user or one of its enabled roles are kept; grants
+// the user merely gave away, and grants on the system catalogs, are not its privileges
+const postgresPrivilegesQuery = `
+		SELECT grantee, table_schema, table_name, privilege_type, is_grantable
+		FROM information_schema.role_table_grants
+		WHERE grantee IN (SELECT role_name FROM information_schema.enabled_roles)
+			AND table_schema NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY grantee, table_schema, table_name, is_grantable, privilege_type`
+
+const mysqlPrivilegesQuery = "SHOW GRANTS"
+
+// GetPrivilegesPostgres lists the table privileges of the connection's user
+// in the connection's database, one grant per table
+func (m *Manager) GetPrivilegesPostgres(connectionName string) ([]Grant, error) {
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	rows, err := m.query(context.Background(), db, connectionName, postgresPrivilegesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list privileges: %w", err)
+	}
+	defer rows.Close()
+
+	// One row per privilege; consecutive rows for the same grantee, table and
+	// grantability are merged
+	grants := []Grant{}
+	for rows.Next() {
+		var grantee, schema, table, privilege, grantable string
+		if err := rows.Scan(&grantee, &schema, &table, &privilege, &grantable); err != nil {
+			return nil, fmt.Errorf("failed to scan privilege: %w", err)
+		}
+		object := schema + "." + table
+		isGrantable := grantable == "YES"
+
+		last := len(grants) - 1
+		if last >= 0 && grants[last].Grantee == grantee && grants[last].Object == object && grants[last].Grantable == isGrantable {
+			grants[last].Privileges = append(grants[last].Privileges, privilege)
+			continue
+		}
+		grants = append(grants, Grant{
+			Privileges: []string{privilege},
+			Object:     object,
+			Grantee:    grantee,
+			Grantable:  isGrantable,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read privileges: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(grants), time.Since(start))
+
+	return grants, nil
+}
+
+// GetPrivilegesMySQL lists the grants of the connection's user, as SHOW GRANTS reports them
+func (m *Manager) GetPrivilegesMySQL(connectionName string) ([]Grant, error) {
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	rows, err := m.query(context.Background(), db, connectionName, mysqlPrivilegesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list privileges: %w", err)
+	}
+	defer rows.Close()
+
+	grants := []Grant{}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("failed to scan grant: %w", err)
+		}
+		grant, err := parseMySQLGrant(line)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read privileges: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(grants), time.Since(start))
+
+	return grants, nil
+}
+
+// mysqlPasswordHash matches the password hash MySQL 5.x includes in SHOW GRANTS
+var mysqlPasswordHash = regexp.MustCompile(`(?i)(IDENTIFIED BY PASSWORD )'[^']*'`)
+
+// parseMySQLGrant reads one line of SHOW GRANTS output, either
+//
+//	GRANT <privileges> ON <object> TO <user> [WITH GRANT OPTION]
+//	GRANT <roles> TO <user> [WITH ADMIN OPTION]
+func parseMySQLGrant(line string) (Grant, error) {
+	statement := mysqlPasswordHash.ReplaceAllString(strings.TrimSpace(line), "${1}'<redacted>'")
+	grant := Grant{Statement: statement}
+
+	if !strings.HasPrefix(strings.ToUpper(statement), "GRANT ") {
+		return grant, fmt.Errorf("unexpected SHOW GRANTS output: %s", statement)
+	}
+	rest := statement[len("GRANT "):]
+
+	to := indexKeyword(rest, " TO ")
+	if to < 0 {
+		return grant, fmt.Errorf("unexpected SHOW GRANTS output: %s", statement)
+	}
+	granted, target := rest[:to], rest[to+len(" TO "):]
+
+	// Options and MySQL 5.x account clauses follow the grantee
+	upperTarget := strings.ToUpper(target)
+	for _, clause := range []string{" IDENTIFIED ", " REQUIRE ", " WITH "} {
+		if i := indexKeyword(target, clause); i >= 0 {
+			target = target[:i]
+		}
+	}
+	grant.Grantee = unquoteMySQL(strings.TrimSpace(target))
+	grant.Grantable = strings.Contains(upperTarget, "WITH GRANT OPTION") || strings.Contains(upperTarget, "WITH ADMIN OPTION")
+
+	if on := indexKeyword(granted, " ON "); on >= 0 {
+		for _, privilege := range splitTopLevel(granted[:on]) {
+			grant.Privileges = append(grant.Privileges, unquoteMySQL(privilege))
+		}
+		grant.Object = unquoteMySQL(strings.TrimSpace(granted[on+len(" ON "):]))
+		return grant, nil
+	}
+	for _, role := range splitTopLevel(granted) {
+		grant.Roles = append(grant.Roles, unquoteMySQL(role))
+	}
+	return grant, nil
+}
+
+// indexKeyword finds a keyword, case-insensitively, outside quotes and parentheses
+func indexKeyword(s, keyword string) int {
+	var quote byte
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '`' || c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && i+len(keyword) <= len(s) && strings.EqualFold(s[i:i+len(keyword)], keyword):
+			return i
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits a comma-separated list, keeping column lists such as
+// SELECT (`a`, `b`) whole
+func splitTopLevel(s string) []string {
+	var parts []string
+	for {
+		i := indexKeyword(s, ",")
+		if i < 0 {
+			break
+		}
+		parts = append(parts, strings.TrimSpace(s[:i]))
+		s = s[i+1:]
+	}
+	if s = strings.TrimSpace(s); s != "" {
+		parts = append(parts, s)
+	}
+	return parts
+}
+
+// unquoteMySQL drops the quotes around names and accounts, so `shop`.* reads
+// shop.* and 'app'@'%' reads app@%
+func unquoteMySQL(s string) string {
+	return strings.NewReplacer("`", "", "'", "", `"`, "").Replace(s)
+}
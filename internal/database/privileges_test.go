@@ -0,0 +1,93 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestParseMySQLGrant(t *testing.T) {
+	tests := []struct {
+		line       string
+		privileges string
+		roles      string
+		object     string
+		grantee    string
+		grantable  bool
+	}{
+		{"GRANT USAGE ON *.* TO `app`@`%`", "USAGE", "", "*.*", "app@%", false},
+		{"GRANT SELECT, INSERT, UPDATE ON `shop`.* TO `app`@`%` WITH GRANT OPTION", "SELECT|INSERT|UPDATE", "", "shop.*", "app@%", true},
+		{"GRANT SELECT (`id`, `email`), UPDATE (`email`) ON `shop`.`users` TO 'app'@'10.0.%'", "SELECT (id, email)|UPDATE (email)", "", "shop.users", "app@10.0.%", false},
+		{"GRANT `reader`@`%`,`auditor`@`%` TO `app`@`%`", "", "reader@%|auditor@%", "", "app@%", false},
+		{"GRANT `admin`@`%` TO `ops`@`localhost` WITH ADMIN OPTION", "", "admin@%", "", "ops@localhost", true},
+		{"GRANT ALL PRIVILEGES ON `on to`.* TO 'legacy'@'localhost' IDENTIFIED BY PASSWORD '*ABCDEF'", "ALL PRIVILEGES", "", "on to.*", "legacy@localhost", false},
+	}
+
+	for _, tt := range tests {
+		grant, err := parseMySQLGrant(tt.line)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, tt.privileges, strings.Join(grant.Privileges, "|"))
+		testutil.AssertEqual(t, tt.roles, strings.Join(grant.Roles, "|"))
+		testutil.AssertEqual(t, tt.object, grant.Object)
+		testutil.AssertEqual(t, tt.grantee, grant.Grantee)
+		testutil.AssertEqual(t, tt.grantable, grant.Grantable)
+	}
+
+	_, err := parseMySQLGrant("REVOKE SELECT ON *.* FROM `app`@`%`")
+	testutil.AssertError(t, err)
+}
+
+func TestParseMySQLGrantRedactsPasswordHash(t *testing.T) {
+	grant, err := parseMySQLGrant("GRANT USAGE ON *.* TO 'legacy'@'%' IDENTIFIED BY PASSWORD '*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19'")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, false, strings.Contains(grant.Statement, "2470C0C0"))
+	testutil.AssertContains(t, grant.Statement, "IDENTIFIED BY PASSWORD '<redacted>'")
+}
+
+func TestGetPrivilegesMySQL(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	db.SetQueryResult(mysqlPrivilegesQuery, []string{"Grants for app@%"}, [][]interface{}{
+		{"GRANT USAGE ON *.* TO `app`@`%`"},
+		{"GRANT SELECT ON `shop`.* TO `app`@`%`"},
+	})
+
+	grants, err := manager.GetPrivilegesMySQL("test-mysql")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(grants))
+	testutil.AssertEqual(t, "SELECT", strings.Join(grants[1].Privileges, ","))
+	testutil.AssertEqual(t, "shop.*", grants[1].Object)
+	testutil.AssertEqual(t, "GRANT SELECT ON `shop`.* TO `app`@`%`", grants[1].Statement)
+}
+
+func TestGetPrivilegesPostgres(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-postgres")
+	db.SetQueryResult(postgresPrivilegesQuery, []string{"grantee", "table_schema", "table_name", "privilege_type", "is_grantable"}, [][]interface{}{
+		{"app", "public", "orders", "INSERT", "NO"},
+		{"app", "public", "orders", "SELECT", "NO"},
+		{"app", "public", "orders", "DELETE", "YES"},
+		{"app", "public", "users", "SELECT", "NO"},
+		{"reporting", "public", "users", "SELECT", "NO"},
+	})
+
+	grants, err := manager.GetPrivilegesPostgres("test-postgres")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 4, len(grants))
+
+	// Privileges on a table are grouped by whether they can be passed on
+	testutil.AssertEqual(t, "public.orders", grants[0].Object)
+	testutil.AssertEqual(t, "INSERT,SELECT", strings.Join(grants[0].Privileges, ","))
+	testutil.AssertEqual(t, false, grants[0].Grantable)
+	testutil.AssertEqual(t, "DELETE", strings.Join(grants[1].Privileges, ","))
+	testutil.AssertEqual(t, true, grants[1].Grantable)
+	testutil.AssertEqual(t, "public.users", grants[2].Object)
+
+	// Grants held through a role keep the role as grantee
+	testutil.AssertEqual(t, "reporting", grants[3].Grantee)
+}
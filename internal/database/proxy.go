@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"golang.org/x/net/proxy"
+)
+
+// proxyDialer returns a dialer that tunnels through the connection's SOCKS5
+// proxy, or nil when the connection dials directly.
+func proxyDialer(conn config.Connection) (proxy.ContextDialer, error) {
+	if conn.Proxy == "" {
+		return nil, nil
+	}
+
+	u, err := config.ParseProxyURL(conn.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth *proxy.Auth
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth = &proxy.Auth{User: u.User.Username(), Password: password}
+	}
+
+	dialer, err := proxy.SOCKS5("tcp", u.Host, auth, proxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOCKS5 dialer: %w", err)
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("SOCKS5 dialer does not support contexts")
+	}
+	return contextDialer, nil
+}
+
+var (
+	mysqlProxyNetworksMu sync.Mutex
+	mysqlProxyNetworks   = make(map[string]bool)
+)
+
+// mysqlNetwork returns the network name to put in a MySQL DSN. Proxied
+// connections get a network registered with the driver, named after a hash
+// of the proxy URL so credentials in it never appear in the DSN.
+func mysqlNetwork(conn config.Connection) (string, error) {
+	dialer, err := proxyDialer(conn)
+	if err != nil || dialer == nil {
+		return "tcp", err
+	}
+
+	network := fmt.Sprintf("socks5-%x", sha256.Sum256([]byte(conn.Proxy)))[:22]
+
+	mysqlProxyNetworksMu.Lock()
+	defer mysqlProxyNetworksMu.Unlock()
+	if !mysqlProxyNetworks[network] {
+		mysql.RegisterDialContext(network, func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", addr)
+		})
+		mysqlProxyNetworks[network] = true
+	}
+	return network, nil
+}
+
+// pqDialer adapts a proxy dialer to the lib/pq Dialer interfaces
+type pqDialer struct {
+	dialer proxy.ContextDialer
+}
+
+func (d pqDialer) Dial(network, address string) (net.Conn, error) {
+	return d.dialer.DialContext(context.Background(), network, address)
+}
+
+func (d pqDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return d.dialer.DialContext(ctx, network, address)
+}
+
+func (d pqDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return d.dialer.DialContext(ctx, network, address)
+}
+
+// openPostgresViaProxy opens a Postgres handle whose connections are dialed through the proxy
+func openPostgresViaProxy(dsn string, dialer proxy.ContextDialer) (*sql.DB, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	connector.Dialer(pqDialer{dialer: dialer})
+	return sql.OpenDB(connector), nil
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/simpleforce/simpleforce"
+)
+
+// logQuery logs a query a tool is about to run when log_queries is enabled.
+// engine selects comment syntax when literals are redacted.
+func (m *Manager) logQuery(connectionName, engine, query string, args ...interface{}) {
+	if !m.config.Settings.LogQueries {
+		return
+	}
+
+	text := query
+	if m.config.Settings.LogQueriesRedactLiterals {
+		text = RedactWhereLiterals(engine, query)
+		if len(args) > 0 {
+			text += fmt.Sprintf(" args=[%s]", strings.TrimSuffix(strings.Repeat("?,", len(args)), ","))
+		}
+	} else if len(args) > 0 {
+		text += fmt.Sprintf(" args=%v", args)
+	}
+	log.Printf("DEBUG query [%s] %s", connectionName, text)
+}
+
+// query runs a SQL query for a tool, logging it first
+func (m *Manager) query(ctx context.Context, db *sql.DB, connectionName, query string, args ...interface{}) (*sql.Rows, error) {
+	m.logQuery(connectionName, m.engineOf(connectionName), query, args...)
+	return db.QueryContext(ctx, query, args...)
+}
+
+// queryRow runs a single-row SQL query for a tool, logging it first
+func (m *Manager) queryRow(ctx context.Context, db *sql.DB, connectionName, query string, args ...interface{}) *sql.Row {
+	m.logQuery(connectionName, m.engineOf(connectionName), query, args...)
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+// soql runs a SOQL query for a tool, logging it first
+func (m *Manager) soql(connectionName string, sfClient *SalesforceClient, query string) (*simpleforce.QueryResult, error) {
+	m.logQuery(connectionName, "salesforce", query)
+	return sfClient.client.Query(query)
+}
+
+// engineOf returns the configured type of a connection, or "" if unknown
+func (m *Manager) engineOf(connectionName string) string {
+	conn, _ := m.config.GetConnection(connectionName)
+	return conn.Type
+}
+
+// whereClauseEnd lists the keywords that end a WHERE clause
+var whereClauseEnd = map[string]bool{
+	"GROUP": true, "ORDER": true, "HAVING": true, "LIMIT": true, "OFFSET": true,
+	"UNION": true, "EXCEPT": true, "INTERSECT": true, "FETCH": true, "WINDOW": true,
+}
+
+// RedactWhereLiterals replaces string and number literals inside WHERE
+// clauses with ?, leaving the rest of the query readable. A query that cannot
+// be parsed is reduced to its first word so nothing leaks.
+func RedactWhereLiterals(engine, query string) string {
+	tokens, err := tokenizeSQL(engine, query)
+	if err != nil {
+		if fields := strings.Fields(query); len(fields) > 0 {
+			return fields[0] + " <unparsed query redacted>"
+		}
+		return query
+	}
+
+	var b strings.Builder
+	last := 0
+	// whereDepth holds the parenthesis depth of each open WHERE clause
+	var whereDepth []int
+	for _, tok := range tokens {
+		for len(whereDepth) > 0 {
+			top := whereDepth[len(whereDepth)-1]
+			if tok.depth < top || (tok.depth == top && (whereClauseEnd[strings.ToUpper(tok.text)] || tok.text == ";")) {
+				whereDepth = whereDepth[:len(whereDepth)-1]
+				continue
+			}
+			break
+		}
+
+		if strings.EqualFold(tok.text, "WHERE") {
+			whereDepth = append(whereDepth, tok.depth)
+			continue
+		}
+		if len(whereDepth) > 0 && isLiteral(tok.text) {
+			b.WriteString(query[last:tok.start])
+			b.WriteString("?")
+			last = tok.end
+		}
+	}
+	b.WriteString(query[last:])
+	return b.String()
+}
+
+// isLiteral reports whether a token is a quoted string or a number
+func isLiteral(text string) bool {
+	if text[0] == '\'' {
+		return true
+	}
+	if text[0] < '0' || text[0] > '9' {
+		return false
+	}
+	for i := 0; i < len(text); i++ {
+		if (text[i] < '0' || text[i] > '9') && text[i] != '.' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+// captureLog redirects the standard logger into a buffer for the duration of a test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() {
+		log.SetOutput(original)
+	})
+	return &buf
+}
+
+func TestLogQueries(t *testing.T) {
+	cfg := testConfig()
+	cfg.Settings.LogQueries = true
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	defer manager.Close()
+	addMockConnection(t, manager.pool, "test-mysql")
+	logs := captureLog(t)
+
+	_, err := manager.GetTableSampleMySQL(context.Background(), "test-mysql", "shop", "orders", 5)
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, logs.String(), "DEBUG query [test-mysql] SELECT * FROM `shop`.`orders` LIMIT 5")
+
+	// Bound parameters are logged alongside the query
+	_, err = manager.ListTablesMySQL("test-mysql", "shop")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, logs.String(), "WHERE TABLE_SCHEMA = ?")
+	testutil.AssertContains(t, logs.String(), "args=[shop]")
+}
+
+func TestLogQueriesDisabled(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+	addMockConnection(t, manager.pool, "test-mysql")
+	logs := captureLog(t)
+
+	_, err := manager.GetTableSampleMySQL(context.Background(), "test-mysql", "shop", "orders", 5)
+	testutil.AssertNoError(t, err)
+	if strings.Contains(logs.String(), "DEBUG query") {
+		t.Errorf("Expected no query logging by default, got %q", logs.String())
+	}
+}
+
+func TestLogQueriesRedactsLiterals(t *testing.T) {
+	cfg := testConfig()
+	cfg.Settings.LogQueries = true
+	cfg.Settings.LogQueriesRedactLiterals = true
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	defer manager.Close()
+	logs := captureLog(t)
+
+	manager.logQuery("test-postgres", "postgres", "SELECT id FROM users WHERE email = 'a@b.com' AND age > 30 LIMIT 10", "secret")
+	testutil.AssertContains(t, logs.String(), "SELECT id FROM users WHERE email = ? AND age > ? LIMIT 10 args=[?]")
+	if strings.Contains(logs.String(), "secret") {
+		t.Error("Expected bound parameters to be redacted")
+	}
+}
+
+func TestRedactWhereLiterals(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT Id FROM Account WHERE Name = 'Acme' ORDER BY Name", "SELECT Id FROM Account WHERE Name = ? ORDER BY Name"},
+		{"SELECT 1 FROM t WHERE x IN (1, 2.5, 'a''b') GROUP BY y", "SELECT 1 FROM t WHERE x IN (?, ?, ?) GROUP BY y"},
+		{"SELECT a FROM t WHERE b = (SELECT c FROM u WHERE d = 'x') AND e = 7", "SELECT a FROM t WHERE b = (SELECT c FROM u WHERE d = ?) AND e = ?"},
+		{"SELECT 'label', 42 FROM t", "SELECT 'label', 42 FROM t"},
+		{"SELECT * FROM t WHERE col_1 = $1", "SELECT * FROM t WHERE col_1 = $1"},
+		{"SELECT * FROM t WHERE s = 'unterminated", "SELECT <unparsed query redacted>"},
+	}
+	for _, tc := range cases {
+		testutil.AssertEqual(t, tc.expected, RedactWhereLiterals("postgres", tc.query))
+	}
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/simpleforce/simpleforce"
+)
+
+// RecentChange is when a table's data was last modified
+type RecentChange struct {
+	Table        string    `json:"table"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// RecentChangesReport lists tables most recently modified first. Engines that
+// don't track modification times give an empty report with Available false
+// and the reason.
+type RecentChangesReport struct {
+	Available bool           `json:"available"`
+	Reason    string         `json:"reason,omitempty"`
+	Tables    []RecentChange `json:"tables"`
+}
+
+// MaxSalesforceRecentObjects bounds how many objects one call checks, since
+// each costs a SOQL query against the org's API limits
+const MaxSalesforceRecentObjects = 50
+
+// UPDATE_TIME is NULL for tables not modified since the server started
+// (InnoDB keeps it in memory only)
+const mysqlRecentChangesQuery = `
+		SELECT TABLE_NAME, UPDATE_TIME
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = ? AND UPDATE_TIME IS NOT NULL
+		ORDER BY UPDATE_TIME DESC, TABLE_NAME
+		LIMIT ?`
+
+// salesforceDateTimeLayout is how SOQL returns datetime fields
+const salesforceDateTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// GetRecentChangesMySQL lists a database's tables by INFORMATION_SCHEMA
+// UPDATE_TIME, most recent first
+func (m *Manager) GetRecentChangesMySQL(ctx context.Context, connectionName, database string, limit int) (*RecentChangesReport, error) {
+	if err := validateIdentifiers("mysql", database); err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	rows, err := m.query(ctx, db, connectionName, mysqlRecentChangesQuery, database, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table update times: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []RecentChange{}
+	for rows.Next() {
+		var change RecentChange
+		if err := rows.Scan(&change.Table, &change.LastModified); err != nil {
+			return nil, fmt.Errorf("failed to scan table update time: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read table update times: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(changes), time.Since(start))
+
+	return &RecentChangesReport{Available: true, Tables: changes}, nil
+}
+
+// GetRecentChangesSalesforce lists objects by their newest record's
+// LastModifiedDate, most recent first. Without objects it checks the org's
+// custom objects; at most MaxSalesforceRecentObjects are queried either way.
+// Objects without LastModifiedDate or without records are left out.
+func (m *Manager) GetRecentChangesSalesforce(ctx context.Context, connectionName string, objects []string, limit int) (*RecentChangesReport, error) {
+	if err := validateIdentifiers("salesforce", objects...); err != nil {
+		return nil, err
+	}
+
+	if len(objects) == 0 {
+		tables, err := m.ListTablesSalesforce(connectionName)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range tables {
+			if table.Type == "CUSTOM" {
+				objects = append(objects, table.Name)
+			}
+		}
+	}
+	if len(objects) > MaxSalesforceRecentObjects {
+		objects = objects[:MaxSalesforceRecentObjects]
+	}
+
+	changes := []RecentChange{}
+	for _, object := range objects {
+		// The Salesforce client has no context support, so check between queries
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		modified, ok, err := m.salesforceLastModified(connectionName, object)
+		if err != nil {
+			log.Printf("Skipping last modified date for Salesforce object %s: %v", object, err)
+			continue
+		}
+		if ok {
+			changes = append(changes, RecentChange{Table: object, LastModified: modified})
+		}
+	}
+
+	sortRecentChanges(changes)
+	if limit > 0 && len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return &RecentChangesReport{Available: true, Tables: changes}, nil
+}
+
+// salesforceLastModified returns the newest LastModifiedDate of an object's
+// records; ok is false when it has none
+func (m *Manager) salesforceLastModified(connectionName, objectName string) (modified time.Time, ok bool, err error) {
+	object, err := quoteIdentifier("salesforce", objectName)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	query := fmt.Sprintf("SELECT LastModifiedDate FROM %s ORDER BY LastModifiedDate DESC LIMIT 1", object)
+
+	var result *simpleforce.QueryResult
+	err = m.withSalesforceClient(connectionName, func(sfClient *SalesforceClient) error {
+		result, err = m.soql(connectionName, sfClient, query)
+		return err
+	})
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if result == nil || len(result.Records) == 0 {
+		return time.Time{}, false, nil
+	}
+
+	value, _ := result.Records[0]["LastModifiedDate"].(string)
+	if modified, err = time.Parse(salesforceDateTimeLayout, value); err != nil {
+		return time.Time{}, false, fmt.Errorf("unexpected LastModifiedDate '%s': %w", value, err)
+	}
+	return modified, true, nil
+}
+
+// sortRecentChanges orders changes most recent first, then by name
+func sortRecentChanges(changes []RecentChange) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].LastModified.Equal(changes[j].LastModified) {
+			return changes[i].LastModified.After(changes[j].LastModified)
+		}
+		return changes[i].Table < changes[j].Table
+	})
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/simpleforce/simpleforce"
+)
+
+func TestGetRecentChangesMySQL(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	updated := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	db.SetQueryResultForArgs(mysqlRecentChangesQuery, []interface{}{"shop", 5}, []string{"TABLE_NAME", "UPDATE_TIME"}, [][]interface{}{
+		{"orders", updated},
+		{"customers", updated.Add(-time.Hour)},
+	})
+
+	report, err := manager.GetRecentChangesMySQL(context.Background(), "test-mysql", "shop", 5)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, report.Available)
+	testutil.AssertEqual(t, 2, len(report.Tables))
+	testutil.AssertEqual(t, "orders", report.Tables[0].Table)
+	testutil.AssertEqual(t, true, report.Tables[0].LastModified.Equal(updated))
+	testutil.AssertEqual(t, "customers", report.Tables[1].Table)
+
+	// Tables never modified since the server started have no update time
+	testutil.AssertContains(t, mysqlRecentChangesQuery, "UPDATE_TIME IS NOT NULL")
+	testutil.AssertContains(t, mysqlRecentChangesQuery, "ORDER BY UPDATE_TIME DESC")
+
+	_, err = manager.GetRecentChangesMySQL(context.Background(), "test-mysql", "shop`; DROP", 5)
+	testutil.AssertError(t, err)
+}
+
+func TestGetRecentChangesSalesforceOrdering(t *testing.T) {
+	fake := &fakeSalesforce{
+		global: simpleforce.SObjectMeta{"sobjects": []interface{}{
+			map[string]interface{}{"name": "Account", "custom": false, "queryable": true},
+			map[string]interface{}{"name": "Invoice__c", "custom": true, "queryable": true},
+			map[string]interface{}{"name": "Payment__c", "custom": true, "queryable": true},
+			map[string]interface{}{"name": "Refund__c", "custom": true, "queryable": true},
+			map[string]interface{}{"name": "Setting__c", "custom": true, "queryable": true},
+		}},
+		lastModified: map[string]string{
+			"Account":    "2026-05-01T12:00:00.000+0000",
+			"Invoice__c": "2026-03-10T08:15:00.000+0000",
+			"Payment__c": "2026-04-22T17:45:30.000+0000",
+			"Refund__c":  "", // no records
+		},
+	}
+	manager := salesforceManager(t, fake)
+
+	// Custom objects only by default; Setting__c has no LastModifiedDate
+	report, err := manager.GetRecentChangesSalesforce(context.Background(), "test-sf", nil, 10)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, report.Available)
+	testutil.AssertEqual(t, 2, len(report.Tables))
+	testutil.AssertEqual(t, "Payment__c", report.Tables[0].Table)
+	testutil.AssertEqual(t, "Invoice__c", report.Tables[1].Table)
+	testutil.AssertEqual(t, "2026-04-22T17:45:30Z", report.Tables[0].LastModified.UTC().Format(time.RFC3339))
+	testutil.AssertEqual(t, 4, fake.queryCalls)
+
+	// Named objects are checked as given, and limit keeps the most recent
+	report, err = manager.GetRecentChangesSalesforce(context.Background(), "test-sf", []string{"Invoice__c", "Account", "Payment__c"}, 2)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(report.Tables))
+	testutil.AssertEqual(t, "Account", report.Tables[0].Table)
+	testutil.AssertEqual(t, "Payment__c", report.Tables[1].Table)
+	testutil.AssertContains(t, fake.lastQuery, "SELECT LastModifiedDate FROM Payment__c ORDER BY LastModifiedDate DESC LIMIT 1")
+}
+
+func TestSortRecentChangesBreaksTiesByName(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	changes := []RecentChange{{Table: "b", LastModified: at}, {Table: "a", LastModified: at}, {Table: "c", LastModified: at.Add(time.Second)}}
+	sortRecentChanges(changes)
+	testutil.AssertEqual(t, "c", changes[0].Table)
+	testutil.AssertEqual(t, "a", changes[1].Table)
+	testutil.AssertEqual(t, "b", changes[2].Table)
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+// replicaConfig adds a read replica to test-mysql using the given routing mode
+func replicaConfig(routing string) *config.Config {
+	cfg := testConfig()
+	conn := cfg.Connections["test-mysql"]
+	conn.ReadReplicaHost = "replica.internal"
+	conn.ReadReplicaPort = 3307
+	conn.ReplicaRouting = routing
+	cfg.Connections["test-mysql"] = conn
+	return cfg
+}
+
+func TestReplicaDSN(t *testing.T) {
+	manager := NewManager(replicaConfig(""), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	primary, _, exists := manager.pool.entryConfig("test-mysql")
+	testutil.AssertEqual(t, true, exists)
+	dsn, err := manager.buildDSN(primary, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, dsn, "@tcp(localhost:3306)/testdb")
+
+	replica, configName, exists := manager.pool.entryConfig(replicaPoolKey("test-mysql"))
+	testutil.AssertEqual(t, true, exists)
+	testutil.AssertEqual(t, "test-mysql", configName)
+	dsn, err = manager.buildDSN(replica, "user", "pass")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, dsn, "@tcp(replica.internal:3307)/testdb")
+
+	// Connections without a replica have no replica entry
+	_, _, exists = manager.pool.entryConfig(replicaPoolKey("test-postgres"))
+	testutil.AssertEqual(t, false, exists)
+}
+
+func TestReplicaRouting(t *testing.T) {
+	tests := []struct {
+		routing        string
+		connection     string
+		dataAccess     bool
+		expectedTarget string
+	}{
+		{"", "test-mysql", true, "test-mysql@replica"},
+		{"", "test-mysql", false, "test-mysql"},
+		{config.ReplicaRoutingData, "test-mysql", true, "test-mysql@replica"},
+		{config.ReplicaRoutingData, "test-mysql", false, "test-mysql"},
+		{config.ReplicaRoutingMetadata, "test-mysql", true, "test-mysql"},
+		{config.ReplicaRoutingMetadata, "test-mysql", false, "test-mysql@replica"},
+		{config.ReplicaRoutingData, "test-postgres", true, "test-postgres"},
+	}
+
+	for _, tt := range tests {
+		manager := NewManager(replicaConfig(tt.routing), testutil.NewMockCredentialManager())
+		testutil.AssertEqual(t, tt.expectedTarget, manager.routeConnection(tt.connection, tt.dataAccess))
+		manager.Close()
+	}
+}
+
+func TestReplicaRoutingPerTool(t *testing.T) {
+	manager := NewManager(replicaConfig(config.ReplicaRoutingData), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	primary := addMockConnection(t, manager.pool, "test-mysql")
+	primary.SetQueryResult("SHOW DATABASES", []string{"Database"}, [][]interface{}{{"testdb"}})
+	replica := addMockConnection(t, manager.pool, replicaPoolKey("test-mysql"))
+	replica.SetQueryResult("SELECT * FROM `testdb`.`users` LIMIT 5", []string{"id"}, [][]interface{}{{int64(1)}})
+
+	_, err := manager.ListDatabasesMySQL("test-mysql")
+	testutil.AssertNoError(t, err)
+	sample, err := manager.GetTableSampleMySQL(context.Background(), "test-mysql", "testdb", "users", 5)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, sample["total_sampled"])
+
+	// Metadata ran on the primary, the sample on the replica's own pool entry
+	testutil.AssertEqual(t, int64(1), manager.GetConnectionStatus("test-mysql").QueriesRun)
+	testutil.AssertEqual(t, int64(1), manager.GetConnectionStatus("test-mysql@replica").QueriesRun)
+}
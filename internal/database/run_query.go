@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// checkReadQuery refuses anything but a single SELECT. The query also runs in
+// a read-only transaction; this catches what that can't, such as MySQL's
+// SELECT ... INTO OUTFILE, which writes a file on the database server.
+func checkReadQuery(engine, query string) error {
+	tokens, err := tokenizeSQL(engine, query)
+	if err != nil {
+		return fmt.Errorf("failed to parse query: %w", err)
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("query is empty")
+	}
+	switch strings.ToUpper(tokens[0].text) {
+	case "SELECT", "WITH", "(":
+	default:
+		return fmt.Errorf("only SELECT queries can be run, not %s", strings.ToUpper(tokens[0].text))
+	}
+	if engine != "mysql" {
+		return nil
+	}
+	for _, tok := range tokens {
+		switch strings.ToUpper(tok.text) {
+		case "OUTFILE", "DUMPFILE":
+			return fmt.Errorf("SELECT ... INTO %s is not allowed", strings.ToUpper(tok.text))
+		}
+	}
+	return nil
+}
+
+// RunReadQuery runs a user's SELECT on a MySQL or PostgreSQL connection in a
+// read-only transaction. LimitQuery caps it to maxRows first, so a query
+// without a LIMIT can't return the whole table. The result has the shape of a
+// table sample plus the query that actually ran.
+func (m *Manager) RunReadQuery(ctx context.Context, connectionName, query string, maxRows int) (map[string]interface{}, error) {
+	engine := m.engineOf(connectionName)
+	if engine != "mysql" && engine != "postgres" {
+		return nil, fmt.Errorf("unsupported database type: %s", engine)
+	}
+	if err := checkReadQuery(engine, query); err != nil {
+		return nil, err
+	}
+	limited, err := LimitQuery(engine, query, maxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := m.GetDataConnection(connectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+	// Nothing is written, so the transaction is always rolled back
+	defer tx.Rollback()
+
+	m.logQuery(connectionName, engine, limited)
+	rows, err := tx.QueryContext(ctx, limited)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column types: %w", err)
+	}
+
+	collector := &sampleCollector{}
+	collector.WriteColumns(columns)
+	summary, err := scanSampleRows(rows, columns, func(i int, val interface{}) interface{} {
+		b, ok := val.([]byte)
+		if !ok {
+			return val
+		}
+		if engine == "postgres" {
+			typeName := columnTypes[i].DatabaseTypeName()
+			if isPostgresArrayType(typeName) {
+				if parsed, err := parsePostgresArray(typeName, string(b)); err == nil {
+					return parsed
+				}
+			}
+		}
+		return cleanTextForJSON(string(b))
+	}, func(row map[string]interface{}) error {
+		return collector.WriteRow(m.withNullRepresentation(row))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, true), summary.Rows, time.Since(start))
+
+	result := collector.result(summary.ScanErrors)
+	result["query"] = limited
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestRunReadQuery(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	db.SetQueryResult("SELECT id, name FROM shop.users LIMIT 2", []string{"id", "name"}, [][]interface{}{
+		{int64(1), []byte("ada")},
+		{int64(2), []byte("grace")},
+	})
+
+	// The missing LIMIT is added before the query runs
+	result, err := manager.RunReadQuery(context.Background(), "test-mysql", "SELECT id, name FROM shop.users", 2)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "SELECT id, name FROM shop.users LIMIT 2", result["query"])
+	testutil.AssertEqual(t, 2, result["total_sampled"])
+	rows := result["rows"].([]map[string]interface{})
+	testutil.AssertEqual(t, "grace", rows[1]["name"])
+	testutil.AssertEqual(t, 1, db.ReadOnlyTxCount())
+}
+
+func TestRunReadQueryRefusesWrites(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+	db := addMockConnection(t, manager.pool, "test-mysql")
+
+	for _, query := range []string{
+		"DELETE FROM shop.users",
+		"UPDATE shop.users SET name = 'x'",
+		"SELECT 1; DROP TABLE shop.users",
+		"SELECT * FROM shop.users INTO OUTFILE '/tmp/users.csv'",
+		"",
+	} {
+		_, err := manager.RunReadQuery(context.Background(), "test-mysql", query, 10)
+		if err == nil {
+			t.Errorf("Expected %q to be refused", query)
+		}
+	}
+	testutil.AssertEqual(t, 0, db.ReadOnlyTxCount())
+
+	// Postgres has no INTO OUTFILE, so the word is only a name there
+	testutil.AssertNoError(t, checkReadQuery("postgres", "SELECT outfile FROM exports"))
+}
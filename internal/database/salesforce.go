@@ -1,23 +1,42 @@
 package database
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
+	"log"
 	"strings"
 
 	"github.com/simpleforce/simpleforce"
 )
 
+// salesforceAPI is the part of the simpleforce client the Salesforce methods
+// use, so tests can substitute a fake
+type salesforceAPI interface {
+	LoginPassword(username, password, token string) error
+	DescribeGlobal() (*simpleforce.SObjectMeta, error)
+	ApexREST(method, path string, requestBody io.Reader) ([]byte, error)
+	Query(q string) (*simpleforce.QueryResult, error)
+}
+
 // SalesforceClient wraps the simpleforce client
 type SalesforceClient struct {
-	client *simpleforce.Client
+	client salesforceAPI
 }
 
-// NewSalesforceClient creates a new Salesforce client
-func NewSalesforceClient(instanceURL, username, password, securityToken string) (*SalesforceClient, error) {
+// newSimpleforceAPI returns a simpleforce client that has not logged in yet
+func newSimpleforceAPI(instanceURL, apiVersion string) salesforceAPI {
 	// Use the provided Salesforce instance URL
-	client := simpleforce.NewClient(instanceURL, simpleforce.DefaultClientID, simpleforce.DefaultAPIVersion)
+	return simpleforce.NewClient(instanceURL, simpleforce.DefaultClientID, apiVersion)
+}
+
+// NewSalesforceClient creates a new Salesforce client for a REST API version such as "62.0"
+func NewSalesforceClient(instanceURL, apiVersion, username, password, securityToken string) (*SalesforceClient, error) {
+	return loginSalesforce(newSimpleforceAPI(instanceURL, apiVersion), username, password, securityToken)
+}
 
+// loginSalesforce logs client in and wraps it
+func loginSalesforce(client salesforceAPI, username, password, securityToken string) (*SalesforceClient, error) {
 	// Login with username, password, and security token
 	// For Salesforce, the password + security token is concatenated
 	fullPassword := password + securityToken
@@ -29,10 +48,16 @@ func NewSalesforceClient(instanceURL, username, password, securityToken string)
 	return &SalesforceClient{client: client}, nil
 }
 
-// ListDatabasesSalesforce returns dummy database info for Salesforce
+// sobjectDescribePath is the REST path describing an SObject's fields
+func sobjectDescribePath(apiVersion, objectName string) string {
+	return fmt.Sprintf("/services/data/v%s/sobjects/%s/describe", apiVersion, objectName)
+}
+
+// ListDatabasesSalesforce lists the org as the single database. Salesforce
+// has no databases, so it is named after the connection, the name resources
+// and the other tools take as the database of a Salesforce connection.
 func (m *Manager) ListDatabasesSalesforce(connectionName string) ([]string, error) {
-	// Salesforce doesn't have databases, return dummy info
-	return []string{"salesforce_org"}, nil
+	return []string{connectionName}, nil
 }
 
 // ListSchemasSalesforce returns dummy schema info for Salesforce
@@ -41,30 +66,13 @@ func (m *Manager) ListSchemasSalesforce(connectionName, database string) ([]stri
 	return []string{"default"}, nil
 }
 
-// ListTablesSalesforce lists Salesforce objects (equivalent to tables)
+// ListTablesSalesforce lists Salesforce objects (equivalent to tables). Row
+// counts are left empty; see AddRowCountsSalesforce.
 func (m *Manager) ListTablesSalesforce(connectionName string) ([]TableInfo, error) {
-	// Get connection config
-	conn, exists := m.config.GetConnection(connectionName)
-	if !exists {
-		return nil, fmt.Errorf("connection '%s' not found", connectionName)
-	}
-
-	// Get Salesforce credentials
-	sfCred, err := m.credManager.GetSalesforce(connectionName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get Salesforce credentials: %w", err)
-	}
-
-	// Create Salesforce client with instance URL from config
-	sfClient, err := NewSalesforceClient(conn.Host, sfCred.Username, sfCred.Password, sfCred.SecurityToken)
-	if err != nil {
-		return nil, err
-	}
-
 	// Use DescribeGlobal to get list of all SObjects
-	globalDesc, err := sfClient.client.DescribeGlobal()
+	globalDesc, err := m.salesforceGlobalDescribe(connectionName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe global Salesforce objects: %w", err)
+		return nil, err
 	}
 
 	var tables []TableInfo
@@ -90,7 +98,7 @@ func (m *Manager) ListTablesSalesforce(connectionName string) ([]TableInfo, erro
 				tables = append(tables, TableInfo{
 					Name:     name,
 					Type:     tableType,
-					RowCount: nil, // We'll skip row counts for performance
+					RowCount: nil, // Counts cost a query per object; see AddRowCountsSalesforce
 				})
 			}
 		}
@@ -99,38 +107,85 @@ func (m *Manager) ListTablesSalesforce(connectionName string) ([]TableInfo, erro
 	return tables, nil
 }
 
-// DescribeTableSalesforce describes a Salesforce object (equivalent to table structure)
-func (m *Manager) DescribeTableSalesforce(connectionName, objectName string) ([]ColumnInfo, error) {
-	// Get connection config
-	conn, exists := m.config.GetConnection(connectionName)
-	if !exists {
-		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+// AddRowCountsSalesforce fills in RowCount with one COUNT() query per object,
+// so callers should only pass the objects they are about to return
+func (m *Manager) AddRowCountsSalesforce(connectionName string, tables []TableInfo) {
+	for i := range tables {
+		// Some objects refuse COUNT(); leave their count empty
+		count, err := m.countSalesforceObject(connectionName, tables[i].Name)
+		if err != nil {
+			log.Printf("Skipping row count for Salesforce object %s: %v", tables[i].Name, err)
+			continue
+		}
+		tables[i].RowCount = &count
 	}
+}
 
-	// Get Salesforce credentials
-	sfCred, err := m.credManager.GetSalesforce(connectionName)
+// CountRowsSalesforce returns the number of records in a Salesforce object
+func (m *Manager) CountRowsSalesforce(ctx context.Context, connectionName, objectName string) (int64, error) {
+	if err := validateIdentifiers("salesforce", objectName); err != nil {
+		return 0, err
+	}
+
+	// The Salesforce client has no context support, so check before issuing the query
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return m.countSalesforceObject(connectionName, objectName)
+}
+
+// countSalesforceObject runs a COUNT() SOQL query for one object
+func (m *Manager) countSalesforceObject(connectionName, objectName string) (int64, error) {
+	query, err := salesforceCountQuery(objectName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get Salesforce credentials: %w", err)
+		return 0, err
 	}
 
-	// Create Salesforce client with instance URL from config
-	sfClient, err := NewSalesforceClient(conn.Host, sfCred.Username, sfCred.Password, sfCred.SecurityToken)
+	var result *simpleforce.QueryResult
+	err = m.withSalesforceClient(connectionName, func(sfClient *SalesforceClient) error {
+		if result, err = m.soql(connectionName, sfClient, query); err != nil {
+			return fmt.Errorf("failed to count Salesforce object %s: %w", objectName, err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	return parseSalesforceCount(result)
+}
 
-	// Use REST API to describe the SObject
-	// Make a direct REST call to describe the object
-	sobjectRestPath := fmt.Sprintf("/services/data/v54.0/sobjects/%s/describe", objectName)
-	respBody, err := sfClient.client.ApexREST("GET", sobjectRestPath, nil)
+// salesforceCountQuery builds the SOQL that counts an object's records
+func salesforceCountQuery(objectName string) (string, error) {
+	object, err := quoteIdentifier("salesforce", objectName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe Salesforce object %s: %w", objectName, err)
+		return "", err
 	}
+	return fmt.Sprintf("SELECT COUNT() FROM %s", object), nil
+}
 
-	// Parse the JSON response
-	var sobjectDesc map[string]interface{}
-	if err := json.Unmarshal(respBody, &sobjectDesc); err != nil {
-		return nil, fmt.Errorf("failed to parse describe response for %s: %w", objectName, err)
+// parseSalesforceCount reads the result of a COUNT() query. Salesforce
+// returns no records for it; the count is reported as totalSize.
+func parseSalesforceCount(result *simpleforce.QueryResult) (int64, error) {
+	if result == nil {
+		return 0, fmt.Errorf("empty response to Salesforce COUNT() query")
+	}
+	if result.TotalSize < 0 {
+		return 0, fmt.Errorf("invalid Salesforce record count: %d", result.TotalSize)
+	}
+	return int64(result.TotalSize), nil
+}
+
+// DescribeTableSalesforce describes a Salesforce object (equivalent to table structure)
+func (m *Manager) DescribeTableSalesforce(connectionName, objectName string) ([]ColumnInfo, error) {
+	if err := validateIdentifiers("salesforce", objectName); err != nil {
+		return nil, err
+	}
+
+	// Use REST API to describe the SObject
+	sobjectDesc, err := m.salesforceObjectDescribe(connectionName, objectName)
+	if err != nil {
+		return nil, err
 	}
 
 	var columns []ColumnInfo
@@ -167,7 +222,16 @@ func (m *Manager) DescribeTableSalesforce(connectionName, objectName string) ([]
 		}
 	}
 
-	return columns, nil
+	return numberColumns(columns), nil
+}
+
+// DescribeDatabaseSalesforce summarizes the org's SObjects; size is not available
+func (m *Manager) DescribeDatabaseSalesforce(connectionName string, maxTables int) (*DatabaseSummary, error) {
+	tables, err := m.ListTablesSalesforce(connectionName)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeTables(tables, maxTables), nil
 }
 
 // ListIndexesSalesforce returns dummy index info for Salesforce objects
@@ -184,36 +248,15 @@ func (m *Manager) ListIndexesSalesforce(connectionName, objectName string) ([]In
 }
 
 // GetTableSampleSalesforce gets sample records from a Salesforce object
-func (m *Manager) GetTableSampleSalesforce(connectionName, objectName string, limit int) (map[string]interface{}, error) {
-	// Get connection config
-	conn, exists := m.config.GetConnection(connectionName)
-	if !exists {
-		return nil, fmt.Errorf("connection '%s' not found", connectionName)
-	}
-
-	// Get Salesforce credentials
-	sfCred, err := m.credManager.GetSalesforce(connectionName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get Salesforce credentials: %w", err)
-	}
-
-	// Create Salesforce client with instance URL from config
-	sfClient, err := NewSalesforceClient(conn.Host, sfCred.Username, sfCred.Password, sfCred.SecurityToken)
-	if err != nil {
+func (m *Manager) GetTableSampleSalesforce(ctx context.Context, connectionName, objectName string, limit int) (map[string]interface{}, error) {
+	if err := validateIdentifiers("salesforce", objectName); err != nil {
 		return nil, err
 	}
 
 	// First, get field names for the object using REST API
-	sobjectRestPath := fmt.Sprintf("/services/data/v54.0/sobjects/%s/describe", objectName)
-	respBody, err := sfClient.client.ApexREST("GET", sobjectRestPath, nil)
+	sobjectDesc, err := m.salesforceObjectDescribe(connectionName, objectName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe Salesforce object %s: %w", objectName, err)
-	}
-
-	// Parse the JSON response
-	var sobjectDesc map[string]interface{}
-	if err := json.Unmarshal(respBody, &sobjectDesc); err != nil {
-		return nil, fmt.Errorf("failed to parse describe response for %s: %w", objectName, err)
+		return nil, err
 	}
 
 	// Build field list (limit to first 20 fields for performance)
@@ -247,12 +290,27 @@ func (m *Manager) GetTableSampleSalesforce(connectionName, objectName string, li
 	}
 
 	// Build SOQL query
+	object, err := quoteIdentifier("salesforce", objectName)
+	if err != nil {
+		return nil, err
+	}
 	query := fmt.Sprintf("SELECT %s FROM %s LIMIT %d",
-		strings.Join(fieldNames, ", "), objectName, limit)
+		strings.Join(fieldNames, ", "), object, limit)
+
+	// The Salesforce client has no context support, so check before issuing the query
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	result, err := sfClient.client.Query(query)
+	var result *simpleforce.QueryResult
+	err = m.withSalesforceClient(connectionName, func(sfClient *SalesforceClient) error {
+		if result, err = m.soql(connectionName, sfClient, query); err != nil {
+			return fmt.Errorf("failed to query Salesforce object %s: %w", objectName, err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query Salesforce object %s: %w", objectName, err)
+		return nil, err
 	}
 
 	// Convert results to our format
@@ -275,11 +333,7 @@ func (m *Manager) GetTableSampleSalesforce(connectionName, objectName string, li
 		results = append(results, row)
 	}
 
-	return map[string]interface{}{
-		"columns":       fieldNames,
-		"rows":          results,
-		"total_sampled": len(results),
-	}, nil
+	return m.sampleResult(fieldNames, results, nil), nil
 }
 
 // mapSalesforceFieldType maps Salesforce field types to more standard types
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/simpleforce/simpleforce"
+)
+
+// salesforceCacheTTL bounds how long a logged-in Salesforce client and the
+// describe results fetched through it are reused before logging in again
+const salesforceCacheTTL = 15 * time.Minute
+
+// salesforceSession is a logged-in client and the describe results fetched through it
+type salesforceSession struct {
+	client    *SalesforceClient
+	expires   time.Time
+	global    *simpleforce.SObjectMeta
+	describes map[string]map[string]interface{}
+}
+
+// salesforceCache keeps one session per connection so repeated tool calls
+// skip the login and describe round trips
+type salesforceCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	now      func() time.Time
+	sessions map[string]*salesforceSession
+}
+
+func newSalesforceCache(ttl time.Duration) *salesforceCache {
+	return &salesforceCache{
+		ttl:      ttl,
+		now:      time.Now,
+		sessions: make(map[string]*salesforceSession),
+	}
+}
+
+// session returns the unexpired session for a connection, or nil
+func (c *salesforceCache) session(connectionName string) *salesforceSession {
+	session, ok := c.sessions[connectionName]
+	if !ok {
+		return nil
+	}
+	if !c.now().Before(session.expires) {
+		delete(c.sessions, connectionName)
+		return nil
+	}
+	return session
+}
+
+// client returns the cached client for a connection, or nil
+func (c *salesforceCache) client(connectionName string) *SalesforceClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if session := c.session(connectionName); session != nil {
+		return session.client
+	}
+	return nil
+}
+
+// store starts a new session for a freshly logged-in client
+func (c *salesforceCache) store(connectionName string, client *SalesforceClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions[connectionName] = &salesforceSession{
+		client:    client,
+		expires:   c.now().Add(c.ttl),
+		describes: make(map[string]map[string]interface{}),
+	}
+}
+
+// globalDescribe returns the cached DescribeGlobal result, or nil
+func (c *salesforceCache) globalDescribe(connectionName string) *simpleforce.SObjectMeta {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if session := c.session(connectionName); session != nil {
+		return session.global
+	}
+	return nil
+}
+
+func (c *salesforceCache) storeGlobalDescribe(connectionName string, client *SalesforceClient, global *simpleforce.SObjectMeta) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Only attach results to the session whose client fetched them
+	if session := c.session(connectionName); session != nil && session.client == client {
+		session.global = global
+	}
+}
+
+// objectDescribe returns the cached describe of one SObject, or nil
+func (c *salesforceCache) objectDescribe(connectionName, objectName string) map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if session := c.session(connectionName); session != nil {
+		return session.describes[objectName]
+	}
+	return nil
+}
+
+func (c *salesforceCache) storeObjectDescribe(connectionName string, client *SalesforceClient, objectName string, desc map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if session := c.session(connectionName); session != nil && session.client == client {
+		session.describes[objectName] = desc
+	}
+}
+
+// invalidate drops a connection's session so the next call logs in again
+func (c *salesforceCache) invalidate(connectionName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sessions, connectionName)
+}
+
+// isSalesforceAuthError reports whether err means the session is no longer valid
+func isSalesforceAuthError(err error) bool {
+	if errors.Is(err, simpleforce.ErrAuthentication) {
+		return true
+	}
+	var sfErr simpleforce.SalesforceError
+	if errors.As(err, &sfErr) {
+		return sfErr.HttpCode == http.StatusUnauthorized || sfErr.ErrorCode == "INVALID_SESSION_ID"
+	}
+	return strings.Contains(err.Error(), "INVALID_SESSION_ID")
+}
+
+// salesforceClient returns a logged-in client for the connection, reusing the
+// cached session until it expires or fails authentication
+func (m *Manager) salesforceClient(connectionName string) (*SalesforceClient, error) {
+	if sfClient := m.sfCache.client(connectionName); sfClient != nil {
+		return sfClient, nil
+	}
+
+	// Get connection config
+	conn, exists := m.config.GetConnection(connectionName)
+	if !exists {
+		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+	}
+
+	// Get Salesforce credentials
+	sfCred, err := m.credManager.GetSalesforce(connectionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Salesforce credentials: %w", err)
+	}
+
+	// Create Salesforce client with instance URL from config
+	sfClient, err := loginSalesforce(m.newSalesforceAPI(conn.Host, conn.SalesforceVersion()), sfCred.Username, sfCred.Password, sfCred.SecurityToken)
+	if err != nil {
+		return nil, err
+	}
+
+	m.sfCache.store(connectionName, sfClient)
+	return sfClient, nil
+}
+
+// withSalesforceClient runs op with the connection's logged-in client. When
+// Salesforce no longer accepts the session, as long-lived connections
+// eventually see with INVALID_SESSION_ID, it logs in again and retries op
+// once; only a second rejection is returned, as an authentication failure.
+func (m *Manager) withSalesforceClient(connectionName string, op func(*SalesforceClient) error) error {
+	sfClient, err := m.salesforceClient(connectionName)
+	if err != nil {
+		return err
+	}
+	err = op(sfClient)
+	if err == nil || !isSalesforceAuthError(err) {
+		return err
+	}
+
+	log.Printf("Salesforce session for connection '%s' is no longer valid; logging in again", connectionName)
+	m.sfCache.invalidate(connectionName)
+	if sfClient, err = m.salesforceClient(connectionName); err != nil {
+		return err
+	}
+	if err = op(sfClient); err != nil && isSalesforceAuthError(err) {
+		m.sfCache.invalidate(connectionName)
+		return fmt.Errorf("Salesforce rejected the session even after logging in again; check the connection's credentials and the user's API access: %w", err)
+	}
+	return err
+}
+
+// salesforceGlobalDescribe returns the connection's DescribeGlobal result,
+// cached for the life of the session
+func (m *Manager) salesforceGlobalDescribe(connectionName string) (*simpleforce.SObjectMeta, error) {
+	var global *simpleforce.SObjectMeta
+	err := m.withSalesforceClient(connectionName, func(sfClient *SalesforceClient) error {
+		if global = m.sfCache.globalDescribe(connectionName); global != nil {
+			return nil
+		}
+		fetched, err := sfClient.client.DescribeGlobal()
+		if err != nil {
+			return fmt.Errorf("failed to describe global Salesforce objects: %w", err)
+		}
+		m.sfCache.storeGlobalDescribe(connectionName, sfClient, fetched)
+		global = fetched
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return global, nil
+}
+
+// salesforceObjectDescribe returns the parsed describe of one SObject,
+// cached for the life of the session
+func (m *Manager) salesforceObjectDescribe(connectionName, objectName string) (map[string]interface{}, error) {
+	conn, _ := m.config.GetConnection(connectionName)
+
+	var desc map[string]interface{}
+	err := m.withSalesforceClient(connectionName, func(sfClient *SalesforceClient) error {
+		if desc = m.sfCache.objectDescribe(connectionName, objectName); desc != nil {
+			return nil
+		}
+		respBody, err := sfClient.client.ApexREST("GET", sobjectDescribePath(conn.SalesforceVersion(), objectName), nil)
+		if err != nil {
+			return fmt.Errorf("failed to describe Salesforce object %s: %w", objectName, err)
+		}
+		if err := json.Unmarshal(respBody, &desc); err != nil {
+			return fmt.Errorf("failed to parse describe response for %s: %w", objectName, err)
+		}
+		m.sfCache.storeObjectDescribe(connectionName, sfClient, objectName, desc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return desc, nil
+}
@@ -0,0 +1,288 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/simpleforce/simpleforce"
+)
+
+func TestSObjectDescribePathUsesConfiguredVersion(t *testing.T) {
+	conn := config.Connection{Type: "salesforce", Host: "https://example.my.salesforce.com", SalesforceAPIVersion: "v59.0"}
+	testutil.AssertEqual(t, "/services/data/v59.0/sobjects/Account/describe", sobjectDescribePath(conn.SalesforceVersion(), "Account"))
+
+	conn.SalesforceAPIVersion = ""
+	testutil.AssertEqual(t, "/services/data/v"+config.DefaultSalesforceAPIVersion+"/sobjects/Contact/describe",
+		sobjectDescribePath(conn.SalesforceVersion(), "Contact"))
+}
+
+func TestSalesforceCountQuery(t *testing.T) {
+	query, err := salesforceCountQuery("Invoice__c")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "SELECT COUNT() FROM Invoice__c", query)
+
+	_, err = salesforceCountQuery("Account; DELETE")
+	testutil.AssertError(t, err)
+}
+
+func TestParseSalesforceCount(t *testing.T) {
+	// COUNT() responses carry the count in totalSize and no records
+	var result simpleforce.QueryResult
+	testutil.AssertNoError(t, json.Unmarshal([]byte(`{"totalSize": 48213, "done": true, "records": []}`), &result))
+	count, err := parseSalesforceCount(&result)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, int64(48213), count)
+
+	var empty simpleforce.QueryResult
+	testutil.AssertNoError(t, json.Unmarshal([]byte(`{"totalSize": 0, "done": true, "records": []}`), &empty))
+	count, err = parseSalesforceCount(&empty)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, int64(0), count)
+
+	_, err = parseSalesforceCount(nil)
+	testutil.AssertError(t, err)
+}
+
+// fakeSalesforce serves canned describe and query responses and counts calls
+type fakeSalesforce struct {
+	global    simpleforce.SObjectMeta
+	describes map[string]string
+	loginErr  error
+	queryErr  error
+	records   []simpleforce.SObject
+	lastQuery string
+	// Sessions from the first expiredLogins logins get INVALID_SESSION_ID
+	expiredLogins int
+	// Newest LastModifiedDate per object; objects left out have no such field
+	lastModified map[string]string
+
+	logins        int
+	globalCalls   int
+	describeCalls int
+	queryCalls    int
+}
+
+func (f *fakeSalesforce) LoginPassword(username, password, token string) error {
+	f.logins++
+	return f.loginErr
+}
+
+func (f *fakeSalesforce) DescribeGlobal() (*simpleforce.SObjectMeta, error) {
+	f.globalCalls++
+	return &f.global, nil
+}
+
+func (f *fakeSalesforce) ApexREST(method, path string, requestBody io.Reader) ([]byte, error) {
+	f.describeCalls++
+	for object, body := range f.describes {
+		if strings.HasSuffix(path, "/sobjects/"+object+"/describe") {
+			return []byte(body), nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected path %s", path)
+}
+
+func (f *fakeSalesforce) Query(q string) (*simpleforce.QueryResult, error) {
+	f.queryCalls++
+	f.lastQuery = q
+	if f.logins <= f.expiredLogins {
+		return nil, simpleforce.SalesforceError{HttpCode: 401, ErrorCode: "INVALID_SESSION_ID", Message: "Session expired or invalid"}
+	}
+	if f.queryErr != nil {
+		return nil, f.queryErr
+	}
+	if f.lastModified != nil {
+		object := strings.Fields(q[strings.Index(q, " FROM ")+len(" FROM "):])[0]
+		date, ok := f.lastModified[object]
+		if !ok {
+			return nil, fmt.Errorf("INVALID_FIELD: No such column 'LastModifiedDate' on entity '%s'", object)
+		}
+		if date == "" {
+			return &simpleforce.QueryResult{Done: true}, nil
+		}
+		return &simpleforce.QueryResult{TotalSize: 1, Done: true, Records: []simpleforce.SObject{{"LastModifiedDate": date}}}, nil
+	}
+	return &simpleforce.QueryResult{TotalSize: 7, Done: true, Records: f.records}, nil
+}
+
+// salesforceManager returns a manager whose Salesforce connection "test-sf" uses fake
+func salesforceManager(t *testing.T, fake *fakeSalesforce) *Manager {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Connections["test-sf"] = config.Connection{Type: "salesforce", Host: "https://example.my.salesforce.com"}
+	credManager := testutil.NewMockCredentialManager()
+	testutil.AssertNoError(t, credManager.StoreSalesforce("test-sf", "user@example.com", "pw", "token"))
+
+	manager := NewManager(cfg, credManager)
+	t.Cleanup(func() { manager.Close() })
+	manager.newSalesforceAPI = func(instanceURL, apiVersion string) salesforceAPI { return fake }
+	return manager
+}
+
+func TestSalesforceDescribeCached(t *testing.T) {
+	fake := &fakeSalesforce{
+		describes: map[string]string{"Account": `{"fields": [{"name": "Id", "type": "id"}, {"name": "Name", "type": "string", "nillable": true}]}`},
+	}
+	manager := salesforceManager(t, fake)
+
+	for i := 0; i < 2; i++ {
+		columns, err := manager.DescribeTableSalesforce("test-sf", "Account")
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, len(columns))
+	}
+	testutil.AssertEqual(t, 1, fake.logins)
+	testutil.AssertEqual(t, 1, fake.describeCalls)
+
+	// Sampling reuses both the session and the describe
+	_, err := manager.GetTableSampleSalesforce(context.Background(), "test-sf", "Account", 5)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, fake.logins)
+	testutil.AssertEqual(t, 1, fake.describeCalls)
+	testutil.AssertEqual(t, 1, fake.queryCalls)
+}
+
+func TestSalesforceCacheExpires(t *testing.T) {
+	fake := &fakeSalesforce{global: simpleforce.SObjectMeta{"sobjects": []interface{}{}}}
+	manager := salesforceManager(t, fake)
+	now := time.Now()
+	manager.sfCache.now = func() time.Time { return now }
+
+	_, err := manager.ListTablesSalesforce("test-sf")
+	testutil.AssertNoError(t, err)
+	_, err = manager.ListTablesSalesforce("test-sf")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, fake.logins)
+	testutil.AssertEqual(t, 1, fake.globalCalls)
+
+	now = now.Add(salesforceCacheTTL)
+	_, err = manager.ListTablesSalesforce("test-sf")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, fake.logins)
+	testutil.AssertEqual(t, 2, fake.globalCalls)
+}
+
+func TestSalesforceAuthErrorInvalidatesSession(t *testing.T) {
+	fake := &fakeSalesforce{queryErr: simpleforce.SalesforceError{HttpCode: 401, ErrorCode: "INVALID_SESSION_ID"}}
+	manager := salesforceManager(t, fake)
+
+	// Each call logs in, is rejected, logs in again and is rejected again
+	_, err := manager.CountRowsSalesforce(context.Background(), "test-sf", "Account")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "even after logging in again")
+	testutil.AssertEqual(t, FailureAuth, ClassifyFailure(err))
+	_, err = manager.CountRowsSalesforce(context.Background(), "test-sf", "Account")
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, 4, fake.logins)
+	testutil.AssertEqual(t, 4, fake.queryCalls)
+
+	// Other failures keep the session and aren't retried
+	fake.queryErr = fmt.Errorf("MALFORMED_QUERY")
+	_, err = manager.CountRowsSalesforce(context.Background(), "test-sf", "Account")
+	testutil.AssertError(t, err)
+	_, err = manager.CountRowsSalesforce(context.Background(), "test-sf", "Account")
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, 5, fake.logins)
+	testutil.AssertEqual(t, 6, fake.queryCalls)
+}
+
+func TestSalesforceExpiredSessionRetried(t *testing.T) {
+	fake := &fakeSalesforce{expiredLogins: 1}
+	manager := salesforceManager(t, fake)
+
+	count, err := manager.CountRowsSalesforce(context.Background(), "test-sf", "Account")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, int64(7), count)
+	testutil.AssertEqual(t, 2, fake.logins)
+	testutil.AssertEqual(t, 2, fake.queryCalls)
+
+	// The new session is cached for later calls
+	_, err = manager.CountRowsSalesforce(context.Background(), "test-sf", "Account")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, fake.logins)
+	testutil.AssertEqual(t, 3, fake.queryCalls)
+}
+
+func TestListTablesSalesforceFiltersQueryable(t *testing.T) {
+	fake := &fakeSalesforce{global: simpleforce.SObjectMeta{"sobjects": []interface{}{
+		map[string]interface{}{"name": "Account", "custom": false, "queryable": true},
+		map[string]interface{}{"name": "Invoice__c", "custom": true, "queryable": true},
+		map[string]interface{}{"name": "AccountChangeEvent", "custom": false, "queryable": false},
+		map[string]interface{}{"name": "", "custom": false, "queryable": true},
+	}}}
+	manager := salesforceManager(t, fake)
+
+	tables, err := manager.ListTablesSalesforce("test-sf")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(tables))
+	testutil.AssertEqual(t, "Account", tables[0].Name)
+	testutil.AssertEqual(t, "STANDARD", tables[0].Type)
+	testutil.AssertEqual(t, "Invoice__c", tables[1].Name)
+	testutil.AssertEqual(t, "CUSTOM", tables[1].Type)
+	if tables[0].RowCount != nil {
+		t.Error("Expected no row count without include_counts")
+	}
+	testutil.AssertEqual(t, 0, fake.queryCalls)
+
+	manager.AddRowCountsSalesforce("test-sf", tables[1:])
+	testutil.AssertEqual(t, int64(7), *tables[1].RowCount)
+	testutil.AssertEqual(t, 1, fake.queryCalls)
+	if tables[0].RowCount != nil {
+		t.Error("Expected only the requested objects to be counted")
+	}
+}
+
+func TestDescribeTableSalesforceMapsFields(t *testing.T) {
+	fake := &fakeSalesforce{describes: map[string]string{"Opportunity": `{"fields": [
+		{"name": "Id", "type": "id", "nillable": false},
+		{"name": "Amount", "type": "currency", "nillable": true},
+		{"name": "StageName", "type": "picklist", "nillable": false, "defaultValue": "Prospecting"},
+		{"name": "CloseDate", "type": "date", "nillable": false, "defaultValue": null},
+		{"name": "IsWon", "type": "boolean", "nillable": false}
+	]}`}}
+	manager := salesforceManager(t, fake)
+
+	columns, err := manager.DescribeTableSalesforce("test-sf", "Opportunity")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 5, len(columns))
+
+	id := columns[0]
+	testutil.AssertEqual(t, "varchar(18)", id.Type)
+	testutil.AssertEqual(t, true, id.IsPrimaryKey)
+	testutil.AssertEqual(t, false, id.Nullable)
+
+	amount := columns[1]
+	testutil.AssertEqual(t, mapSalesforceFieldType("currency"), amount.Type)
+	testutil.AssertEqual(t, true, amount.Nullable)
+	testutil.AssertEqual(t, false, amount.IsPrimaryKey)
+
+	stage := columns[2]
+	testutil.AssertEqual(t, "varchar(255)", stage.Type)
+	testutil.AssertEqual(t, "Prospecting", *stage.DefaultValue)
+	if columns[3].DefaultValue != nil {
+		t.Error("Expected a null default to be omitted")
+	}
+	testutil.AssertEqual(t, "boolean", columns[4].Type)
+
+	// Ordinals follow the describe call's field order
+	for i, col := range columns {
+		testutil.AssertEqual(t, i+1, col.Ordinal)
+	}
+	testutil.AssertEqual(t, "IsWon", columns[4].Name)
+}
+
+func TestSalesforceLoginFailure(t *testing.T) {
+	fake := &fakeSalesforce{loginErr: simpleforce.ErrAuthentication}
+	manager := salesforceManager(t, fake)
+
+	_, err := manager.DescribeTableSalesforce("test-sf", "Account")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "failed to login to Salesforce")
+	testutil.AssertEqual(t, 0, fake.describeCalls)
+}
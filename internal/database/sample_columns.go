@@ -0,0 +1,68 @@
+package database
+
+import "sort"
+
+// CapSampleColumns trims a table sample (as returned by the GetTableSample*
+// methods) to at most maxColumns columns and returns the names it dropped.
+// Primary key columns are kept first, then columns with at least one non-null
+// value in the sampled rows; kept columns stay in their original order.
+// A maxColumns of 0 or less leaves the sample untouched.
+func CapSampleColumns(sample map[string]interface{}, maxColumns int, primaryKeys []string) []string {
+	columns, _ := sample["columns"].([]string)
+	if maxColumns <= 0 || len(columns) <= maxColumns {
+		return nil
+	}
+	rows, _ := sample["rows"].([]map[string]interface{})
+
+	isPrimaryKey := make(map[string]bool, len(primaryKeys))
+	for _, name := range primaryKeys {
+		isPrimaryKey[name] = true
+	}
+
+	// Lower rank is kept first; ties keep the original column order
+	rank := func(column string) int {
+		if isPrimaryKey[column] {
+			return 0
+		}
+		for _, row := range rows {
+			if row[column] != nil {
+				return 1
+			}
+		}
+		return 2
+	}
+
+	order := make([]int, len(columns))
+	ranks := make([]int, len(columns))
+	for i, column := range columns {
+		order[i] = i
+		ranks[i] = rank(column)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return ranks[order[a]] < ranks[order[b]]
+	})
+
+	keep := make([]bool, len(columns))
+	for _, i := range order[:maxColumns] {
+		keep[i] = true
+	}
+
+	kept := make([]string, 0, maxColumns)
+	var omitted []string
+	for i, column := range columns {
+		if keep[i] {
+			kept = append(kept, column)
+		} else {
+			omitted = append(omitted, column)
+		}
+	}
+
+	for _, row := range rows {
+		for _, column := range omitted {
+			delete(row, column)
+		}
+	}
+	sample["columns"] = kept
+
+	return omitted
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func wideSample() map[string]interface{} {
+	return map[string]interface{}{
+		"columns": []string{"notes", "id", "name", "deleted_at", "email"},
+		"rows": []map[string]interface{}{
+			{"notes": nil, "id": 1, "name": "a", "deleted_at": nil, "email": nil},
+			{"notes": nil, "id": 2, "name": "b", "deleted_at": nil, "email": "b@example.com"},
+		},
+		"total_sampled": 2,
+	}
+}
+
+func TestCapSampleColumnsKeepsPrimaryKeyAndNonNull(t *testing.T) {
+	sample := wideSample()
+
+	omitted := CapSampleColumns(sample, 3, []string{"id"})
+
+	testutil.AssertEqual(t, "id,name,email", strings.Join(sample["columns"].([]string), ","))
+	testutil.AssertEqual(t, "notes,deleted_at", strings.Join(omitted, ","))
+	for _, row := range sample["rows"].([]map[string]interface{}) {
+		testutil.AssertEqual(t, 3, len(row))
+		if _, ok := row["notes"]; ok {
+			t.Error("Expected omitted column to be removed from rows")
+		}
+	}
+}
+
+func TestCapSampleColumnsPrimaryKeyWinsOverNonNull(t *testing.T) {
+	sample := wideSample()
+
+	// notes is always null but is the key, so it is kept ahead of the populated columns
+	omitted := CapSampleColumns(sample, 2, []string{"notes"})
+
+	testutil.AssertEqual(t, "notes,id", strings.Join(sample["columns"].([]string), ","))
+	testutil.AssertEqual(t, "name,deleted_at,email", strings.Join(omitted, ","))
+}
+
+func TestCapSampleColumnsUnderLimit(t *testing.T) {
+	for _, maxColumns := range []int{0, 5, 10} {
+		sample := wideSample()
+		omitted := CapSampleColumns(sample, maxColumns, nil)
+		if omitted != nil {
+			t.Errorf("max %d: expected nothing omitted, got %v", maxColumns, omitted)
+		}
+		testutil.AssertEqual(t, 5, len(sample["columns"].([]string)))
+	}
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+)
+
+// maxSampleScanErrors is how many rows a table sample may skip because they
+// failed to scan before the whole sample is abandoned
+const maxSampleScanErrors = 10
+
+// ErrSampleFull is returned by a SampleWriter's WriteRow when it won't take
+// any more rows. The row is dropped and the sample ends early, marked
+// Truncated, instead of failing.
+var ErrSampleFull = errors.New("sample is full")
+
+// SampleWriter receives a table sample as it is read, so callers can encode
+// rows one at a time instead of holding the whole sample. WriteColumns is
+// called once, before any row.
+type SampleWriter interface {
+	WriteColumns(columns []string) error
+	WriteRow(row map[string]interface{}) error
+}
+
+// SampleSummary describes a sample that was streamed to a SampleWriter
+type SampleSummary struct {
+	Rows       int
+	ScanErrors []string
+	Truncated  bool // the writer returned ErrSampleFull before every row was read
+}
+
+// sampleCollector is the SampleWriter behind the GetTableSample* methods
+type sampleCollector struct {
+	columns []string
+	rows    []map[string]interface{}
+}
+
+func (c *sampleCollector) WriteColumns(columns []string) error {
+	c.columns = columns
+	return nil
+}
+
+func (c *sampleCollector) WriteRow(row map[string]interface{}) error {
+	c.rows = append(c.rows, row)
+	return nil
+}
+
+// result is the map the GetTableSample* methods return
+func (c *sampleCollector) result(scanErrors []string) map[string]interface{} {
+	result := map[string]interface{}{
+		"columns":       c.columns,
+		"rows":          c.rows,
+		"total_sampled": len(c.rows),
+	}
+	if len(scanErrors) > 0 {
+		result["scan_errors"] = scanErrors
+	}
+	return result
+}
+
+// rowScanner is the part of *sql.Rows a table sample reads
+type rowScanner interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// scanSampleRows reads every row of a table sample, passing each non-nil
+// value through convert and each finished row to emit. A row that fails to
+// scan is skipped and its error recorded instead of failing the sample; more
+// than maxSampleScanErrors skipped rows is treated as a real failure. Reading
+// stops when emit returns ErrSampleFull.
+func scanSampleRows(rows rowScanner, columns []string, convert func(i int, val interface{}) interface{}, emit func(row map[string]interface{}) error) (*SampleSummary, error) {
+	summary := &SampleSummary{}
+	rowNumber := 0
+	for rows.Next() {
+		rowNumber++
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			summary.ScanErrors = append(summary.ScanErrors, fmt.Sprintf("row %d: %v", rowNumber, err))
+			if len(summary.ScanErrors) > maxSampleScanErrors {
+				return nil, fmt.Errorf("failed to scan row: %d rows could not be read, last: %w", len(summary.ScanErrors), err)
+			}
+			continue
+		}
+
+		row := make(map[string]interface{})
+		for i, col := range columns {
+			if values[i] == nil {
+				row[col] = nil
+			} else {
+				row[col] = convert(i, values[i])
+			}
+		}
+		if err := emit(row); err != nil {
+			if errors.Is(err, ErrSampleFull) {
+				summary.Truncated = true
+				break
+			}
+			return nil, err
+		}
+		summary.Rows++
+	}
+
+	// A driver error ends iteration; keep the rows read so far and say why it stopped
+	if err := rows.Err(); err != nil {
+		summary.ScanErrors = append(summary.ScanErrors, fmt.Sprintf("stopped after row %d: %v", rowNumber, err))
+	}
+	return summary, nil
+}
+
+// sampleResult builds the result map returned by the GetTableSample* methods
+// for engines that read the whole sample at once
+func (m *Manager) sampleResult(columns []string, results []map[string]interface{}, scanErrors []string) map[string]interface{} {
+	collector := &sampleCollector{columns: columns}
+	for _, row := range results {
+		collector.WriteRow(m.withNullRepresentation(row))
+	}
+	return collector.result(scanErrors)
+}
+
+// withNullRepresentation applies the null_representation setting to a row
+func (m *Manager) withNullRepresentation(row map[string]interface{}) map[string]interface{} {
+	applyNullRepresentation(row, m.config.Settings.NullRepresentation, m.config.Settings.NullPlaceholder)
+	return row
+}
+
+// applyNullRepresentation rewrites the nil values in a sample row: omit drops
+// the key, placeholder replaces the value with text and anything else keeps
+// JSON null. Empty strings are never touched.
+func applyNullRepresentation(row map[string]interface{}, mode, placeholder string) {
+	if placeholder == "" {
+		placeholder = config.DefaultNullPlaceholder
+	}
+	for col, val := range row {
+		if val != nil {
+			continue
+		}
+		switch mode {
+		case config.NullRepresentationOmit:
+			delete(row, col)
+		case config.NullRepresentationPlaceholder:
+			row[col] = placeholder
+		}
+	}
+}
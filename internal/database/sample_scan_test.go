@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+// fakeRows yields one value per row, failing Scan on the listed rows
+type fakeRows struct {
+	values  []interface{}
+	badRows map[int]bool
+	err     error
+	next    int
+}
+
+func (r *fakeRows) Next() bool {
+	if r.next >= len(r.values) {
+		return false
+	}
+	r.next++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	if r.badRows[r.next] {
+		return fmt.Errorf("converting column index 0: unsupported type")
+	}
+	*dest[0].(*interface{}) = r.values[r.next-1]
+	return nil
+}
+
+func (r *fakeRows) Err() error { return r.err }
+
+func identity(i int, val interface{}) interface{} { return val }
+
+// collectRows scans rows into a sampleCollector
+func collectRows(rows rowScanner, columns []string) ([]map[string]interface{}, []string, error) {
+	collector := &sampleCollector{}
+	summary, err := scanSampleRows(rows, columns, identity, collector.WriteRow)
+	if err != nil {
+		return nil, nil, err
+	}
+	return collector.rows, summary.ScanErrors, nil
+}
+
+func TestScanSampleRowsSkipsBadRow(t *testing.T) {
+	rows := &fakeRows{values: []interface{}{"a", "b", "c"}, badRows: map[int]bool{2: true}}
+
+	results, scanErrors, err := collectRows(rows, []string{"name"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(results))
+	testutil.AssertEqual(t, "a", results[0]["name"])
+	testutil.AssertEqual(t, "c", results[1]["name"])
+	testutil.AssertEqual(t, 1, len(scanErrors))
+	testutil.AssertEqual(t, true, strings.HasPrefix(scanErrors[0], "row 2: "))
+}
+
+func TestScanSampleRowsGivesUpAfterCap(t *testing.T) {
+	values := make([]interface{}, maxSampleScanErrors+2)
+	badRows := make(map[int]bool)
+	for i := range values {
+		values[i] = i
+		badRows[i+1] = true
+	}
+
+	_, _, err := collectRows(&fakeRows{values: values, badRows: badRows}, []string{"id"})
+	testutil.AssertError(t, err)
+}
+
+func TestScanSampleRowsKeepsRowsBeforeDriverError(t *testing.T) {
+	rows := &fakeRows{values: []interface{}{"a"}, err: errors.New("connection reset")}
+
+	results, scanErrors, err := collectRows(rows, []string{"name"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(results))
+	testutil.AssertEqual(t, 1, len(scanErrors))
+	testutil.AssertEqual(t, "stopped after row 1: connection reset", scanErrors[0])
+}
+
+func TestScanSampleRowsStopsWhenWriterIsFull(t *testing.T) {
+	rows := &fakeRows{values: []interface{}{"a", "b", "c"}}
+	var emitted []string
+	summary, err := scanSampleRows(rows, []string{"name"}, identity, func(row map[string]interface{}) error {
+		if len(emitted) == 2 {
+			return ErrSampleFull
+		}
+		emitted = append(emitted, row["name"].(string))
+		return nil
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, summary.Rows)
+	testutil.AssertEqual(t, true, summary.Truncated)
+	testutil.AssertEqual(t, "a b", strings.Join(emitted, " "))
+}
+
+func TestStreamTableSampleEmitsRowsAsRead(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+	mockDB := addMockConnection(t, manager.pool, "test-mysql")
+	mockDB.SetQueryResult("SELECT * FROM `testdb`.`users` LIMIT 3", []string{"id"},
+		[][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}})
+
+	w := &recordingWriter{}
+	summary, err := manager.StreamTableSampleMySQL(context.Background(), "test-mysql", "testdb", "users", 3, w)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, summary.Rows)
+	testutil.AssertEqual(t, "columns row row row", strings.Join(w.calls, " "))
+}
+
+// recordingWriter notes the order of SampleWriter calls
+type recordingWriter struct {
+	calls []string
+}
+
+func (w *recordingWriter) WriteColumns(columns []string) error {
+	w.calls = append(w.calls, "columns")
+	return nil
+}
+
+func (w *recordingWriter) WriteRow(row map[string]interface{}) error {
+	w.calls = append(w.calls, "row")
+	return nil
+}
+
+func TestSampleResultScanErrors(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	result := manager.sampleResult([]string{"name"}, nil, nil)
+	if _, ok := result["scan_errors"]; ok {
+		t.Error("Expected no scan_errors when every row was read")
+	}
+
+	result = manager.sampleResult([]string{"name"}, nil, []string{"row 2: bad value"})
+	testutil.AssertEqual(t, "row 2: bad value", result["scan_errors"].([]string)[0])
+}
+
+// nullSample samples a MySQL table with one NULL and one empty string value
+func nullSample(t *testing.T, mode, placeholder string) map[string]interface{} {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Settings.NullRepresentation = mode
+	cfg.Settings.NullPlaceholder = placeholder
+	manager := NewManager(cfg, testutil.NewMockCredentialManager())
+	t.Cleanup(func() { manager.Close() })
+
+	mockDB := addMockConnection(t, manager.pool, "test-mysql")
+	mockDB.SetQueryResult("SELECT * FROM `testdb`.`users` LIMIT 5", []string{"id", "email", "nickname"},
+		[][]interface{}{{int64(1), nil, []byte("")}})
+
+	sample, err := manager.GetTableSampleMySQL(context.Background(), "test-mysql", "testdb", "users", 5)
+	testutil.AssertNoError(t, err)
+	return sample["rows"].([]map[string]interface{})[0]
+}
+
+func TestSampleNullRepresentation(t *testing.T) {
+	row := nullSample(t, "", "")
+	email, present := row["email"]
+	testutil.AssertEqual(t, true, present)
+	testutil.AssertEqual(t, nil, email)
+	testutil.AssertEqual(t, "", row["nickname"])
+
+	row = nullSample(t, config.NullRepresentationOmit, "")
+	_, present = row["email"]
+	testutil.AssertEqual(t, false, present)
+	testutil.AssertEqual(t, "", row["nickname"])
+
+	row = nullSample(t, config.NullRepresentationPlaceholder, "")
+	testutil.AssertEqual(t, "NULL", row["email"])
+	testutil.AssertEqual(t, "", row["nickname"])
+
+	row = nullSample(t, config.NullRepresentationPlaceholder, "<null>")
+	testutil.AssertEqual(t, "<null>", row["email"])
+}
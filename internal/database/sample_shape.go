@@ -0,0 +1,102 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Result shapes for table samples
+const (
+	SampleShapeObjects       = "objects"        // array of {column: value} objects
+	SampleShapeColumnar      = "columnar"       // column names plus one value array per column
+	SampleShapeMarkdownTable = "markdown_table" // a Markdown table as a single string
+)
+
+// ShapeSample renders a table sample (as returned by the GetTableSample*
+// methods) in the requested shape. An empty shape means objects.
+func ShapeSample(sample map[string]interface{}, shape string) (interface{}, error) {
+	columns, _ := sample["columns"].([]string)
+	rows, ok := sample["rows"].([]map[string]interface{})
+	if !ok && sample["rows"] != nil {
+		return nil, fmt.Errorf("invalid sample data format: expected rows array")
+	}
+
+	switch shape {
+	case "", SampleShapeObjects:
+		if rows == nil {
+			rows = []map[string]interface{}{}
+		}
+		return rows, nil
+
+	case SampleShapeColumnar:
+		values := make(map[string][]interface{}, len(columns))
+		for _, column := range columns {
+			columnValues := make([]interface{}, len(rows))
+			for i, row := range rows {
+				columnValues[i] = row[column]
+			}
+			values[column] = columnValues
+		}
+		return map[string]interface{}{
+			"columns": columns,
+			"values":  values,
+		}, nil
+
+	case SampleShapeMarkdownTable:
+		return markdownTable(columns, rows), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported result_shape '%s' (expected %s, %s or %s)",
+			shape, SampleShapeObjects, SampleShapeColumnar, SampleShapeMarkdownTable)
+	}
+}
+
+// markdownTable renders rows as a Markdown table, showing NULL for nil values
+func markdownTable(columns []string, rows []map[string]interface{}) string {
+	if len(columns) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	cells := make([]string, len(columns))
+
+	writeRow := func() {
+		b.WriteString("| ")
+		b.WriteString(strings.Join(cells, " | "))
+		b.WriteString(" |\n")
+	}
+
+	for i, column := range columns {
+		cells[i] = markdownCell(column)
+	}
+	writeRow()
+	for i := range cells {
+		cells[i] = "---"
+	}
+	writeRow()
+
+	for _, row := range rows {
+		for i, column := range columns {
+			value := row[column]
+			if raw, ok := value.(json.RawMessage); ok {
+				// Typed samples carry JSON columns as raw JSON
+				value = string(raw)
+			}
+			if value == nil {
+				cells[i] = "NULL"
+			} else {
+				cells[i] = markdownCell(fmt.Sprint(value))
+			}
+		}
+		writeRow()
+	}
+	return b.String()
+}
+
+// markdownCellReplacer escapes pipes and flattens line breaks so a value stays in its cell
+var markdownCellReplacer = strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ", "\r", " ")
+
+func markdownCell(text string) string {
+	return markdownCellReplacer.Replace(text)
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func shapeTestSample() map[string]interface{} {
+	return map[string]interface{}{
+		"columns": []string{"id", "name", "note"},
+		"rows": []map[string]interface{}{
+			{"id": 1, "name": "Ada", "note": nil},
+			{"id": 2, "name": "Bob", "note": "a|b\nc"},
+		},
+		"total_sampled": 2,
+	}
+}
+
+func shapeJSON(t *testing.T, shape string) string {
+	t.Helper()
+	shaped, err := ShapeSample(shapeTestSample(), shape)
+	testutil.AssertNoError(t, err)
+	data, err := json.Marshal(shaped)
+	testutil.AssertNoError(t, err)
+	return string(data)
+}
+
+func TestShapeSampleObjects(t *testing.T) {
+	expected := `[{"id":1,"name":"Ada","note":null},{"id":2,"name":"Bob","note":"a|b\nc"}]`
+	testutil.AssertEqual(t, expected, shapeJSON(t, SampleShapeObjects))
+	// Objects is the default
+	testutil.AssertEqual(t, expected, shapeJSON(t, ""))
+}
+
+func TestShapeSampleColumnar(t *testing.T) {
+	expected := `{"columns":["id","name","note"],"values":{"id":[1,2],"name":["Ada","Bob"],"note":[null,"a|b\nc"]}}`
+	testutil.AssertEqual(t, expected, shapeJSON(t, SampleShapeColumnar))
+}
+
+func TestShapeSampleMarkdownTable(t *testing.T) {
+	shaped, err := ShapeSample(shapeTestSample(), SampleShapeMarkdownTable)
+	testutil.AssertNoError(t, err)
+
+	expected := "| id | name | note |\n" +
+		"| --- | --- | --- |\n" +
+		"| 1 | Ada | NULL |\n" +
+		"| 2 | Bob | a\\|b c |\n"
+	testutil.AssertEqual(t, expected, shaped)
+}
+
+func TestShapeSampleEmptyAndInvalid(t *testing.T) {
+	empty := map[string]interface{}{"columns": []string{}, "rows": []map[string]interface{}{}, "total_sampled": 0}
+	shaped, err := ShapeSample(empty, SampleShapeObjects)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, len(shaped.([]map[string]interface{})))
+
+	_, err = ShapeSample(shapeTestSample(), "xml")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "unsupported result_shape 'xml'")
+}
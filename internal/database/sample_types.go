@@ -0,0 +1,187 @@
+package database
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type classes a typed sample coerces cells to, derived from declared column types
+const (
+	typeClassInteger   = "integer"
+	typeClassNumber    = "number"
+	typeClassBoolean   = "boolean"
+	typeClassDate      = "date"
+	typeClassTimestamp = "timestamp"
+	typeClassJSON      = "json"
+)
+
+// typeClassNames maps declared base type names, lowercased and without
+// parameters, to their type class. Engines name the same types differently:
+// MySQL COLUMN_TYPE, Postgres data_type, Glue/Hive types and the types
+// mapSalesforceFieldType produces all appear here.
+var typeClassNames = map[string]string{
+	"tinyint": typeClassInteger, "smallint": typeClassInteger, "mediumint": typeClassInteger,
+	"int": typeClassInteger, "integer": typeClassInteger, "bigint": typeClassInteger,
+	"int2": typeClassInteger, "int4": typeClassInteger, "int8": typeClassInteger,
+	"smallserial": typeClassInteger, "serial": typeClassInteger, "bigserial": typeClassInteger,
+	"year": typeClassInteger,
+
+	"decimal": typeClassNumber, "numeric": typeClassNumber, "float": typeClassNumber,
+	"double": typeClassNumber, "double precision": typeClassNumber, "real": typeClassNumber,
+	"float4": typeClassNumber, "float8": typeClassNumber,
+
+	"bool": typeClassBoolean, "boolean": typeClassBoolean,
+
+	"date": typeClassDate,
+
+	"datetime": typeClassTimestamp, "timestamp": typeClassTimestamp, "timestamptz": typeClassTimestamp,
+	"timestamp without time zone": typeClassTimestamp, "timestamp with time zone": typeClassTimestamp,
+
+	"json": typeClassJSON, "jsonb": typeClassJSON,
+}
+
+// columnTypeClass returns the type class of a declared column type, or "" for
+// types whose values are left as they are (text, binary, arrays, intervals...)
+func columnTypeClass(declared string) string {
+	declared = strings.ToLower(strings.TrimSpace(declared))
+	// MySQL stores booleans as tinyint(1)
+	if strings.HasPrefix(declared, "tinyint(1)") {
+		return typeClassBoolean
+	}
+	if i := strings.IndexByte(declared, '('); i >= 0 {
+		declared = strings.TrimSpace(declared[:i])
+	}
+	declared = strings.TrimSuffix(declared, " unsigned")
+	return typeClassNames[declared]
+}
+
+// SampleTypes maps each column of a table to its type class, for typed samples
+func SampleTypes(columns []ColumnInfo) map[string]string {
+	types := make(map[string]string, len(columns))
+	for _, col := range columns {
+		if class := columnTypeClass(col.Type); class != "" {
+			types[col.Name] = class
+		}
+	}
+	return types
+}
+
+// timestampLayouts are the textual timestamp forms engines return: MySQL and
+// Glue without a zone, Salesforce with a numeric one, and RFC 3339
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// coerceValue converts a cell to the JSON type of its column's type class.
+// Values that don't parse as that type, including NULL placeholders, are
+// returned unchanged.
+func coerceValue(class string, value interface{}) interface{} {
+	if t, ok := value.(time.Time); ok {
+		switch class {
+		case typeClassDate:
+			return t.Format("2006-01-02")
+		case typeClassTimestamp:
+			return t.Format(time.RFC3339Nano)
+		}
+		return value
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+	text = strings.TrimSpace(text)
+
+	switch class {
+	case typeClassInteger:
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n
+		}
+		// Beyond int64, keep every digit
+		if _, err := strconv.ParseUint(text, 10, 64); err == nil {
+			return json.Number(text)
+		}
+	case typeClassNumber:
+		// json.Number keeps decimals exact instead of rounding them through float64
+		if isJSONNumber(text) {
+			return json.Number(text)
+		}
+	case typeClassBoolean:
+		switch strings.ToLower(text) {
+		case "1", "t", "true", "y", "yes":
+			return true
+		case "0", "f", "false", "n", "no":
+			return false
+		}
+	case typeClassDate:
+		if t, err := time.Parse("2006-01-02", text); err == nil {
+			return t.Format("2006-01-02")
+		}
+	case typeClassTimestamp:
+		for _, layout := range timestampLayouts {
+			// Timestamps without a zone are read as UTC
+			if t, err := time.Parse(layout, text); err == nil {
+				return t.Format(time.RFC3339Nano)
+			}
+		}
+	case typeClassJSON:
+		if json.Valid([]byte(text)) {
+			return json.RawMessage(text)
+		}
+	}
+	return value
+}
+
+// isJSONNumber reports whether text is a number literal JSON accepts as is,
+// which rules out NaN, infinities, hex and forms like ".5"
+func isJSONNumber(text string) bool {
+	if text == "" || (text[0] != '-' && (text[0] < '0' || text[0] > '9')) {
+		return false
+	}
+	if _, err := strconv.ParseFloat(text, 64); err != nil {
+		return false
+	}
+	return json.Valid([]byte(text))
+}
+
+// typeRow coerces every cell of a row whose column has a type class
+func typeRow(row map[string]interface{}, types map[string]string) map[string]interface{} {
+	for column, value := range row {
+		if class, ok := types[column]; ok && value != nil {
+			row[column] = coerceValue(class, value)
+		}
+	}
+	return row
+}
+
+// TypeSample coerces the rows of a sample returned by the GetTableSample*
+// methods to their columns' JSON types
+func TypeSample(sample map[string]interface{}, types map[string]string) {
+	rows, _ := sample["rows"].([]map[string]interface{})
+	for _, row := range rows {
+		typeRow(row, types)
+	}
+}
+
+// typedSampleWriter coerces each row before passing it on
+type typedSampleWriter struct {
+	SampleWriter
+	types map[string]string
+}
+
+// NewTypedSampleWriter wraps w so that streamed rows are coerced to their
+// columns' JSON types
+func NewTypedSampleWriter(w SampleWriter, types map[string]string) SampleWriter {
+	return &typedSampleWriter{SampleWriter: w, types: types}
+}
+
+func (w *typedSampleWriter) WriteRow(row map[string]interface{}) error {
+	return w.SampleWriter.WriteRow(typeRow(row, w.types))
+}
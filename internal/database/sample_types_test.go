@@ -0,0 +1,99 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestColumnTypeClass(t *testing.T) {
+	cases := map[string]string{
+		"int(11)":                     typeClassInteger,
+		"bigint unsigned":             typeClassInteger,
+		"integer":                     typeClassInteger,
+		"decimal(10,2)":               typeClassNumber,
+		"double precision":            typeClassNumber,
+		"tinyint(1)":                  typeClassBoolean,
+		"tinyint(4)":                  typeClassInteger,
+		"boolean":                     typeClassBoolean,
+		"date":                        typeClassDate,
+		"DATETIME":                    typeClassTimestamp,
+		"timestamp without time zone": typeClassTimestamp,
+		"jsonb":                       typeClassJSON,
+		"varchar(255)":                "",
+		"text":                        "",
+		"bytea":                       "",
+	}
+	for declared, expected := range cases {
+		testutil.AssertEqual(t, expected, columnTypeClass(declared))
+	}
+}
+
+func TestCoerceValue(t *testing.T) {
+	testutil.AssertEqual(t, int64(42), coerceValue(typeClassInteger, "42"))
+	testutil.AssertEqual(t, json.Number("18446744073709551615"), coerceValue(typeClassInteger, "18446744073709551615"))
+	testutil.AssertEqual(t, json.Number("19.90"), coerceValue(typeClassNumber, "19.90"))
+	testutil.AssertEqual(t, true, coerceValue(typeClassBoolean, "1"))
+	testutil.AssertEqual(t, false, coerceValue(typeClassBoolean, "f"))
+	testutil.AssertEqual(t, "2024-03-01", coerceValue(typeClassDate, "2024-03-01"))
+	testutil.AssertEqual(t, `{"a":1}`, string(coerceValue(typeClassJSON, `{"a":1}`).(json.RawMessage)))
+
+	// MySQL, Glue and Salesforce timestamp forms
+	testutil.AssertEqual(t, "2024-03-01T10:30:00Z", coerceValue(typeClassTimestamp, "2024-03-01 10:30:00"))
+	testutil.AssertEqual(t, "2024-03-01T10:30:00.5Z", coerceValue(typeClassTimestamp, "2024-03-01 10:30:00.500"))
+	testutil.AssertEqual(t, "2024-03-01T10:30:00+02:00", coerceValue(typeClassTimestamp, "2024-03-01T10:30:00.000+0200"))
+
+	// Drivers that decode times themselves
+	ts := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	testutil.AssertEqual(t, "2024-03-01", coerceValue(typeClassDate, ts))
+	testutil.AssertEqual(t, "2024-03-01T10:30:00Z", coerceValue(typeClassTimestamp, ts))
+
+	// Values that don't parse, and values already typed, pass through
+	testutil.AssertEqual(t, "0000-00-00", coerceValue(typeClassDate, "0000-00-00"))
+	testutil.AssertEqual(t, "NaN", coerceValue(typeClassNumber, "NaN"))
+	testutil.AssertEqual(t, "maybe", coerceValue(typeClassBoolean, "maybe"))
+	testutil.AssertEqual(t, 3.5, coerceValue(typeClassNumber, 3.5))
+	testutil.AssertEqual(t, "abc", coerceValue("", "abc"))
+}
+
+func TestTypedSampleWriter(t *testing.T) {
+	types := SampleTypes([]ColumnInfo{
+		{Name: "id", Type: "int(11)"},
+		{Name: "price", Type: "decimal(10,2)"},
+		{Name: "created", Type: "date"},
+		{Name: "name", Type: "varchar(50)"},
+	})
+
+	collector := &sampleCollector{}
+	w := NewTypedSampleWriter(collector, types)
+	testutil.AssertNoError(t, w.WriteColumns([]string{"id", "price", "created", "name"}))
+	testutil.AssertNoError(t, w.WriteRow(map[string]interface{}{"id": "7", "price": "19.90", "created": "2024-03-01", "name": "42"}))
+	testutil.AssertNoError(t, w.WriteRow(map[string]interface{}{"id": "8", "price": nil, "created": nil, "name": nil}))
+
+	data, err := json.Marshal(collector.rows)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t,
+		`[{"created":"2024-03-01","id":7,"name":"42","price":19.90},{"created":null,"id":8,"name":null,"price":null}]`,
+		string(data))
+}
+
+func TestTypeSampleAndMarkdown(t *testing.T) {
+	sample := map[string]interface{}{
+		"columns": []string{"Amount", "Active", "Meta"},
+		"rows": []map[string]interface{}{
+			{"Amount": "1250.5", "Active": "true", "Meta": `{"tier":"gold"}`},
+		},
+		"total_sampled": 1,
+	}
+	TypeSample(sample, map[string]string{"Amount": typeClassNumber, "Active": typeClassBoolean, "Meta": typeClassJSON})
+
+	data, err := json.Marshal(sample["rows"])
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, `[{"Active":true,"Amount":1250.5,"Meta":{"tier":"gold"}}]`, string(data))
+
+	shaped, err := ShapeSample(sample, SampleShapeMarkdownTable)
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, shaped.(string), `| 1250.5 | true | {"tier":"gold"} |`)
+}
@@ -0,0 +1,247 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+)
+
+// Athena bills $5 per TB scanned, rounded up to the next megabyte with a
+// 10 MB minimum per query
+const (
+	AthenaPricePerTB    = 5.0
+	athenaBytesPerTB    = 1 << 40
+	athenaBillingUnit   = 1 << 20
+	athenaMinimumBilled = 10 << 20
+)
+
+// Ways EstimateScanGlue arrived at its estimate
+const (
+	ScanEstimateExplain    = "explain"    // Athena's EXPLAIN (TYPE IO) estimate
+	ScanEstimatePartitions = "partitions" // sum of the catalog sizes of matching partitions
+	ScanEstimateTable      = "table"      // the catalog size of the whole table
+)
+
+// glueSizeParameters are the table and partition parameters that may hold a
+// size in bytes: the Glue crawler's, then Hive's and Spark's
+var glueSizeParameters = []string{"sizeKey", "totalSize", "spark.sql.statistics.totalSize"}
+
+// ScanEstimate is the data an Athena query on a table is expected to scan
+// and what that would cost
+type ScanEstimate struct {
+	Method                string  `json:"method"`
+	EstimatedBytes        int64   `json:"estimated_bytes"`
+	EstimatedCostUSD      float64 `json:"estimated_cost_usd"`
+	PricePerTBUSD         float64 `json:"price_per_tb_usd"`
+	Partitions            int     `json:"partitions,omitempty"`
+	PartitionsWithoutSize int     `json:"partitions_without_size,omitempty"`
+	Note                  string  `json:"note,omitempty"`
+}
+
+// athenaScanCost is what Athena charges for scanning bytes
+func athenaScanCost(bytes int64) float64 {
+	billed := (bytes + athenaBillingUnit - 1) / athenaBillingUnit * athenaBillingUnit
+	if billed < athenaMinimumBilled {
+		billed = athenaMinimumBilled
+	}
+	return float64(billed) / athenaBytesPerTB * AthenaPricePerTB
+}
+
+// glueSize reads a size in bytes from catalog parameters
+func glueSize(parameters map[string]*string) (int64, bool) {
+	for _, key := range glueSizeParameters {
+		if value, ok := parameters[key]; ok {
+			if size, err := strconv.ParseInt(aws.StringValue(value), 10, 64); err == nil && size >= 0 {
+				return size, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// sumPartitionBytes adds up the catalog sizes of partitions. Partitions with
+// no recorded size are counted at the average of those with one, so a
+// partly-crawled table still gets an estimate; unsized is how many that was.
+func sumPartitionBytes(partitions []*glue.Partition) (total int64, unsized int) {
+	sized := 0
+	for _, partition := range partitions {
+		if size, ok := glueSize(partition.Parameters); ok {
+			total += size
+			sized++
+		} else {
+			unsized++
+		}
+	}
+	if sized > 0 && unsized > 0 {
+		total += total / int64(sized) * int64(unsized)
+	}
+	return total, unsized
+}
+
+// explainIOBytes reads the input size Athena expects to scan from the output
+// of EXPLAIN (TYPE IO, FORMAT JSON), which may follow a header line. Athena
+// reports NaN, as a string, when the table has no statistics, so ok is false
+// unless every input table has an estimate.
+func explainIOBytes(plan string) (int64, bool) {
+	start := strings.Index(plan, "{")
+	if start < 0 {
+		return 0, false
+	}
+	var io struct {
+		InputTableColumnInfos []struct {
+			Estimate struct {
+				OutputSizeInBytes interface{} `json:"outputSizeInBytes"`
+			} `json:"estimate"`
+		} `json:"inputTableColumnInfos"`
+	}
+	if err := json.NewDecoder(strings.NewReader(plan[start:])).Decode(&io); err != nil || len(io.InputTableColumnInfos) == 0 {
+		return 0, false
+	}
+	var total float64
+	for _, input := range io.InputTableColumnInfos {
+		size, ok := input.Estimate.OutputSizeInBytes.(float64)
+		if !ok || math.IsNaN(size) || math.IsInf(size, 0) || size < 0 {
+			return 0, false
+		}
+		total += size
+	}
+	return int64(total), true
+}
+
+// EstimateScanGlue estimates how many bytes a query reading a Glue table in
+// Athena would scan, and its cost, so callers can warn before sampling a
+// large table. Without a partition filter it asks Athena's EXPLAIN (TYPE IO)
+// first, which reads no data; when that can't run or has no statistics, or a
+// filter is given, it adds up the catalog sizes of the matching partitions
+// (or the table's own size when it isn't partitioned). The filter is a Glue
+// partition expression such as dt >= '2024-01-01'.
+func (m *Manager) EstimateScanGlue(ctx context.Context, connectionName, database, tableName, partitionFilter string) (*ScanEstimate, error) {
+	if err := validateIdentifiers("glue", database, tableName); err != nil {
+		return nil, err
+	}
+	if _, exists := m.config.GetConnection(connectionName); !exists {
+		return nil, fmt.Errorf("connection %s not found", connectionName)
+	}
+
+	explainNote := "EXPLAIN isn't used with a partition filter"
+	if partitionFilter == "" {
+		estimate, err := m.explainScanGlue(ctx, connectionName, database, tableName)
+		if err == nil {
+			return estimate, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		explainNote = fmt.Sprintf("EXPLAIN gave no estimate (%v)", err)
+	}
+
+	clients, err := m.glueClients(connectionName)
+	if err != nil {
+		return nil, err
+	}
+	table, err := clients.glue.GetTableWithContext(ctx, &glue.GetTableInput{
+		DatabaseName: aws.String(database),
+		Name:         aws.String(tableName),
+	})
+	if err != nil {
+		m.glueFailed(connectionName, err)
+		return nil, err
+	}
+
+	if len(table.Table.PartitionKeys) == 0 {
+		if partitionFilter != "" {
+			return nil, fmt.Errorf("table %s.%s is not partitioned; leave out partition_filter", database, tableName)
+		}
+		size, ok := glueSize(table.Table.Parameters)
+		if !ok {
+			return nil, fmt.Errorf("no scan estimate for %s.%s: %s and the catalog records no size for the table (run a Glue crawler to record one)", database, tableName, explainNote)
+		}
+		return &ScanEstimate{
+			Method:           ScanEstimateTable,
+			EstimatedBytes:   size,
+			EstimatedCostUSD: athenaScanCost(size),
+			PricePerTBUSD:    AthenaPricePerTB,
+			Note:             explainNote + "; the whole table is scanned unless LIMIT stops the query early",
+		}, nil
+	}
+
+	var partitions []*glue.Partition
+	input := &glue.GetPartitionsInput{
+		DatabaseName: aws.String(database),
+		TableName:    aws.String(tableName),
+	}
+	if partitionFilter != "" {
+		input.Expression = aws.String(partitionFilter)
+	}
+	err = clients.glue.GetPartitionsPagesWithContext(ctx, input, func(page *glue.GetPartitionsOutput, lastPage bool) bool {
+		partitions = append(partitions, page.Partitions...)
+		return true
+	})
+	if err != nil {
+		m.glueFailed(connectionName, err)
+		return nil, err
+	}
+
+	total, unsized := sumPartitionBytes(partitions)
+	if unsized == len(partitions) && len(partitions) > 0 {
+		return nil, fmt.Errorf("no scan estimate for %s.%s: %s and the catalog records no size for any of its %d partitions (run a Glue crawler to record them)", database, tableName, explainNote, len(partitions))
+	}
+	note := explainNote
+	if unsized > 0 {
+		note += fmt.Sprintf("; %d of %d partitions have no recorded size and are counted at the average", unsized, len(partitions))
+	}
+	return &ScanEstimate{
+		Method:                ScanEstimatePartitions,
+		EstimatedBytes:        total,
+		EstimatedCostUSD:      athenaScanCost(total),
+		PricePerTBUSD:         AthenaPricePerTB,
+		Partitions:            len(partitions),
+		PartitionsWithoutSize: unsized,
+		Note:                  note,
+	}, nil
+}
+
+// explainScanGlue asks Athena how much a full read of the table would scan
+func (m *Manager) explainScanGlue(ctx context.Context, connectionName, database, tableName string) (*ScanEstimate, error) {
+	conn, _ := m.config.GetConnection(connectionName)
+	if !conn.GlueSamplingEnabled() {
+		return nil, ErrGlueSamplingDisabled
+	}
+	outLoc, err := athenaOutputLocation(conn)
+	if err != nil {
+		return nil, err
+	}
+	table, err := qualifiedName("glue", database, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.runAthenaQuery(ctx, connectionName, database, outLoc, "EXPLAIN (TYPE IO, FORMAT JSON) SELECT * FROM "+table)
+	if err != nil {
+		return nil, err
+	}
+	// The plan comes back as one text column, possibly split across rows
+	var plan string
+	for _, row := range rows {
+		for _, datum := range row.Data {
+			plan += aws.StringValue(datum.VarCharValue) + "\n"
+		}
+	}
+	bytes, ok := explainIOBytes(plan)
+	if !ok {
+		return nil, fmt.Errorf("the table has no statistics")
+	}
+	return &ScanEstimate{
+		Method:           ScanEstimateExplain,
+		EstimatedBytes:   bytes,
+		EstimatedCostUSD: athenaScanCost(bytes),
+		PricePerTBUSD:    AthenaPricePerTB,
+		Note:             "the whole table is scanned unless LIMIT stops the query early",
+	}, nil
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func partitionOfSize(parameters map[string]string) *glue.Partition {
+	return &glue.Partition{Parameters: aws.StringMap(parameters)}
+}
+
+func TestAthenaScanCost(t *testing.T) {
+	// Anything under 10 MB is billed as 10 MB
+	minimum := 10.0 * (1 << 20) / (1 << 40) * AthenaPricePerTB
+	testutil.AssertEqual(t, minimum, athenaScanCost(0))
+	testutil.AssertEqual(t, minimum, athenaScanCost(1))
+	testutil.AssertEqual(t, minimum, athenaScanCost(10<<20))
+
+	// Bytes round up to the next megabyte
+	testutil.AssertEqual(t, athenaScanCost(11<<20), athenaScanCost(10<<20+1))
+
+	testutil.AssertEqual(t, 5.0, athenaScanCost(1<<40))
+	testutil.AssertEqual(t, 12.5, athenaScanCost(5<<39))
+}
+
+func TestSumPartitionBytes(t *testing.T) {
+	total, unsized := sumPartitionBytes([]*glue.Partition{
+		partitionOfSize(map[string]string{"sizeKey": "1000", "recordCount": "10"}),
+		partitionOfSize(map[string]string{"totalSize": "3000"}),
+		partitionOfSize(map[string]string{"spark.sql.statistics.totalSize": "2000"}),
+	})
+	testutil.AssertEqual(t, int64(6000), total)
+	testutil.AssertEqual(t, 0, unsized)
+
+	// Partitions without a size count at the average of the others
+	total, unsized = sumPartitionBytes([]*glue.Partition{
+		partitionOfSize(map[string]string{"sizeKey": "1000"}),
+		partitionOfSize(map[string]string{"sizeKey": "3000"}),
+		partitionOfSize(map[string]string{"recordCount": "10"}),
+		partitionOfSize(map[string]string{"sizeKey": "not a number"}),
+	})
+	testutil.AssertEqual(t, int64(8000), total)
+	testutil.AssertEqual(t, 2, unsized)
+
+	total, unsized = sumPartitionBytes([]*glue.Partition{partitionOfSize(nil)})
+	testutil.AssertEqual(t, int64(0), total)
+	testutil.AssertEqual(t, 1, unsized)
+
+	total, unsized = sumPartitionBytes(nil)
+	testutil.AssertEqual(t, int64(0), total)
+	testutil.AssertEqual(t, 0, unsized)
+}
+
+func TestExplainIOBytes(t *testing.T) {
+	// Shaped like Athena's EXPLAIN (TYPE IO, FORMAT JSON), after the header row
+	plan := `Query Plan
+{
+  "inputTableColumnInfos" : [ {
+    "table" : {"catalog" : "awsdatacatalog", "schemaTable" : {"schema" : "events", "table" : "clicks"}},
+    "columnConstraints" : [ ],
+    "estimate" : {"outputRowCount" : 1.2E7, "outputSizeInBytes" : 5.36870912E8, "cpuCost" : 5.36870912E8, "maxMemory" : 0.0, "networkCost" : 0.0}
+  } ],
+  "estimate" : {"outputRowCount" : 1.2E7, "outputSizeInBytes" : 5.36870912E8, "cpuCost" : 5.36870912E8, "maxMemory" : 0.0, "networkCost" : 5.36870912E8}
+}
+`
+	bytes, ok := explainIOBytes(plan)
+	testutil.AssertEqual(t, true, ok)
+	testutil.AssertEqual(t, int64(512<<20), bytes)
+
+	// Tables without statistics report NaN
+	_, ok = explainIOBytes(`{"inputTableColumnInfos": [{"estimate": {"outputRowCount": "NaN", "outputSizeInBytes": "NaN"}}]}`)
+	testutil.AssertEqual(t, false, ok)
+
+	_, ok = explainIOBytes("Query Plan\nFragment 0 [SINGLE]")
+	testutil.AssertEqual(t, false, ok)
+}
+
+func TestEstimateScanGlueValidatesIdentifiers(t *testing.T) {
+	manager := NewManager(glueConfig(nil), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	// Fails before any AWS call or MFA prompt
+	_, err := manager.EstimateScanGlue(context.Background(), "test-glue", "events", "clicks; DROP TABLE x", "")
+	testutil.AssertError(t, err)
+
+	_, err = manager.EstimateScanGlue(context.Background(), "missing", "events", "clicks", "")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "not found")
+}
@@ -0,0 +1,224 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SchemaDump is the structure of every table in a MySQL database or
+// PostgreSQL schema, as written by simpledb-cli schema dump
+type SchemaDump struct {
+	Connection string        `json:"connection"`
+	Type       string        `json:"type"`
+	Database   string        `json:"database"`
+	Schema     string        `json:"schema,omitempty"`
+	Tables     []TableSchema `json:"tables"`
+}
+
+// TableSchema is one table or view of a SchemaDump
+type TableSchema struct {
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	Columns     []ColumnInfo     `json:"columns"`
+	Indexes     []IndexInfo      `json:"indexes"`
+	ForeignKeys []ForeignKeyInfo `json:"foreign_keys"`
+}
+
+// DumpSchema describes every table of a database (MySQL) or schema
+// (PostgreSQL, default public): columns, indexes and foreign keys
+func (m *Manager) DumpSchema(connectionName, database, schema string) (*SchemaDump, error) {
+	conn, exists := m.config.GetConnection(connectionName)
+	if !exists {
+		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+	}
+
+	var tables []TableInfo
+	var foreignKeys []ForeignKeyInfo
+	var describe func(table string) ([]ColumnInfo, []IndexInfo, error)
+	var err error
+
+	switch conn.Type {
+	case "mysql":
+		schema = ""
+		if tables, err = m.ListTablesMySQL(connectionName, database); err != nil {
+			return nil, err
+		}
+		if foreignKeys, err = m.ListForeignKeysMySQL(connectionName, database, ""); err != nil {
+			return nil, err
+		}
+		describe = func(table string) ([]ColumnInfo, []IndexInfo, error) {
+			columns, err := m.DescribeTableMySQL(connectionName, database, table)
+			if err != nil {
+				return nil, nil, err
+			}
+			indexes, err := m.ListIndexesMySQL(connectionName, database, table)
+			return columns, indexes, err
+		}
+	case "postgres":
+		if schema == "" {
+			schema = "public"
+		}
+		if tables, err = m.ListTablesPostgres(connectionName, database, schema); err != nil {
+			return nil, err
+		}
+		if foreignKeys, err = m.ListForeignKeysPostgres(connectionName, database, schema, ""); err != nil {
+			return nil, err
+		}
+		describe = func(table string) ([]ColumnInfo, []IndexInfo, error) {
+			columns, err := m.DescribeTablePostgres(connectionName, database, table, schema)
+			if err != nil {
+				return nil, nil, err
+			}
+			indexes, err := m.ListIndexesPostgres(connectionName, database, table, schema)
+			return columns, indexes, err
+		}
+	default:
+		return nil, fmt.Errorf("schema dumps are not supported for %s connections", conn.Type)
+	}
+
+	keysByTable := make(map[string][]ForeignKeyInfo)
+	for _, fk := range foreignKeys {
+		keysByTable[fk.Table] = append(keysByTable[fk.Table], fk)
+	}
+
+	dump := &SchemaDump{
+		Connection: connectionName,
+		Type:       conn.Type,
+		Database:   database,
+		Schema:     schema,
+		Tables:     make([]TableSchema, 0, len(tables)),
+	}
+	for _, table := range tables {
+		columns, indexes, err := describe(table.Name)
+		if err != nil {
+			return nil, fmt.Errorf("table '%s': %w", table.Name, err)
+		}
+		sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+
+		tableSchema := TableSchema{
+			Name:        table.Name,
+			Type:        table.Type,
+			Columns:     columns,
+			Indexes:     indexes,
+			ForeignKeys: keysByTable[table.Name],
+		}
+		if tableSchema.Columns == nil {
+			tableSchema.Columns = []ColumnInfo{}
+		}
+		if tableSchema.Indexes == nil {
+			tableSchema.Indexes = []IndexInfo{}
+		}
+		if tableSchema.ForeignKeys == nil {
+			tableSchema.ForeignKeys = []ForeignKeyInfo{}
+		}
+		dump.Tables = append(dump.Tables, tableSchema)
+	}
+	return dump, nil
+}
+
+// DDL renders the dump as CREATE TABLE and CREATE INDEX statements. Views are
+// listed in comments, since their definitions aren't part of the dump.
+func (d *SchemaDump) DDL() (string, error) {
+	namespace := d.Database
+	if d.Type == "postgres" {
+		namespace = d.Schema
+	}
+	quote := func(name string) string {
+		quoted, _ := quoteIdentifier(d.Type, name)
+		return quoted
+	}
+	quoteList := func(names []string) string {
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = quote(name)
+		}
+		return strings.Join(quoted, ", ")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Schema of %s (connection %s)\n", namespace, d.Connection)
+
+	for _, table := range d.Tables {
+		name, err := qualifiedName(d.Type, namespace, table.Name)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("\n")
+
+		if isView(table.Type) {
+			columns := make([]string, len(table.Columns))
+			for i, col := range table.Columns {
+				columns[i] = col.Name + " " + col.Type
+			}
+			fmt.Fprintf(&b, "-- View %s (%s)\n", name, strings.Join(columns, ", "))
+			continue
+		}
+
+		var lines, primaryKey []string
+		for _, col := range table.Columns {
+			line := "  " + quote(col.Name) + " " + col.Type
+			if !col.Nullable {
+				line += " NOT NULL"
+			}
+			if col.DefaultValue != nil {
+				line += " DEFAULT " + defaultExpression(d.Type, *col.DefaultValue)
+			}
+			lines = append(lines, line)
+			if col.IsPrimaryKey {
+				primaryKey = append(primaryKey, col.Name)
+			}
+		}
+		if len(primaryKey) > 0 {
+			lines = append(lines, "  PRIMARY KEY ("+quoteList(primaryKey)+")")
+		}
+		for _, fk := range table.ForeignKeys {
+			referenced := quote(fk.ReferencedTable)
+			// Tables in another schema arrive qualified as schema.table
+			if parts := strings.SplitN(fk.ReferencedTable, ".", 2); len(parts) == 2 {
+				referenced = quote(parts[0]) + "." + quote(parts[1])
+			}
+			lines = append(lines, fmt.Sprintf("  CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+				quote(fk.Name), quoteList(fk.Columns), referenced, quoteList(fk.ReferencedColumns)))
+		}
+		fmt.Fprintf(&b, "CREATE TABLE %s (\n%s\n);\n", name, strings.Join(lines, ",\n"))
+
+		for _, idx := range table.Indexes {
+			if isPrimaryKeyIndex(d.Type, table.Name, idx) {
+				continue
+			}
+			if idx.Definition != "" {
+				fmt.Fprintf(&b, "%s;\n", idx.Definition)
+				continue
+			}
+			unique := ""
+			if idx.Unique {
+				unique = "UNIQUE "
+			}
+			fmt.Fprintf(&b, "CREATE %sINDEX %s ON %s (%s);\n", unique, quote(idx.Name), name, quoteList(idx.Columns))
+		}
+	}
+	return b.String(), nil
+}
+
+// isPrimaryKeyIndex reports whether an index only backs the primary key,
+// which CREATE TABLE already declares
+func isPrimaryKeyIndex(engine, table string, idx IndexInfo) bool {
+	if engine == "mysql" {
+		return idx.Name == "PRIMARY"
+	}
+	return idx.Name == table+"_pkey"
+}
+
+// mysqlDefaultKeyword matches MySQL defaults that are expressions rather than literals
+var mysqlDefaultKeyword = regexp.MustCompile(`(?i)^(NULL|CURRENT_TIMESTAMP(\(\d*\))?|NOW\(\)|-?\d+(\.\d+)?|b'[01]*'|\(.*\))$`)
+
+// defaultExpression renders a column default for DDL. Postgres reports
+// defaults as expressions already; MySQL reports literal defaults unquoted.
+func defaultExpression(engine, value string) string {
+	if engine != "mysql" || mysqlDefaultKeyword.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
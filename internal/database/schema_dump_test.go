@@ -0,0 +1,154 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+// shopFixture sets up a MySQL database with two tables and a view, each
+// described separately
+func shopFixture(t *testing.T) *Manager {
+	t.Helper()
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	t.Cleanup(func() { manager.Close() })
+
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	db.SetQueryResult(mysqlListTablesQuery, []string{"TABLE_NAME", "TABLE_TYPE", "TABLE_ROWS"}, [][]interface{}{
+		{"active_customers", "VIEW", int64(0)},
+		{"customers", "BASE TABLE", int64(10)},
+		{"orders", "BASE TABLE", int64(25)},
+	})
+	describeColumns := []string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_DEFAULT", "IS_PRIMARY_KEY"}
+	db.SetQueryResultForArgs(mysqlDescribeTableQuery, []interface{}{"shop", "customers"}, describeColumns, [][]interface{}{
+		{"id", "int", false, nil, true},
+		{"email", "varchar(255)", false, nil, false},
+		{"tier", "varchar(10)", true, "basic", false},
+	})
+	db.SetQueryResultForArgs(mysqlDescribeTableQuery, []interface{}{"shop", "orders"}, describeColumns, [][]interface{}{
+		{"id", "int", false, nil, true},
+		{"customer_id", "int", false, nil, false},
+		{"created_at", "datetime", false, "CURRENT_TIMESTAMP", false},
+	})
+	db.SetQueryResultForArgs(mysqlDescribeTableQuery, []interface{}{"shop", "active_customers"}, describeColumns, [][]interface{}{
+		{"id", "int", false, nil, false},
+	})
+	indexColumns := []string{"INDEX_NAME", "COLUMN_NAME", "INDEX_TYPE", "IS_UNIQUE"}
+	db.SetQueryResultForArgs(mysqlListIndexesQuery, []interface{}{"shop", "customers"}, indexColumns, [][]interface{}{
+		{"PRIMARY", "id", "BTREE", true},
+		{"email_unique", "email", "BTREE", true},
+	})
+	db.SetQueryResultForArgs(mysqlListIndexesQuery, []interface{}{"shop", "orders"}, indexColumns, [][]interface{}{
+		{"PRIMARY", "id", "BTREE", true},
+		{"customer_created", "customer_id", "BTREE", false},
+		{"customer_created", "created_at", "BTREE", false},
+	})
+	db.SetQueryResult(mysqlForeignKeysQuery, []string{"TABLE_NAME", "CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_SCHEMA", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME"}, [][]interface{}{
+		{"orders", "orders_customer_fk", "customer_id", "shop", "customers", "id"},
+	})
+	return manager
+}
+
+func TestDumpSchemaIncludesAllTablesAndColumns(t *testing.T) {
+	manager := shopFixture(t)
+
+	dump, err := manager.DumpSchema("test-mysql", "shop", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, len(dump.Tables))
+
+	columnNames := func(table TableSchema) string {
+		names := make([]string, len(table.Columns))
+		for i, col := range table.Columns {
+			names[i] = col.Name
+		}
+		return strings.Join(names, ",")
+	}
+	testutil.AssertEqual(t, "active_customers", dump.Tables[0].Name)
+	testutil.AssertEqual(t, "id,email,tier", columnNames(dump.Tables[1]))
+	testutil.AssertEqual(t, "id,customer_id,created_at", columnNames(dump.Tables[2]))
+
+	orders := dump.Tables[2]
+	testutil.AssertEqual(t, 2, len(orders.Indexes))
+	testutil.AssertEqual(t, "customer_created", orders.Indexes[1].Name)
+	testutil.AssertEqual(t, "customer_id,created_at", strings.Join(orders.Indexes[1].Columns, ","))
+	testutil.AssertEqual(t, 1, len(orders.ForeignKeys))
+	testutil.AssertEqual(t, "customers", orders.ForeignKeys[0].ReferencedTable)
+	testutil.AssertEqual(t, 0, len(dump.Tables[1].ForeignKeys))
+}
+
+func TestDescribeTableOrdinals(t *testing.T) {
+	manager := shopFixture(t)
+
+	columns, err := manager.DescribeTableMySQL("test-mysql", "shop", "orders")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, len(columns))
+	for i, col := range columns {
+		testutil.AssertEqual(t, i+1, col.Ordinal)
+	}
+	testutil.AssertEqual(t, "created_at", columns[2].Name)
+
+	// The dump keeps each table's ordinals
+	dump, err := manager.DumpSchema("test-mysql", "shop", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, dump.Tables[1].Columns[2].Ordinal)
+	testutil.AssertEqual(t, "tier", dump.Tables[1].Columns[2].Name)
+}
+
+func TestDumpSchemaDDL(t *testing.T) {
+	manager := shopFixture(t)
+
+	dump, err := manager.DumpSchema("test-mysql", "shop", "")
+	testutil.AssertNoError(t, err)
+	ddl, err := dump.DDL()
+	testutil.AssertNoError(t, err)
+
+	testutil.AssertContains(t, ddl, "-- View `shop`.`active_customers` (id int)")
+	testutil.AssertContains(t, ddl, "CREATE TABLE `shop`.`customers` (\n"+
+		"  `id` int NOT NULL,\n"+
+		"  `email` varchar(255) NOT NULL,\n"+
+		"  `tier` varchar(10) DEFAULT 'basic',\n"+
+		"  PRIMARY KEY (`id`)\n"+
+		");\n")
+	testutil.AssertContains(t, ddl, "`created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP")
+	testutil.AssertContains(t, ddl, "CONSTRAINT `orders_customer_fk` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`)")
+	testutil.AssertContains(t, ddl, "CREATE UNIQUE INDEX `email_unique` ON `shop`.`customers` (`email`);")
+	testutil.AssertContains(t, ddl, "CREATE INDEX `customer_created` ON `shop`.`orders` (`customer_id`, `created_at`);")
+	testutil.AssertEqual(t, false, strings.Contains(ddl, "PRIMARY`"))
+}
+
+func TestDumpSchemaUnsupportedType(t *testing.T) {
+	manager := salesforceManager(t, &fakeSalesforce{})
+	_, err := manager.DumpSchema("test-sf", "", "")
+	testutil.AssertError(t, err)
+}
+
+func TestListForeignKeysPostgres(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-postgres")
+	db.SetQueryResult(postgresForeignKeysQuery, []string{"relname", "conname", "attname", "nspname", "relname", "attname"}, [][]interface{}{
+		{"line_items", "line_items_order_fk", "order_id", "public", "orders", "id"},
+		{"line_items", "line_items_order_fk", "order_rev", "public", "orders", "rev"},
+		{"line_items", "line_items_sku_fk", "sku", "catalog", "products", "sku"},
+	})
+
+	foreignKeys, err := manager.ListForeignKeysPostgres("test-postgres", "testdb", "", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(foreignKeys))
+	testutil.AssertEqual(t, "order_id,order_rev", strings.Join(foreignKeys[0].Columns, ","))
+	testutil.AssertEqual(t, "id,rev", strings.Join(foreignKeys[0].ReferencedColumns, ","))
+	// Tables outside the schema are qualified
+	testutil.AssertEqual(t, "catalog.products", foreignKeys[1].ReferencedTable)
+}
+
+func TestParseIndexDef(t *testing.T) {
+	method, columns := parseIndexDef("CREATE UNIQUE INDEX users_email ON public.users USING btree (lower((email)::text), \"Tenant Id\")")
+	testutil.AssertEqual(t, "btree", method)
+	testutil.AssertEqual(t, "lower((email)::text)|Tenant Id", strings.Join(columns, "|"))
+
+	method, columns = parseIndexDef("CREATE INDEX docs_body ON public.docs USING gin (body) WHERE (published IS TRUE)")
+	testutil.AssertEqual(t, "gin", method)
+	testutil.AssertEqual(t, "body", strings.Join(columns, "|"))
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrAmbiguousTable is returned when a table name given without a schema
+// exists in several schemas, none of them on the search_path
+var ErrAmbiguousTable = errors.New("table name is ambiguous")
+
+// schemaCandidate is a schema holding a table of the name being resolved
+type schemaCandidate struct {
+	schema       string
+	onSearchPath bool
+}
+
+// Schemas holding the table, those on the session's search_path first in
+// search_path order, then the rest by name
+const resolveSchemaQuery = `
+		SELECT table_schema, array_position(current_schemas(false), table_schema::text) IS NOT NULL
+		FROM information_schema.tables
+		WHERE table_name = $1 AND table_schema NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY array_position(current_schemas(false), table_schema::text) NULLS LAST, table_schema`
+
+// pickSchema chooses the schema a table name refers to, the way Postgres
+// resolves an unqualified name, but also looking beyond the search_path when
+// the table is in exactly one other schema. "" means no schema has the table.
+func pickSchema(tableName string, candidates []schemaCandidate) (string, error) {
+	switch {
+	case len(candidates) == 0:
+		return "", nil
+	case candidates[0].onSearchPath, len(candidates) == 1:
+		return candidates[0].schema, nil
+	}
+	schemas := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		schemas[i] = candidate.schema
+	}
+	return "", fmt.Errorf("%w: table %q exists in schemas %s; pass schema to pick one", ErrAmbiguousTable, tableName, strings.Join(schemas, ", "))
+}
+
+// ResolveSchemaPostgres finds the schema of a table named without one: the
+// first schema on the connection's search_path that has it, or else the only
+// schema that does. It returns "" when no schema has the table, and
+// ErrAmbiguousTable when several do but none is on the search_path.
+func (m *Manager) ResolveSchemaPostgres(ctx context.Context, connectionName, tableName string) (string, error) {
+	if err := validateIdentifier("postgres", tableName); err != nil {
+		return "", err
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+
+	rows, err := m.query(ctx, db, connectionName, resolveSchemaQuery, tableName)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the schema of %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	var candidates []schemaCandidate
+	for rows.Next() {
+		var candidate schemaCandidate
+		if err := rows.Scan(&candidate.schema, &candidate.onSearchPath); err != nil {
+			return "", fmt.Errorf("failed to scan schema: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to resolve the schema of %s: %w", tableName, err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), len(candidates), time.Since(start))
+
+	return pickSchema(tableName, candidates)
+}
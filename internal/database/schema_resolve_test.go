@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestPickSchema(t *testing.T) {
+	schema, err := pickSchema("orders", nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "", schema)
+
+	// The search_path wins, as it does for Postgres itself
+	schema, err = pickSchema("orders", []schemaCandidate{{"app", true}, {"archive", false}, {"billing", false}})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "app", schema)
+
+	schema, err = pickSchema("invoices", []schemaCandidate{{"billing", false}})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "billing", schema)
+
+	_, err = pickSchema("events", []schemaCandidate{{"archive", false}, {"billing", false}})
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, true, errors.Is(err, ErrAmbiguousTable))
+	testutil.AssertContains(t, err.Error(), `table "events" exists in schemas archive, billing; pass schema to pick one`)
+}
+
+func TestResolveSchemaPostgres(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-postgres")
+	columns := []string{"table_schema", "on_search_path"}
+	db.SetQueryResultForArgs(resolveSchemaQuery, []interface{}{"invoices"}, columns, [][]interface{}{
+		{"billing", false},
+	})
+	db.SetQueryResultForArgs(resolveSchemaQuery, []interface{}{"events"}, columns, [][]interface{}{
+		{"archive", false},
+		{"tracking", false},
+	})
+	db.SetQueryResultForArgs(resolveSchemaQuery, []interface{}{"missing"}, columns, nil)
+
+	schema, err := manager.ResolveSchemaPostgres(context.Background(), "test-postgres", "invoices")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "billing", schema)
+
+	_, err = manager.ResolveSchemaPostgres(context.Background(), "test-postgres", "events")
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, true, errors.Is(err, ErrAmbiguousTable))
+	testutil.AssertContains(t, err.Error(), "archive, tracking")
+
+	schema, err = manager.ResolveSchemaPostgres(context.Background(), "test-postgres", "missing")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "", schema)
+
+	_, err = manager.ResolveSchemaPostgres(context.Background(), "test-postgres", "orders; DROP TABLE x")
+	testutil.AssertEqual(t, true, errors.Is(err, ErrInvalidIdentifier))
+}
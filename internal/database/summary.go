@@ -0,0 +1,44 @@
+package database
+
+import "strings"
+
+// DatabaseSummary is a high-level overview of a database or schema
+type DatabaseSummary struct {
+	TableCount     int         `json:"table_count"`
+	ViewCount      int         `json:"view_count"`
+	EstimatedRows  int64       `json:"estimated_rows"`
+	TotalSizeBytes *int64      `json:"total_size_bytes,omitempty"` // not available for every engine
+	Tables         []TableInfo `json:"tables"`
+	Truncated      bool        `json:"truncated"`
+}
+
+// isView reports whether a table type reported by an engine is a view
+// (e.g. "VIEW", "SYSTEM VIEW", Glue's "VIRTUAL_VIEW")
+func isView(tableType string) bool {
+	return strings.Contains(strings.ToUpper(tableType), "VIEW")
+}
+
+// summarizeTables aggregates counts and row estimates over all tables and
+// keeps at most maxTables of them in the listing (0 means no cap).
+func summarizeTables(tables []TableInfo, maxTables int) *DatabaseSummary {
+	summary := &DatabaseSummary{Tables: tables}
+	for _, table := range tables {
+		if isView(table.Type) {
+			summary.ViewCount++
+		} else {
+			summary.TableCount++
+		}
+		if table.RowCount != nil {
+			summary.EstimatedRows += *table.RowCount
+		}
+	}
+
+	if maxTables > 0 && len(tables) > maxTables {
+		summary.Tables = tables[:maxTables]
+		summary.Truncated = true
+	}
+	if summary.Tables == nil {
+		summary.Tables = []TableInfo{}
+	}
+	return summary
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func rowCount(n int64) *int64 {
+	return &n
+}
+
+func TestSummarizeTables(t *testing.T) {
+	tables := []TableInfo{
+		{Name: "customers", Type: "BASE TABLE", RowCount: rowCount(1200)},
+		{Name: "orders", Type: "BASE TABLE", RowCount: rowCount(54000)},
+		{Name: "audit_log", Type: "BASE TABLE"}, // no estimate available
+		{Name: "active_customers", Type: "VIEW"},
+		{Name: "daily_events", Type: "VIRTUAL_VIEW"},
+		{Name: "raw_events", Type: "EXTERNAL_TABLE", RowCount: rowCount(800)},
+	}
+
+	summary := summarizeTables(tables, 100)
+	testutil.AssertEqual(t, 4, summary.TableCount)
+	testutil.AssertEqual(t, 2, summary.ViewCount)
+	testutil.AssertEqual(t, int64(56000), summary.EstimatedRows)
+	testutil.AssertEqual(t, 6, len(summary.Tables))
+	testutil.AssertEqual(t, false, summary.Truncated)
+}
+
+func TestSummarizeTablesCap(t *testing.T) {
+	var tables []TableInfo
+	for i := 0; i < 25; i++ {
+		tables = append(tables, TableInfo{Name: fmt.Sprintf("table_%02d", i), Type: "BASE TABLE", RowCount: rowCount(10)})
+	}
+
+	summary := summarizeTables(tables, 10)
+	testutil.AssertEqual(t, 10, len(summary.Tables))
+	testutil.AssertEqual(t, "table_09", summary.Tables[9].Name)
+	testutil.AssertEqual(t, true, summary.Truncated)
+
+	// Counts still cover every table, not just the listed ones
+	testutil.AssertEqual(t, 25, summary.TableCount)
+	testutil.AssertEqual(t, int64(250), summary.EstimatedRows)
+}
+
+func TestSummarizeTablesEmpty(t *testing.T) {
+	summary := summarizeTables(nil, 10)
+	testutil.AssertEqual(t, 0, summary.TableCount)
+	testutil.AssertEqual(t, 0, len(summary.Tables))
+	testutil.AssertEqual(t, false, summary.Truncated)
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Object types describe_table reports for MySQL and PostgreSQL
+const (
+	ObjectTypeTable = "table"
+	ObjectTypeView  = "view"
+)
+
+const mysqlObjectTypeQuery = `
+		SELECT TABLE_TYPE
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`
+
+const postgresObjectTypeQuery = `
+		SELECT table_type
+		FROM information_schema.tables
+		WHERE table_schema = $1 AND table_name = $2`
+
+const postgresViewDefinitionQuery = `
+		SELECT pg_get_viewdef(c.oid, true)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('v', 'm')`
+
+// objectType maps an information_schema TABLE_TYPE to table or view
+func objectType(tableType string) string {
+	switch tableType {
+	case "VIEW", "SYSTEM VIEW":
+		return ObjectTypeView
+	}
+	return ObjectTypeTable
+}
+
+// lookupObjectType runs an object type query, returning "" when no table
+// or view has the name
+func (m *Manager) lookupObjectType(connectionName, query string, args ...interface{}) (string, error) {
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+
+	var tableType string
+	err = m.queryRow(context.Background(), db, connectionName, query, args...).Scan(&tableType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get object type: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), 1, time.Since(start))
+
+	return objectType(tableType), nil
+}
+
+// ObjectTypeMySQL reports whether a name is a table or a view, or "" when
+// it is neither
+func (m *Manager) ObjectTypeMySQL(connectionName, database, tableName string) (string, error) {
+	if err := validateIdentifiers("mysql", database, tableName); err != nil {
+		return "", err
+	}
+	return m.lookupObjectType(connectionName, mysqlObjectTypeQuery, database, tableName)
+}
+
+// ObjectTypePostgres reports whether a name is a table or a view, or "" when
+// it is neither
+func (m *Manager) ObjectTypePostgres(connectionName, database, tableName, schema string) (string, error) {
+	if err := validateIdentifiers("postgres", database, schema, tableName); err != nil {
+		return "", err
+	}
+	if schema == "" {
+		schema = "public"
+	}
+	return m.lookupObjectType(connectionName, postgresObjectTypeQuery, schema, tableName)
+}
+
+// ViewDefinitionPostgres returns the query a view is defined by, as
+// pg_get_viewdef reconstructs it
+func (m *Manager) ViewDefinitionPostgres(connectionName, database, viewName, schema string) (string, error) {
+	if err := validateIdentifiers("postgres", database, schema, viewName); err != nil {
+		return "", err
+	}
+
+	db, err := m.GetConnection(connectionName)
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+
+	if schema == "" {
+		schema = "public"
+	}
+
+	var definition string
+	if err := m.queryRow(context.Background(), db, connectionName, postgresViewDefinitionQuery, schema, viewName).Scan(&definition); err != nil {
+		return "", fmt.Errorf("failed to get view definition: %w", err)
+	}
+
+	m.pool.recordQuery(m.routeConnection(connectionName, false), 1, time.Since(start))
+
+	return definition, nil
+}
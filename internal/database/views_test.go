@@ -0,0 +1,53 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestObjectTypeMySQL(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-mysql")
+	columns := []string{"TABLE_TYPE"}
+	db.SetQueryResultForArgs(mysqlObjectTypeQuery, []interface{}{"shop", "orders"}, columns, [][]interface{}{{"BASE TABLE"}})
+	db.SetQueryResultForArgs(mysqlObjectTypeQuery, []interface{}{"shop", "order_totals"}, columns, [][]interface{}{{"VIEW"}})
+	db.SetQueryResultForArgs(mysqlObjectTypeQuery, []interface{}{"shop", "missing"}, columns, nil)
+
+	objectType, err := manager.ObjectTypeMySQL("test-mysql", "shop", "orders")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, ObjectTypeTable, objectType)
+
+	objectType, err = manager.ObjectTypeMySQL("test-mysql", "shop", "order_totals")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, ObjectTypeView, objectType)
+
+	objectType, err = manager.ObjectTypeMySQL("test-mysql", "shop", "missing")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "", objectType)
+}
+
+func TestObjectTypePostgres(t *testing.T) {
+	manager := NewManager(testConfig(), testutil.NewMockCredentialManager())
+	defer manager.Close()
+
+	db := addMockConnection(t, manager.pool, "test-postgres")
+	db.SetQueryResultForArgs(postgresObjectTypeQuery, []interface{}{"public", "active_users"}, []string{"table_type"}, [][]interface{}{{"VIEW"}})
+	db.SetQueryResultForArgs(postgresViewDefinitionQuery, []interface{}{"reporting", "active_users"}, []string{"pg_get_viewdef"}, [][]interface{}{
+		{" SELECT users.id\n   FROM users\n  WHERE users.active;"},
+	})
+
+	// No schema means public, as for the other tools
+	objectType, err := manager.ObjectTypePostgres("test-postgres", "app", "active_users", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, ObjectTypeView, objectType)
+
+	definition, err := manager.ViewDefinitionPostgres("test-postgres", "app", "active_users", "reporting")
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, definition, "FROM users")
+
+	_, err = manager.ObjectTypePostgres("test-postgres", "app", "users; DROP TABLE x", "")
+	testutil.AssertError(t, err)
+}
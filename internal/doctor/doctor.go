@@ -0,0 +1,226 @@
+// Package doctor checks that simpledb-mcp can run on this machine: the
+// config loads, its directory is writable, the keychain and biometric prompt
+// answer, and every connection can be reached.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/credentials"
+	"github.com/eliziario/simpledb-mcp/internal/database"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn" // works, but maybe not as intended
+	StatusFail Status = "fail" // critical: something won't work until it's fixed
+	StatusSkip Status = "skip"
+)
+
+// statusMarks prefix each check in the printed checklist
+var statusMarks = map[Status]string{
+	StatusPass: "✅",
+	StatusWarn: "⚠️ ",
+	StatusFail: "❌",
+	StatusSkip: "➖",
+}
+
+// biometricReason is shown in the prompt the biometric check raises
+const biometricReason = "SimpleDB MCP doctor is checking biometric authentication"
+
+// Check is one line of the checklist, with a hint on how to fix it
+type Check struct {
+	Name   string
+	Status Status
+	Detail string
+	Hint   string
+}
+
+// Failed reports whether any check failed
+func Failed(checks []Check) bool {
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes checks as a checklist, hints indented under failures and warnings
+func Print(w io.Writer, checks []Check) {
+	for _, check := range checks {
+		fmt.Fprintf(w, "%s %s: %s\n", statusMarks[check.Status], check.Name, check.Detail)
+		if check.Hint != "" && (check.Status == StatusFail || check.Status == StatusWarn) {
+			fmt.Fprintf(w, "   → %s\n", check.Hint)
+		}
+	}
+}
+
+// CheckConfig loads and validates the config file. The config is returned
+// whenever it loaded, even if invalid, so connections can still be tested.
+func CheckConfig() (Check, *config.Config) {
+	check := Check{Name: "Config"}
+	path, err := config.ConfigPath()
+	if err != nil {
+		check.Status, check.Detail = StatusFail, err.Error()
+		check.Hint = "set HOME to your home directory"
+		return check, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		check.Status, check.Detail = StatusFail, err.Error()
+		check.Hint = fmt.Sprintf("fix the YAML in %s, or move it aside and run 'simpledb-cli config' to start over", path)
+		return check, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s is invalid: %s", path, strings.ReplaceAll(err.Error(), "\n", "; "))
+		check.Hint = "fix the settings listed, or run 'simpledb-cli config show' to see the config the server would use"
+		return check, cfg
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		check.Status, check.Detail = StatusWarn, fmt.Sprintf("%s doesn't exist yet; using defaults", path)
+		check.Hint = "add a connection with 'simpledb-cli config' or 'simpledb-cli connection add'"
+		return check, cfg
+	}
+	if len(cfg.Connections) == 0 {
+		check.Status, check.Detail = StatusWarn, fmt.Sprintf("%s has no connections", path)
+		check.Hint = "add a connection with 'simpledb-cli config' or 'simpledb-cli connection add'"
+		return check, cfg
+	}
+	check.Status, check.Detail = StatusPass, fmt.Sprintf("%s loaded (%d connections)", path, len(cfg.Connections))
+	return check, cfg
+}
+
+// CheckConfigDir makes sure dir exists and a file can be written in it, as
+// saving the config and the credential fallback file need
+func CheckConfigDir(dir string) Check {
+	check := Check{Name: "Config directory"}
+	hint := fmt.Sprintf("make %s writable by your user, e.g. chown -R $USER %s", dir, dir)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		check.Status, check.Detail, check.Hint = StatusFail, err.Error(), hint
+		return check
+	}
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		check.Status, check.Detail, check.Hint = StatusFail, fmt.Sprintf("%s is not writable: %v", dir, err), hint
+		return check
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	check.Status, check.Detail = StatusPass, dir+" is writable"
+	return check
+}
+
+// CheckKeychain checks the system keychain answers. When it doesn't, a
+// configured credential fallback file makes that a warning instead of a failure.
+func CheckKeychain(store credentials.KeyStore, fallbackFile string) Check {
+	check := Check{Name: "Keychain"}
+	err := credentials.ProbeKeyStore(store)
+	switch {
+	case err == nil:
+		check.Status, check.Detail = StatusPass, "the system keychain answers"
+	case fallbackFile != "":
+		check.Status = StatusWarn
+		check.Detail = fmt.Sprintf("the system keychain is unavailable (%v); credentials come from %s* environment variables and %s", err, credentials.CredentialEnvPrefix, fallbackFile)
+		check.Hint = "on Linux, run a Secret Service such as gnome-keyring to keep credentials in the keychain instead"
+	default:
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("the system keychain is unavailable: %v", err)
+		check.Hint = "on Linux, run a Secret Service such as gnome-keyring, or set 'credential_fallback: file' under settings to use environment variables and a credentials file"
+	}
+	return check
+}
+
+// CheckBiometric raises the prompt credential reads will raise, falling back
+// to the system password prompt the same way, so the user must approve it
+func CheckBiometric(require bool, biometric, passcode credentials.BiometricAuthenticator) Check {
+	check := Check{Name: "Biometric authentication"}
+	if !require {
+		check.Status, check.Detail = StatusSkip, "require_biometric is off; credentials are read without a prompt"
+		return check
+	}
+	if biometric == nil {
+		check.Status, check.Detail = StatusPass, "this platform has no biometric prompt; credentials are read without one"
+		return check
+	}
+
+	authenticated, err := biometric.Authenticate(biometricReason)
+	if err == nil {
+		if !authenticated {
+			check.Status, check.Detail = StatusWarn, "the biometric prompt was cancelled or failed"
+			check.Hint = "run doctor again and approve the prompt"
+			return check
+		}
+		check.Status, check.Detail = StatusPass, "biometric prompt approved"
+		return check
+	}
+
+	if passcode != nil {
+		if authenticated, passcodeErr := passcode.Authenticate(biometricReason); passcodeErr == nil {
+			if !authenticated {
+				check.Status, check.Detail = StatusWarn, fmt.Sprintf("biometrics are unavailable (%v) and the password prompt was cancelled or failed", err)
+				check.Hint = "run doctor again and approve the prompt"
+				return check
+			}
+			check.Status, check.Detail = StatusWarn, fmt.Sprintf("biometrics are unavailable (%v); the system password prompt is used instead", err)
+			check.Hint = "enroll a fingerprint in System Settings > Touch ID & Password to use Touch ID"
+			return check
+		}
+	}
+
+	check.Status, check.Detail = StatusFail, fmt.Sprintf("%v: %v", credentials.ErrBiometricUnavailable, err)
+	check.Hint = "set 'require_biometric: false' under settings to read credentials without a prompt"
+	return check
+}
+
+// failureHints suggest a fix for each kind of connection failure
+var failureHints = map[database.FailureKind]string{
+	database.FailureAuth:        "check the username, and store the password again with 'simpledb-cli config'",
+	database.FailureUnreachable: "check host and port, VPN and proxy; 'simpledb-cli connection detect <host> <port>' tells what answers there",
+	database.FailurePermission:  "grant the connection's user access to the database",
+	database.FailureTimeout:     "the server didn't answer in time; check firewalls, or raise connect_timeout under settings",
+}
+
+// CheckConnections runs test on each enabled connection, in name order
+func CheckConnections(cfg *config.Config, test func(connectionName string) error) []Check {
+	names := cfg.ListConnections()
+	sort.Strings(names)
+
+	checks := make([]Check, 0, len(names))
+	for _, name := range names {
+		conn := cfg.Connections[name]
+		check := Check{Name: fmt.Sprintf("Connection '%s' (%s)", name, conn.Type)}
+		if !conn.IsEnabled() {
+			check.Status, check.Detail = StatusSkip, "disabled"
+			checks = append(checks, check)
+			continue
+		}
+
+		if err := test(name); err != nil {
+			kind := database.ClassifyFailure(err)
+			check.Status, check.Detail = StatusFail, fmt.Sprintf("%s: %v", kind, err)
+			check.Hint = failureHints[kind]
+			if errors.Is(err, credentials.ErrNotFound) {
+				check.Hint = "no password is stored for it; add one with 'simpledb-cli config'"
+			}
+		} else {
+			check.Status, check.Detail = StatusPass, "connected"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
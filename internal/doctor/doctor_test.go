@@ -0,0 +1,174 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/credentials"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/go-sql-driver/mysql"
+)
+
+// brokenKeyStore fails like go-keyring on Linux without a Secret Service
+type brokenKeyStore struct{}
+
+func (brokenKeyStore) Get(key string) (string, error) {
+	return "", errors.New("The name org.freedesktop.secrets was not provided by any .service files")
+}
+func (brokenKeyStore) Set(key, secret string) error { return errors.New("no secret service") }
+func (brokenKeyStore) Delete(key string) error      { return errors.New("no secret service") }
+
+func TestCheckConfig(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  orders-db:
+    type: mysql
+    host: localhost
+    port: 3306
+`)
+	check, cfg := CheckConfig()
+	testutil.AssertEqual(t, StatusPass, check.Status)
+	testutil.AssertContains(t, check.Detail, "(1 connections)")
+	testutil.AssertEqual(t, 1, len(cfg.Connections))
+
+	// Invalid configs fail but are still returned for the connection tests
+	testutil.WriteConfigFile(t, `
+connections:
+  orders-db:
+    type: mysql
+    host: localhost
+    port: 3306
+    max_rows: -5
+`)
+	check, cfg = CheckConfig()
+	testutil.AssertEqual(t, StatusFail, check.Status)
+	testutil.AssertContains(t, check.Detail, "max_rows cannot be negative")
+	testutil.AssertEqual(t, true, cfg != nil)
+
+	testutil.WriteConfigFile(t, "connections: [not, a, map")
+	check, cfg = CheckConfig()
+	testutil.AssertEqual(t, StatusFail, check.Status)
+	testutil.AssertContains(t, check.Hint, "fix the YAML")
+	testutil.AssertEqual(t, true, cfg == nil)
+
+	// No config file yet is a warning, not a failure
+	t.Setenv("HOME", testutil.TempDir(t))
+	check, _ = CheckConfig()
+	testutil.AssertEqual(t, StatusWarn, check.Status)
+	testutil.AssertContains(t, check.Detail, "doesn't exist yet")
+}
+
+func TestCheckConfigDir(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(t), "simpledb-mcp")
+	check := CheckConfigDir(dir)
+	testutil.AssertEqual(t, StatusPass, check.Status)
+	testutil.AssertFileExists(t, dir)
+
+	entries, err := os.ReadDir(dir)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, len(entries))
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	readOnly := testutil.TempDir(t)
+	testutil.AssertNoError(t, os.Chmod(readOnly, 0555))
+	defer os.Chmod(readOnly, 0755)
+	check = CheckConfigDir(readOnly)
+	testutil.AssertEqual(t, StatusFail, check.Status)
+	testutil.AssertContains(t, check.Hint, "writable")
+}
+
+func TestCheckKeychain(t *testing.T) {
+	check := CheckKeychain(credentials.NewFakeKeyStore(), "")
+	testutil.AssertEqual(t, StatusPass, check.Status)
+
+	check = CheckKeychain(brokenKeyStore{}, "")
+	testutil.AssertEqual(t, StatusFail, check.Status)
+	testutil.AssertContains(t, check.Detail, "org.freedesktop.secrets")
+	testutil.AssertContains(t, check.Hint, "credential_fallback: file")
+
+	// A configured fallback keeps the server working
+	check = CheckKeychain(brokenKeyStore{}, "/run/secrets/simpledb.json")
+	testutil.AssertEqual(t, StatusWarn, check.Status)
+	testutil.AssertContains(t, check.Detail, "/run/secrets/simpledb.json")
+}
+
+func TestCheckBiometric(t *testing.T) {
+	unavailable := errors.New("biometry is not enrolled")
+	tests := []struct {
+		name      string
+		require   bool
+		biometric credentials.BiometricAuthenticator
+		passcode  credentials.BiometricAuthenticator
+		want      Status
+		prompts   int
+	}{
+		{"not required", false, &credentials.FakeAuthenticator{Authenticated: true}, nil, StatusSkip, 0},
+		{"no prompt on this platform", true, nil, nil, StatusPass, 0},
+		{"approved", true, &credentials.FakeAuthenticator{Authenticated: true}, nil, StatusPass, 1},
+		{"cancelled", true, &credentials.FakeAuthenticator{}, nil, StatusWarn, 1},
+		{"password fallback", true, &credentials.FakeAuthenticator{Err: unavailable}, &credentials.FakeAuthenticator{Authenticated: true}, StatusWarn, 1},
+		{"unavailable", true, &credentials.FakeAuthenticator{Err: unavailable}, &credentials.FakeAuthenticator{Err: unavailable}, StatusFail, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := CheckBiometric(tt.require, tt.biometric, tt.passcode)
+			testutil.AssertEqual(t, tt.want, check.Status)
+			if fake, ok := tt.biometric.(*credentials.FakeAuthenticator); ok {
+				testutil.AssertEqual(t, tt.prompts, fake.Calls())
+			}
+			if tt.want == StatusFail {
+				testutil.AssertContains(t, check.Hint, "require_biometric: false")
+			}
+		})
+	}
+}
+
+func TestCheckConnections(t *testing.T) {
+	disabled := false
+	cfg := config.DefaultConfig()
+	cfg.Connections["warehouse"] = config.Connection{Type: "postgres"}
+	cfg.Connections["orders-db"] = config.Connection{Type: "mysql"}
+	cfg.Connections["legacy"] = config.Connection{Type: "mysql", Enabled: &disabled}
+	cfg.Connections["crm"] = config.Connection{Type: "salesforce"}
+
+	var tested []string
+	checks := CheckConnections(cfg, func(name string) error {
+		tested = append(tested, name)
+		switch name {
+		case "orders-db":
+			return &mysql.MySQLError{Number: 1045, Message: "Access denied for user 'app'@'10.0.0.1'"}
+		case "crm":
+			return fmt.Errorf("failed to get credentials for connection 'crm': %w", credentials.ErrNotFound)
+		}
+		return nil
+	})
+
+	testutil.AssertEqual(t, []string{"crm", "orders-db", "warehouse"}, tested)
+	testutil.AssertEqual(t, 4, len(checks))
+	testutil.AssertEqual(t, StatusFail, checks[0].Status)
+	testutil.AssertContains(t, checks[0].Hint, "no password is stored")
+	testutil.AssertEqual(t, StatusSkip, checks[1].Status)
+	testutil.AssertEqual(t, StatusFail, checks[2].Status)
+	testutil.AssertContains(t, checks[2].Detail, "auth_failed")
+	testutil.AssertContains(t, checks[2].Hint, "store the password again")
+	testutil.AssertEqual(t, StatusPass, checks[3].Status)
+	testutil.AssertEqual(t, "Connection 'warehouse' (postgres)", checks[3].Name)
+	testutil.AssertEqual(t, true, Failed(checks))
+	testutil.AssertEqual(t, false, Failed(checks[1:2]))
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	Print(&buf, []Check{
+		{Name: "Config", Status: StatusPass, Detail: "loaded", Hint: "not shown"},
+		{Name: "Keychain", Status: StatusFail, Detail: "unavailable", Hint: "set credential_fallback"},
+	})
+	testutil.AssertEqual(t, "✅ Config: loaded\n❌ Keychain: unavailable\n   → set credential_fallback\n", buf.String())
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Level is the severity of a log line
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// levelNames maps --log-level values to levels
+var levelNames = map[string]Level{
+	"debug": LevelDebug,
+	"info":  LevelInfo,
+	"warn":  LevelWarn,
+	"error": LevelError,
+}
+
+// ParseLevel reads a level name: debug, info, warn or error
+func ParseLevel(name string) (Level, error) {
+	level, ok := levelNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return 0, fmt.Errorf("unknown log level '%s': use debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// logPrefix matches the date and time the standard logger puts before each message
+var logPrefix = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} )?(\d{2}:\d{2}:\d{2}(\.\d+)? )?`)
+
+// LineLevel classifies a log line by how its message starts: "DEBUG" lines
+// are debug, "Warning"/"WARN" lines warnings, "ERROR"/"Error"/"Failed" lines
+// errors, and everything else is info.
+func LineLevel(line string) Level {
+	message := line[len(logPrefix.FindString(line)):]
+	switch {
+	case strings.HasPrefix(message, "DEBUG"):
+		return LevelDebug
+	case strings.HasPrefix(message, "Warning"), strings.HasPrefix(message, "WARN"):
+		return LevelWarn
+	case strings.HasPrefix(message, "ERROR"), strings.HasPrefix(message, "Error"), strings.HasPrefix(message, "Failed"):
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+// levelWriter drops log lines below a minimum level
+type levelWriter struct {
+	out io.Writer
+	min Level
+}
+
+// NewLevelWriter wraps out so that log lines below min are discarded.
+// The standard logger issues one Write per line, so each write is classified whole.
+func NewLevelWriter(out io.Writer, min Level) io.Writer {
+	if min <= LevelDebug {
+		return out
+	}
+	return &levelWriter{out: out, min: min}
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	if LineLevel(string(p)) < w.min {
+		return len(p), nil
+	}
+	if _, err := w.out.Write(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestLineLevel(t *testing.T) {
+	testutil.AssertEqual(t, LevelDebug, LineLevel("2026/10/18 09:15:02 DEBUG query [prod] SELECT 1\n"))
+	testutil.AssertEqual(t, LevelWarn, LineLevel("2026/10/18 09:15:02 Warning: config file is version 3\n"))
+	testutil.AssertEqual(t, LevelError, LineLevel("09:15:02.123456 Failed to create server: boom\n"))
+	testutil.AssertEqual(t, LevelInfo, LineLevel("Created new database connection for 'prod'\n"))
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("WARN")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, LevelWarn, level)
+
+	_, err = ParseLevel("verbose")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "unknown log level 'verbose'")
+}
+
+func TestLevelWriterDropsLowerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(NewLevelWriter(&buf, LevelWarn), "", log.LstdFlags)
+
+	logger.Printf("DEBUG query [prod] SELECT 1")
+	logger.Printf("Created new database connection for 'prod'")
+	logger.Printf("Warning: connection_pool.ping_interval is too long")
+	logger.Printf("Failed to create server: boom")
+
+	output := buf.String()
+	testutil.AssertEqual(t, false, bytes.Contains(buf.Bytes(), []byte("DEBUG")))
+	testutil.AssertEqual(t, false, bytes.Contains(buf.Bytes(), []byte("Created new")))
+	testutil.AssertContains(t, output, "Warning: connection_pool.ping_interval")
+	testutil.AssertContains(t, output, "Failed to create server")
+}
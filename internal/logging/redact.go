@@ -0,0 +1,147 @@
+// Package logging holds helpers for shaping the server's log output.
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+)
+
+// minRedactLength skips values so short that even as whole words they are
+// more likely ordinary log text than a connection detail
+const minRedactLength = 3
+
+// Redactor masks connection hosts, sockets and usernames wherever they appear
+// in text as whole tokens. A value only matches where it isn't run into
+// neighbouring letters, digits or underscores, so a username such as "app"
+// is masked in "user app" but "application" is left alone.
+type Redactor struct {
+	values []string
+	masks  map[string]string
+}
+
+// NewRedactor builds a Redactor for the hosts, read replica hosts, socket
+// paths, proxies and usernames of the given connections
+func NewRedactor(connections map[string]config.Connection) *Redactor {
+	seen := make(map[string]bool)
+	var values []string
+	add := func(value string) {
+		if len(value) >= minRedactLength && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+
+	for _, conn := range connections {
+		add(conn.Host)
+		add(conn.ReadReplicaHost)
+		add(conn.Socket)
+		add(conn.Username)
+		// Salesforce hosts are instance URLs; mask the bare hostname too
+		if parsed, err := url.Parse(conn.Host); err == nil && parsed.Hostname() != "" {
+			add(parsed.Hostname())
+		}
+		// Proxy URLs can carry credentials; mask the whole URL and its parts
+		if parsed, err := url.Parse(conn.Proxy); err == nil && parsed.Host != "" {
+			add(conn.Proxy)
+			add(parsed.Host)
+			add(parsed.Hostname())
+			if parsed.User != nil {
+				add(parsed.User.Username())
+				password, _ := parsed.User.Password()
+				add(password)
+			}
+		}
+	}
+
+	// Longest first so a hostname is masked whole rather than by a shorter value inside it
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+
+	masks := make(map[string]string, len(values))
+	for _, value := range values {
+		masks[value] = Mask(value)
+	}
+	return &Redactor{values: values, masks: masks}
+}
+
+// Redact returns text with every known host and username masked
+func (r *Redactor) Redact(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		value := r.tokenAt(text, i)
+		if value == "" {
+			b.WriteByte(text[i])
+			i++
+			continue
+		}
+		b.WriteString(r.masks[value])
+		i += len(value)
+	}
+	return b.String()
+}
+
+// tokenAt returns the longest value that starts at text[i] as a whole token,
+// or "" if none does
+func (r *Redactor) tokenAt(text string, i int) string {
+	for _, value := range r.values {
+		if !strings.HasPrefix(text[i:], value) {
+			continue
+		}
+		end := i + len(value)
+		if isWordByte(value[0]) && i > 0 && isWordByte(text[i-1]) {
+			continue
+		}
+		if isWordByte(value[len(value)-1]) && end < len(text) && isWordByte(text[end]) {
+			continue
+		}
+		return value
+	}
+	return ""
+}
+
+// isWordByte reports whether c can be part of a word. Bytes of multi-byte
+// UTF-8 characters count, so a value is never matched inside a non-ASCII word.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 ||
+		('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// Mask keeps the first two characters of a value and replaces the rest with a
+// short hash, so masked values stay distinguishable without being readable.
+func Mask(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	hash := hex.EncodeToString(sum[:])[:6]
+	if len(value) <= 4 {
+		return "***#" + hash
+	}
+	return value[:2] + "***#" + hash
+}
+
+// redactingWriter applies a Redactor to everything written through it
+type redactingWriter struct {
+	out      io.Writer
+	redactor *Redactor
+}
+
+// NewRedactingWriter wraps out so that log lines are redacted before being written.
+// The standard logger issues one Write per line, so values are never split.
+func NewRedactingWriter(out io.Writer, redactor *Redactor) io.Writer {
+	return &redactingWriter{out: out, redactor: redactor}
+}
+
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, w.redactor.Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
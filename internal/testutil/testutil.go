@@ -1,11 +1,18 @@
 package testutil
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/eliziario/simpledb-mcp/internal/credentials"
 )
@@ -23,16 +30,31 @@ func TempDir(t *testing.T) string {
 	return dir
 }
 
+// WriteConfigFile points HOME at a temp dir and writes content as the
+// simpledb-mcp config file there, returning its path
+func WriteConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	home := TempDir(t)
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "simpledb-mcp")
+	AssertNoError(t, os.MkdirAll(dir, 0755))
+	path := filepath.Join(dir, "config.yaml")
+	AssertNoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
 // MockCredentialManager is a mock implementation of credentials.Manager for testing
 type MockCredentialManager struct {
-	credentials map[string]string
-	errors      map[string]error
+	credentials   map[string]string
+	sfCredentials map[string]*credentials.SalesforceCredential
+	errors        map[string]error
 }
 
 func NewMockCredentialManager() *MockCredentialManager {
 	return &MockCredentialManager{
-		credentials: make(map[string]string),
-		errors:      make(map[string]error),
+		credentials:   make(map[string]string),
+		sfCredentials: make(map[string]*credentials.SalesforceCredential),
+		errors:        make(map[string]error),
 	}
 }
 
@@ -77,6 +99,30 @@ func (m *MockCredentialManager) TestConnection(connectionName, username string)
 	return err
 }
 
+func (m *MockCredentialManager) StoreSalesforce(connectionName, username, password, securityToken string) error {
+	key := fmt.Sprintf("%s:salesforce", connectionName)
+	if err, exists := m.errors[key]; exists {
+		return err
+	}
+	m.sfCredentials[key] = &credentials.SalesforceCredential{
+		Username:      username,
+		Password:      password,
+		SecurityToken: securityToken,
+	}
+	return nil
+}
+
+func (m *MockCredentialManager) GetSalesforce(connectionName string) (*credentials.SalesforceCredential, error) {
+	key := fmt.Sprintf("%s:salesforce", connectionName)
+	if err, exists := m.errors[key]; exists {
+		return nil, err
+	}
+	if cred, exists := m.sfCredentials[key]; exists {
+		return cred, nil
+	}
+	return nil, fmt.Errorf("credential not found")
+}
+
 // SetError sets an error to be returned for a specific credential key
 func (m *MockCredentialManager) SetError(connectionName, username string, err error) {
 	key := fmt.Sprintf("%s:%s", connectionName, username)
@@ -92,9 +138,11 @@ func (m *MockCredentialManager) SetCredential(connectionName, username, password
 
 // MockDriver is a mock SQL driver for testing
 type MockDriver struct {
+	mutex       sync.Mutex
 	connections map[string]*MockDB
 	shouldFail  bool
 	failError   error
+	pingDelay   time.Duration
 }
 
 type MockDB struct {
@@ -103,9 +151,12 @@ type MockDB struct {
 	closed     bool
 	pingFails  bool
 	pingError  error
+	pingDelay  time.Duration
+	pings      int64
 	queryFails bool
 	queryError error
 	results    map[string]*MockRows
+	readOnlyTx int64
 }
 
 type MockRows struct {
@@ -122,28 +173,69 @@ func NewMockDriver() *MockDriver {
 }
 
 func (d *MockDriver) Open(dsn string) (driver.Conn, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	
 	if d.shouldFail {
 		return nil, d.failError
 	}
 	
 	db := &MockDB{
-		driver:  d,
-		dsn:     dsn,
-		results: make(map[string]*MockRows),
+		driver:    d,
+		dsn:       dsn,
+		pingDelay: d.pingDelay,
+		results:   make(map[string]*MockRows),
 	}
 	d.connections[dsn] = db
 	return db, nil
 }
 
 func (d *MockDriver) SetShouldFail(fail bool, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 	d.shouldFail = fail
 	d.failError = err
 }
 
+// SetPingDelay makes pings on connections opened afterwards take this long,
+// simulating a slow or unreachable host. Pings still honor context cancellation.
+func (d *MockDriver) SetPingDelay(delay time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.pingDelay = delay
+}
+
 func (d *MockDriver) GetConnection(dsn string) *MockDB {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 	return d.connections[dsn]
 }
 
+var mockDriverCount int64
+
+// RegisterMockDriver registers the driver under a unique name and returns it,
+// for code that opens its own handles with sql.Open.
+func RegisterMockDriver(d *MockDriver) string {
+	driverName := fmt.Sprintf("simpledb-mock-%d", atomic.AddInt64(&mockDriverCount, 1))
+	sql.Register(driverName, d)
+	return driverName
+}
+
+// OpenMockDB registers the driver under a unique name and opens a *sql.DB on it.
+// The pool is limited to a single connection so tests can reach it via GetConnection(dsn).
+func OpenMockDB(t *testing.T, d *MockDriver, dsn string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open(RegisterMockDriver(d), dsn)
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db
+}
+
 // MockDB methods
 func (db *MockDB) Prepare(query string) (driver.Stmt, error) {
 	return &MockStmt{db: db, query: query}, nil
@@ -158,13 +250,44 @@ func (db *MockDB) Begin() (driver.Tx, error) {
 	return &MockTx{}, nil
 }
 
-func (db *MockDB) Ping() error {
+// BeginTx starts a transaction, counting the read-only ones
+func (db *MockDB) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.ReadOnly {
+		atomic.AddInt64(&db.readOnlyTx, 1)
+	}
+	return &MockTx{}, nil
+}
+
+// ReadOnlyTxCount returns how many read-only transactions were started
+func (db *MockDB) ReadOnlyTxCount() int {
+	return int(atomic.LoadInt64(&db.readOnlyTx))
+}
+
+func (db *MockDB) Ping(ctx context.Context) error {
+	atomic.AddInt64(&db.pings, 1)
+	if db.pingDelay > 0 {
+		select {
+		case <-time.After(db.pingDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 	if db.pingFails {
 		return db.pingError
 	}
 	return nil
 }
 
+// PingCount returns how many times the connection has been pinged
+func (db *MockDB) PingCount() int {
+	return int(atomic.LoadInt64(&db.pings))
+}
+
+// SetPingDelay makes later pings on this connection take this long
+func (db *MockDB) SetPingDelay(delay time.Duration) {
+	db.pingDelay = delay
+}
+
 func (db *MockDB) SetPingFails(fails bool, err error) {
 	db.pingFails = fails
 	db.pingError = err
@@ -178,6 +301,20 @@ func (db *MockDB) SetQueryResult(query string, columns []string, data [][]interf
 	}
 }
 
+// SetQueryResultForArgs returns a result only when the query runs with these
+// arguments, for queries that run once per table; it takes precedence over SetQueryResult
+func (db *MockDB) SetQueryResultForArgs(query string, args []interface{}, columns []string, data [][]interface{}) {
+	db.results[mockQueryKey(query, args)] = &MockRows{
+		columns: columns,
+		data:    data,
+		index:   0,
+	}
+}
+
+func mockQueryKey(query string, args []interface{}) string {
+	return fmt.Sprintf("%s\x00%v", query, args)
+}
+
 func (db *MockDB) SetQueryFails(fails bool, err error) {
 	db.queryFails = fails
 	db.queryError = err
@@ -193,8 +330,10 @@ func (stmt *MockStmt) Close() error {
 	return nil
 }
 
+// NumInput returns -1 so queries with placeholder arguments are accepted;
+// results are still looked up by the exact query text.
 func (stmt *MockStmt) NumInput() int {
-	return 0
+	return -1
 }
 
 func (stmt *MockStmt) Exec(args []driver.Value) (driver.Result, error) {
@@ -209,6 +348,18 @@ func (stmt *MockStmt) Query(args []driver.Value) (driver.Rows, error) {
 		return nil, stmt.db.queryError
 	}
 	
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg
+	}
+	if rows, exists := stmt.db.results[mockQueryKey(stmt.query, values)]; exists {
+		return &MockRows{
+			columns: rows.columns,
+			data:    rows.data,
+			index:   0,
+		}, nil
+	}
+	
 	if rows, exists := stmt.db.results[stmt.query]; exists {
 		return &MockRows{
 			columns: rows.columns,
@@ -237,7 +388,7 @@ func (r *MockRows) Close() error {
 
 func (r *MockRows) Next(dest []driver.Value) error {
 	if r.index >= len(r.data) {
-		return sql.ErrNoRows
+		return io.EOF
 	}
 	
 	row := r.data[r.index]
@@ -294,14 +445,22 @@ func AssertError(t *testing.T, err error) {
 	}
 }
 
-// AssertEqual checks if two values are equal
+// AssertEqual checks if two values are equal. Values of types that can't be
+// compared with == (such as structs holding maps) are compared deeply.
 func AssertEqual(t *testing.T, expected, actual interface{}) {
 	t.Helper()
-	if expected != actual {
+	if !equal(expected, actual) {
 		t.Errorf("Expected %v, got %v", expected, actual)
 	}
 }
 
+func equal(expected, actual interface{}) bool {
+	if expected == nil || actual == nil || reflect.TypeOf(expected).Comparable() && reflect.TypeOf(actual).Comparable() {
+		return expected == actual
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
 // AssertContains checks if a string contains a substring
 func AssertContains(t *testing.T, str, substr string) {
 	t.Helper()
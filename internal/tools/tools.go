@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -29,9 +30,10 @@ type ListSchemasArgs struct {
 }
 
 type ListTablesArgs struct {
-	Connection string `json:"connection" jsonschema:"required,description=Name of the database connection"`
-	Database   string `json:"database" jsonschema:"required,description=Name of the database"`
-	Schema     string `json:"schema,omitempty" jsonschema:"description=Name of the schema (PostgreSQL only, optional)"`
+	Connection    string `json:"connection" jsonschema:"required,description=Name of the database connection"`
+	Database      string `json:"database" jsonschema:"required,description=Name of the database"`
+	Schema        string `json:"schema,omitempty" jsonschema:"description=Name of the schema (PostgreSQL only, optional)"`
+	IncludeCounts bool `json:"include_counts,omitempty" jsonschema:"description=Salesforce only: fetch row counts with one COUNT() query per object (slow)"`
 }
 
 type DescribeTableArgs struct {
@@ -54,6 +56,7 @@ type GetTableSampleArgs struct {
 	Table      string `json:"table" jsonschema:"required,description=Name of the table"`
 	Schema     string `json:"schema,omitempty" jsonschema:"description=Name of the schema (PostgreSQL only, optional)"`
 	Limit      int    `json:"limit,omitempty" jsonschema:"minimum=1,maximum=100,description=Number of rows to sample (default: 10, max: 100)"`
+	ResultShape string `json:"result_shape,omitempty" jsonschema:"enum=objects,enum=columnar,enum=markdown_table,description=How rows are rendered (default: objects)"`
 }
 
 type GetConnectionStatusArgs struct {
@@ -89,7 +92,7 @@ func (h *Handler) registerTools() error {
 		return err
 	}
 	
-	if err := h.server.RegisterTool("list_databases", "List databases available on a connection", h.listDatabases); err != nil {
+	if err := h.server.RegisterTool("list_databases", "List databases available on a connection; a Salesforce org is listed as one database named after its connection", h.listDatabases); err != nil {
 		return err
 	}
 	
@@ -245,6 +248,9 @@ func (h *Handler) listTables(args ListTablesArgs) (*mcp_golang.ToolResponse, err
        tables, err = h.dbManager.ListTablesPostgres(args.Connection, args.Database, args.Schema)
    case "salesforce":
        tables, err = h.dbManager.ListTablesSalesforce(args.Connection)
+       if err == nil && args.IncludeCounts {
+           h.dbManager.AddRowCountsSalesforce(args.Connection, tables)
+       }
    case "glue":
        tables, err = h.dbManager.ListTablesGlue(args.Connection, args.Database, args.Schema)
    default:
@@ -333,6 +339,9 @@ func (h *Handler) getTableSample(args GetTableSampleArgs) (*mcp_golang.ToolRespo
 	if !exists {
 		return nil, fmt.Errorf("connection '%s' not found", args.Connection)
 	}
+	if err := conn.CheckDataAccess(args.Connection); err != nil {
+		return nil, err
+	}
 
 	limit := args.Limit
 	if limit == 0 {
@@ -340,11 +349,8 @@ func (h *Handler) getTableSample(args GetTableSampleArgs) (*mcp_golang.ToolRespo
 	}
 
 	// Enforce max limit
-	if limit > int(h.config.Settings.MaxRows) {
-		limit = int(h.config.Settings.MaxRows)
-	}
-	if limit > 100 {
-		limit = 100
+	if sampleLimit := h.config.Settings.SampleRowLimit(conn); limit > sampleLimit {
+		limit = sampleLimit
 	}
 
 	var sample map[string]interface{}
@@ -352,13 +358,13 @@ func (h *Handler) getTableSample(args GetTableSampleArgs) (*mcp_golang.ToolRespo
 
    switch conn.Type {
    case "mysql":
-       sample, err = h.dbManager.GetTableSampleMySQL(args.Connection, args.Database, args.Table, limit)
+       sample, err = h.dbManager.GetTableSampleMySQL(context.Background(), args.Connection, args.Database, args.Table, limit)
    case "postgres":
-       sample, err = h.dbManager.GetTableSamplePostgres(args.Connection, args.Database, args.Table, args.Schema, limit)
+       sample, err = h.dbManager.GetTableSamplePostgres(context.Background(), args.Connection, args.Database, args.Table, args.Schema, limit)
    case "salesforce":
-       sample, err = h.dbManager.GetTableSampleSalesforce(args.Connection, args.Table, limit)
+       sample, err = h.dbManager.GetTableSampleSalesforce(context.Background(), args.Connection, args.Table, limit)
    case "glue":
-       sample, err = h.dbManager.GetTableSampleGlue(args.Connection, args.Database, args.Table, limit)
+       sample, err = h.dbManager.GetTableSampleGlue(context.Background(), args.Connection, args.Database, args.Table, limit)
    default:
        return nil, fmt.Errorf("unsupported database type: %s", conn.Type)
    }
@@ -367,13 +373,16 @@ func (h *Handler) getTableSample(args GetTableSampleArgs) (*mcp_golang.ToolRespo
 		return nil, err
 	}
 
-	// Return array of dictionaries for better readability
-	rows, ok := sample["rows"].([]map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid sample data format: expected rows array")
+	// Array of dictionaries by default for better readability
+	shaped, err := database.ShapeSample(sample, args.ResultShape)
+	if err != nil {
+		return nil, err
+	}
+	if table, ok := shaped.(string); ok {
+		return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(table)), nil
 	}
 	
-	content, err := newJSONContent(rows)
+	content, err := newJSONContent(shaped)
 	if err != nil {
 		return nil, err
 	}
@@ -384,9 +393,19 @@ func (h *Handler) getConnectionStatus(args GetConnectionStatusArgs) (*mcp_golang
 	if args.Connection != "" {
 		// Get status for specific connection
 		status := h.dbManager.GetConnectionStatus(args.Connection)
+		statusText := fmt.Sprintf("Connection '%s': %s (last used: %s, idle: %s, pings: %d ok / %d failed, queries: %d, rows: %d, query time: %s)", 
+			status.Name, status.State, status.LastUsed.Format("15:04:05"), status.IdleTime.Truncate(time.Second),
+			status.SuccessfulPings, status.FailedPings, status.QueriesRun, status.RowsReturned, status.TotalQueryTime)
+		if status.LastQueryAt.IsZero() {
+			statusText += "\n  Last query: never"
+		} else {
+			statusText += fmt.Sprintf("\n  Last query: %s", status.LastQueryAt.Format("15:04:05"))
+		}
+		if status.LastError != "" {
+			statusText += fmt.Sprintf("\n  Last error (%s, %s): %s", status.LastErrorAt.Format("15:04:05"), status.LastErrorKind, status.LastError)
+		}
 		return mcp_golang.NewToolResponse(
-			mcp_golang.NewTextContent(fmt.Sprintf("Connection '%s': %s (last used: %s, idle: %s)", 
-				status.Name, status.State, status.LastUsed.Format("15:04:05"), status.IdleTime.Truncate(time.Second))),
+			mcp_golang.NewTextContent(statusText),
 		), nil
 	} else {
 		// Get status for all connections
@@ -399,8 +418,12 @@ func (h *Handler) getConnectionStatus(args GetConnectionStatusArgs) (*mcp_golang
 		
 		statusText := fmt.Sprintf("Connection pool status (%d connections):\n", len(statuses))
 		for _, status := range statuses {
-			statusText += fmt.Sprintf("  • %s: %s (idle: %s, errors: %d)\n", 
-				status.Name, status.State, status.IdleTime.Truncate(time.Second), status.ErrorCount)
+			statusText += fmt.Sprintf("  • %s: %s (idle: %s, errors: %d, pings: %d ok / %d failed, queries: %d, rows: %d)\n", 
+				status.Name, status.State, status.IdleTime.Truncate(time.Second), status.ErrorCount,
+				status.SuccessfulPings, status.FailedPings, status.QueriesRun, status.RowsReturned)
+			if status.LastError != "" {
+				statusText += fmt.Sprintf("    last error (%s, %s): %s\n", status.LastErrorAt.Format("15:04:05"), status.LastErrorKind, status.LastError)
+			}
 		}
 		
 		return mcp_golang.NewToolResponse(
@@ -421,7 +444,10 @@ func (h *Handler) getPoolMetrics(args GetPoolMetricsArgs) (*mcp_golang.ToolRespo
   Failed pings: %d
   Ping interval: %s
   Max idle time: %s
-  Success rate: %.1f%%`,
+  Success rate: %.1f%%
+  Queries run: %d
+  Rows returned: %d
+  Total query time: %s`,
 		metrics.TotalConnections,
 		metrics.ActiveConnections,
 		metrics.ConnectedCount,
@@ -431,6 +457,9 @@ func (h *Handler) getPoolMetrics(args GetPoolMetricsArgs) (*mcp_golang.ToolRespo
 		metrics.PingInterval,
 		metrics.MaxIdleTime,
 		float64(metrics.SuccessfulPings) / float64(metrics.SuccessfulPings + metrics.FailedPings) * 100,
+		metrics.TotalQueries,
+		metrics.TotalRowsReturned,
+		metrics.TotalQueryTime,
 	)
 	
 	return mcp_golang.NewToolResponse(
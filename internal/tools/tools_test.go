@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -73,7 +74,8 @@ func TestNewHandler(t *testing.T) {
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
 	
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	if handler == nil {
 		t.Error("Expected non-nil handler")
@@ -163,7 +165,8 @@ func TestListConnections(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	args := ListConnectionsArgs{}
 	response, err := handler.listConnections(args)
@@ -182,11 +185,12 @@ func TestListDatabases(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	// Test with valid connection name
 	args := ListDatabasesArgs{Connection: "test-mysql"}
-	_, err := handler.listDatabases(args)
+	_, err = handler.listDatabases(args)
 	// Expected to fail since no real database, but should not panic
 	testutil.AssertError(t, err)
 	
@@ -197,6 +201,27 @@ func TestListDatabases(t *testing.T) {
 	testutil.AssertContains(t, err.Error(), "not found")
 }
 
+func TestListDatabasesSalesforce(t *testing.T) {
+	cfg := testConfig()
+	cfg.Connections["crm"] = config.Connection{Type: "salesforce", Host: "https://example.my.salesforce.com"}
+	dbManager := database.NewManager(cfg, testutil.NewMockCredentialManager())
+	defer dbManager.Close()
+
+	handler, err := NewHandler(dbManager, cfg, mcp_golang.NewServer(stdio.NewStdioServerTransport()))
+	testutil.AssertNoError(t, err)
+
+	response, err := handler.listDatabases(ListDatabasesArgs{Connection: "crm"})
+	testutil.AssertNoError(t, err)
+	var databases []string
+	testutil.AssertNoError(t, json.Unmarshal([]byte(response.Content[0].TextContent.Text), &databases))
+
+	// The org is one database named after the connection, as pkg/api lists it
+	expected, err := dbManager.ListDatabasesSalesforce("crm")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, expected, databases)
+	testutil.AssertEqual(t, []string{"crm"}, databases)
+}
+
 func TestListSchemas(t *testing.T) {
 	cfg := testConfig()
 	credManager := testutil.NewMockCredentialManager()
@@ -205,14 +230,15 @@ func TestListSchemas(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	// Test with PostgreSQL connection
 	args := ListSchemasArgs{
 		Connection: "test-postgres",
 		Database:   "testdb",
 	}
-	_, err := handler.listSchemas(args)
+	_, err = handler.listSchemas(args)
 	// Expected to fail since no real database
 	testutil.AssertError(t, err)
 	
@@ -243,14 +269,15 @@ func TestListTables(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	// Test with MySQL
 	args := ListTablesArgs{
 		Connection: "test-mysql",
 		Database:   "testdb",
 	}
-	_, err := handler.listTables(args)
+	_, err = handler.listTables(args)
 	testutil.AssertError(t, err) // Expected - no real DB
 	
 	// Test with PostgreSQL
@@ -280,14 +307,15 @@ func TestDescribeTable(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	args := DescribeTableArgs{
 		Connection: "test-mysql",
 		Database:   "testdb",
 		Table:      "users",
 	}
-	_, err := handler.describeTable(args)
+	_, err = handler.describeTable(args)
 	testutil.AssertError(t, err) // Expected - no real DB
 	
 	// Test with invalid connection
@@ -309,14 +337,15 @@ func TestListIndexes(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	args := ListIndexesArgs{
 		Connection: "test-mysql",
 		Database:   "testdb",
 		Table:      "users",
 	}
-	_, err := handler.listIndexes(args)
+	_, err = handler.listIndexes(args)
 	testutil.AssertError(t, err) // Expected - no real DB
 	
 	// Test with invalid connection
@@ -338,7 +367,8 @@ func TestGetTableSample(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	// Test with default limit
 	args := GetTableSampleArgs{
@@ -347,7 +377,7 @@ func TestGetTableSample(t *testing.T) {
 		Table:      "users",
 		Limit:      0, // Should use default of 10
 	}
-	_, err := handler.getTableSample(args)
+	_, err = handler.getTableSample(args)
 	testutil.AssertError(t, err) // Expected - no real DB
 	
 	// Test with custom limit
@@ -390,6 +420,20 @@ func TestGetTableSample(t *testing.T) {
 	_, err = handler.getTableSample(args)
 	testutil.AssertError(t, err)
 	testutil.AssertContains(t, err.Error(), "not found")
+	
+	// Connections with sampling disabled refuse before touching the database
+	allow := false
+	conn := cfg.Connections["test-mysql"]
+	conn.AllowSampling = &allow
+	cfg.Connections["test-mysql"] = conn
+	args = GetTableSampleArgs{
+		Connection: "test-mysql",
+		Database:   "testdb",
+		Table:      "users",
+	}
+	_, err = handler.getTableSample(args)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "data access disabled for this connection")
 }
 
 func TestGetConnectionStatus(t *testing.T) {
@@ -400,7 +444,8 @@ func TestGetConnectionStatus(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	// Test specific connection
 	args := GetConnectionStatusArgs{Connection: "test-mysql"}
@@ -427,7 +472,8 @@ func TestGetPoolMetrics(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	args := GetPoolMetricsArgs{}
 	response, err := handler.getPoolMetrics(args)
@@ -447,7 +493,8 @@ func TestToolRegistration(t *testing.T) {
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
 	
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	// Test that handler was created successfully
 	if handler == nil {
@@ -475,11 +522,12 @@ func TestUnsupportedDatabaseType(t *testing.T) {
 	
 	transport := stdio.NewStdioServerTransport()
 	mcpServer := mcp_golang.NewServer(transport)
-	handler := NewHandler(dbManager, cfg, mcpServer)
+	handler, err := NewHandler(dbManager, cfg, mcpServer)
+	testutil.AssertNoError(t, err)
 	
 	// Test with unsupported database type
 	args := ListDatabasesArgs{Connection: "unsupported"}
-	_, err := handler.listDatabases(args)
+	_, err = handler.listDatabases(args)
 	testutil.AssertError(t, err)
 	testutil.AssertContains(t, err.Error(), "unsupported database type")
 }
\ No newline at end of file
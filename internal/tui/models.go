@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -37,8 +38,10 @@ type Model struct {
 	menuOptions []string
 
 	// Connections
-	connections      []string
+	connections      []string // visible connections, narrowed by connectionFilter
 	connectionCursor int
+	connectionFilter string
+	filtering        bool // typing into the filter
 
 	// Forms
 	formInputs   []string
@@ -46,6 +49,12 @@ type Model struct {
 	formLabels   []string
 	tempConn     config.Connection
 	tempConnName string
+	formTemplate string // cloud template applied on save, chosen with Ctrl+T
+
+	// Settings
+	settingsCursor  int
+	settingsEditing bool
+	settingsInput   string
 
 	// Messages
 	message     string
@@ -154,8 +163,22 @@ func (m Model) handleMenuKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleConnectionsKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.filtering {
+		return m.handleFilterKeys(key)
+	}
+
 	switch key.String() {
-	case "q", "esc":
+	case "/":
+		m.filtering = true
+		m.clearMessage()
+	case "esc":
+		if m.connectionFilter != "" {
+			m.setConnectionFilter("")
+			return m, nil
+		}
+		m.state = StateMenu
+		m.clearMessage()
+	case "q":
 		m.state = StateMenu
 		m.clearMessage()
 	case "up", "k":
@@ -176,6 +199,11 @@ func (m Model) handleConnectionsKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.loadConnectionForm(m.connections[m.connectionCursor])
 			m.clearMessage()
 		}
+	case "c":
+		if len(m.connections) > 0 {
+			m.state = StateAddConnection
+			m.loadCopyForm(m.connections[m.connectionCursor])
+		}
 	case "d":
 		if len(m.connections) > 0 {
 			connName := m.connections[m.connectionCursor]
@@ -191,6 +219,47 @@ func (m Model) handleConnectionsKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 			connName := m.connections[m.connectionCursor]
 			m.testConnection(connName)
 		}
+	case "x":
+		if len(m.connections) > 0 {
+			connName := m.connections[m.connectionCursor]
+			enable := !m.config.Connections[connName].IsEnabled()
+			if err := m.config.SetConnectionEnabled(connName, enable); err != nil {
+				m.setErrorMessage(fmt.Sprintf("Failed to update connection: %v", err))
+			} else if enable {
+				m.setSuccessMessage(fmt.Sprintf("Connection '%s' enabled", connName))
+			} else {
+				m.setSuccessMessage(fmt.Sprintf("Connection '%s' disabled; tools refuse it until it is enabled again", connName))
+			}
+		}
+	}
+	return m, nil
+}
+
+// handleFilterKeys edits the connections filter, narrowing the list as the user types
+func (m Model) handleFilterKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.String() {
+	case "esc":
+		m.filtering = false
+		m.setConnectionFilter("")
+	case "enter":
+		m.filtering = false
+	case "up", "down":
+		m.filtering = false
+		return m.handleConnectionsKeys(key)
+	case "backspace":
+		if len(m.connectionFilter) > 0 {
+			m.setConnectionFilter(m.connectionFilter[:len(m.connectionFilter)-1])
+		}
+	case "ctrl+u":
+		m.setConnectionFilter("")
+	default:
+		filter := m.connectionFilter
+		for _, char := range key.String() {
+			if char >= 32 && char <= 126 {
+				filter += string(char)
+			}
+		}
+		m.setConnectionFilter(filter)
 	}
 	return m, nil
 }
@@ -216,6 +285,10 @@ func (m Model) handleFormKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "ctrl+u": // Clear current field
 		m.formInputs[m.formCursor] = ""
+	case "ctrl+d": // Detect the type from host and port
+		m.detectConnectionType()
+	case "ctrl+t": // Cycle through the cloud connection templates
+		m.nextTemplate()
 	case "ctrl+v": // This won't actually trigger, but we handle pasted content below
 		// Paste is handled in the default case
 	default:
@@ -245,9 +318,65 @@ func (m Model) handleFormKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleSettingsKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.settingsEditing {
+		return m.handleSettingsEditKeys(key)
+	}
+
 	switch key.String() {
 	case "q", "esc":
 		m.state = StateMenu
+		m.clearMessage()
+	case "up", "k":
+		m.settingsCursor = (m.settingsCursor - 1 + len(settingFields)) % len(settingFields)
+	case "down", "j":
+		m.settingsCursor = (m.settingsCursor + 1) % len(settingFields)
+	case "enter":
+		field := settingFields[m.settingsCursor]
+		current := field.get(&m.config.Settings)
+		if field.toggle {
+			next := "true"
+			if current == "true" {
+				next = "false"
+			}
+			if err := m.applySetting(next); err != nil {
+				m.setErrorMessage(err.Error())
+			} else {
+				m.setSuccessMessage(fmt.Sprintf("%s set to %s", field.label, next))
+			}
+			return m, nil
+		}
+		m.settingsEditing = true
+		m.settingsInput = current
+		m.clearMessage()
+	}
+	return m, nil
+}
+
+func (m Model) handleSettingsEditKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.String() {
+	case "esc":
+		m.settingsEditing = false
+		m.clearMessage()
+	case "enter":
+		field := settingFields[m.settingsCursor]
+		if err := m.applySetting(m.settingsInput); err != nil {
+			m.setErrorMessage(err.Error())
+			return m, nil
+		}
+		m.settingsEditing = false
+		m.setSuccessMessage(fmt.Sprintf("%s set to %s", field.label, field.get(&m.config.Settings)))
+	case "backspace":
+		if len(m.settingsInput) > 0 {
+			m.settingsInput = m.settingsInput[:len(m.settingsInput)-1]
+		}
+	case "ctrl+u":
+		m.settingsInput = ""
+	default:
+		for _, char := range key.String() {
+			if char >= 32 && char <= 126 {
+				m.settingsInput += string(char)
+			}
+		}
 	}
 	return m, nil
 }
@@ -276,8 +405,48 @@ func (m Model) handleServiceKeys(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // Helper methods
 func (m *Model) loadConnections() {
-	m.connections = m.config.ListConnections()
 	m.connectionCursor = 0
+	m.filterConnections()
+}
+
+// setConnectionFilter changes the filter, keeping the selected connection
+// selected if it is still visible
+func (m *Model) setConnectionFilter(filter string) {
+	selected := ""
+	if m.connectionCursor < len(m.connections) {
+		selected = m.connections[m.connectionCursor]
+	}
+	m.connectionFilter = filter
+	m.filterConnections()
+
+	m.connectionCursor = 0
+	for i, name := range m.connections {
+		if name == selected {
+			m.connectionCursor = i
+		}
+	}
+}
+
+// filterConnections lists the connections whose name, type or host contains
+// the filter (case-insensitive) and keeps the cursor within that list
+func (m *Model) filterConnections() {
+	filter := strings.ToLower(m.connectionFilter)
+	var visible []string
+	for _, name := range m.config.ListConnections() {
+		conn, _ := m.config.GetConnection(name)
+		if filter == "" ||
+			strings.Contains(strings.ToLower(name), filter) ||
+			strings.Contains(strings.ToLower(conn.Type), filter) ||
+			strings.Contains(strings.ToLower(conn.Host), filter) {
+			visible = append(visible, name)
+		}
+	}
+	sort.Strings(visible)
+	m.connections = visible
+
+	if m.connectionCursor >= len(m.connections) {
+		m.connectionCursor = max(len(m.connections)-1, 0)
+	}
 }
 
 func (m *Model) clearForm() {
@@ -287,6 +456,7 @@ func (m *Model) clearForm() {
 	m.formCursor = 0
 	m.tempConnName = ""
 	m.tempConn = config.Connection{}
+	m.formTemplate = ""
 }
 
 func (m *Model) loadConnectionForm(connName string) {
@@ -306,6 +476,29 @@ func (m *Model) loadConnectionForm(connName string) {
 	m.formCursor = 0
 }
 
+// loadCopyForm prefills the add form from an existing connection under a
+// "-copy" name. Passwords live in the keychain and aren't copied, so the
+// cursor starts on the password field.
+func (m *Model) loadCopyForm(connName string) {
+	m.clearForm()
+	m.loadConnectionForm(connName)
+	m.tempConnName = ""
+	m.formInputs[0] = m.copyName(connName)
+	m.formCursor = len(m.formInputs) - 1
+	m.setWarningMessage(fmt.Sprintf("Copied '%s'; passwords aren't copied, enter one for the new connection", connName))
+}
+
+// copyName returns connName with a "-copy" suffix that no connection uses yet
+func (m *Model) copyName(connName string) string {
+	name := connName + "-copy"
+	for i := 2; ; i++ {
+		if _, exists := m.config.GetConnection(name); !exists {
+			return name
+		}
+		name = fmt.Sprintf("%s-copy%d", connName, i)
+	}
+}
+
 func (m *Model) saveConnection() {
 	// Validate and create connection
 	connName := strings.TrimSpace(m.formInputs[0])
@@ -313,15 +506,21 @@ func (m *Model) saveConnection() {
 		m.setErrorMessage("Connection name is required")
 		return
 	}
-
-	// For now, default to MySQL. In full implementation, add type selection
-	conn := config.Connection{
-		Type:     "mysql",
-		Host:     strings.TrimSpace(m.formInputs[1]),
-		Database: strings.TrimSpace(m.formInputs[3]),
-		Username: strings.TrimSpace(m.formInputs[4]),
+	if _, exists := m.config.GetConnection(connName); !exists {
+		if err := m.config.ValidateConnectionName(connName); err != nil {
+			m.setErrorMessage(err.Error())
+			return
+		}
 	}
 
+	// Start from the loaded connection so an edit or copy keeps the settings
+	// the form doesn't show. New connections default to MySQL for now; in full
+	// implementation, add type selection
+	conn := m.tempConn
+	conn.Host = strings.TrimSpace(m.formInputs[1])
+	conn.Database = strings.TrimSpace(m.formInputs[3])
+	conn.Username = strings.TrimSpace(m.formInputs[4])
+
 	// Parse port
 	if portStr := strings.TrimSpace(m.formInputs[2]); portStr != "" {
 		var port int
@@ -330,7 +529,20 @@ func (m *Model) saveConnection() {
 			return
 		}
 		conn.Port = port
-	} else {
+	}
+
+	// Template values fill whatever the form left unset
+	if m.formTemplate != "" {
+		var err error
+		if conn, err = config.ApplyTemplate(m.formTemplate, conn); err != nil {
+			m.setErrorMessage(err.Error())
+			return
+		}
+	}
+	if conn.Type == "" {
+		conn.Type = "mysql"
+	}
+	if conn.Port == 0 {
 		conn.Port = 3306 // Default MySQL port
 	}
 
@@ -342,7 +554,7 @@ func (m *Model) saveConnection() {
 
 	// Store password in keychain if provided
 	if password := strings.TrimSpace(m.formInputs[5]); password != "" {
-		credManager := credentials.NewManager(m.config.Settings.CacheCredentials)
+		credManager := credentials.NewProvider(m.config.Settings.CacheCredentials, m.config.Settings.CredentialFallbackFile())
 		if err := credManager.Store(connName, conn.Username, password); err != nil {
 			m.setErrorMessage(fmt.Sprintf("Failed to store credentials: %v", err))
 			return
@@ -354,6 +566,81 @@ func (m *Model) saveConnection() {
 	m.loadConnections()
 }
 
+// nextTemplate selects the next cloud template, or none after the last one.
+// The port field shows the template's port unless the user typed another.
+func (m *Model) nextTemplate() {
+	names := config.TemplateNames()
+	next := 0
+	for i, name := range names {
+		if name == m.formTemplate {
+			next = i + 1
+		}
+	}
+
+	port := strings.TrimSpace(m.formInputs[2])
+	if port == "" || port == m.templatePort() {
+		port = ""
+	}
+	if next >= len(names) {
+		m.formTemplate = ""
+		m.formInputs[2] = port
+		m.setWarningMessage("No template")
+		return
+	}
+
+	m.formTemplate = names[next]
+	if port == "" {
+		port = m.templatePort()
+	}
+	m.formInputs[2] = port
+	m.setSuccessMessage(fmt.Sprintf("Template %s: %s", m.formTemplate, config.DescribeTemplate(m.formTemplate)))
+}
+
+// templatePort is the port of the selected template, or "" without one
+func (m *Model) templatePort() string {
+	if m.formTemplate == "" {
+		return ""
+	}
+	template, err := config.ApplyTemplate(m.formTemplate, config.Connection{})
+	if err != nil || template.Port == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", template.Port)
+}
+
+// detectConnectionType probes the host and port in the form and sets the
+// connection type (and ssl_mode for postgres) the form will save with
+func (m *Model) detectConnectionType() {
+	host := strings.TrimSpace(m.formInputs[1])
+	var port int
+	if _, err := fmt.Sscanf(strings.TrimSpace(m.formInputs[2]), "%d", &port); err != nil || host == "" {
+		m.setErrorMessage("Enter a host and port to detect the connection type")
+		return
+	}
+
+	detected, err := database.DetectConnectionType(host, port, database.DetectTimeout)
+	if err != nil {
+		m.setErrorMessage(fmt.Sprintf("Detection failed: %v", err))
+		return
+	}
+	if detected.Type == "" {
+		m.setWarningMessage(fmt.Sprintf("%s:%d did not answer like MySQL or PostgreSQL", host, port))
+		return
+	}
+
+	m.tempConn.Type = detected.Type
+	msg := fmt.Sprintf("Detected %s", detected.Type)
+	if detected.Type == "postgres" && m.tempConn.SSLMode == "" {
+		m.tempConn.SSLMode = detected.SSLMode
+		msg += fmt.Sprintf(" (ssl_mode %s)", detected.SSLMode)
+	}
+	if detected.Method == "port" {
+		m.setWarningMessage(msg + " from the port number only; the server didn't answer the handshake")
+		return
+	}
+	m.setSuccessMessage(msg)
+}
+
 func (m *Model) testConnection(connName string) {
 	_, exists := m.config.GetConnection(connName)
 	if !exists {
@@ -362,13 +649,14 @@ func (m *Model) testConnection(connName string) {
 	}
 
 	// Create a database manager to test the connection
-	credManager := credentials.NewManager(m.config.Settings.CacheCredentials)
+	credManager := credentials.NewProvider(m.config.Settings.CacheCredentials, m.config.Settings.CredentialFallbackFile())
+	credManager.SetRequireBiometric(m.config.Settings.RequireBiometric)
 	dbManager := database.NewManager(m.config, credManager)
 	defer dbManager.Close()
 
 	// Test the connection
 	if err := dbManager.TestConnection(connName); err != nil {
-		m.setErrorMessage(fmt.Sprintf("Connection test failed: %v", err))
+		m.setErrorMessage(fmt.Sprintf("Connection test failed (%s): %v", database.ClassifyFailure(err), err))
 	} else {
 		m.setSuccessMessage(fmt.Sprintf("Connection '%s' test successful!", connName))
 	}
@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func connectionsModel(t *testing.T) Model {
+	t.Helper()
+	t.Setenv("HOME", testutil.TempDir(t))
+	cfg := config.DefaultConfig()
+	cfg.Connections["analytics"] = config.Connection{
+		Type:     "postgres",
+		Host:     "db.example.com",
+		Port:     5432,
+		Database: "warehouse",
+		Username: "readonly",
+		SSLMode:  "require",
+	}
+	m := Model{state: StateConnections, config: cfg, formInputs: make([]string, 6)}
+	m.loadConnections()
+	return m
+}
+
+func TestCopyConnectionPrefillsForm(t *testing.T) {
+	m := connectionsModel(t)
+
+	m = press(m, typed("c"))
+	testutil.AssertEqual(t, StateAddConnection, m.state)
+	testutil.AssertEqual(t, "analytics-copy", m.formInputs[0])
+	testutil.AssertEqual(t, "db.example.com", m.formInputs[1])
+	testutil.AssertEqual(t, "5432", m.formInputs[2])
+	testutil.AssertEqual(t, "warehouse", m.formInputs[3])
+	testutil.AssertEqual(t, "readonly", m.formInputs[4])
+	testutil.AssertEqual(t, "", m.formInputs[5])
+
+	// The cursor waits on the password field, which isn't copied
+	testutil.AssertEqual(t, 5, m.formCursor)
+	testutil.AssertEqual(t, "warning", m.messageType)
+
+	// Saving keeps settings the form doesn't show
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	copied, exists := m.config.GetConnection("analytics-copy")
+	testutil.AssertEqual(t, true, exists)
+	testutil.AssertEqual(t, "postgres", copied.Type)
+	testutil.AssertEqual(t, "require", copied.SSLMode)
+	_, exists = m.config.GetConnection("analytics")
+	testutil.AssertEqual(t, true, exists)
+}
+
+func TestCopyConnectionNameIsUnique(t *testing.T) {
+	m := connectionsModel(t)
+	m.config.Connections["analytics-copy"] = config.Connection{Type: "postgres", Host: "other"}
+	m.loadConnections()
+
+	m = press(m, typed("c"))
+	testutil.AssertEqual(t, "analytics-copy2", m.formInputs[0])
+}
+
+func TestToggleConnectionEnabled(t *testing.T) {
+	m := connectionsModel(t)
+
+	m = press(m, typed("x"))
+	testutil.AssertEqual(t, false, m.config.Connections["analytics"].IsEnabled())
+	testutil.AssertEqual(t, "success", m.messageType)
+	testutil.AssertContains(t, m.View(), "[disabled]")
+
+	// The disabled connection is kept in the saved config
+	loaded, err := config.Load()
+	testutil.AssertNoError(t, err)
+	conn, exists := loaded.GetConnection("analytics")
+	testutil.AssertEqual(t, true, exists)
+	testutil.AssertEqual(t, false, conn.IsEnabled())
+
+	m = press(m, typed("x"))
+	testutil.AssertEqual(t, true, m.config.Connections["analytics"].IsEnabled())
+}
+
+func TestFilterConnections(t *testing.T) {
+	m := connectionsModel(t)
+	m.config.Connections["orders-mysql"] = config.Connection{Type: "mysql", Host: "orders.internal", Port: 3306}
+	m.config.Connections["users-mysql"] = config.Connection{Type: "mysql", Host: "users.internal", Port: 3306}
+	m.loadConnections()
+	testutil.AssertEqual(t, 3, len(m.connections))
+
+	// Select the last connection, then filter it out
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	testutil.AssertEqual(t, "users-mysql", m.connections[m.connectionCursor])
+
+	m = press(m, typed("/"), typed("o"), typed("r"), typed("d"))
+	testutil.AssertEqual(t, true, m.filtering)
+	testutil.AssertEqual(t, "orders-mysql", strings.Join(m.connections, ","))
+	testutil.AssertEqual(t, 0, m.connectionCursor)
+
+	// Type and host match too, case-insensitively
+	m = press(m, keyClear, typed("MYSQL"))
+	testutil.AssertEqual(t, "orders-mysql,users-mysql", strings.Join(m.connections, ","))
+	m = press(m, keyClear, typed("example.com"))
+	testutil.AssertEqual(t, "analytics", strings.Join(m.connections, ","))
+
+	// Nothing matches: the list is empty and the cursor stays at zero
+	m = press(m, typed("zzz"))
+	testutil.AssertEqual(t, 0, len(m.connections))
+	testutil.AssertEqual(t, 0, m.connectionCursor)
+
+	// Enter keeps the filter and returns to navigation; Esc clears it
+	m = press(m, keyClear, typed("users"), tea.KeyMsg{Type: tea.KeyEnter})
+	testutil.AssertEqual(t, false, m.filtering)
+	testutil.AssertEqual(t, "users-mysql", strings.Join(m.connections, ","))
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	testutil.AssertEqual(t, StateConnections, m.state)
+	testutil.AssertEqual(t, 3, len(m.connections))
+	testutil.AssertEqual(t, "users-mysql", m.connections[m.connectionCursor])
+}
+
+func TestAddConnectionFromTemplate(t *testing.T) {
+	m := connectionsModel(t)
+	m = press(m, typed("a"))
+	testutil.AssertEqual(t, StateAddConnection, m.state)
+
+	// aurora-mysql, then aurora-postgres
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlT}, tea.KeyMsg{Type: tea.KeyCtrlT})
+	testutil.AssertEqual(t, "aurora-postgres", m.formTemplate)
+	testutil.AssertEqual(t, "5432", m.formInputs[2])
+
+	m.formInputs[0] = "aurora"
+	m.formInputs[1] = "cluster.example.com"
+	m.formCursor = len(m.formInputs) - 1
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	saved, exists := m.config.GetConnection("aurora")
+	testutil.AssertEqual(t, true, exists)
+	testutil.AssertEqual(t, "postgres", saved.Type)
+	testutil.AssertEqual(t, 5432, saved.Port)
+	testutil.AssertEqual(t, "require", saved.SSLMode)
+	testutil.AssertEqual(t, "cluster.example.com", saved.Host)
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+)
+
+// settingField is one editable line on the settings screen
+type settingField struct {
+	label  string
+	toggle bool // booleans flip on Enter instead of taking typed input
+	get    func(s *config.Settings) string
+	set    func(s *config.Settings, value string) error
+}
+
+// settingFields lists the global settings the TUI can edit, in display order
+var settingFields = []settingField{
+	durationSetting("Query Timeout", false, func(s *config.Settings) *time.Duration { return &s.QueryTimeout }),
+	durationSetting("Connect Timeout", false, func(s *config.Settings) *time.Duration { return &s.ConnectTimeout }),
+	intSetting("Max Rows", 1, func(s *config.Settings) *int { return &s.MaxRows }),
+	intSetting("Sample Max Columns", 0, func(s *config.Settings) *int { return &s.SampleMaxColumns }),
+	durationSetting("Cache Credentials", true, func(s *config.Settings) *time.Duration { return &s.CacheCredentials }),
+	boolSetting("Require Biometric", func(s *config.Settings) *bool { return &s.RequireBiometric }),
+	boolSetting("Hide System Databases", func(s *config.Settings) *bool { return &s.HideSystemDatabases }),
+	boolSetting("Resolve Table Schema", func(s *config.Settings) *bool { return &s.ResolveTableSchema }),
+	intSetting("Max List Items", 0, func(s *config.Settings) *int { return &s.MaxListItems }),
+	intSetting("Sample Max Rows", 1, func(s *config.Settings) *int { return &s.SampleMaxRows }),
+	intSetting("Sample Max Bytes", 0, func(s *config.Settings) *int { return &s.SampleMaxBytes }),
+	durationSetting("Slow Query Threshold", true, func(s *config.Settings) *time.Duration { return &s.SlowQueryThreshold }),
+	boolSetting("Redact Connection Details In Logs", func(s *config.Settings) *bool { return &s.RedactConnectionDetailsInLogs }),
+	boolSetting("Log Queries", func(s *config.Settings) *bool { return &s.LogQueries }),
+	boolSetting("Log Queries: Redact Literals", func(s *config.Settings) *bool { return &s.LogQueriesRedactLiterals }),
+	boolSetting("Pool: Enable Keepalive", func(s *config.Settings) *bool { return &s.ConnectionPool.EnableKeepalive }),
+	durationSetting("Pool: Ping Interval", false, func(s *config.Settings) *time.Duration { return &s.ConnectionPool.PingInterval }),
+	durationSetting("Pool: Max Idle Time", false, func(s *config.Settings) *time.Duration { return &s.ConnectionPool.MaxIdleTime }),
+	intSetting("Pool: Max Error Count", 1, func(s *config.Settings) *int { return &s.ConnectionPool.MaxErrorCount }),
+	durationSetting("Pool: Reconnect Delay", true, func(s *config.Settings) *time.Duration { return &s.ConnectionPool.ReconnectDelay }),
+	intSetting("Pool: Max Pooled Connections", 0, func(s *config.Settings) *int { return &s.ConnectionPool.MaxPooledConnections }),
+}
+
+// durationSetting edits a duration such as "30s" or "5m"; allowZero permits 0 (disabled)
+func durationSetting(label string, allowZero bool, field func(s *config.Settings) *time.Duration) settingField {
+	return settingField{
+		label: label,
+		get:   func(s *config.Settings) string { return field(s).String() },
+		set: func(s *config.Settings, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("%s must be a duration such as 30s or 5m", label)
+			}
+			if d < 0 || (d == 0 && !allowZero) {
+				return fmt.Errorf("%s must be positive", label)
+			}
+			*field(s) = d
+			return nil
+		},
+	}
+}
+
+// intSetting edits a whole number no smaller than min
+func intSetting(label string, min int, field func(s *config.Settings) *int) settingField {
+	return settingField{
+		label: label,
+		get:   func(s *config.Settings) string { return strconv.Itoa(*field(s)) },
+		set: func(s *config.Settings, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s must be a whole number", label)
+			}
+			if n < min {
+				return fmt.Errorf("%s must be at least %d", label, min)
+			}
+			*field(s) = n
+			return nil
+		},
+	}
+}
+
+// boolSetting edits an on/off setting
+func boolSetting(label string, field func(s *config.Settings) *bool) settingField {
+	return settingField{
+		label:  label,
+		toggle: true,
+		get:    func(s *config.Settings) string { return strconv.FormatBool(*field(s)) },
+		set: func(s *config.Settings, value string) error {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s must be true or false", label)
+			}
+			*field(s) = b
+			return nil
+		},
+	}
+}
+
+// applySetting validates and stores a new value for the selected field, then
+// saves the configuration. An invalid value leaves the settings unchanged.
+func (m *Model) applySetting(value string) error {
+	field := settingFields[m.settingsCursor]
+	previous := field.get(&m.config.Settings)
+	if err := field.set(&m.config.Settings, strings.TrimSpace(value)); err != nil {
+		return err
+	}
+	if err := m.config.Save(); err != nil {
+		field.set(&m.config.Settings, previous)
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	return nil
+}
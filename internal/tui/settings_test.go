@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+// press feeds key presses to the model and returns the updated model
+func press(m Model, keys ...tea.KeyMsg) Model {
+	for _, key := range keys {
+		updated, _ := m.Update(key)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func typed(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+}
+
+var (
+	keyDown  = tea.KeyMsg{Type: tea.KeyDown}
+	keyEnter = tea.KeyMsg{Type: tea.KeyEnter}
+	keyClear = tea.KeyMsg{Type: tea.KeyCtrlU}
+)
+
+func settingsModel(t *testing.T) Model {
+	t.Helper()
+	t.Setenv("HOME", testutil.TempDir(t))
+	return Model{state: StateSettings, config: config.DefaultConfig()}
+}
+
+func TestEditMaxRowsSavesConfig(t *testing.T) {
+	m := settingsModel(t)
+
+	// Max Rows is the third field
+	m = press(m, keyDown, keyDown, keyEnter, keyClear, typed("250"), keyEnter)
+	testutil.AssertEqual(t, false, m.settingsEditing)
+	testutil.AssertEqual(t, "success", m.messageType)
+	testutil.AssertEqual(t, 250, m.config.Settings.MaxRows)
+
+	saved, err := config.Load()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 250, saved.Settings.MaxRows)
+}
+
+func TestEditSettingRejectsInvalidValues(t *testing.T) {
+	m := settingsModel(t)
+
+	// Query Timeout is the first field
+	m = press(m, keyEnter, keyClear, typed("soon"), keyEnter)
+	testutil.AssertEqual(t, true, m.settingsEditing)
+	testutil.AssertEqual(t, "error", m.messageType)
+	testutil.AssertEqual(t, 30*time.Second, m.config.Settings.QueryTimeout)
+
+	m = press(m, keyClear, typed("45s"), keyEnter)
+	testutil.AssertEqual(t, false, m.settingsEditing)
+	testutil.AssertEqual(t, 45*time.Second, m.config.Settings.QueryTimeout)
+
+	// Max Rows must be positive
+	m = press(m, keyDown, keyDown, keyEnter, keyClear, typed("0"), keyEnter)
+	testutil.AssertEqual(t, "error", m.messageType)
+	testutil.AssertEqual(t, 1000, m.config.Settings.MaxRows)
+}
+
+func TestToggleRequireBiometric(t *testing.T) {
+	m := settingsModel(t)
+	original := m.config.Settings.RequireBiometric
+
+	for i := 0; i < 5; i++ {
+		m = press(m, keyDown)
+	}
+	m = press(m, keyEnter)
+	testutil.AssertEqual(t, !original, m.config.Settings.RequireBiometric)
+
+	saved, err := config.Load()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, !original, saved.Settings.RequireBiometric)
+}
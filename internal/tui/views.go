@@ -76,8 +76,17 @@ func (m Model) menuView() string {
 func (m Model) connectionsView() string {
 	title := titleStyle.Render("Database Connections")
 
+	filter := ""
+	if m.filtering {
+		filter = focusedInputStyle.Render("/" + m.connectionFilter + "│")
+	} else if m.connectionFilter != "" {
+		filter = inputStyle.Render("/" + m.connectionFilter)
+	}
+
 	var connectionsList strings.Builder
-	if len(m.connections) == 0 {
+	if len(m.connections) == 0 && m.connectionFilter != "" {
+		connectionsList.WriteString(helpStyle.Render("No connections match the filter"))
+	} else if len(m.connections) == 0 {
 		connectionsList.WriteString(helpStyle.Render("No connections configured"))
 	} else {
 		for i, conn := range m.connections {
@@ -92,6 +101,9 @@ func (m Model) connectionsView() string {
 			if connConfig, exists := m.config.GetConnection(conn); exists {
 				display := fmt.Sprintf("%s %s (%s) - %s:%d/%s", 
 					cursor, conn, connConfig.Type, connConfig.Host, connConfig.Port, connConfig.Database)
+				if !connConfig.IsEnabled() {
+					display += " [disabled]"
+				}
 				connectionsList.WriteString(style.Render(display) + "\n")
 			} else {
 				connectionsList.WriteString(style.Render(fmt.Sprintf("%s %s (error)", cursor, conn)) + "\n")
@@ -99,12 +111,17 @@ func (m Model) connectionsView() string {
 		}
 	}
 
-	actions := helpStyle.Render("a: Add • e: Edit • d: Delete • t: Test • q: Back")
+	actions := helpStyle.Render("a: Add • e: Edit • c: Copy • d: Delete • t: Test • x: Enable/Disable • /: Filter • q: Back")
+	if m.filtering {
+		actions = helpStyle.Render("Type to filter by name, type or host • Enter: Done • Esc: Clear")
+	} else if m.connectionFilter != "" {
+		actions = helpStyle.Render("a: Add • e: Edit • c: Copy • d: Delete • t: Test • x: Enable/Disable • /: Filter • Esc: Clear filter • q: Back")
+	}
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		title,
-		"",
+		filter,
 		connectionsList.String(),
 		"",
 		actions,
@@ -134,7 +151,7 @@ func (m Model) formView(title string) string {
 		form.WriteString(inputRendered + "\n")
 	}
 
-	help := helpStyle.Render("Tab/↑↓: Navigate • Enter: Save • Esc: Cancel • Ctrl+U: Clear • Paste: Cmd+V (Mac) or Ctrl+V")
+	help := helpStyle.Render("Tab/↑↓: Navigate • Enter: Save • Esc: Cancel • Ctrl+U: Clear • Ctrl+D: Detect type • Ctrl+T: Template • Paste: Cmd+V (Mac) or Ctrl+V")
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -148,26 +165,32 @@ func (m Model) formView(title string) string {
 func (m Model) settingsView() string {
 	title := titleStyle.Render("Settings")
 
-	settings := fmt.Sprintf(`Query Timeout: %s
-Max Rows: %d
-Cache Credentials: %s
-Require Biometric: %t
-
-Config Location: %s`,
-		m.config.Settings.QueryTimeout,
-		m.config.Settings.MaxRows,
-		m.config.Settings.CacheCredentials,
-		m.config.Settings.RequireBiometric,
-		"~/.config/simpledb-mcp/config.yaml",
-	)
+	var settings strings.Builder
+	for i, field := range settingFields {
+		cursor := " "
+		style := listItemStyle
+		value := field.get(&m.config.Settings)
+		if i == m.settingsCursor {
+			cursor = ">"
+			style = selectedListItemStyle
+			if m.settingsEditing {
+				value = focusedInputStyle.Render(m.settingsInput + "│")
+			}
+		}
+		settings.WriteString(style.Render(fmt.Sprintf("%s %s:", cursor, field.label)) + " " + value + "\n")
+	}
+	settings.WriteString(fmt.Sprintf("\nConfig Location: %s", "~/.config/simpledb-mcp/config.yaml"))
 
-	help := helpStyle.Render("q: Back")
+	help := helpStyle.Render("↑/↓: Navigate • Enter: Edit/Toggle • q: Back")
+	if m.settingsEditing {
+		help = helpStyle.Render("Enter: Save • Esc: Cancel • Ctrl+U: Clear")
+	}
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		title,
 		"",
-		borderStyle.Render(settings),
+		borderStyle.Render(settings.String()),
 		"",
 		help,
 	)
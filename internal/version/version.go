@@ -0,0 +1,35 @@
+// Package version holds build information stamped in at link time, e.g.
+//
+//	go build -ldflags "-X github.com/eliziario/simpledb-mcp/internal/version.Version=v0.3.0"
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// These are overridden with -ldflags -X at build time.
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// Number returns the version without a leading "v", as reported to MCP clients
+func Number() string {
+	return strings.TrimPrefix(Version, "v")
+}
+
+// String returns a one-line description of the build
+func String() string {
+	return fmt.Sprintf("%s (commit %s, built %s)", Version, Commit, BuildDate)
+}
+
+// Info returns the build information as a map for JSON responses
+func Info() map[string]string {
+	return map[string]string{
+		"version":    Version,
+		"commit":     Commit,
+		"build_date": BuildDate,
+	}
+}
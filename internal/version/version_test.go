@@ -0,0 +1,26 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestDefaults(t *testing.T) {
+	testutil.AssertEqual(t, "dev", Version)
+	testutil.AssertEqual(t, "unknown", Commit)
+	testutil.AssertEqual(t, "unknown", BuildDate)
+	testutil.AssertEqual(t, "dev (commit unknown, built unknown)", String())
+}
+
+func TestInjectedValues(t *testing.T) {
+	original := [3]string{Version, Commit, BuildDate}
+	defer func() { Version, Commit, BuildDate = original[0], original[1], original[2] }()
+
+	Version, Commit, BuildDate = "v0.3.0", "abc1234", "2024-06-01T12:00:00Z"
+
+	testutil.AssertEqual(t, "0.3.0", Number())
+	testutil.AssertEqual(t, "v0.3.0 (commit abc1234, built 2024-06-01T12:00:00Z)", String())
+	testutil.AssertEqual(t, "abc1234", Info()["commit"])
+	testutil.AssertEqual(t, "2024-06-01T12:00:00Z", Info()["build_date"])
+}
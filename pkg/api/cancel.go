@@ -0,0 +1,188 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// callRegistry tracks in-flight tool calls by JSON-RPC request ID so that
+// HTTP clients can cancel a slow call from a separate request. IDs are only
+// unique per client, so each is kept under the caller's scope (its MCP
+// session or WebSocket connection), and every registration separately, so
+// two calls sharing an ID neither overwrite nor deregister each other.
+type callRegistry struct {
+	calls map[string]map[uint64]context.CancelFunc
+	next  uint64
+	mutex sync.Mutex
+}
+
+func newCallRegistry() *callRegistry {
+	return &callRegistry{
+		calls: make(map[string]map[uint64]context.CancelFunc),
+	}
+}
+
+// callScopeKey carries the scope a call's ID is unique in
+type callScopeKey struct{}
+
+// withCallScope sets the scope calls registered from ctx are kept under
+func withCallScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, callScopeKey{}, scope)
+}
+
+// callScope is the scope set on ctx, or "" for clients without a session
+func callScope(ctx context.Context) string {
+	scope, _ := ctx.Value(callScopeKey{}).(string)
+	return scope
+}
+
+// callKey is the registry key of a request ID within a scope
+func callKey(scope, requestID string) string {
+	return scope + "\x00" + requestID
+}
+
+// register derives a cancellable context for the call with the given ID, in
+// the scope set on parent. The returned function must be called once the
+// call finishes.
+func (r *callRegistry) register(parent context.Context, requestID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	key := callKey(callScope(parent), requestID)
+
+	r.mutex.Lock()
+	r.next++
+	token := r.next
+	if r.calls[key] == nil {
+		r.calls[key] = make(map[uint64]context.CancelFunc)
+	}
+	r.calls[key][token] = cancel
+	r.mutex.Unlock()
+
+	return ctx, func() {
+		r.mutex.Lock()
+		delete(r.calls[key], token)
+		if len(r.calls[key]) == 0 {
+			delete(r.calls, key)
+		}
+		r.mutex.Unlock()
+		cancel()
+	}
+}
+
+// cancel cancels the in-flight calls with the given ID in a scope, reporting
+// whether any was found
+func (r *callRegistry) cancel(scope, requestID string) bool {
+	r.mutex.Lock()
+	var cancels []context.CancelFunc
+	for _, cancel := range r.calls[callKey(scope, requestID)] {
+		cancels = append(cancels, cancel)
+	}
+	r.mutex.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+	return len(cancels) > 0
+}
+
+// callContextKey carries a tracked call's cancellable context on the HTTP request
+type callContextKey struct{}
+
+// middleware makes tool handlers observe cancellation of their tracked call.
+// The HTTP request context itself is left alone so the transport can still
+// write the cancellation error back to the client.
+func (r *callRegistry) middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			callCtx, ok := ctx.Value(callContextKey{}).(context.Context)
+			if !ok {
+				return next(ctx, request)
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			stop := context.AfterFunc(callCtx, cancel)
+			defer stop()
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// sessionIDHeader carries the MCP session of a streamable HTTP request, when
+// the client has one
+const sessionIDHeader = "Mcp-Session-Id"
+
+// jsonRPCRequest holds the fields of an incoming message needed to track it
+type jsonRPCRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+// requestIDString normalizes a JSON-RPC ID so numeric and string IDs can be
+// looked up by the same value (e.g. 7 and "7").
+func requestIDString(raw json.RawMessage) string {
+	return strings.Trim(strings.TrimSpace(string(raw)), `"`)
+}
+
+// trackCalls wraps the MCP HTTP handler so every tools/call request carries
+// a context that can be cancelled through the registry.
+func (r *callRegistry) trackCalls(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Body == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		var message jsonRPCRequest
+		if err := json.Unmarshal(body, &message); err != nil || message.Method != string(mcp.MethodToolsCall) || len(message.ID) == 0 {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		ctx := withCallScope(req.Context(), req.Header.Get(sessionIDHeader))
+		callCtx, done := r.register(ctx, requestIDString(message.ID))
+		defer done()
+		next.ServeHTTP(w, req.WithContext(context.WithValue(ctx, callContextKey{}, callCtx)))
+	})
+}
+
+// handleCancelEndpoint cancels an in-flight call given its request ID in the "id" query parameter
+func (r *callRegistry) handleCancelEndpoint(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requestID := req.URL.Query().Get("id")
+	if requestID == "" {
+		http.Error(w, "id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	if !r.cancel(req.Header.Get(sessionIDHeader), requestID) {
+		http.Error(w, fmt.Sprintf("no in-flight call with request ID '%s'", requestID), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"request_id": requestID,
+		"cancelled":  true,
+	})
+}
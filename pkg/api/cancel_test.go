@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newBlockingServer serves a single tool that blocks until its context is cancelled
+func newBlockingServer(t *testing.T, calls *callRegistry) *httptest.Server {
+	t.Helper()
+	mcpServer := server.NewMCPServer("test", "0.0.0",
+		server.WithToolCapabilities(false),
+		server.WithToolHandlerMiddleware(calls.middleware()),
+	)
+	mcpServer.AddTool(
+		mcp.NewTool("block"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	)
+
+	httpServer := server.NewStreamableHTTPServer(mcpServer, server.WithStateLess(true))
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", calls.trackCalls(httpServer))
+	mux.HandleFunc("/mcp/cancel", calls.handleCancelEndpoint)
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// waitForCalls polls until the registry tracks count calls with the given
+// request ID in a scope
+func waitForCalls(t *testing.T, calls *callRegistry, scope, requestID string, count int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		calls.mutex.Lock()
+		registered := len(calls.calls[callKey(scope, requestID)])
+		calls.mutex.Unlock()
+		if registered == count {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Call %s was never registered %d times", requestID, count)
+}
+
+// postBlockingCall calls the blocking tool with a request ID, in a session
+// when sessionID is set, and returns the response body once it finishes
+func postBlockingCall(ts *httptest.Server, requestID, sessionID string) <-chan string {
+	done := make(chan string, 1)
+	go func() {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/mcp",
+			strings.NewReader(`{"jsonrpc":"2.0","id":`+requestID+`,"method":"tools/call","params":{"name":"block"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			req.Header.Set(sessionIDHeader, sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			done <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		done <- string(body)
+	}()
+	return done
+}
+
+// postCancel cancels a request ID through the cancel endpoint and returns the status
+func postCancel(t *testing.T, ts *httptest.Server, requestID, sessionID string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/mcp/cancel?id="+requestID, nil)
+	testutil.AssertNoError(t, err)
+	if sessionID != "" {
+		req.Header.Set(sessionIDHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	testutil.AssertNoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestCancelInFlightCall(t *testing.T) {
+	calls := newCallRegistry()
+	ts := newBlockingServer(t, calls)
+
+	type callResult struct {
+		body string
+		err  error
+	}
+	done := make(chan callResult, 1)
+	go func() {
+		resp, err := http.Post(ts.URL+"/mcp", "application/json",
+			strings.NewReader(`{"jsonrpc":"2.0","id":42,"method":"tools/call","params":{"name":"block"}}`))
+		if err != nil {
+			done <- callResult{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		done <- callResult{body: string(body), err: err}
+	}()
+
+	waitForCalls(t, calls, "", "42", 1)
+
+	resp, err := http.Post(ts.URL+"/mcp/cancel?id=42", "application/json", nil)
+	testutil.AssertNoError(t, err)
+	resp.Body.Close()
+	testutil.AssertEqual(t, http.StatusOK, resp.StatusCode)
+
+	select {
+	case result := <-done:
+		testutil.AssertNoError(t, result.err)
+		testutil.AssertContains(t, result.body, "context canceled")
+	case <-time.After(2 * time.Second):
+		t.Fatal("Blocking call did not return after cancellation")
+	}
+
+	// The call deregisters itself once it returns
+	testutil.AssertEqual(t, false, calls.cancel("", "42"))
+}
+
+func TestCancelCallsSharingAnID(t *testing.T) {
+	calls := newCallRegistry()
+	ts := newBlockingServer(t, calls)
+
+	// Two sessions both number their first call 1
+	first := postBlockingCall(ts, "1", "session-a")
+	second := postBlockingCall(ts, "1", "session-b")
+	waitForCalls(t, calls, "session-a", "1", 1)
+	waitForCalls(t, calls, "session-b", "1", 1)
+
+	// Cancelling one session's call leaves the other's alone
+	testutil.AssertEqual(t, http.StatusOK, postCancel(t, ts, "1", "session-a"))
+	select {
+	case body := <-first:
+		testutil.AssertContains(t, body, "context canceled")
+	case <-time.After(2 * time.Second):
+		t.Fatal("Cancelled call did not return")
+	}
+	select {
+	case body := <-second:
+		t.Fatalf("The other session's call returned: %s", body)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The first call finishing doesn't deregister the second, which can still be cancelled
+	waitForCalls(t, calls, "session-a", "1", 0)
+	waitForCalls(t, calls, "session-b", "1", 1)
+	testutil.AssertEqual(t, http.StatusOK, postCancel(t, ts, "1", "session-b"))
+	select {
+	case body := <-second:
+		testutil.AssertContains(t, body, "context canceled")
+	case <-time.After(2 * time.Second):
+		t.Fatal("Second call did not return after cancellation")
+	}
+}
+
+func TestCallRegistrySameIDInOneScope(t *testing.T) {
+	calls := newCallRegistry()
+	firstCtx, firstDone := calls.register(context.Background(), "1")
+	secondCtx, secondDone := calls.register(context.Background(), "1")
+	defer secondDone()
+
+	// One call finishing keeps the other registered
+	firstDone()
+	testutil.AssertEqual(t, context.Canceled, firstCtx.Err())
+	testutil.AssertEqual(t, nil, secondCtx.Err())
+	testutil.AssertEqual(t, true, calls.cancel("", "1"))
+	testutil.AssertEqual(t, context.Canceled, secondCtx.Err())
+}
+
+func TestCancelUnknownCall(t *testing.T) {
+	calls := newCallRegistry()
+	ts := newBlockingServer(t, calls)
+
+	resp, err := http.Post(ts.URL+"/mcp/cancel?id=missing", "application/json", nil)
+	testutil.AssertNoError(t, err)
+	resp.Body.Close()
+	testutil.AssertEqual(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestCallRegistryStringAndNumericIDs(t *testing.T) {
+	testutil.AssertEqual(t, "7", requestIDString([]byte(`7`)))
+	testutil.AssertEqual(t, "abc", requestIDString([]byte(`"abc"`)))
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// concurrencyLimiter holds one semaphore per connection, sized by its
+// max_concurrent_queries, so a burst of parallel tool calls can't pile onto
+// one database
+type concurrencyLimiter struct {
+	cfg   *config.Config
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newConcurrencyLimiter(cfg *config.Config) *concurrencyLimiter {
+	return &concurrencyLimiter{
+		cfg:   cfg,
+		slots: make(map[string]chan struct{}),
+	}
+}
+
+// semaphore returns the connection's semaphore, or nil if it has no limit
+func (l *concurrencyLimiter) semaphore(connectionName string, conn config.Connection) chan struct{} {
+	limit := l.cfg.Settings.ConcurrencyLimit(conn)
+	if limit <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slots, ok := l.slots[connectionName]
+	if !ok || cap(slots) != limit {
+		slots = make(chan struct{}, limit)
+		l.slots[connectionName] = slots
+	}
+	return slots
+}
+
+// middleware holds a slot of the call's connection while the tool runs.
+// Beyond the limit, calls wait for a slot (or their cancellation) in queue
+// mode and fail straight away in reject mode.
+func (l *concurrencyLimiter) middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			connectionName := mcp.ParseString(request, "connection", "")
+			conn, exists := l.cfg.GetConnection(connectionName)
+			if !exists {
+				return next(ctx, request)
+			}
+			slots := l.semaphore(connectionName, conn)
+			if slots == nil {
+				return next(ctx, request)
+			}
+
+			if l.cfg.Settings.ConcurrencyLimitMode == config.ConcurrencyLimitReject {
+				select {
+				case slots <- struct{}{}:
+				default:
+					return nil, withCode(CodeBusy, fmt.Errorf("connection '%s' already has %d queries running; retry when one finishes", connectionName, cap(slots)))
+				}
+			} else {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return nil, fmt.Errorf("gave up waiting for a free query slot on connection '%s': %w", connectionName, ctx.Err())
+				}
+			}
+			defer func() { <-slots }()
+
+			return next(ctx, request)
+		}
+	}
+}
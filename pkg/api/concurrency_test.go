@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func limitedConfig(mode string) *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Settings.MaxConcurrentQueries = 2
+	cfg.Settings.ConcurrencyLimitMode = mode
+	cfg.Connections["test-mysql"] = config.Connection{Type: "mysql", Host: "localhost", Port: 3306}
+	cfg.Connections["wide"] = config.Connection{Type: "mysql", Host: "localhost", Port: 3306, MaxConcurrentQueries: 3}
+	return cfg
+}
+
+// blockingTool holds each call until release is closed, counting how many run at once
+type blockingTool struct {
+	release chan struct{}
+	started chan struct{}
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func newBlockingTool() *blockingTool {
+	return &blockingTool{release: make(chan struct{}), started: make(chan struct{}, 10)}
+}
+
+func (b *blockingTool) handler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	b.mu.Lock()
+	b.running++
+	if b.running > b.peak {
+		b.peak = b.running
+	}
+	b.mu.Unlock()
+	b.started <- struct{}{}
+
+	<-b.release
+
+	b.mu.Lock()
+	b.running--
+	b.mu.Unlock()
+	return mcp.NewToolResultText("ok"), nil
+}
+
+// startCalls runs n calls on a connection in the background
+func startCalls(handler server.ToolHandlerFunc, connectionName string, n int) chan error {
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			request := sampleRequest()
+			request.Params.Arguments = map[string]any{"connection": connectionName}
+			_, err := handler(context.Background(), request)
+			errs <- err
+		}()
+	}
+	return errs
+}
+
+func TestConcurrencyLimitQueuesExcessCalls(t *testing.T) {
+	tool := newBlockingTool()
+	handler := newConcurrencyLimiter(limitedConfig(config.ConcurrencyLimitQueue)).middleware()(tool.handler)
+
+	errs := startCalls(handler, "test-mysql", 3)
+	<-tool.started
+	<-tool.started
+
+	// The third call waits for a slot
+	select {
+	case <-tool.started:
+		t.Fatal("third call ran while two were in flight")
+	case err := <-errs:
+		t.Fatalf("third call returned early: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(tool.release)
+	for i := 0; i < 3; i++ {
+		testutil.AssertNoError(t, <-errs)
+	}
+	testutil.AssertEqual(t, 2, tool.peak)
+}
+
+func TestConcurrencyLimitRejectsExcessCalls(t *testing.T) {
+	tool := newBlockingTool()
+	handler := newConcurrencyLimiter(limitedConfig(config.ConcurrencyLimitReject)).middleware()(tool.handler)
+
+	errs := startCalls(handler, "test-mysql", 2)
+	<-tool.started
+	<-tool.started
+
+	request := sampleRequest()
+	_, err := handler(context.Background(), request)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "connection 'test-mysql' already has 2 queries running")
+
+	close(tool.release)
+	testutil.AssertNoError(t, <-errs)
+	testutil.AssertNoError(t, <-errs)
+
+	// Slots are returned once calls finish
+	_, err = handler(context.Background(), request)
+	testutil.AssertNoError(t, err)
+}
+
+func TestConcurrencyLimitPerConnection(t *testing.T) {
+	tool := newBlockingTool()
+	handler := newConcurrencyLimiter(limitedConfig(config.ConcurrencyLimitReject)).middleware()(tool.handler)
+
+	// The connection's own limit wins over the setting, and connections don't share slots
+	errs := startCalls(handler, "wide", 3)
+	for i := 0; i < 3; i++ {
+		<-tool.started
+	}
+	more := startCalls(handler, "test-mysql", 2)
+	<-tool.started
+	<-tool.started
+
+	close(tool.release)
+	for i := 0; i < 3; i++ {
+		testutil.AssertNoError(t, <-errs)
+	}
+	testutil.AssertNoError(t, <-more)
+	testutil.AssertNoError(t, <-more)
+	testutil.AssertEqual(t, 5, tool.peak)
+}
+
+func TestConcurrencyLimitQueuedCallCanBeCancelled(t *testing.T) {
+	tool := newBlockingTool()
+	defer close(tool.release)
+	handler := newConcurrencyLimiter(limitedConfig(config.ConcurrencyLimitQueue)).middleware()(tool.handler)
+
+	startCalls(handler, "test-mysql", 2)
+	<-tool.started
+	<-tool.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := handler(ctx, sampleRequest())
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "gave up waiting for a free query slot")
+}
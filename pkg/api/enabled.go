@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// enabledMiddleware refuses tool calls naming a connection configured with
+// enabled: false. close_connection is let through, so the pooled sessions of
+// a connection taken out of service can still be closed.
+func enabledMiddleware(cfg *config.Config) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if request.Params.Name == "close_connection" {
+				return next(ctx, request)
+			}
+			connectionName := mcp.ParseString(request, "connection", "")
+			if conn, exists := cfg.GetConnection(connectionName); exists {
+				if err := conn.CheckEnabled(connectionName); err != nil {
+					return nil, err
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+const enabledConfig = `
+connections:
+  crm:
+    type: salesforce
+    host: https://crm.my.salesforce.com
+  crm-legacy:
+    type: salesforce
+    host: https://legacy.my.salesforce.com
+    enabled: false
+`
+
+func TestDisabledConnectionHiddenAndRejected(t *testing.T) {
+	testutil.WriteConfigFile(t, enabledConfig)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	text, err := callTool(t, s, "list_connections", nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, text, `"count":1`)
+	if strings.Contains(text, "crm-legacy") {
+		t.Errorf("Expected disabled connection to be hidden, got %s", text)
+	}
+
+	_, err = callTool(t, s, "list_databases", map[string]interface{}{"connection": "crm-legacy"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "connection disabled")
+
+	_, err = callTool(t, s, "list_databases", map[string]interface{}{"connection": "crm"})
+	testutil.AssertNoError(t, err)
+
+	// Disabling takes the connection out of service without deleting it
+	conn, exists := s.config.GetConnection("crm-legacy")
+	testutil.AssertEqual(t, true, exists)
+	testutil.AssertEqual(t, false, conn.IsEnabled())
+}
+
+func TestCloseConnectionAllowedWhenDisabled(t *testing.T) {
+	testutil.WriteConfigFile(t, enabledConfig)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	_, err = callTool(t, s, "close_connection", map[string]interface{}{"connection": "crm-legacy"})
+	testutil.AssertNoError(t, err)
+}
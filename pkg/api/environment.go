@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// environmentDescription documents the environment argument added to every
+// tool that takes a connection
+const environmentDescription = "Expected environment of the connection (e.g. prod); the call fails if the connection is labeled otherwise"
+
+// withEnvironmentArg adds the optional environment argument to tools that
+// take a connection
+func withEnvironmentArg(tools []server.ServerTool) []server.ServerTool {
+	for i := range tools {
+		if _, ok := tools[i].Tool.InputSchema.Properties["connection"]; ok {
+			mcp.WithString("environment", mcp.Description(environmentDescription))(&tools[i].Tool)
+		}
+	}
+	return tools
+}
+
+// environmentMiddleware scopes tool calls to an environment. A call naming an
+// environment fails unless its connection is labeled with it, and results for
+// labeled connections say which environment they came from.
+func environmentMiddleware(cfg *config.Config) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			connectionName := mcp.ParseString(request, "connection", "")
+			conn, exists := cfg.GetConnection(connectionName)
+			if !exists {
+				return next(ctx, request)
+			}
+
+			if environment := mcp.ParseString(request, "environment", ""); environment != "" && environment != conn.Environment {
+				if conn.Environment == "" {
+					return nil, withCode(CodeEnvironmentMismatch, fmt.Errorf("connection '%s' has no environment, not '%s'", connectionName, environment))
+				}
+				return nil, withCode(CodeEnvironmentMismatch, fmt.Errorf("connection '%s' is in environment '%s', not '%s'", connectionName, conn.Environment, environment))
+			}
+
+			result, err := next(ctx, request)
+			if err != nil || conn.Environment == "" {
+				return result, err
+			}
+			return withEnvironment(result, conn.Environment), nil
+		}
+	}
+}
+
+// withEnvironment adds an environment field to a JSON object result. The
+// field is spliced in after the opening brace rather than re-encoding the
+// object, so the rest of the text is left byte for byte as the tool wrote it.
+// Other results are returned unchanged.
+func withEnvironment(result *mcp.CallToolResult, environment string) *mcp.CallToolResult {
+	if result == nil || result.IsError || len(result.Content) != 1 {
+		return result
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return result
+	}
+
+	body := strings.TrimLeft(text.Text, " \t\r\n")
+	if !strings.HasPrefix(body, "{") || !json.Valid([]byte(body)) {
+		return result
+	}
+	value, err := json.Marshal(environment)
+	if err != nil {
+		return result
+	}
+
+	field := `"environment":` + string(value)
+	rest := body[1:]
+	if !strings.HasPrefix(strings.TrimLeft(rest, " \t\r\n"), "}") {
+		field += ","
+	}
+	text.Text = "{" + field + rest
+	result.Content[0] = text
+	return result
+}
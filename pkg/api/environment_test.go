@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const environmentsConfig = `
+connections:
+  crm-dev:
+    type: salesforce
+    host: https://dev.my.salesforce.com
+    environment: dev
+  crm-prod:
+    type: salesforce
+    host: https://prod.my.salesforce.com
+    environment: prod
+  scratch:
+    type: salesforce
+    host: https://scratch.my.salesforce.com
+`
+
+func TestListConnectionsScopedToEnvironment(t *testing.T) {
+	testutil.WriteConfigFile(t, environmentsConfig)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	text, err := callTool(t, s, "list_connections", map[string]interface{}{"environment": "prod"})
+	testutil.AssertNoError(t, err)
+
+	var result struct {
+		Connections []struct {
+			Name        string `json:"name"`
+			Environment string `json:"environment"`
+		} `json:"connections"`
+		Count int `json:"count"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal([]byte(text), &result))
+	testutil.AssertEqual(t, 1, result.Count)
+	testutil.AssertEqual(t, "crm-prod", result.Connections[0].Name)
+	testutil.AssertEqual(t, "prod", result.Connections[0].Environment)
+
+	text, err = callTool(t, s, "list_connections", nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, text, `"count":3`)
+}
+
+func TestEnvironmentInToolOutput(t *testing.T) {
+	testutil.WriteConfigFile(t, environmentsConfig)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	text, err := callTool(t, s, "list_databases", map[string]interface{}{"connection": "crm-prod"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, text, `"environment":"prod"`)
+	testutil.AssertContains(t, text, `"databases":["crm-prod"]`)
+
+	// Unlabeled connections are unchanged
+	text, err = callTool(t, s, "list_databases", map[string]interface{}{"connection": "scratch"})
+	testutil.AssertNoError(t, err)
+	if strings.Contains(text, "environment") {
+		t.Errorf("Expected no environment for an unlabeled connection, got %s", text)
+	}
+}
+
+func TestEnvironmentMismatchRefused(t *testing.T) {
+	testutil.WriteConfigFile(t, environmentsConfig)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	_, err = callTool(t, s, "list_databases", map[string]interface{}{"connection": "crm-prod", "environment": "dev"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "connection 'crm-prod' is in environment 'prod', not 'dev'")
+
+	_, err = callTool(t, s, "list_databases", map[string]interface{}{"connection": "crm-dev", "environment": "dev"})
+	testutil.AssertNoError(t, err)
+}
+
+func TestWithEnvironmentKeepsResultText(t *testing.T) {
+	sample := `{"columns":["id","note"],"rows":[[1,"<b>Tom & Jerry</b>"]],"count":1}`
+	result := withEnvironment(mcp.NewToolResultText(sample), "prod")
+	text := result.Content[0].(mcp.TextContent).Text
+
+	// Values come through as the tool wrote them, not HTML-escaped
+	testutil.AssertEqual(t, `{"environment":"prod","columns":["id","note"],"rows":[[1,"<b>Tom & Jerry</b>"]],"count":1}`, text)
+
+	result = withEnvironment(mcp.NewToolResultText(`{ }`), "prod")
+	testutil.AssertEqual(t, `{"environment":"prod" }`, result.Content[0].(mcp.TextContent).Text)
+
+	// Non-object results are left alone
+	result = withEnvironment(mcp.NewToolResultText("| id |\n|----|"), "prod")
+	testutil.AssertEqual(t, "| id |\n|----|", result.Content[0].(mcp.TextContent).Text)
+}
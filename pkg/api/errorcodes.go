@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/database"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrorCode is the machine-readable reason a tool call failed, returned in
+// the error result's _meta.error_code next to the message
+type ErrorCode string
+
+const (
+	CodeInvalidArgument     ErrorCode = "invalid_argument"     // a parameter is missing or has a bad value
+	CodeConnectionNotFound  ErrorCode = "connection_not_found" // no connection has that name
+	CodeConnectionDisabled  ErrorCode = "connection_disabled"  // the connection has enabled: false
+	CodeEnvironmentMismatch ErrorCode = "environment_mismatch" // the connection isn't in the requested environment
+	CodeUnsupported         ErrorCode = "unsupported"          // the tool doesn't work on this connection type
+	CodeDataAccessDisabled  ErrorCode = "data_access_disabled" // allow_sampling or glue_sample_enabled is false
+	CodeNotFound            ErrorCode = "not_found"            // something else the call names doesn't exist
+	CodeBusy                ErrorCode = "busy"                 // the connection is at max_concurrent_queries
+	CodeAuthFailed          ErrorCode = "auth_failed"          // the database rejected the credentials, or there are none
+	CodeUnreachable         ErrorCode = "unreachable"          // the database couldn't be reached
+	CodePermissionDenied    ErrorCode = "permission_denied"    // the connection's user lacks a grant
+	CodeTimeout             ErrorCode = "timeout"              // the call ran out of time
+	CodeCancelled           ErrorCode = "cancelled"            // the call was cancelled
+	CodeInternal            ErrorCode = "internal"             // anything else
+)
+
+// toolError attaches a code to an error raised by a tool handler
+type toolError struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *toolError) Error() string { return e.err.Error() }
+func (e *toolError) Unwrap() error { return e.err }
+
+// withCode wraps err with a code
+func withCode(code ErrorCode, err error) error {
+	return &toolError{code: code, err: err}
+}
+
+// invalidArgument reports a bad or missing parameter
+func invalidArgument(format string, args ...interface{}) error {
+	return withCode(CodeInvalidArgument, fmt.Errorf(format, args...))
+}
+
+// missingParameter reports a required parameter that wasn't passed
+func missingParameter(name string) error {
+	return invalidArgument("%s parameter is required", name)
+}
+
+// connectionNotFound reports a connection name missing from the config
+func connectionNotFound(connectionName string) error {
+	return withCode(CodeConnectionNotFound, fmt.Errorf("connection '%s' not found", connectionName))
+}
+
+// unsupported reports a tool or option the connection's type doesn't support
+func unsupported(format string, args ...interface{}) error {
+	return withCode(CodeUnsupported, fmt.Errorf(format, args...))
+}
+
+// failureCodes maps database failure kinds to error codes
+var failureCodes = map[database.FailureKind]ErrorCode{
+	database.FailureAuth:        CodeAuthFailed,
+	database.FailureUnreachable: CodeUnreachable,
+	database.FailurePermission:  CodePermissionDenied,
+	database.FailureTimeout:     CodeTimeout,
+}
+
+// errorCode picks the code of a tool error: the one a handler attached, else
+// one implied by a known sentinel, else the database failure kind
+func errorCode(err error) ErrorCode {
+	var te *toolError
+	if errors.As(err, &te) {
+		return te.code
+	}
+	switch {
+	case errors.Is(err, config.ErrConnectionDisabled):
+		return CodeConnectionDisabled
+	case errors.Is(err, config.ErrDataAccessDisabled), errors.Is(err, database.ErrGlueSamplingDisabled):
+		return CodeDataAccessDisabled
+	case errors.Is(err, database.ErrInvalidIdentifier), errors.Is(err, database.ErrAmbiguousTable):
+		return CodeInvalidArgument
+	case errors.Is(err, context.Canceled):
+		return CodeCancelled
+	}
+	if code, ok := failureCodes[database.ClassifyFailure(err)]; ok {
+		return code
+	}
+	return CodeInternal
+}
+
+// errorCodesMiddleware turns the errors tool handlers return into error
+// results, as MCP recommends, with the message as text and the code in
+// _meta.error_code so clients can tell failures apart without parsing text
+func errorCodesMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err == nil {
+				return result, nil
+			}
+			result = mcp.NewToolResultError(err.Error())
+			result.Meta = map[string]any{"error_code": string(errorCode(err))}
+			return result, nil
+		}
+	}
+}
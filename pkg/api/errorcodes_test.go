@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/database"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/go-sql-driver/mysql"
+)
+
+const errorCodesConfig = `
+connections:
+  crm:
+    type: salesforce
+    host: https://crm.my.salesforce.com
+  crm-legacy:
+    type: salesforce
+    host: https://legacy.my.salesforce.com
+    enabled: false
+  warehouse:
+    type: postgres
+    host: localhost
+    port: 5432
+    database: analytics
+    username: analyst
+    environment: prod
+`
+
+// callToolErrorCode calls a tool expected to fail and returns the code in
+// its error result's _meta
+func callToolErrorCode(t *testing.T, s *Server, name string, args map[string]interface{}) ErrorCode {
+	t.Helper()
+	params, err := json.Marshal(map[string]interface{}{"name": name, "arguments": args})
+	testutil.AssertNoError(t, err)
+	message := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":%s}`, params)
+
+	data, err := json.Marshal(s.mcpServer.HandleMessage(context.Background(), []byte(message)))
+	testutil.AssertNoError(t, err)
+
+	var decoded struct {
+		Result struct {
+			Meta struct {
+				ErrorCode ErrorCode `json:"error_code"`
+			} `json:"_meta"`
+			IsError bool `json:"isError"`
+		} `json:"result"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal(data, &decoded))
+	if !decoded.Result.IsError {
+		t.Fatalf("Expected %s to return an error result, got %s", name, data)
+	}
+	return decoded.Result.Meta.ErrorCode
+}
+
+func TestToolErrorCodes(t *testing.T) {
+	testutil.WriteConfigFile(t, errorCodesConfig)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	tests := []struct {
+		name string
+		tool string
+		args map[string]interface{}
+		want ErrorCode
+	}{
+		{"missing parameter", "list_tables", nil, CodeInvalidArgument},
+		{"unknown connection", "list_databases", map[string]interface{}{"connection": "nope"}, CodeConnectionNotFound},
+		{"disabled connection", "list_databases", map[string]interface{}{"connection": "crm-legacy"}, CodeConnectionDisabled},
+		{"wrong environment", "list_databases", map[string]interface{}{"connection": "warehouse", "environment": "staging"}, CodeEnvironmentMismatch},
+		{"unsupported type", "count_rows", map[string]interface{}{"connection": "warehouse", "table": "orders"}, CodeUnsupported},
+		{"invalid argument", "ping_all", map[string]interface{}{"timeout_ms": -1}, CodeInvalidArgument},
+		{"unknown call", "cancel_query", map[string]interface{}{"request_id": "42"}, CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.AssertEqual(t, tt.want, callToolErrorCode(t, s, tt.tool, tt.args))
+		})
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"attached code survives wrapping", fmt.Errorf("outer: %w", connectionNotFound("prod")), CodeConnectionNotFound},
+		{"scrubbed error keeps its cause", &scrubbedError{message: "masked", cause: missingParameter("table")}, CodeInvalidArgument},
+		{"data access disabled", fmt.Errorf("failed to get table sample: %w", config.ErrDataAccessDisabled), CodeDataAccessDisabled},
+		{"glue sampling disabled", database.ErrGlueSamplingDisabled, CodeDataAccessDisabled},
+		{"invalid identifier", fmt.Errorf("failed to list tables: %w", database.ErrInvalidIdentifier), CodeInvalidArgument},
+		{"ambiguous table", fmt.Errorf("%w: table \"events\" exists in schemas archive, tracking", database.ErrAmbiguousTable), CodeInvalidArgument},
+		{"cancelled", fmt.Errorf("failed to list tables: %w", context.Canceled), CodeCancelled},
+		{"timeout", fmt.Errorf("failed to list tables: %w", context.DeadlineExceeded), CodeTimeout},
+		{"auth failed", &mysql.MySQLError{Number: 1045, Message: "Access denied for user 'app'@'10.0.0.1'"}, CodeAuthFailed},
+		{"permission denied", &mysql.MySQLError{Number: 1142, Message: "SELECT command denied to user 'app'"}, CodePermissionDenied},
+		{"anything else", errors.New("something broke"), CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.AssertEqual(t, tt.want, errorCode(tt.err))
+		})
+	}
+}
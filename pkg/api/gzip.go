@@ -0,0 +1,161 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// gzipMinSize is the smallest response body worth compressing
+const gzipMinSize = 1024
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// gzipHandler compresses responses for clients that send Accept-Encoding: gzip.
+// Bodies smaller than minSize are sent as-is, and event streams are passed
+// through untouched so streamed responses are still flushed as they arrive.
+func gzipHandler(next http.Handler, minSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize}
+		defer gw.close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		fields := strings.Split(part, ";")
+		if strings.TrimSpace(fields[0]) != "gzip" {
+			continue
+		}
+		for _, param := range fields[1:] {
+			if q, found := strings.CutPrefix(strings.TrimSpace(param), "q="); found {
+				if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response until it knows whether
+// the body is large enough to compress, then commits to plain or gzip output.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize     int
+	status      int
+	buffer      bytes.Buffer
+	gz          *gzip.Writer
+	committed   bool
+	passthrough bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.committed || w.status != 0 {
+		return
+	}
+	w.status = status
+
+	// Streams, bodiless and already-encoded responses are never compressed
+	if w.Header().Get("Content-Encoding") != "" ||
+		strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") ||
+		status == http.StatusNoContent || status == http.StatusNotModified || status < http.StatusOK {
+		w.commit(false)
+	}
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.committed {
+		if w.passthrough {
+			return w.ResponseWriter.Write(data)
+		}
+		return w.gz.Write(data)
+	}
+
+	w.buffer.Write(data)
+	if w.buffer.Len() >= w.minSize {
+		if err := w.commit(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+// Flush sends buffered output to the client. A response flushed before it
+// reaches minSize is committed uncompressed.
+func (w *gzipResponseWriter) Flush() {
+	if !w.committed {
+		if w.status == 0 {
+			w.WriteHeader(http.StatusOK)
+		}
+		w.commit(false)
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// commit writes the headers and any buffered body, either plain or compressed
+func (w *gzipResponseWriter) commit(compress bool) error {
+	if w.committed {
+		return nil
+	}
+	w.committed = true
+	w.passthrough = !compress
+
+	if compress {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		w.gz = gzipWriterPool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	data := w.buffer.Bytes()
+	w.buffer.Reset()
+	if compress {
+		_, err := w.gz.Write(data)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+// close finishes the response, sending small bodies uncompressed
+func (w *gzipResponseWriter) close() {
+	if !w.committed {
+		w.commit(false)
+	}
+	if w.gz != nil {
+		w.gz.Close()
+		gzipWriterPool.Put(w.gz)
+		w.gz = nil
+	}
+}
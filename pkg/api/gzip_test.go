@@ -0,0 +1,79 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func serveWithGzip(t *testing.T, body string, contentType string, acceptEncoding string) *http.Response {
+	t.Helper()
+
+	handler := gzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, body)
+	}), gzipMinSize)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Result()
+}
+
+func TestGzipLargeResponse(t *testing.T) {
+	body := strings.Repeat(`{"id":1,"name":"sample row"},`, 200)
+
+	resp := serveWithGzip(t, body, "application/json", "gzip, deflate")
+	testutil.AssertEqual(t, "gzip", resp.Header.Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(resp.Body)
+	testutil.AssertNoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, body, string(decompressed))
+}
+
+func TestGzipWithoutAcceptEncoding(t *testing.T) {
+	body := strings.Repeat(`{"id":1,"name":"sample row"},`, 200)
+
+	resp := serveWithGzip(t, body, "application/json", "")
+	testutil.AssertEqual(t, "", resp.Header.Get("Content-Encoding"))
+
+	plain, err := io.ReadAll(resp.Body)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, body, string(plain))
+
+	resp = serveWithGzip(t, body, "application/json", "gzip;q=0")
+	testutil.AssertEqual(t, "", resp.Header.Get("Content-Encoding"))
+}
+
+func TestGzipSmallResponseNotCompressed(t *testing.T) {
+	body := `{"jsonrpc":"2.0","id":1,"result":{}}`
+
+	resp := serveWithGzip(t, body, "application/json", "gzip")
+	testutil.AssertEqual(t, "", resp.Header.Get("Content-Encoding"))
+
+	plain, err := io.ReadAll(resp.Body)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, body, string(plain))
+}
+
+func TestGzipEventStreamPassthrough(t *testing.T) {
+	body := "data: " + strings.Repeat("x", 2*gzipMinSize) + "\n\n"
+
+	resp := serveWithGzip(t, body, "text/event-stream", "gzip")
+	testutil.AssertEqual(t, "", resp.Header.Get("Content-Encoding"))
+
+	plain, err := io.ReadAll(resp.Body)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, body, string(plain))
+}
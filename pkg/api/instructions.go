@@ -0,0 +1,69 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+)
+
+// serverInstructions returns the instructions advertised to MCP clients on
+// initialize: settings.instructions when set, otherwise a summary of the
+// configured connections and the limits tools enforce
+func serverInstructions(cfg *config.Config) string {
+	if cfg.Settings.Instructions != "" {
+		return cfg.Settings.Instructions
+	}
+
+	var b strings.Builder
+	b.WriteString("simpledb-mcp explores databases through read-only tools: list databases and tables, describe them, and sample their data.\n")
+
+	var names []string
+	for _, name := range cfg.ListConnections() {
+		if cfg.Connections[name].IsEnabled() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		b.WriteString("\nNo connections are configured yet.\n")
+	} else {
+		b.WriteString("\nConnections (pass the name as the connection argument):\n")
+		for _, name := range names {
+			conn := cfg.Connections[name]
+			var notes []string
+			if conn.Environment != "" {
+				notes = append(notes, "environment "+conn.Environment)
+			}
+			if conn.MaxRows > 0 {
+				notes = append(notes, fmt.Sprintf("at most %d rows", conn.MaxRows))
+			}
+			if !conn.SamplingAllowed() {
+				notes = append(notes, "schema only, data reads are refused")
+			}
+			fmt.Fprintf(&b, "- %s (%s", name, conn.Type)
+			for _, note := range notes {
+				b.WriteString(", " + note)
+			}
+			b.WriteString(")\n")
+		}
+	}
+
+	b.WriteString("\nLimits:\n")
+	if cfg.Settings.SafeModeEnabled() {
+		b.WriteString("- Safe mode is on: every database session is read-only\n")
+	}
+	fmt.Fprintf(&b, "- Results are capped at %d rows (get_table_sample returns at most %d)\n", cfg.Settings.MaxRows, cfg.Settings.SampleRowLimit(config.Connection{}))
+	if cfg.Settings.MaxListItems > 0 {
+		fmt.Fprintf(&b, "- Lists of databases, schemas, tables, columns and indexes are capped at %d items; a cut list has truncated: true and its total\n", cfg.Settings.MaxListItems)
+	}
+	fmt.Fprintf(&b, "- Queries time out after %s\n", cfg.Settings.QueryTimeout)
+	for _, name := range names {
+		if cfg.Connections[name].Environment == "prod" || cfg.Connections[name].Environment == "production" {
+			b.WriteString("- Production connections are labeled with their environment; pass environment to make sure a call reaches the intended one\n")
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestServerInstructionsSummarizesConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Settings.MaxRows = 250
+	locked := false
+	cfg.Connections["billing"] = config.Connection{Type: "postgres", Host: "db.internal", Environment: "prod"}
+	cfg.Connections["analytics"] = config.Connection{Type: "mysql", Host: "localhost", AllowSampling: &locked}
+
+	instructions := serverInstructions(cfg)
+	testutil.AssertContains(t, instructions, "- analytics (mysql, schema only, data reads are refused)\n- billing (postgres, environment prod)\n")
+	testutil.AssertContains(t, instructions, "capped at 250 rows")
+	testutil.AssertContains(t, instructions, "Production connections")
+	// Hosts stay out of what the model sees
+	testutil.AssertEqual(t, false, strings.Contains(instructions, "db.internal"))
+}
+
+func TestServerInstructionsFromSettings(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Settings.Instructions = "Only query the reporting replica."
+	testutil.AssertEqual(t, "Only query the reporting replica.", serverInstructions(cfg))
+}
+
+func TestInitializeAdvertisesInstructions(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  orders-db:
+    type: mysql
+    host: localhost
+    port: 3306
+  ledger:
+    type: postgres
+    host: localhost
+    port: 5432
+    max_rows: 100
+settings:
+  max_rows: 500
+  sample_max_rows: 200
+`)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	response := s.mcpServer.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`))
+	data, err := json.Marshal(response)
+	testutil.AssertNoError(t, err)
+
+	var decoded struct {
+		Result struct {
+			Instructions string `json:"instructions"`
+		} `json:"result"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal(data, &decoded))
+	testutil.AssertContains(t, decoded.Result.Instructions, "- orders-db (mysql)")
+	testutil.AssertContains(t, decoded.Result.Instructions, "- ledger (postgres, at most 100 rows)")
+	testutil.AssertContains(t, decoded.Result.Instructions, "capped at 500 rows (get_table_sample returns at most 200)")
+}
@@ -0,0 +1,20 @@
+package api
+
+// capList cuts a list tool's items to settings.max_list_items (0 means no
+// cap), returning the kept items and how many there were
+func capList[T any](items []T, max int) ([]T, int) {
+	total := len(items)
+	if max > 0 && total > max {
+		return items[:max], total
+	}
+	return items, total
+}
+
+// markTruncated tells clients that a list was cut, and how long it was, so
+// they can narrow the request
+func markTruncated(result map[string]interface{}, shown, total int) {
+	if shown < total {
+		result["truncated"] = true
+		result["total"] = total
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/database"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+func TestCapList(t *testing.T) {
+	items, total := capList([]string{"a", "b", "c"}, 2)
+	testutil.AssertEqual(t, 2, len(items))
+	testutil.AssertEqual(t, 3, total)
+
+	items, total = capList([]string{"a", "b"}, 2)
+	testutil.AssertEqual(t, 2, len(items))
+	testutil.AssertEqual(t, 2, total)
+
+	// 0 means no cap
+	items, _ = capList([]string{"a", "b", "c"}, 0)
+	testutil.AssertEqual(t, 3, len(items))
+}
+
+func TestDescribeTableTruncatesLongColumnLists(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  warehouse:
+    type: postgres
+    host: localhost
+    port: 5432
+settings:
+  max_list_items: 3
+`)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	columnCount := 5
+	s.describe = func(conn config.Connection, connectionName, databaseName, tableName, schema string, withDefinition bool) (tableDescription, error) {
+		columns := make([]database.ColumnInfo, columnCount)
+		for i := range columns {
+			columns[i] = database.ColumnInfo{Name: fmt.Sprintf("c%d", i), Type: "integer"}
+		}
+		return tableDescription{Columns: columns, ObjectType: database.ObjectTypeTable}, nil
+	}
+
+	var result struct {
+		Columns   []database.ColumnInfo `json:"columns"`
+		Truncated *bool                 `json:"truncated"`
+		Total     *int                  `json:"total"`
+	}
+	describe := func() {
+		result.Truncated, result.Total = nil, nil
+		text, err := callTool(t, s, "describe_table", map[string]interface{}{"connection": "warehouse", "database": "analytics", "table": "wide"})
+		testutil.AssertNoError(t, err)
+		testutil.AssertNoError(t, json.Unmarshal([]byte(text), &result))
+	}
+
+	describe()
+	testutil.AssertEqual(t, 3, len(result.Columns))
+	testutil.AssertEqual(t, "c2", result.Columns[2].Name)
+	testutil.AssertEqual(t, true, *result.Truncated)
+	testutil.AssertEqual(t, 5, *result.Total)
+
+	// A list within the cap carries no truncation metadata
+	columnCount = 3
+	describe()
+	testutil.AssertEqual(t, 3, len(result.Columns))
+	if result.Truncated != nil || result.Total != nil {
+		t.Errorf("Expected no truncation metadata, got truncated=%v total=%v", result.Truncated, result.Total)
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// timingMiddleware logs the duration and request/response sizes of every tool
+// call, and emits a warning when a call takes longer than slowThreshold.
+// A zero threshold disables the slow-call warning.
+func timingMiddleware(slowThreshold time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+			elapsed := time.Since(start)
+
+			toolName := request.Params.Name
+			connectionName := mcp.ParseString(request, "connection", "")
+			requestSize := argumentsSize(request)
+			responseSize := resultSize(result)
+
+			log.Printf("Tool call '%s' (connection: '%s') took %s (request: %d bytes, response: %d bytes)",
+				toolName, connectionName, elapsed, requestSize, responseSize)
+
+			if slowThreshold > 0 && elapsed > slowThreshold {
+				log.Printf("WARNING: slow tool call '%s' (connection: '%s') took %s (threshold: %s)",
+					toolName, connectionName, elapsed, slowThreshold)
+			}
+
+			return result, err
+		}
+	}
+}
+
+// argumentsSize returns the JSON-encoded size of the tool call arguments
+func argumentsSize(request mcp.CallToolRequest) int {
+	if request.Params.Arguments == nil {
+		return 0
+	}
+	data, err := json.Marshal(request.Params.Arguments)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// resultSize returns the total size of the text content in a tool result
+func resultSize(result *mcp.CallToolResult) int {
+	if result == nil {
+		return 0
+	}
+	size := 0
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			size += len(text.Text)
+		}
+	}
+	return size
+}
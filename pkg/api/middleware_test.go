@@ -0,0 +1,91 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// captureLog redirects the standard logger into a buffer for the duration of a test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() {
+		log.SetOutput(original)
+	})
+	return &buf
+}
+
+func sampleRequest() mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_table_sample"
+	request.Params.Arguments = map[string]any{
+		"connection": "test-mysql",
+		"table":      "users",
+	}
+	return request
+}
+
+func TestTimingMiddlewareSlowCall(t *testing.T) {
+	buf := captureLog(t)
+
+	slow := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		time.Sleep(50 * time.Millisecond)
+		return mcp.NewToolResultText("slow result"), nil
+	}
+
+	handler := timingMiddleware(10 * time.Millisecond)(slow)
+	result, err := handler(context.Background(), sampleRequest())
+	testutil.AssertNoError(t, err)
+	if result == nil {
+		t.Fatal("Expected non-nil result")
+	}
+
+	output := buf.String()
+	testutil.AssertContains(t, output, "WARNING: slow tool call 'get_table_sample'")
+	testutil.AssertContains(t, output, "connection: 'test-mysql'")
+	testutil.AssertContains(t, output, "response: 11 bytes")
+}
+
+func TestTimingMiddlewareFastCall(t *testing.T) {
+	buf := captureLog(t)
+
+	fast := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	handler := timingMiddleware(time.Second)(fast)
+	_, err := handler(context.Background(), sampleRequest())
+	testutil.AssertNoError(t, err)
+
+	output := buf.String()
+	testutil.AssertContains(t, output, "Tool call 'get_table_sample'")
+	if strings.Contains(output, "WARNING") {
+		t.Errorf("Expected no slow-call warning, got %q", output)
+	}
+}
+
+func TestTimingMiddlewareDisabledThreshold(t *testing.T) {
+	buf := captureLog(t)
+
+	slow := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		time.Sleep(10 * time.Millisecond)
+		return nil, nil
+	}
+
+	handler := timingMiddleware(0)(slow)
+	_, err := handler(context.Background(), sampleRequest())
+	testutil.AssertNoError(t, err)
+
+	if strings.Contains(buf.String(), "WARNING") {
+		t.Errorf("Expected no warning when threshold is disabled, got %q", buf.String())
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Resource URIs. A Postgres table may be written schema.table; other tables
+// are looked up by name.
+const (
+	tablesResourceTemplate = "simpledb://{connection}/{database}/tables"
+	schemaResourceTemplate = "simpledb://{connection}/{database}/schema/{table}"
+)
+
+// tablesResourceURI is the table list resource of one database
+func tablesResourceURI(connectionName, databaseName string) string {
+	return fmt.Sprintf("simpledb://%s/%s/tables", url.PathEscape(connectionName), url.PathEscape(databaseName))
+}
+
+// registerResources exposes table lists and table schemas as MCP resources
+// backed by the same lookups as list_tables and describe_table. Each kind is
+// only registered while its tool is enabled, so disabled_tools covers both.
+func (s *Server) registerResources(enabled []server.ServerTool) {
+	toolEnabled := make(map[string]bool, len(enabled))
+	for _, tool := range enabled {
+		toolEnabled[tool.Tool.Name] = true
+	}
+
+	if toolEnabled["list_tables"] {
+		// Connections with a known database get a concrete entry in resources/list
+		names := s.config.ListConnections()
+		sort.Strings(names)
+		for _, name := range names {
+			databaseName := resourceDatabase(name, s.config.Connections[name])
+			if databaseName == "" || !s.config.Connections[name].IsEnabled() {
+				continue
+			}
+			s.mcpServer.AddResource(
+				mcp.NewResource(tablesResourceURI(name, databaseName), fmt.Sprintf("%s/%s tables", name, databaseName),
+					mcp.WithResourceDescription(fmt.Sprintf("Tables in database %s on connection %s", databaseName, name)),
+					mcp.WithMIMEType("application/json"),
+				),
+				s.handleTablesResource,
+			)
+		}
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(tablesResourceTemplate, "Table list",
+				mcp.WithTemplateDescription("Tables in a database, as returned by list_tables"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.handleTablesResource,
+		)
+	}
+
+	if toolEnabled["describe_table"] {
+		s.mcpServer.AddResourceTemplate(
+			mcp.NewResourceTemplate(schemaResourceTemplate, "Table schema",
+				mcp.WithTemplateDescription("Columns of a table, as returned by describe_table"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			s.handleSchemaResource,
+		)
+	}
+}
+
+// resourceDatabase is the database a connection's resources are listed under:
+// the configured database, or the connection name for Salesforce
+func resourceDatabase(connectionName string, conn config.Connection) string {
+	if conn.Type == "salesforce" {
+		return connectionName
+	}
+	return conn.Database
+}
+
+func (s *Server) handleTablesResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	connectionName, databaseName, err := resourceTarget(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+	}
+	if err := conn.CheckEnabled(connectionName); err != nil {
+		return nil, err
+	}
+
+	tables, err := s.listTables(conn, connectionName, databaseName, "")
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"connection": connectionName,
+		"database":   databaseName,
+		"tables":     tables,
+		"count":      len(tables),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return jsonResource(request.Params.URI, jsonData), nil
+}
+
+func (s *Server) handleSchemaResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	connectionName, databaseName, err := resourceTarget(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+	}
+	if err := conn.CheckEnabled(connectionName); err != nil {
+		return nil, err
+	}
+
+	tableName := resourceSegment(request.Params.URI, 3)
+	if tableName == "" {
+		return nil, fmt.Errorf("resource URI %s has no table", request.Params.URI)
+	}
+	schema := ""
+	if conn.Type == "postgres" {
+		if before, after, found := strings.Cut(tableName, "."); found {
+			schema, tableName = before, after
+		}
+	}
+
+	jsonData, err := s.describeTableJSON(conn, connectionName, databaseName, tableName, schema, false)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResource(request.Params.URI, jsonData), nil
+}
+
+// resourceTarget returns the connection and database named by a resource URI
+func resourceTarget(uri string) (string, string, error) {
+	if !strings.HasPrefix(uri, "simpledb://") {
+		return "", "", fmt.Errorf("unsupported resource URI: %s", uri)
+	}
+	connectionName, databaseName := resourceSegment(uri, 0), resourceSegment(uri, 1)
+	if connectionName == "" || databaseName == "" {
+		return "", "", fmt.Errorf("resource URI %s must name a connection and database", uri)
+	}
+	return connectionName, databaseName, nil
+}
+
+// resourceSegment returns the unescaped path segment at index i of a
+// simpledb:// URI, counting the connection as segment 0
+func resourceSegment(uri string, i int) string {
+	segments := strings.Split(strings.TrimPrefix(uri, "simpledb://"), "/")
+	if i >= len(segments) {
+		return ""
+	}
+	segment, err := url.PathUnescape(segments[i])
+	if err != nil {
+		return ""
+	}
+	return segment
+}
+
+func jsonResource(uri string, data []byte) []mcp.ResourceContents {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)},
+	}
+}
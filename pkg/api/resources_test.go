@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/database"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+const resourcesConfig = `
+connections:
+  warehouse:
+    type: postgres
+    host: localhost
+    port: 5432
+    database: analytics
+  crm:
+    type: salesforce
+    host: https://example.my.salesforce.com
+  adhoc:
+    type: mysql
+    host: localhost
+    port: 3306
+`
+
+// rpc sends one JSON-RPC request to the server and decodes its result into out
+func rpc(t *testing.T, s *Server, method string, params interface{}, out interface{}) error {
+	t.Helper()
+	encoded, err := json.Marshal(params)
+	testutil.AssertNoError(t, err)
+	message := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"%s","params":%s}`, method, encoded)
+
+	data, err := json.Marshal(s.mcpServer.HandleMessage(context.Background(), []byte(message)))
+	testutil.AssertNoError(t, err)
+	var decoded struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal(data, &decoded))
+	if decoded.Error != nil {
+		return fmt.Errorf("%s", decoded.Error.Message)
+	}
+	testutil.AssertNoError(t, json.Unmarshal(decoded.Result, out))
+	return nil
+}
+
+func TestResourceList(t *testing.T) {
+	testutil.WriteConfigFile(t, resourcesConfig)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	var resources struct {
+		Resources []struct {
+			URI string `json:"uri"`
+		} `json:"resources"`
+	}
+	testutil.AssertNoError(t, rpc(t, s, "resources/list", map[string]interface{}{}, &resources))
+	var uris []string
+	for _, r := range resources.Resources {
+		uris = append(uris, r.URI)
+	}
+	// adhoc has no configured database, so it is only reachable through the template
+	testutil.AssertEqual(t, 2, len(uris))
+	testutil.AssertContains(t, fmt.Sprint(uris), "simpledb://warehouse/analytics/tables")
+	testutil.AssertContains(t, fmt.Sprint(uris), "simpledb://crm/crm/tables")
+
+	var templates struct {
+		ResourceTemplates []struct {
+			URITemplate string `json:"uriTemplate"`
+		} `json:"resourceTemplates"`
+	}
+	testutil.AssertNoError(t, rpc(t, s, "resources/templates/list", map[string]interface{}{}, &templates))
+	var patterns []string
+	for _, r := range templates.ResourceTemplates {
+		patterns = append(patterns, r.URITemplate)
+	}
+	testutil.AssertContains(t, fmt.Sprint(patterns), schemaResourceTemplate)
+	testutil.AssertContains(t, fmt.Sprint(patterns), tablesResourceTemplate)
+}
+
+func TestReadSchemaResource(t *testing.T) {
+	testutil.WriteConfigFile(t, resourcesConfig)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	var described []string
+	s.describe = func(conn config.Connection, connectionName, databaseName, tableName, schema string, withDefinition bool) (tableDescription, error) {
+		described = append(described, fmt.Sprintf("%s/%s/%s.%s", connectionName, databaseName, schema, tableName))
+		return tableDescription{Columns: []database.ColumnInfo{{Name: "id", Type: "integer", IsPrimaryKey: true}}}, nil
+	}
+
+	var read struct {
+		Contents []struct {
+			URI      string `json:"uri"`
+			MIMEType string `json:"mimeType"`
+			Text     string `json:"text"`
+		} `json:"contents"`
+	}
+	uri := "simpledb://warehouse/analytics/schema/sales.orders"
+	testutil.AssertNoError(t, rpc(t, s, "resources/read", map[string]interface{}{"uri": uri}, &read))
+	testutil.AssertEqual(t, 1, len(read.Contents))
+	testutil.AssertEqual(t, uri, read.Contents[0].URI)
+	testutil.AssertEqual(t, "application/json", read.Contents[0].MIMEType)
+	testutil.AssertEqual(t, "warehouse/analytics/sales.orders", described[0])
+
+	// The document is what describe_table returns
+	text, err := callTool(t, s, "describe_table", map[string]interface{}{
+		"connection": "warehouse", "database": "analytics", "table": "orders", "schema": "sales",
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, text, read.Contents[0].Text)
+}
+
+func TestResourcesFollowDisabledTools(t *testing.T) {
+	testutil.WriteConfigFile(t, resourcesConfig+`
+settings:
+  disabled_tools: [list_tables, describe_table]
+`)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	var read struct{}
+	err = rpc(t, s, "resources/read", map[string]interface{}{"uri": "simpledb://warehouse/analytics/schema/orders"}, &read)
+	testutil.AssertError(t, err)
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxResultCacheEntries bounds the cache; beyond it the entry closest to
+// expiry makes room
+const maxResultCacheEntries = 256
+
+// noCacheDescription documents the no_cache argument added to cacheable tools
+const noCacheDescription = "Skip the result cache and query the database again (the fresh result replaces the cached one)"
+
+// liveStateTools report or change the state of the server itself, or what
+// the database is doing right now, rather than read database contents, so
+// their results are never cached
+var liveStateTools = map[string]bool{
+	"get_connection_status": true,
+	"list_active_queries":   true,
+	"get_pool_metrics":      true,
+	"ping_all":              true,
+	"cancel_query":          true,
+	"close_connection":      true,
+}
+
+type cachedResult struct {
+	result  *mcp.CallToolResult
+	expires time.Time
+}
+
+// resultCache keeps successful results of read-only tools for a short TTL,
+// keyed by tool name and arguments, so repeated identical calls don't query
+// the database again
+type resultCache struct {
+	ttl       time.Duration
+	now       func() time.Time
+	mu        sync.Mutex
+	cacheable map[string]bool
+	entries   map[string]cachedResult
+}
+
+func newResultCache(ttl time.Duration) *resultCache {
+	return &resultCache{
+		ttl:       ttl,
+		now:       time.Now,
+		cacheable: make(map[string]bool),
+		entries:   make(map[string]cachedResult),
+	}
+}
+
+// register marks the read-only tools among those registered as cacheable and
+// gives them the no_cache argument
+func (c *resultCache) register(tools []server.ServerTool) []server.ServerTool {
+	if c.ttl <= 0 {
+		return tools
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range tools {
+		name := tools[i].Tool.Name
+		if hint := tools[i].Tool.Annotations.ReadOnlyHint; hint == nil || !*hint || liveStateTools[name] {
+			continue
+		}
+		c.cacheable[name] = true
+		mcp.WithBoolean("no_cache", mcp.Description(noCacheDescription))(&tools[i].Tool)
+	}
+	return tools
+}
+
+// cacheKey identifies a call by tool name and arguments. Arguments are
+// re-encoded as JSON, which sorts object keys, so their order doesn't matter;
+// no_cache itself is left out.
+func cacheKey(request mcp.CallToolRequest) (string, bool) {
+	args := request.GetArguments()
+	normalized := make(map[string]any, len(args))
+	for name, value := range args {
+		if name != "no_cache" {
+			normalized[name] = value
+		}
+	}
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", false
+	}
+	return request.Params.Name + "\x00" + string(data), true
+}
+
+func (c *resultCache) get(key string) *mcp.CallToolResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+	return copyResult(entry.result)
+}
+
+func (c *resultCache) store(key string, result *mcp.CallToolResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= maxResultCacheEntries {
+		soonest := ""
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			} else if soonest == "" || entry.expires.Before(c.entries[soonest].expires) {
+				soonest = k
+			}
+		}
+		if len(c.entries) >= maxResultCacheEntries {
+			delete(c.entries, soonest)
+		}
+	}
+	c.entries[key] = cachedResult{result: copyResult(result), expires: now.Add(c.ttl)}
+}
+
+// copyResult copies a result's content list, so middleware that rewrites the
+// content of a returned result can't alter the cached one
+func copyResult(result *mcp.CallToolResult) *mcp.CallToolResult {
+	copied := *result
+	copied.Content = append([]mcp.Content(nil), result.Content...)
+	return &copied
+}
+
+// middleware answers repeated calls of cacheable tools from the cache. Only
+// successful results are stored; errors and error results always go through
+// to the next call. With no_cache the call always runs and refreshes the
+// cached result.
+func (c *resultCache) middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			c.mu.Lock()
+			cacheable := c.ttl > 0 && c.cacheable[request.Params.Name]
+			c.mu.Unlock()
+			if !cacheable {
+				return next(ctx, request)
+			}
+			key, ok := cacheKey(request)
+			if !ok {
+				return next(ctx, request)
+			}
+
+			if !mcp.ParseBoolean(request, "no_cache", false) {
+				if result := c.get(key); result != nil {
+					return result, nil
+				}
+			}
+
+			result, err := next(ctx, request)
+			if err == nil && result != nil && !result.IsError {
+				c.store(key, result)
+			}
+			return result, err
+		}
+	}
+}
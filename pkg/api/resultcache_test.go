@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// countingHandler returns a new result text on every call
+func countingHandler(calls *int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		*calls++
+		return mcp.NewToolResultText(fmt.Sprintf("result %d", *calls)), nil
+	}
+}
+
+// cacheFor returns a cache with get_table_sample and get_pool_metrics registered
+func cacheFor(ttl time.Duration) *resultCache {
+	cache := newResultCache(ttl)
+	cache.register([]server.ServerTool{
+		{Tool: readOnlyTool("get_table_sample", mcp.WithString("connection"))},
+		{Tool: readOnlyTool("get_pool_metrics")},
+	})
+	return cache
+}
+
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	return text.Text
+}
+
+func TestResultCacheHitAndMiss(t *testing.T) {
+	cache := cacheFor(time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	calls := 0
+	handler := cache.middleware()(countingHandler(&calls))
+
+	result, err := handler(context.Background(), sampleRequest())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "result 1", resultText(t, result))
+
+	// Same tool and arguments, in any order: served from the cache
+	request := sampleRequest()
+	request.Params.Arguments = map[string]any{"table": "users", "connection": "test-mysql"}
+	result, err = handler(context.Background(), request)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "result 1", resultText(t, result))
+	testutil.AssertEqual(t, 1, calls)
+
+	// Different arguments miss
+	request.Params.Arguments = map[string]any{"table": "users", "connection": "test-mysql", "limit": 5}
+	result, _ = handler(context.Background(), request)
+	testutil.AssertEqual(t, "result 2", resultText(t, result))
+
+	// Entries expire after the TTL
+	now = now.Add(time.Minute)
+	result, _ = handler(context.Background(), sampleRequest())
+	testutil.AssertEqual(t, "result 3", resultText(t, result))
+}
+
+func TestResultCacheNoCacheBypass(t *testing.T) {
+	cache := cacheFor(time.Minute)
+	calls := 0
+	handler := cache.middleware()(countingHandler(&calls))
+
+	handler(context.Background(), sampleRequest())
+
+	request := sampleRequest()
+	request.Params.Arguments = map[string]any{"connection": "test-mysql", "table": "users", "no_cache": true}
+	result, err := handler(context.Background(), request)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "result 2", resultText(t, result))
+
+	// The fresh result replaced the cached one
+	result, _ = handler(context.Background(), sampleRequest())
+	testutil.AssertEqual(t, "result 2", resultText(t, result))
+	testutil.AssertEqual(t, 2, calls)
+}
+
+func TestResultCacheSkipsErrorsAndLiveState(t *testing.T) {
+	cache := cacheFor(time.Minute)
+	calls := 0
+	failing := cache.middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection refused")
+		}
+		if calls == 2 {
+			return mcp.NewToolResultError("table not found"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	_, err := failing(context.Background(), sampleRequest())
+	testutil.AssertError(t, err)
+	result, _ := failing(context.Background(), sampleRequest())
+	testutil.AssertEqual(t, true, result.IsError)
+	result, _ = failing(context.Background(), sampleRequest())
+	testutil.AssertEqual(t, "ok", resultText(t, result))
+	testutil.AssertEqual(t, 3, calls)
+
+	// Live server state is never cached
+	calls = 0
+	handler := cache.middleware()(countingHandler(&calls))
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_pool_metrics"
+	handler(context.Background(), request)
+	handler(context.Background(), request)
+	testutil.AssertEqual(t, 2, calls)
+}
+
+func TestResultCacheDisabled(t *testing.T) {
+	cache := cacheFor(0)
+	calls := 0
+	handler := cache.middleware()(countingHandler(&calls))
+	handler(context.Background(), sampleRequest())
+	handler(context.Background(), sampleRequest())
+	testutil.AssertEqual(t, 2, calls)
+}
+
+func TestResultCacheRegisterAddsNoCacheArg(t *testing.T) {
+	tools := newResultCache(time.Minute).register([]server.ServerTool{
+		{Tool: readOnlyTool("describe_table", mcp.WithString("connection"))},
+		{Tool: readOnlyTool("ping_all")},
+	})
+	_, ok := tools[0].Tool.InputSchema.Properties["no_cache"]
+	testutil.AssertEqual(t, true, ok)
+	_, ok = tools[1].Tool.InputSchema.Properties["no_cache"]
+	testutil.AssertEqual(t, false, ok)
+}
+
+func TestResultCacheLeavesActiveQueriesLive(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  orders-db:
+    type: mysql
+    host: localhost
+    port: 3306
+settings:
+  result_cache_ttl: 1m
+`)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	// What is running now changes from call to call
+	testutil.AssertEqual(t, false, s.results.cacheable["list_active_queries"])
+	testutil.AssertEqual(t, true, s.results.cacheable["list_tables"])
+}
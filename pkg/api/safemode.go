@@ -0,0 +1,41 @@
+package api
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// readOnlyTool is mcp.NewTool for a tool that never writes to a database.
+// Only tools declared this way are registered in safe mode; a tool built with
+// plain mcp.NewTool is treated as write-capable.
+func readOnlyTool(name string, opts ...mcp.ToolOption) mcp.Tool {
+	opts = append(opts, mcp.WithReadOnlyHintAnnotation(true), mcp.WithDestructiveHintAnnotation(false))
+	return mcp.NewTool(name, opts...)
+}
+
+// readOnlyTools splits tools into those declared read-only and the names of the rest
+func readOnlyTools(tools []server.ServerTool) ([]server.ServerTool, []string) {
+	allowed := make([]server.ServerTool, 0, len(tools))
+	refused := []string{}
+	for _, tool := range tools {
+		if hint := tool.Tool.Annotations.ReadOnlyHint; hint != nil && *hint {
+			allowed = append(allowed, tool)
+		} else {
+			refused = append(refused, tool.Tool.Name)
+		}
+	}
+	return allowed, refused
+}
+
+// safeModeInfo describes the enforced safe mode posture for GetInfo
+func (s *Server) safeModeInfo() map[string]interface{} {
+	enabled := s.config.Settings.SafeModeEnabled()
+	info := map[string]interface{}{
+		"enabled":            enabled,
+		"read_only_sessions": enabled,
+	}
+	if enabled {
+		info["refused_tools"] = s.refusedTools
+	}
+	return info
+}
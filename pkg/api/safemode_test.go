@@ -0,0 +1,53 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestReadOnlyToolsRefusesUndeclaredTools(t *testing.T) {
+	tools := []server.ServerTool{
+		{Tool: readOnlyTool("describe_table")},
+		{Tool: mcp.NewTool("drop_table")},
+	}
+
+	allowed, refused := readOnlyTools(tools)
+	testutil.AssertEqual(t, 1, len(allowed))
+	testutil.AssertEqual(t, "describe_table", allowed[0].Tool.Name)
+	testutil.AssertEqual(t, 1, len(refused))
+	testutil.AssertEqual(t, "drop_table", refused[0])
+}
+
+func TestSafeModeDefaultsOnForHTTP(t *testing.T) {
+	testutil.WriteConfigFile(t, "connections: {}\n")
+
+	s, err := NewServerWithFlags("http", ":0", "/mcp")
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	safeMode := s.GetInfo()["safe_mode"].(map[string]interface{})
+	testutil.AssertEqual(t, true, safeMode["enabled"])
+	testutil.AssertEqual(t, true, safeMode["read_only_sessions"])
+	testutil.AssertEqual(t, 0, len(safeMode["refused_tools"].([]string)))
+
+	// Every built-in tool is declared read-only, so all of them stay available
+	testutil.AssertContains(t, strings.Join(registeredTools(t, s), ","), "get_table_sample")
+}
+
+func TestSafeModeOffForStdioUnlessSet(t *testing.T) {
+	testutil.WriteConfigFile(t, "connections: {}\n")
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+	testutil.AssertEqual(t, false, s.GetInfo()["safe_mode"].(map[string]interface{})["enabled"])
+
+	testutil.WriteConfigFile(t, "connections: {}\nsettings:\n  safe_mode: true\n")
+	s, err = NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+	testutil.AssertEqual(t, true, s.GetInfo()["safe_mode"].(map[string]interface{})["enabled"])
+}
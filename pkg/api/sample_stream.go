@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/eliziario/simpledb-mcp/internal/database"
+)
+
+// sampleStream is a database.SampleWriter that encodes a get_table_sample
+// result in the objects shape while rows are scanned, so a sample is never
+// held as both row maps and JSON. The output is byte-for-byte what marshaling
+// the whole result map would produce. It does not stream to the client: the
+// MCP result is a single text content, so the JSON is written to memory and
+// sent once complete. Memory is bounded instead by maxBytes: a row that would
+// take the encoded rows past it is refused with database.ErrSampleFull, which
+// ends the sample early. Only the closing fields written by finish, a few
+// hundred bytes, can go past maxBytes.
+//
+// Capping columns needs every row to decide which columns to keep, so when the
+// table is wider than maxColumns the stream collects the rows instead and
+// buffered reports true; sample then returns them for the regular path. The
+// collected rows are held to maxBytes by their encoded size.
+type sampleStream struct {
+	w          io.Writer
+	connection string
+	maxColumns int
+	maxBytes   int
+
+	buffering bool
+	columns   []string
+	rows      []map[string]interface{}
+	written   int
+	size      int
+	err       error
+}
+
+func newSampleStream(w io.Writer, connection string, maxColumns, maxBytes int) *sampleStream {
+	return &sampleStream{w: w, connection: connection, maxColumns: maxColumns, maxBytes: maxBytes}
+}
+
+func (s *sampleStream) WriteColumns(columns []string) error {
+	s.columns = columns
+	if s.maxColumns > 0 && len(columns) > s.maxColumns {
+		s.buffering = true
+		return nil
+	}
+
+	// "connection" is the only result key that sorts before "data"
+	connection, err := json.Marshal(s.connection)
+	if err != nil {
+		return err
+	}
+	s.write([]byte(`{"connection":`))
+	s.write(connection)
+	s.write([]byte(`,"data":[`))
+	return s.err
+}
+
+func (s *sampleStream) WriteRow(row map[string]interface{}) error {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return fmt.Errorf("failed to marshal row: %w", err)
+	}
+	if s.maxBytes > 0 && s.size+len(data)+1 > s.maxBytes {
+		return database.ErrSampleFull
+	}
+	if s.buffering {
+		s.size += len(data) + 1
+		s.rows = append(s.rows, row)
+		return nil
+	}
+
+	if s.written > 0 {
+		s.write([]byte(","))
+	}
+	s.write(data)
+	s.written++
+	return s.err
+}
+
+// finish closes the data array and writes the remaining result fields, all of
+// which sort after "data"
+func (s *sampleStream) finish(rest map[string]interface{}) error {
+	tail, err := json.Marshal(rest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	s.write([]byte("],"))
+	s.write(tail[1:])
+	return s.err
+}
+
+// sample returns a buffered sample in the form the GetTableSample* methods use
+func (s *sampleStream) sample(summary *database.SampleSummary) map[string]interface{} {
+	sampleData := map[string]interface{}{
+		"columns":       s.columns,
+		"rows":          s.rows,
+		"total_sampled": len(s.rows),
+	}
+	if len(summary.ScanErrors) > 0 {
+		sampleData["scan_errors"] = summary.ScanErrors
+	}
+	if summary.Truncated {
+		sampleData["truncated"] = true
+	}
+	return sampleData
+}
+
+func (s *sampleStream) write(p []byte) {
+	if s.err == nil {
+		_, s.err = s.w.Write(p)
+		s.size += len(p)
+	}
+}
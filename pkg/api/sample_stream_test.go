@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/database"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+)
+
+// countingWriter records how many bytes have been written so far
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestSampleStreamWritesRowsIncrementally(t *testing.T) {
+	w := &countingWriter{}
+	stream := newSampleStream(w, "test-mysql", 0, 0)
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "<alice>"},
+		{"id": 2, "name": nil},
+		{"id": 3, "name": ""},
+	}
+
+	testutil.AssertNoError(t, stream.WriteColumns([]string{"id", "name"}))
+	written := w.Len()
+	for _, row := range rows {
+		testutil.AssertNoError(t, stream.WriteRow(row))
+		if w.Len() <= written {
+			t.Fatalf("Expected row to be written before the sample finished")
+		}
+		written = w.Len()
+	}
+	if len(stream.rows) != 0 {
+		t.Error("Expected the stream not to keep rows")
+	}
+
+	rest := map[string]interface{}{
+		"database":      "shop",
+		"table":         "users",
+		"schema":        "",
+		"limit":         10,
+		"result_shape":  database.SampleShapeObjects,
+		"total_sampled": 3,
+		"scan_errors":   []string{"row 4: bad"},
+	}
+	testutil.AssertNoError(t, stream.finish(rest))
+
+	// Identical to marshaling the whole result at once
+	whole := map[string]interface{}{"connection": "test-mysql", "data": rows}
+	for key, value := range rest {
+		whole[key] = value
+	}
+	expected, err := json.Marshal(whole)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, string(expected), w.String())
+}
+
+func TestSampleStreamEmptySample(t *testing.T) {
+	w := &countingWriter{}
+	stream := newSampleStream(w, "c", 0, 0)
+	testutil.AssertNoError(t, stream.WriteColumns([]string{"id"}))
+	testutil.AssertNoError(t, stream.finish(map[string]interface{}{"total_sampled": 0}))
+	testutil.AssertEqual(t, `{"connection":"c","data":[],"total_sampled":0}`, w.String())
+}
+
+func TestSampleStreamBuffersWideTables(t *testing.T) {
+	w := &countingWriter{}
+	stream := newSampleStream(w, "c", 1, 0)
+	testutil.AssertNoError(t, stream.WriteColumns([]string{"id", "name"}))
+	testutil.AssertNoError(t, stream.WriteRow(map[string]interface{}{"id": 1, "name": "a"}))
+
+	testutil.AssertEqual(t, true, stream.buffering)
+	testutil.AssertEqual(t, 0, w.writes)
+	sample := stream.sample(&database.SampleSummary{Rows: 1})
+	testutil.AssertEqual(t, 1, sample["total_sampled"])
+	testutil.AssertEqual(t, 2, len(sample["columns"].([]string)))
+}
+
+func TestSampleStreamMemoryStaysWithinMaxBytes(t *testing.T) {
+	const maxBytes = 1024
+	w := &countingWriter{}
+	stream := newSampleStream(w, "c", 0, maxBytes)
+	testutil.AssertNoError(t, stream.WriteColumns([]string{"id", "payload"}))
+
+	// Far more rows than fit: the stream refuses them once it is full, and
+	// never takes the written output past the cap
+	written := 0
+	for i := 0; i < 1000; i++ {
+		err := stream.WriteRow(map[string]interface{}{"id": i, "payload": strings.Repeat("x", 40)})
+		if errors.Is(err, database.ErrSampleFull) {
+			break
+		}
+		testutil.AssertNoError(t, err)
+		written++
+		if w.Len() > maxBytes {
+			t.Fatalf("Expected at most %d bytes, got %d after %d rows", maxBytes, w.Len(), written)
+		}
+	}
+	if written == 0 || written == 1000 {
+		t.Fatalf("Expected the cap to stop the sample part way, wrote %d rows", written)
+	}
+
+	testutil.AssertNoError(t, stream.finish(map[string]interface{}{"total_sampled": written, "truncated": true}))
+	var result map[string]interface{}
+	testutil.AssertNoError(t, json.Unmarshal(w.Bytes(), &result))
+	testutil.AssertEqual(t, written, len(result["data"].([]interface{})))
+	testutil.AssertEqual(t, true, result["truncated"])
+}
+
+func TestSampleStreamBufferedRowsStayWithinMaxBytes(t *testing.T) {
+	stream := newSampleStream(&countingWriter{}, "c", 1, 64)
+	testutil.AssertNoError(t, stream.WriteColumns([]string{"id", "name"}))
+	testutil.AssertNoError(t, stream.WriteRow(map[string]interface{}{"id": 1, "name": "alice"}))
+	err := stream.WriteRow(map[string]interface{}{"id": 2, "name": strings.Repeat("b", 64)})
+	if !errors.Is(err, database.ErrSampleFull) {
+		t.Fatalf("Expected ErrSampleFull, got %v", err)
+	}
+
+	sample := stream.sample(&database.SampleSummary{Rows: 1, Truncated: true})
+	testutil.AssertEqual(t, 1, sample["total_sampled"])
+	testutil.AssertEqual(t, true, sample["truncated"])
+}
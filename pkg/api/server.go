@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/eliziario/simpledb-mcp/internal/config"
 	"github.com/eliziario/simpledb-mcp/internal/credentials"
 	"github.com/eliziario/simpledb-mcp/internal/database"
+	"github.com/eliziario/simpledb-mcp/internal/logging"
+	"github.com/eliziario/simpledb-mcp/internal/version"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -21,6 +26,29 @@ type Server struct {
 	mcpServer     *server.MCPServer
 	httpServer    *server.StreamableHTTPServer
 	stdHTTPServer *http.Server
+	calls         *callRegistry
+
+	// results caches read-only tool results for settings.result_cache_ttl
+	results *resultCache
+
+	// pii redacts pii_patterns matches from sampled values (nil when none are configured)
+	pii *database.PIIRedactor
+
+	// refusedTools lists tools safe mode kept from registering
+	refusedTools []string
+
+	// describe looks up a table's or view's columns for describe_table and
+	// the schema resources; tests replace it to avoid a live database
+	describe func(conn config.Connection, connectionName, databaseName, tableName, schema string, withDefinition bool) (tableDescription, error)
+}
+
+// tableDescription is what describe_table reports about a table or view
+type tableDescription struct {
+	Columns []database.ColumnInfo
+	// ObjectType is table or view for MySQL and PostgreSQL, "" for the rest
+	ObjectType string
+	// Definition is a PostgreSQL view's query, when asked for
+	Definition string
 }
 
 // Tool argument structures
@@ -41,6 +69,13 @@ type ListTablesArgs struct {
 	Schema     string `json:"schema,omitempty"`
 }
 
+type DescribeDatabaseArgs struct {
+	Connection string `json:"connection"`
+	Database   string `json:"database"`
+	Schema     string `json:"schema,omitempty"`
+	MaxTables  int    `json:"max_tables,omitempty"`
+}
+
 type DescribeTableArgs struct {
 	Connection string `json:"connection"`
 	Database   string `json:"database"`
@@ -69,6 +104,10 @@ type GetConnectionStatusArgs struct {
 
 type GetPoolMetricsArgs struct{}
 
+type CancelQueryArgs struct {
+	RequestID string `json:"request_id"`
+}
+
 func NewServer() (*Server, error) {
 	return NewServerWithFlags("", "", "")
 }
@@ -91,18 +130,42 @@ func NewServerWithFlags(transport, address, path string) (*Server, error) {
 		cfg.Settings.Server.Path = path
 	}
 
+	if cfg.Settings.RedactConnectionDetailsInLogs {
+		log.SetOutput(logging.NewRedactingWriter(log.Writer(), logging.NewRedactor(cfg.Connections)))
+	}
+
+	pii, err := database.NewPIIRedactor(cfg.Settings.PIIPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pii_patterns: %w", err)
+	}
+
 	// Initialize credential manager
-	credManager := credentials.NewManager(cfg.Settings.CacheCredentials)
+	credManager := credentials.NewProvider(cfg.Settings.CacheCredentials, cfg.Settings.CredentialFallbackFile())
+	credManager.SetRequireBiometric(cfg.Settings.RequireBiometric)
 
 	// Initialize database manager
 	dbManager := database.NewManager(cfg, credManager)
 
+	// Registry of in-flight tool calls that HTTP clients can cancel
+	calls := newCallRegistry()
+	results := newResultCache(cfg.Settings.ResultCacheTTL)
+
 	// Create MCP server using the new framework
 	mcpServer := server.NewMCPServer(
 		"simpledb-mcp",
-		"0.1.0",
+		version.Number(),
 		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(false, false),
+		server.WithInstructions(serverInstructions(cfg)),
 		server.WithRecovery(),
+		server.WithToolHandlerMiddleware(errorCodesMiddleware()),
+		server.WithToolHandlerMiddleware(scrubErrorsMiddleware()),
+		server.WithToolHandlerMiddleware(timingMiddleware(cfg.Settings.SlowQueryThreshold)),
+		server.WithToolHandlerMiddleware(calls.middleware()),
+		server.WithToolHandlerMiddleware(enabledMiddleware(cfg)),
+		server.WithToolHandlerMiddleware(environmentMiddleware(cfg)),
+		server.WithToolHandlerMiddleware(results.middleware()),
+		server.WithToolHandlerMiddleware(newConcurrencyLimiter(cfg).middleware()),
 	)
 
 	serverInstance := &Server{
@@ -110,7 +173,11 @@ func NewServerWithFlags(transport, address, path string) (*Server, error) {
 		dbManager:   dbManager,
 		credManager: credManager,
 		mcpServer:   mcpServer,
+		calls:       calls,
+		results:     results,
+		pii:         pii,
 	}
+	serverInstance.describe = serverInstance.describeObject
 
 	// Create HTTP server if needed
 	if cfg.Settings.Server.Transport == "http" {
@@ -120,15 +187,33 @@ func NewServerWithFlags(transport, address, path string) (*Server, error) {
 			server.WithStateLess(true), // Disable sessions for compatibility
 		)
 
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Settings.Server.Path, serverInstance.calls.trackCalls(httpServer))
+		mux.HandleFunc(cfg.Settings.Server.Path+"/cancel", serverInstance.calls.handleCancelEndpoint)
+
 		stdHTTPServer := &http.Server{
 			Addr:    cfg.Settings.Server.Address,
-			Handler: httpServer,
+			Handler: gzipHandler(mux, gzipMinSize),
 		}
 
 		serverInstance.httpServer = httpServer
 		serverInstance.stdHTTPServer = stdHTTPServer
 	}
 
+	// Create WebSocket server if needed
+	if cfg.Settings.Server.Transport == "websocket" {
+		wsHandler := newWebsocketHandler(mcpServer, serverInstance.calls)
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Settings.Server.Path, wsHandler)
+		mux.HandleFunc(cfg.Settings.Server.Path+"/cancel", serverInstance.calls.handleCancelEndpoint)
+
+		serverInstance.stdHTTPServer = &http.Server{
+			Addr:    cfg.Settings.Server.Address,
+			Handler: mux,
+		}
+		serverInstance.stdHTTPServer.RegisterOnShutdown(wsHandler.closeAll)
+	}
+
 	// Register all tools
 	if err := serverInstance.registerTools(); err != nil {
 		return nil, fmt.Errorf("failed to register tools: %w", err)
@@ -137,101 +222,254 @@ func NewServerWithFlags(transport, address, path string) (*Server, error) {
 	return serverInstance, nil
 }
 
+// registerTools registers every tool allowed by the enabled_tools/disabled_tools settings
 func (s *Server) registerTools() error {
-	// Create and register tools
-	s.mcpServer.AddTool(
-		mcp.NewTool("list_connections", mcp.WithDescription("List all configured database connections")),
-		s.handleListConnections,
-	)
-
-	s.mcpServer.AddTool(
-		mcp.NewTool("list_databases",
-			mcp.WithDescription("List databases available on a connection"),
-			mcp.WithString("connection", mcp.Required()),
-		),
-		s.handleListDatabases,
-	)
-
-	s.mcpServer.AddTool(
-		mcp.NewTool("list_schemas",
-			mcp.WithDescription("List schemas in a database (PostgreSQL only)"),
-			mcp.WithString("connection", mcp.Required()),
-			mcp.WithString("database", mcp.Required()),
-		),
-		s.handleListSchemas,
-	)
-
-	s.mcpServer.AddTool(
-		mcp.NewTool("list_tables",
-			mcp.WithDescription("List tables in a database/schema"),
-			mcp.WithString("connection", mcp.Required()),
-			mcp.WithString("database"),
-			mcp.WithString("schema"),
-		),
-		s.handleListTables,
-	)
-
-	s.mcpServer.AddTool(
-		mcp.NewTool("describe_table",
-			mcp.WithDescription("Get detailed information about a table's structure"),
-			mcp.WithString("connection", mcp.Required()),
-			mcp.WithString("database", mcp.Required()),
-			mcp.WithString("table", mcp.Required()),
-			mcp.WithString("schema"),
-		),
-		s.handleDescribeTable,
-	)
-
-	s.mcpServer.AddTool(
-		mcp.NewTool("list_indexes",
-			mcp.WithDescription("List indexes for a table"),
-			mcp.WithString("connection", mcp.Required()),
-			mcp.WithString("database", mcp.Required()),
-			mcp.WithString("table", mcp.Required()),
-			mcp.WithString("schema"),
-		),
-		s.handleListIndexes,
-	)
-
-	s.mcpServer.AddTool(
-		mcp.NewTool("get_table_sample",
-			mcp.WithDescription("Get a sample of data from a table"),
-			mcp.WithString("connection", mcp.Required()),
-			mcp.WithString("database", mcp.Required()),
-			mcp.WithString("table", mcp.Required()),
-			mcp.WithString("schema"),
-			mcp.WithNumber("limit"),
-		),
-		s.handleGetTableSample,
-	)
-
-	s.mcpServer.AddTool(
-		mcp.NewTool("get_connection_status",
-			mcp.WithDescription("Get status of database connections"),
-			mcp.WithString("connection"),
-		),
-		s.handleGetConnectionStatus,
-	)
+	tools := []server.ServerTool{
+		{
+			Tool: readOnlyTool("list_connections",
+				mcp.WithDescription("List all configured database connections"),
+				mcp.WithString("environment", mcp.Description("Only list connections labeled with this environment (e.g. prod)")),
+			),
+			Handler: s.handleListConnections,
+		},
+		{
+			Tool: readOnlyTool("list_databases",
+				mcp.WithDescription("List databases available on a connection; a Salesforce org is listed as one database named after its connection"),
+				mcp.WithString("connection", mcp.Required()),
+			),
+			Handler: s.handleListDatabases,
+		},
+		{
+			Tool: readOnlyTool("list_schemas",
+				mcp.WithDescription("List schemas in a database (PostgreSQL only)"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database", mcp.Required()),
+			),
+			Handler: s.handleListSchemas,
+		},
+		{
+			Tool: readOnlyTool("list_tables",
+				mcp.WithDescription("List tables in a database/schema"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database"),
+				mcp.WithString("schema"),
+				mcp.WithBoolean("include_counts",
+					mcp.Description("Salesforce only: fill in row counts with one COUNT() query per returned object (slow on large orgs)"),
+				),
+				mcp.WithNumber("offset", mcp.Description("Skip this many tables, sorted by name (default 0)")),
+				mcp.WithNumber("limit", mcp.Description("Return at most this many tables; the result carries next_offset when more remain (default all)")),
+			),
+			Handler: s.handleListTables,
+		},
+		{
+			Tool: readOnlyTool("describe_database",
+				mcp.WithDescription("Summarize a database/schema: table and view counts, estimated rows, total size and table names"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database"),
+				mcp.WithString("schema"),
+				mcp.WithNumber("max_tables", mcp.Description("Maximum number of tables to list (default 100)")),
+			),
+			Handler: s.handleDescribeDatabase,
+		},
+		{
+			Tool: readOnlyTool("describe_table",
+				mcp.WithDescription("Get detailed information about a table's structure"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database", mcp.Required()),
+				mcp.WithString("table", mcp.Required()),
+				mcp.WithString("schema"),
+				mcp.WithBoolean("include_definition",
+					mcp.Description("PostgreSQL only: when the table is a view, also return the query that defines it"),
+				),
+			),
+			Handler: s.handleDescribeTable,
+		},
+		{
+			Tool: readOnlyTool("list_indexes",
+				mcp.WithDescription("List indexes for a table"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database", mcp.Required()),
+				mcp.WithString("table", mcp.Required()),
+				mcp.WithString("schema"),
+			),
+			Handler: s.handleListIndexes,
+		},
+		{
+			Tool: readOnlyTool("list_constraints",
+				mcp.WithDescription("List check constraints and enum values for a database or table (MySQL and PostgreSQL)"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database", mcp.Required()),
+				mcp.WithString("schema"),
+				mcp.WithString("table", mcp.Description("Limit results to this table")),
+			),
+			Handler: s.handleListConstraints,
+		},
+		{
+			Tool: readOnlyTool("get_index_usage",
+				mcp.WithDescription("Report how often each index has been scanned since statistics were last reset, flagging unused ones (MySQL and PostgreSQL)"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database", mcp.Required()),
+				mcp.WithString("schema"),
+				mcp.WithString("table", mcp.Description("Limit results to this table")),
+			),
+			Handler: s.handleGetIndexUsage,
+		},
+		{
+			Tool: readOnlyTool("list_active_queries",
+				mcp.WithDescription("List the queries running on the server right now, longest running first, with pid, user, state, duration and query text (MySQL and PostgreSQL)"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithNumber("min_duration_seconds", mcp.Description("Only list queries running at least this long (default 0)")),
+				mcp.WithNumber("limit", mcp.Description("Maximum number of queries to return (default 50)")),
+				mcp.WithBoolean("redact_literals", mcp.Description("Replace literal values in WHERE clauses with ? (default true)")),
+			),
+			Handler: s.handleListActiveQueries,
+		},
+		{
+			Tool: readOnlyTool("list_recent_changes",
+				mcp.WithDescription("List tables by when their data last changed, most recent first, where the engine tracks it: MySQL table update times (since the server started) and Salesforce LastModifiedDate"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database", mcp.Description("Database name (not used for Salesforce)")),
+				mcp.WithString("tables", mcp.Description(fmt.Sprintf("Salesforce only: comma-separated objects to check (default the org's custom objects; at most %d are checked, one query each)", database.MaxSalesforceRecentObjects))),
+				mcp.WithNumber("limit", mcp.Description("Maximum number of tables to return (default 20)")),
+			),
+			Handler: s.handleListRecentChanges,
+		},
+		{
+			Tool: readOnlyTool("get_privileges",
+				mcp.WithDescription("List the privileges granted to the connection's user, to check what it may do before trying (MySQL and PostgreSQL)"),
+				mcp.WithString("connection", mcp.Required()),
+			),
+			Handler: s.handleGetPrivileges,
+		},
+		{
+			Tool: readOnlyTool("get_table_sample",
+				mcp.WithDescription("Get a sample of data from a table"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database", mcp.Required()),
+				mcp.WithString("table", mcp.Required()),
+				mcp.WithString("schema"),
+				mcp.WithNumber("limit"),
+				mcp.WithNumber("max_columns",
+					mcp.Description("Maximum number of columns to return; primary key and non-null columns are kept first"),
+				),
+				mcp.WithString("result_shape",
+					mcp.Description("How rows are rendered: objects (default), columnar or markdown_table"),
+					mcp.Enum(database.SampleShapeObjects, database.SampleShapeColumnar, database.SampleShapeMarkdownTable),
+				),
+				mcp.WithBoolean("typed",
+					mcp.Description("Return numbers, booleans and JSON as JSON values and dates/timestamps as RFC 3339, based on the table's column types"),
+				),
+			),
+			Handler: s.handleGetTableSample,
+		},
+		{
+			Tool: readOnlyTool("estimate_scan",
+				mcp.WithDescription(fmt.Sprintf("Estimate the bytes an Athena query reading a Glue table would scan, and the cost at $%.2f per TB, before sampling it; uses EXPLAIN, or the catalog's partition sizes", database.AthenaPricePerTB)),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database", mcp.Required()),
+				mcp.WithString("table", mcp.Required()),
+				mcp.WithString("partition_filter", mcp.Description("Only count partitions matching this Glue partition expression, e.g. dt >= '2024-01-01'")),
+			),
+			Handler: s.handleEstimateScan,
+		},
+		{
+			Tool: readOnlyTool("count_rows",
+				mcp.WithDescription("Count the records in a Salesforce object using SOQL COUNT()"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("table", mcp.Required()),
+			),
+			Handler: s.handleCountRows,
+		},
+		{
+			Tool: readOnlyTool("get_distinct_values",
+				mcp.WithDescription("List the distinct values of a column, optionally with how many rows hold each"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("database", mcp.Description("Database name (not used for Salesforce)")),
+				mcp.WithString("table", mcp.Required()),
+				mcp.WithString("column", mcp.Required()),
+				mcp.WithString("schema"),
+				mcp.WithNumber("limit", mcp.Description("Maximum number of values to return (default 50)")),
+				mcp.WithBoolean("with_counts", mcp.Description("Count the rows holding each value and return the most common first")),
+			),
+			Handler: s.handleGetDistinctValues,
+		},
+		{
+			Tool: readOnlyTool("run_query",
+				mcp.WithDescription("Run a read-only SELECT on a MySQL or PostgreSQL connection; a LIMIT of the connection's max_rows is added or enforced"),
+				mcp.WithString("connection", mcp.Required()),
+				mcp.WithString("query", mcp.Required(), mcp.Description("A single SELECT (or WITH ... SELECT) statement; qualify tables with their database or schema")),
+				mcp.WithNumber("limit", mcp.Description("Maximum number of rows to return (default and maximum: the connection's max_rows)")),
+			),
+			Handler: s.handleRunQuery,
+		},
+		{
+			Tool: readOnlyTool("get_connection_status",
+				mcp.WithDescription("Get status of database connections"),
+				mcp.WithString("connection"),
+			),
+			Handler: s.handleGetConnectionStatus,
+		},
+		{
+			Tool: readOnlyTool("get_pool_metrics",
+				mcp.WithDescription("Get connection pool performance metrics"),
+			),
+			Handler: s.handleGetPoolMetrics,
+		},
+		{
+			Tool: readOnlyTool("ping_all",
+				mcp.WithDescription("Ping every MySQL and PostgreSQL connection and report round-trip latency in milliseconds"),
+				mcp.WithNumber("pings", mcp.Description("Pings per connection (default 1, max 10); min/avg/max are reported for more than one")),
+				mcp.WithNumber("timeout_ms", mcp.Description("Timeout for each ping in milliseconds (default 5000)")),
+			),
+			Handler: s.handlePingAll,
+		},
+		{
+			Tool: readOnlyTool("cancel_query",
+				mcp.WithDescription("Cancel an in-flight tool call by its JSON-RPC request ID (HTTP transport only)"),
+				mcp.WithString("request_id", mcp.Required()),
+			),
+			Handler: s.handleCancelQuery,
+		},
+		{
+			Tool: readOnlyTool("close_connection",
+				mcp.WithDescription("Close a connection's pooled database handles and cached clients to free them, e.g. before maintenance; its configuration is kept and the next call reconnects"),
+				mcp.WithString("connection", mcp.Description("Connection to close")),
+				mcp.WithBoolean("all", mcp.Description("Close every connection instead of one")),
+			),
+			Handler: s.handleCloseConnection,
+		},
+	}
 
-	s.mcpServer.AddTool(
-		mcp.NewTool("get_pool_metrics",
-			mcp.WithDescription("Get connection pool performance metrics"),
-		),
-		s.handleGetPoolMetrics,
-	)
+	enabled, err := filterTools(withEnvironmentArg(tools), s.config.Settings.EnabledTools, s.config.Settings.DisabledTools)
+	if err != nil {
+		return err
+	}
+	if s.config.Settings.SafeModeEnabled() {
+		enabled, s.refusedTools = readOnlyTools(enabled)
+		log.Printf("Safe mode on: database sessions are read-only; %d read-only tools registered, refused: %v", len(enabled), s.refusedTools)
+	}
+	enabled = s.results.register(enabled)
+	s.mcpServer.AddTools(enabled...)
+	s.registerResources(enabled)
 
 	return nil
 }
 
 func (s *Server) handleListConnections(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	environment := mcp.ParseString(request, "environment", "")
+
 	connections := make([]map[string]interface{}, 0, len(s.config.Connections))
 	for name, conn := range s.config.Connections {
+		if !conn.IsEnabled() || environment != "" && conn.Environment != environment {
+			continue
+		}
 		connections = append(connections, map[string]interface{}{
-			"name":     name,
-			"type":     conn.Type,
-			"host":     conn.Host,
-			"port":     conn.Port,
-			"database": conn.Database,
+			"name":        name,
+			"type":        conn.Type,
+			"host":        conn.Host,
+			"port":        conn.Port,
+			"database":    conn.Database,
+			"environment": conn.Environment,
 		})
 	}
 
@@ -239,6 +477,9 @@ func (s *Server) handleListConnections(ctx context.Context, request mcp.CallTool
 		"connections": connections,
 		"count":       len(connections),
 	}
+	if environment != "" {
+		result["environment"] = environment
+	}
 
 	jsonData, err := json.Marshal(result)
 	if err != nil {
@@ -251,12 +492,12 @@ func (s *Server) handleListConnections(ctx context.Context, request mcp.CallTool
 func (s *Server) handleListDatabases(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	connectionName := mcp.ParseString(request, "connection", "")
 	if connectionName == "" {
-		return nil, fmt.Errorf("connection parameter is required")
+		return nil, missingParameter("connection")
 	}
 
 	conn, exists := s.config.GetConnection(connectionName)
 	if !exists {
-		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+		return nil, connectionNotFound(connectionName)
 	}
 
 	var databases []string
@@ -268,23 +509,24 @@ func (s *Server) handleListDatabases(ctx context.Context, request mcp.CallToolRe
 	case "postgres":
 		databases, err = s.dbManager.ListDatabasesPostgres(connectionName)
 	case "salesforce":
-		// Return connection name as the single database
-		databases = []string{connectionName}
+		databases, err = s.dbManager.ListDatabasesSalesforce(connectionName)
 	case "glue":
 		databases, err = s.dbManager.ListDatabasesGlue(connectionName)
 	default:
-		return nil, fmt.Errorf("unsupported database type: %s", conn.Type)
+		return nil, unsupported("unsupported database type: %s", conn.Type)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to list databases: %w", err)
 	}
 
+	databases, total := capList(databases, s.config.Settings.MaxListItems)
 	result := map[string]interface{}{
 		"connection": connectionName,
 		"databases":  databases,
 		"count":      len(databases),
 	}
+	markTruncated(result, len(databases), total)
 
 	jsonData, err := json.Marshal(result)
 	if err != nil {
@@ -297,17 +539,17 @@ func (s *Server) handleListDatabases(ctx context.Context, request mcp.CallToolRe
 func (s *Server) handleListSchemas(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	connectionName := mcp.ParseString(request, "connection", "")
 	if connectionName == "" {
-		return nil, fmt.Errorf("connection parameter is required")
+		return nil, missingParameter("connection")
 	}
 
 	databaseName := mcp.ParseString(request, "database", "")
 	if databaseName == "" {
-		return nil, fmt.Errorf("database parameter is required")
+		return nil, missingParameter("database")
 	}
 
 	conn, exists := s.config.GetConnection(connectionName)
 	if !exists {
-		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+		return nil, connectionNotFound(connectionName)
 	}
 
 	var schemas []string
@@ -317,25 +559,27 @@ func (s *Server) handleListSchemas(ctx context.Context, request mcp.CallToolRequ
 	case "postgres":
 		schemas, err = s.dbManager.ListSchemasPostgres(connectionName, databaseName)
 	case "mysql":
-		return nil, fmt.Errorf("MySQL does not support schemas - use list_databases instead")
+		return nil, unsupported("MySQL does not support schemas - use list_databases instead")
 	case "salesforce":
 		schemas, err = s.dbManager.ListSchemasSalesforce(connectionName, databaseName)
 	case "glue":
 		schemas, err = s.dbManager.ListSchemasGlue(connectionName, databaseName)
 	default:
-		return nil, fmt.Errorf("unsupported database type: %s", conn.Type)
+		return nil, unsupported("unsupported database type: %s", conn.Type)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to list schemas: %w", err)
 	}
 
+	schemas, total := capList(schemas, s.config.Settings.MaxListItems)
 	result := map[string]interface{}{
 		"connection": connectionName,
 		"database":   databaseName,
 		"schemas":    schemas,
 		"count":      len(schemas),
 	}
+	markTruncated(result, len(schemas), total)
 
 	jsonData, err := json.Marshal(result)
 	if err != nil {
@@ -348,7 +592,7 @@ func (s *Server) handleListSchemas(ctx context.Context, request mcp.CallToolRequ
 func (s *Server) handleListTables(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	connectionName := mcp.ParseString(request, "connection", "")
 	if connectionName == "" {
-		return nil, fmt.Errorf("connection parameter is required")
+		return nil, missingParameter("connection")
 	}
 
 	schema := mcp.ParseString(request, "schema", "")
@@ -360,40 +604,117 @@ func (s *Server) handleListTables(ctx context.Context, request mcp.CallToolReque
 		if conn.Type == "salesforce" {
 			databaseName = connectionName // Use connection name as database name
 		} else {
-			return nil, fmt.Errorf("database parameter is required")
+			return nil, missingParameter("database")
 		}
 	}
 
 	if !exists {
-		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+		return nil, connectionNotFound(connectionName)
 	}
 
-	var tables []database.TableInfo
+	includeCounts := mcp.ParseBoolean(request, "include_counts", false)
+	if includeCounts && conn.Type == "salesforce" {
+		// Row counts query the data, so allow_sampling: false refuses them
+		if err := conn.CheckDataAccess(connectionName); err != nil {
+			return nil, err
+		}
+	}
+	offset := mcp.ParseInt(request, "offset", 0)
+	limit := mcp.ParseInt(request, "limit", 0)
+	// max_list_items caps a page like limit does; next_offset reaches the rest
+	maxItems := s.config.Settings.MaxListItems
+	capped := maxItems > 0 && (limit <= 0 || limit > maxItems)
+	if capped {
+		limit = maxItems
+	}
+
+	tables, err := s.listTables(conn, connectionName, databaseName, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	page := database.PageTables(tables, offset, limit)
+	if includeCounts && conn.Type == "salesforce" {
+		s.dbManager.AddRowCountsSalesforce(connectionName, page.Tables)
+	}
+
+	result := map[string]interface{}{
+		"connection": connectionName,
+		"database":   databaseName,
+		"schema":     schema,
+		"tables":     page.Tables,
+		"count":      len(page.Tables),
+		"total":      page.Total,
+	}
+	if page.NextOffset != nil {
+		result["next_offset"] = *page.NextOffset
+		if capped {
+			result["truncated"] = true
+		}
+	}
+	if includeCounts && conn.Type == "salesforce" {
+		result["warning"] = "include_counts runs one COUNT() query per object and can be slow; use limit to count a page at a time"
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleDescribeDatabase(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
+
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
+
+	schema := mcp.ParseString(request, "schema", "")
+	databaseName := mcp.ParseString(request, "database", "")
+	if databaseName == "" {
+		if conn.Type == "salesforce" {
+			databaseName = connectionName // Use connection name as database name
+		} else {
+			return nil, missingParameter("database")
+		}
+	}
+
+	maxTables := mcp.ParseInt(request, "max_tables", 100)
+	if maxTables < 1 {
+		maxTables = 1
+	}
+
+	var summary *database.DatabaseSummary
 	var err error
 
 	switch conn.Type {
 	case "mysql":
-		tables, err = s.dbManager.ListTablesMySQL(connectionName, databaseName)
+		summary, err = s.dbManager.DescribeDatabaseMySQL(connectionName, databaseName, maxTables)
 	case "postgres":
-		tables, err = s.dbManager.ListTablesPostgres(connectionName, databaseName, schema)
+		summary, err = s.dbManager.DescribeDatabasePostgres(connectionName, databaseName, schema, maxTables)
 	case "salesforce":
-		tables, err = s.dbManager.ListTablesSalesforce(connectionName)
+		summary, err = s.dbManager.DescribeDatabaseSalesforce(connectionName, maxTables)
 	case "glue":
-		tables, err = s.dbManager.ListTablesGlue(connectionName, databaseName, schema)
+		summary, err = s.dbManager.DescribeDatabaseGlue(connectionName, databaseName, maxTables)
 	default:
-		return nil, fmt.Errorf("unsupported database type: %s", conn.Type)
+		return nil, unsupported("unsupported database type: %s", conn.Type)
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tables: %w", err)
+		return nil, fmt.Errorf("failed to describe database: %w", err)
 	}
 
 	result := map[string]interface{}{
 		"connection": connectionName,
 		"database":   databaseName,
 		"schema":     schema,
-		"tables":     tables,
-		"count":      len(tables),
+		"summary":    summary,
 	}
 
 	jsonData, err := json.Marshal(result)
@@ -407,31 +728,70 @@ func (s *Server) handleListTables(ctx context.Context, request mcp.CallToolReque
 func (s *Server) handleDescribeTable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	connectionName := mcp.ParseString(request, "connection", "")
 	if connectionName == "" {
-		return nil, fmt.Errorf("connection parameter is required")
+		return nil, missingParameter("connection")
 	}
 
 	databaseName := mcp.ParseString(request, "database", "")
 
 	conn, exists := s.config.GetConnection(connectionName)
 	if !exists {
-		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+		return nil, connectionNotFound(connectionName)
 	}
 
 	if databaseName == "" {
 		if conn.Type == "salesforce" {
 			databaseName = connectionName // Use connection name as database name
 		} else {
-			return nil, fmt.Errorf("database parameter is required")
+			return nil, missingParameter("database")
 		}
 	}
 
 	tableName := mcp.ParseString(request, "table", "")
 	if tableName == "" {
-		return nil, fmt.Errorf("table parameter is required")
+		return nil, missingParameter("table")
 	}
 
-	schema := mcp.ParseString(request, "schema", "")
+	schema, err := s.resolveSchema(ctx, conn, connectionName, tableName, mcp.ParseString(request, "schema", ""))
+	if err != nil {
+		return nil, err
+	}
 
+	includeDefinition := mcp.ParseBoolean(request, "include_definition", false)
+
+	jsonData, err := s.describeTableJSON(conn, connectionName, databaseName, tableName, schema, includeDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// listTables lists the tables of a database on any connection type
+func (s *Server) listTables(conn config.Connection, connectionName, databaseName, schema string) ([]database.TableInfo, error) {
+	var tables []database.TableInfo
+	var err error
+
+	switch conn.Type {
+	case "mysql":
+		tables, err = s.dbManager.ListTablesMySQL(connectionName, databaseName)
+	case "postgres":
+		tables, err = s.dbManager.ListTablesPostgres(connectionName, databaseName, schema)
+	case "salesforce":
+		tables, err = s.dbManager.ListTablesSalesforce(connectionName)
+	case "glue":
+		tables, err = s.dbManager.ListTablesGlue(connectionName, databaseName, schema)
+	default:
+		return nil, unsupported("unsupported database type: %s", conn.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	return tables, nil
+}
+
+// describeTable returns a table's columns on any connection type
+func (s *Server) describeTable(conn config.Connection, connectionName, databaseName, tableName, schema string) ([]database.ColumnInfo, error) {
 	var tableInfo []database.ColumnInfo
 	var err error
 
@@ -445,13 +805,56 @@ func (s *Server) handleDescribeTable(ctx context.Context, request mcp.CallToolRe
 	case "glue":
 		tableInfo, err = s.dbManager.DescribeTableGlue(connectionName, databaseName, tableName, schema)
 	default:
-		return nil, fmt.Errorf("unsupported database type: %s", conn.Type)
+		return nil, unsupported("unsupported database type: %s", conn.Type)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe table: %w", err)
 	}
+	return tableInfo, nil
+}
+
+// describeObject returns a table's columns on any connection type and, for
+// MySQL and PostgreSQL, whether it is a view
+func (s *Server) describeObject(conn config.Connection, connectionName, databaseName, tableName, schema string, withDefinition bool) (tableDescription, error) {
+	var description tableDescription
+	columns, err := s.describeTable(conn, connectionName, databaseName, tableName, schema)
+	if err != nil {
+		return description, err
+	}
+	description.Columns = columns
+
+	switch conn.Type {
+	case "mysql":
+		description.ObjectType, err = s.dbManager.ObjectTypeMySQL(connectionName, databaseName, tableName)
+	case "postgres":
+		description.ObjectType, err = s.dbManager.ObjectTypePostgres(connectionName, databaseName, tableName, schema)
+		if err == nil && withDefinition && description.ObjectType == database.ObjectTypeView {
+			description.Definition, err = s.dbManager.ViewDefinitionPostgres(connectionName, databaseName, tableName, schema)
+		}
+	}
+	if err != nil {
+		return description, fmt.Errorf("failed to describe table: %w", err)
+	}
+	return description, nil
+}
+
+// describeTableJSON is the describe_table result, shared with the table schema resources
+func (s *Server) describeTableJSON(conn config.Connection, connectionName, databaseName, tableName, schema string, withDefinition bool) ([]byte, error) {
+	description, err := s.describe(conn, connectionName, databaseName, tableName, schema, withDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	tableInfo := description.Columns
+	if description.ObjectType == database.ObjectTypeView {
+		// A view has no primary key of its own, whatever its base tables have
+		for i := range tableInfo {
+			tableInfo[i].IsPrimaryKey = false
+		}
+	}
 
+	tableInfo, total := capList(tableInfo, s.config.Settings.MaxListItems)
 	result := map[string]interface{}{
 		"connection": connectionName,
 		"database":   databaseName,
@@ -459,40 +862,59 @@ func (s *Server) handleDescribeTable(ctx context.Context, request mcp.CallToolRe
 		"schema":     schema,
 		"columns":    tableInfo,
 	}
+	if description.ObjectType != "" {
+		result["object_type"] = description.ObjectType
+	}
+	if description.Definition != "" {
+		result["definition"] = description.Definition
+	}
+	markTruncated(result, len(tableInfo), total)
 
 	jsonData, err := json.Marshal(result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal result: %w", err)
 	}
+	return jsonData, nil
+}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+// resolveSchema fills in the schema of a Postgres table named without one
+// when resolve_table_schema is on; otherwise schema is returned unchanged and
+// the tools assume public
+func (s *Server) resolveSchema(ctx context.Context, conn config.Connection, connectionName, tableName, schema string) (string, error) {
+	if schema != "" || conn.Type != "postgres" || !s.config.Settings.ResolveTableSchema {
+		return schema, nil
+	}
+	return s.dbManager.ResolveSchemaPostgres(ctx, connectionName, tableName)
 }
 
 func (s *Server) handleListIndexes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	connectionName := mcp.ParseString(request, "connection", "")
 	if connectionName == "" {
-		return nil, fmt.Errorf("connection parameter is required")
+		return nil, missingParameter("connection")
 	}
 
 	databaseName := mcp.ParseString(request, "database", "")
 	if databaseName == "" {
-		return nil, fmt.Errorf("database parameter is required")
+		return nil, missingParameter("database")
 	}
 
 	tableName := mcp.ParseString(request, "table", "")
 	if tableName == "" {
-		return nil, fmt.Errorf("table parameter is required")
+		return nil, missingParameter("table")
 	}
 
 	schema := mcp.ParseString(request, "schema", "")
 
 	conn, exists := s.config.GetConnection(connectionName)
 	if !exists {
-		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+		return nil, connectionNotFound(connectionName)
+	}
+	schema, err := s.resolveSchema(ctx, conn, connectionName, tableName, schema)
+	if err != nil {
+		return nil, err
 	}
 
 	var indexes []database.IndexInfo
-	var err error
 
 	switch conn.Type {
 	case "mysql":
@@ -504,13 +926,14 @@ func (s *Server) handleListIndexes(ctx context.Context, request mcp.CallToolRequ
 	case "glue":
 		indexes, err = s.dbManager.ListIndexesGlue(connectionName, databaseName, tableName)
 	default:
-		return nil, fmt.Errorf("unsupported database type: %s", conn.Type)
+		return nil, unsupported("unsupported database type: %s", conn.Type)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to list indexes: %w", err)
 	}
 
+	indexes, total := capList(indexes, s.config.Settings.MaxListItems)
 	result := map[string]interface{}{
 		"connection": connectionName,
 		"database":   databaseName,
@@ -519,6 +942,7 @@ func (s *Server) handleListIndexes(ctx context.Context, request mcp.CallToolRequ
 		"indexes":    indexes,
 		"count":      len(indexes),
 	}
+	markTruncated(result, len(indexes), total)
 
 	jsonData, err := json.Marshal(result)
 	if err != nil {
@@ -528,65 +952,48 @@ func (s *Server) handleListIndexes(ctx context.Context, request mcp.CallToolRequ
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
-func (s *Server) handleGetTableSample(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleListConstraints(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	connectionName := mcp.ParseString(request, "connection", "")
 	if connectionName == "" {
-		return nil, fmt.Errorf("connection parameter is required")
+		return nil, missingParameter("connection")
 	}
 
 	databaseName := mcp.ParseString(request, "database", "")
 	if databaseName == "" {
-		return nil, fmt.Errorf("database parameter is required")
-	}
-
-	tableName := mcp.ParseString(request, "table", "")
-	if tableName == "" {
-		return nil, fmt.Errorf("table parameter is required")
+		return nil, missingParameter("database")
 	}
 
 	schema := mcp.ParseString(request, "schema", "")
-	limit := mcp.ParseInt(request, "limit", 10)
-
-	// Enforce max limit
-	if limit > 100 {
-		limit = 100
-	}
-	if limit < 1 {
-		limit = 1
-	}
+	tableName := mcp.ParseString(request, "table", "")
 
 	conn, exists := s.config.GetConnection(connectionName)
 	if !exists {
-		return nil, fmt.Errorf("connection '%s' not found", connectionName)
+		return nil, connectionNotFound(connectionName)
 	}
 
-	var sampleData map[string]interface{}
+	var constraints []database.ConstraintInfo
 	var err error
 
 	switch conn.Type {
 	case "mysql":
-		sampleData, err = s.dbManager.GetTableSampleMySQL(connectionName, databaseName, tableName, limit)
+		constraints, err = s.dbManager.ListConstraintsMySQL(connectionName, databaseName, tableName)
 	case "postgres":
-		sampleData, err = s.dbManager.GetTableSamplePostgres(connectionName, databaseName, tableName, schema, limit)
-	case "salesforce":
-		sampleData, err = s.dbManager.GetTableSampleSalesforce(connectionName, tableName, limit)
-	case "glue":
-		sampleData, err = s.dbManager.GetTableSampleGlue(connectionName, databaseName, tableName, limit)
+		constraints, err = s.dbManager.ListConstraintsPostgres(connectionName, databaseName, schema, tableName)
 	default:
-		return nil, fmt.Errorf("unsupported database type: %s", conn.Type)
+		return nil, unsupported("list_constraints is not supported for %s connections", conn.Type)
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get table sample: %w", err)
+		return nil, fmt.Errorf("failed to list constraints: %w", err)
 	}
 
 	result := map[string]interface{}{
-		"connection": connectionName,
-		"database":   databaseName,
-		"table":      tableName,
-		"schema":     schema,
-		"limit":      limit,
-		"data":       sampleData,
+		"connection":  connectionName,
+		"database":    databaseName,
+		"table":       tableName,
+		"schema":      schema,
+		"constraints": constraints,
+		"count":       len(constraints),
 	}
 
 	jsonData, err := json.Marshal(result)
@@ -597,49 +1004,66 @@ func (s *Server) handleGetTableSample(ctx context.Context, request mcp.CallToolR
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
-func (s *Server) handleGetConnectionStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleGetIndexUsage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
 
-	var result map[string]interface{}
+	databaseName := mcp.ParseString(request, "database", "")
+	if databaseName == "" {
+		return nil, missingParameter("database")
+	}
 
-	if connectionName != "" {
-		// Get status for specific connection
-		err := s.dbManager.TestConnection(connectionName)
-		status := "connected"
-		errorMsg := ""
-		if err != nil {
-			status = "disconnected"
-			errorMsg = err.Error()
-		}
+	schema := mcp.ParseString(request, "schema", "")
+	tableName := mcp.ParseString(request, "table", "")
 
-		result = map[string]interface{}{
-			"connection": connectionName,
-			"status":     status,
-			"error":      errorMsg,
-		}
-	} else {
-		// Get status for all connections
-		connections := make(map[string]interface{})
-		for name := range s.config.Connections {
-			err := s.dbManager.TestConnection(name)
-			status := "connected"
-			errorMsg := ""
-			if err != nil {
-				status = "disconnected"
-				errorMsg = err.Error()
-			}
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
 
-			connections[name] = map[string]interface{}{
-				"status": status,
-				"error":  errorMsg,
-			}
+	var report *database.IndexUsageReport
+	var err error
+
+	switch conn.Type {
+	case "mysql":
+		report, err = s.dbManager.GetIndexUsageMySQL(ctx, connectionName, databaseName, tableName)
+	case "postgres":
+		report, err = s.dbManager.GetIndexUsagePostgres(ctx, connectionName, databaseName, schema, tableName)
+	default:
+		// Not an error, so clients can run it across every connection
+		report = &database.IndexUsageReport{
+			Reason:  fmt.Sprintf("%s connections don't expose index statistics", conn.Type),
+			Indexes: []database.IndexUsage{},
 		}
+	}
 
-		result = map[string]interface{}{
-			"connections": connections,
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index usage: %w", err)
+	}
+
+	unused := 0
+	for _, idx := range report.Indexes {
+		if idx.Unused {
+			unused++
 		}
 	}
 
+	result := map[string]interface{}{
+		"connection":      connectionName,
+		"database":        databaseName,
+		"schema":          schema,
+		"table":           tableName,
+		"stats_available": report.StatsAvailable,
+		"indexes":         report.Indexes,
+		"count":           len(report.Indexes),
+		"unused_count":    unused,
+	}
+	if report.Reason != "" {
+		result["reason"] = report.Reason
+	}
+
 	jsonData, err := json.Marshal(result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal result: %w", err)
@@ -648,7 +1072,680 @@ func (s *Server) handleGetConnectionStatus(ctx context.Context, request mcp.Call
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
-func (s *Server) handleGetPoolMetrics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleListActiveQueries(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
+
+	minDuration := mcp.ParseFloat64(request, "min_duration_seconds", 0)
+	if minDuration < 0 {
+		return nil, invalidArgument("min_duration_seconds cannot be negative")
+	}
+	limit := mcp.ParseInt(request, "limit", 50)
+	redactLiterals := mcp.ParseBoolean(request, "redact_literals", true)
+
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
+	if rowLimit := s.config.Settings.RowLimit(conn); limit > rowLimit {
+		limit = rowLimit
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	var report *database.ActiveQueriesReport
+	var err error
+
+	switch conn.Type {
+	case "mysql", "postgres":
+		report, err = s.dbManager.GetActiveQueries(ctx, connectionName, time.Duration(minDuration*float64(time.Second)), limit, redactLiterals)
+	default:
+		// Not an error, so clients can run it across every connection
+		report = &database.ActiveQueriesReport{
+			Reason:  fmt.Sprintf("%s connections don't expose running queries", conn.Type),
+			Queries: []database.ActiveQuery{},
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active queries: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"connection": connectionName,
+		"available":  report.Available,
+		"queries":    report.Queries,
+		"count":      len(report.Queries),
+	}
+	if report.Reason != "" {
+		result["reason"] = report.Reason
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleGetPrivileges(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
+
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
+
+	var grants []database.Grant
+	var err error
+
+	switch conn.Type {
+	case "mysql":
+		grants, err = s.dbManager.GetPrivilegesMySQL(connectionName)
+	case "postgres":
+		grants, err = s.dbManager.GetPrivilegesPostgres(connectionName)
+	default:
+		return nil, unsupported("get_privileges is not supported for %s connections", conn.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get privileges: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"connection": connectionName,
+		"grants":     grants,
+		"count":      len(grants),
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleCountRows(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
+
+	tableName := mcp.ParseString(request, "table", "")
+	if tableName == "" {
+		return nil, missingParameter("table")
+	}
+
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
+	if conn.Type != "salesforce" {
+		return nil, unsupported("count_rows is only supported for Salesforce connections")
+	}
+	if err := conn.CheckDataAccess(connectionName); err != nil {
+		return nil, err
+	}
+
+	count, err := s.dbManager.CountRowsSalesforce(ctx, connectionName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count rows: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"connection": connectionName,
+		"table":      tableName,
+		"row_count":  count,
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleEstimateScan(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
+
+	databaseName := mcp.ParseString(request, "database", "")
+	if databaseName == "" {
+		return nil, missingParameter("database")
+	}
+
+	tableName := mcp.ParseString(request, "table", "")
+	if tableName == "" {
+		return nil, missingParameter("table")
+	}
+
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
+	if conn.Type != "glue" {
+		return nil, unsupported("estimate_scan is only supported for Glue connections")
+	}
+
+	partitionFilter := strings.TrimSpace(mcp.ParseString(request, "partition_filter", ""))
+	estimate, err := s.dbManager.EstimateScanGlue(ctx, connectionName, databaseName, tableName, partitionFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate scan: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"connection": connectionName,
+		"database":   databaseName,
+		"table":      tableName,
+		"estimate":   estimate,
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleListRecentChanges(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
+
+	databaseName := mcp.ParseString(request, "database", "")
+	limit := mcp.ParseInt(request, "limit", 20)
+
+	var objects []string
+	for _, name := range strings.Split(mcp.ParseString(request, "tables", ""), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			objects = append(objects, name)
+		}
+	}
+
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
+	if rowLimit := s.config.Settings.RowLimit(conn); limit > rowLimit {
+		limit = rowLimit
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	var report *database.RecentChangesReport
+	var err error
+
+	switch conn.Type {
+	case "mysql":
+		if databaseName == "" {
+			return nil, missingParameter("database")
+		}
+		report, err = s.dbManager.GetRecentChangesMySQL(ctx, connectionName, databaseName, limit)
+	case "salesforce":
+		report, err = s.dbManager.GetRecentChangesSalesforce(ctx, connectionName, objects, limit)
+	default:
+		// Not an error, so clients can run it across every connection
+		reason := fmt.Sprintf("%s connections don't record when table data last changed", conn.Type)
+		switch conn.Type {
+		case "postgres":
+			reason = "PostgreSQL doesn't record when table data last changed; pg_stat_user_tables only has vacuum and analyze times"
+		case "glue":
+			reason = "the Glue catalog records schema changes, not data changes"
+		}
+		report = &database.RecentChangesReport{Reason: reason, Tables: []database.RecentChange{}}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent changes: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"connection": connectionName,
+		"database":   databaseName,
+		"available":  report.Available,
+		"tables":     report.Tables,
+		"count":      len(report.Tables),
+	}
+	if report.Reason != "" {
+		result["reason"] = report.Reason
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleGetDistinctValues(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
+
+	tableName := mcp.ParseString(request, "table", "")
+	if tableName == "" {
+		return nil, missingParameter("table")
+	}
+
+	columnName := mcp.ParseString(request, "column", "")
+	if columnName == "" {
+		return nil, missingParameter("column")
+	}
+
+	databaseName := mcp.ParseString(request, "database", "")
+	schema := mcp.ParseString(request, "schema", "")
+	limit := mcp.ParseInt(request, "limit", 50)
+	withCounts := mcp.ParseBoolean(request, "with_counts", false)
+
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
+	if rowLimit := s.config.Settings.RowLimit(conn); limit > rowLimit {
+		limit = rowLimit
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	if err := conn.CheckDataAccess(connectionName); err != nil {
+		return nil, err
+	}
+	if conn.Type != "salesforce" && databaseName == "" {
+		return nil, missingParameter("database")
+	}
+	schema, err := s.resolveSchema(ctx, conn, connectionName, tableName, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []database.DistinctValue
+
+	switch conn.Type {
+	case "mysql":
+		values, err = s.dbManager.GetDistinctValuesMySQL(ctx, connectionName, databaseName, tableName, columnName, limit, withCounts)
+	case "postgres":
+		values, err = s.dbManager.GetDistinctValuesPostgres(ctx, connectionName, databaseName, tableName, schema, columnName, limit, withCounts)
+	case "salesforce":
+		values, err = s.dbManager.GetDistinctValuesSalesforce(ctx, connectionName, tableName, columnName, limit, withCounts)
+	default:
+		return nil, unsupported("get_distinct_values is not supported for %s connections", conn.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distinct values: %w", err)
+	}
+	s.pii.RedactDistinctValues(values)
+
+	result := map[string]interface{}{
+		"connection":  connectionName,
+		"database":    databaseName,
+		"table":       tableName,
+		"schema":      schema,
+		"column":      columnName,
+		"limit":       limit,
+		"with_counts": withCounts,
+		"values":      values,
+		"count":       len(values),
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleRunQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
+
+	query := mcp.ParseString(request, "query", "")
+	if query == "" {
+		return nil, missingParameter("query")
+	}
+
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
+	if err := conn.CheckDataAccess(connectionName); err != nil {
+		return nil, err
+	}
+	if conn.Type != "mysql" && conn.Type != "postgres" {
+		return nil, unsupported("run_query is not supported for %s connections", conn.Type)
+	}
+
+	rowLimit := s.config.Settings.RowLimit(conn)
+	limit := mcp.ParseInt(request, "limit", rowLimit)
+	if limit > rowLimit {
+		limit = rowLimit
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	result, err := s.dbManager.RunReadQuery(ctx, connectionName, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query: %w", err)
+	}
+	s.pii.RedactSample(result)
+	result["connection"] = connectionName
+	result["limit"] = limit
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleGetTableSample(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	if connectionName == "" {
+		return nil, missingParameter("connection")
+	}
+
+	databaseName := mcp.ParseString(request, "database", "")
+	if databaseName == "" {
+		return nil, missingParameter("database")
+	}
+
+	tableName := mcp.ParseString(request, "table", "")
+	if tableName == "" {
+		return nil, missingParameter("table")
+	}
+
+	schema := mcp.ParseString(request, "schema", "")
+	limit := mcp.ParseInt(request, "limit", 10)
+	maxColumns := mcp.ParseInt(request, "max_columns", s.config.Settings.SampleMaxColumns)
+	shape := mcp.ParseString(request, "result_shape", database.SampleShapeObjects)
+	typed := mcp.ParseBoolean(request, "typed", false)
+
+	conn, exists := s.config.GetConnection(connectionName)
+	if !exists {
+		return nil, connectionNotFound(connectionName)
+	}
+	if sampleLimit := s.config.Settings.SampleRowLimit(conn); limit > sampleLimit {
+		limit = sampleLimit
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	if err := conn.CheckDataAccess(connectionName); err != nil {
+		return nil, err
+	}
+	schema, err := s.resolveSchema(ctx, conn, connectionName, tableName, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var types map[string]string
+	if typed {
+		columns, err := s.tableColumns(conn.Type, connectionName, databaseName, tableName, schema)
+		if err != nil {
+			return nil, fmt.Errorf("typed samples need the table's column types: %w", err)
+		}
+		types = database.SampleTypes(columns)
+	}
+
+	var sampleData map[string]interface{}
+	streamed := false
+
+	// SQL samples in the default shape are encoded row by row as they are read.
+	// mcp-go sends a tool result as one text content, so the encoded sample is
+	// still held in full, up to sample_max_bytes; the builder hands it over
+	// without another copy.
+	if (conn.Type == "mysql" || conn.Type == "postgres") && shape == database.SampleShapeObjects {
+		var buf strings.Builder
+		stream := newSampleStream(&buf, connectionName, maxColumns, s.config.Settings.SampleByteLimit())
+		var w database.SampleWriter = stream
+		if typed {
+			w = database.NewTypedSampleWriter(stream, types)
+		}
+		// Redacted before typing, so JSON columns are matched as text
+		w = database.NewPIISampleWriter(w, s.pii)
+		var summary *database.SampleSummary
+		if conn.Type == "mysql" {
+			summary, err = s.dbManager.StreamTableSampleMySQL(ctx, connectionName, databaseName, tableName, limit, w)
+		} else {
+			summary, err = s.dbManager.StreamTableSamplePostgres(ctx, connectionName, databaseName, tableName, schema, limit, w)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get table sample: %w", err)
+		}
+
+		if !stream.buffering {
+			rest := map[string]interface{}{
+				"database":      databaseName,
+				"table":         tableName,
+				"schema":        schema,
+				"limit":         limit,
+				"result_shape":  shape,
+				"total_sampled": summary.Rows,
+			}
+			if len(summary.ScanErrors) > 0 {
+				rest["scan_errors"] = summary.ScanErrors
+			}
+			if summary.Truncated {
+				rest["truncated"] = true
+			}
+			if typed {
+				rest["typed"] = true
+			}
+			if err := stream.finish(rest); err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(buf.String()), nil
+		}
+		sampleData = stream.sample(summary)
+		streamed = true
+	}
+
+	switch {
+	case sampleData != nil:
+		// Already read by the stream above
+	case conn.Type == "mysql":
+		sampleData, err = s.dbManager.GetTableSampleMySQL(ctx, connectionName, databaseName, tableName, limit)
+	case conn.Type == "postgres":
+		sampleData, err = s.dbManager.GetTableSamplePostgres(ctx, connectionName, databaseName, tableName, schema, limit)
+	case conn.Type == "salesforce":
+		sampleData, err = s.dbManager.GetTableSampleSalesforce(ctx, connectionName, tableName, limit)
+	case conn.Type == "glue":
+		sampleData, err = s.dbManager.GetTableSampleGlue(ctx, connectionName, databaseName, tableName, limit)
+	default:
+		return nil, unsupported("unsupported database type: %s", conn.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table sample: %w", err)
+	}
+	if !streamed {
+		s.pii.RedactSample(sampleData)
+		if typed {
+			database.TypeSample(sampleData, types)
+		}
+	}
+
+	var omittedColumns []string
+	if columns, _ := sampleData["columns"].([]string); maxColumns > 0 && len(columns) > maxColumns {
+		primaryKeys := s.primaryKeyColumns(conn.Type, connectionName, databaseName, tableName, schema)
+		omittedColumns = database.CapSampleColumns(sampleData, maxColumns, primaryKeys)
+	}
+
+	data, err := database.ShapeSample(sampleData, shape)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"connection":    connectionName,
+		"database":      databaseName,
+		"table":         tableName,
+		"schema":        schema,
+		"limit":         limit,
+		"result_shape":  shape,
+		"data":          data,
+		"total_sampled": sampleData["total_sampled"],
+	}
+	if omittedColumns != nil {
+		result["omitted_columns"] = omittedColumns
+	}
+	if scanErrors, ok := sampleData["scan_errors"]; ok {
+		result["scan_errors"] = scanErrors
+	}
+	if truncated, ok := sampleData["truncated"]; ok {
+		result["truncated"] = truncated
+	}
+	if typed {
+		result["typed"] = true
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// tableColumns describes a table on any connection type
+func (s *Server) tableColumns(connType, connectionName, databaseName, tableName, schema string) ([]database.ColumnInfo, error) {
+	switch connType {
+	case "mysql":
+		return s.dbManager.DescribeTableMySQL(connectionName, databaseName, tableName)
+	case "postgres":
+		return s.dbManager.DescribeTablePostgres(connectionName, databaseName, tableName, schema)
+	case "salesforce":
+		return s.dbManager.DescribeTableSalesforce(connectionName, tableName)
+	case "glue":
+		return s.dbManager.DescribeTableGlue(connectionName, databaseName, tableName, schema)
+	default:
+		return nil, unsupported("unsupported database type: %s", connType)
+	}
+}
+
+// primaryKeyColumns returns the primary key column names of a table, or nil if
+// they can't be determined. It is only used to prioritize columns, so errors are ignored.
+func (s *Server) primaryKeyColumns(connType, connectionName, databaseName, tableName, schema string) []string {
+	var columns []database.ColumnInfo
+	switch connType {
+	case "mysql":
+		columns, _ = s.dbManager.DescribeTableMySQL(connectionName, databaseName, tableName)
+	case "postgres":
+		columns, _ = s.dbManager.DescribeTablePostgres(connectionName, databaseName, tableName, schema)
+	case "salesforce":
+		// The record Id is the only key Salesforce objects have
+		return []string{"Id"}
+	}
+
+	var keys []string
+	for _, col := range columns {
+		if col.IsPrimaryKey {
+			keys = append(keys, col.Name)
+		}
+	}
+	return keys
+}
+
+func (s *Server) handleGetConnectionStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+
+	var result map[string]interface{}
+
+	if connectionName != "" {
+		// Get status for specific connection
+		err := s.dbManager.TestConnection(connectionName)
+		status := "connected"
+		errorMsg := ""
+		if err != nil {
+			status = "disconnected"
+			errorMsg = err.Error()
+		}
+
+		result = map[string]interface{}{
+			"connection": connectionName,
+			"status":     status,
+			"error":      errorMsg,
+			"failure":    database.ClassifyFailure(err),
+		}
+		addPoolStatus(result, s.dbManager.GetConnectionStatus(connectionName))
+	} else {
+		// Get status for all connections
+		connections := make(map[string]interface{})
+		for name, conn := range s.config.Connections {
+			if !conn.IsEnabled() {
+				continue
+			}
+			err := s.dbManager.TestConnection(name)
+			status := "connected"
+			errorMsg := ""
+			if err != nil {
+				status = "disconnected"
+				errorMsg = err.Error()
+			}
+
+			entry := map[string]interface{}{
+				"status":  status,
+				"error":   errorMsg,
+				"failure": database.ClassifyFailure(err),
+			}
+			addPoolStatus(entry, s.dbManager.GetConnectionStatus(name))
+			connections[name] = entry
+		}
+
+		result = map[string]interface{}{
+			"connections": connections,
+		}
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// addPoolStatus adds the pool's health information for a connection to a status entry
+func addPoolStatus(entry map[string]interface{}, poolStatus *database.ConnectionStatus) {
+	entry["successful_pings"] = poolStatus.SuccessfulPings
+	entry["failed_pings"] = poolStatus.FailedPings
+	entry["last_error"] = poolStatus.LastError
+	if poolStatus.LastErrorKind != "" {
+		entry["last_error_kind"] = poolStatus.LastErrorKind
+	}
+	entry["queries_run"] = poolStatus.QueriesRun
+	entry["rows_returned"] = poolStatus.RowsReturned
+	entry["total_query_time"] = poolStatus.TotalQueryTime.String()
+	if !poolStatus.LastErrorAt.IsZero() {
+		entry["last_error_at"] = poolStatus.LastErrorAt
+	}
+	if !poolStatus.LastQueryAt.IsZero() {
+		entry["last_query_at"] = poolStatus.LastQueryAt
+	}
+}
+
+func (s *Server) handleGetPoolMetrics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	metrics := s.dbManager.GetPoolMetrics()
 
 	jsonData, err := json.Marshal(metrics)
@@ -659,8 +1756,91 @@ func (s *Server) handleGetPoolMetrics(ctx context.Context, request mcp.CallToolR
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
+func (s *Server) handlePingAll(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	pings := mcp.ParseInt(request, "pings", 1)
+	if pings < 1 {
+		pings = 1
+	}
+	if pings > 10 {
+		pings = 10
+	}
+	timeoutMs := mcp.ParseInt(request, "timeout_ms", 5000)
+	if timeoutMs < 1 {
+		return nil, invalidArgument("timeout_ms must be positive")
+	}
+
+	results := s.dbManager.PingAll(ctx, pings, time.Duration(timeoutMs)*time.Millisecond)
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"connections": results,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleCancelQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	requestID := mcp.ParseString(request, "request_id", "")
+	if requestID == "" {
+		return nil, missingParameter("request_id")
+	}
+
+	if !s.calls.cancel(callScope(ctx), requestID) {
+		return nil, withCode(CodeNotFound, fmt.Errorf("no in-flight call with request ID '%s'", requestID))
+	}
+
+	result := map[string]interface{}{
+		"request_id": requestID,
+		"cancelled":  true,
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+func (s *Server) handleCloseConnection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	connectionName := mcp.ParseString(request, "connection", "")
+	all := mcp.ParseBoolean(request, "all", false)
+
+	var result map[string]interface{}
+	switch {
+	case all && connectionName != "":
+		return nil, invalidArgument("pass either connection or all, not both")
+	case all:
+		closed := s.dbManager.CloseAllConnections()
+		result = map[string]interface{}{
+			"closed": closed,
+			"count":  len(closed),
+		}
+	case connectionName != "":
+		closed, err := s.dbManager.CloseConnection(connectionName)
+		if err != nil {
+			return nil, err
+		}
+		result = map[string]interface{}{
+			"connection": connectionName,
+			"closed":     closed,
+		}
+	default:
+		return nil, invalidArgument("connection parameter is required (or all: true)")
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
 func (s *Server) Run(ctx context.Context) error {
-	log.Printf("Starting SimpleDB MCP Server v0.1.0")
+	log.Printf("Starting SimpleDB MCP Server %s", version.String())
 	log.Printf("Configuration loaded with %d connections", len(s.config.Connections))
 	log.Printf("Using %s transport", s.config.Settings.Server.Transport)
 
@@ -669,10 +1849,10 @@ func (s *Server) Run(ctx context.Context) error {
 	switch s.config.Settings.Server.Transport {
 	case "stdio":
 		log.Println("Starting MCP server with stdio transport...")
-		return server.ServeStdio(s.mcpServer)
+		return s.serveStdio(ctx, os.Stdin)
 
-	case "http":
-		log.Printf("Starting MCP server with HTTP transport on %s%s", s.config.Settings.Server.Address, s.config.Settings.Server.Path)
+	case "http", "websocket":
+		log.Printf("Starting MCP server with %s transport on %s%s", s.config.Settings.Server.Transport, s.config.Settings.Server.Address, s.config.Settings.Server.Path)
 
 		if s.stdHTTPServer == nil {
 			return fmt.Errorf("HTTP server not initialized")
@@ -748,13 +1928,18 @@ func (s *Server) GetInfo() map[string]interface{} {
 
 	return map[string]interface{}{
 		"server": map[string]interface{}{
-			"name":    "simpledb-mcp",
-			"version": "0.1.0",
+			"name":       "simpledb-mcp",
+			"version":    version.Number(),
+			"commit":     version.Commit,
+			"build_date": version.BuildDate,
 		},
 		"connections": connections,
+		"safe_mode":   s.safeModeInfo(),
 		"settings": map[string]interface{}{
 			"query_timeout":     s.config.Settings.QueryTimeout.String(),
 			"max_rows":          s.config.Settings.MaxRows,
+			"sample_max_rows":   s.config.Settings.SampleMaxRows,
+			"sample_max_bytes":  s.config.Settings.SampleByteLimit(),
 			"cache_credentials": s.config.Settings.CacheCredentials.String(),
 			"require_biometric": s.config.Settings.RequireBiometric,
 		},
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/config"
+	"github.com/eliziario/simpledb-mcp/internal/database"
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// callTool invokes a tool through the MCP tools/call method and returns the
+// text result, or the error message if the call failed
+func callTool(t *testing.T, s *Server, name string, args map[string]interface{}) (string, error) {
+	t.Helper()
+	params, err := json.Marshal(map[string]interface{}{"name": name, "arguments": args})
+	testutil.AssertNoError(t, err)
+	message := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":%s}`, params)
+
+	response := s.mcpServer.HandleMessage(context.Background(), []byte(message))
+	data, err := json.Marshal(response)
+	testutil.AssertNoError(t, err)
+
+	var decoded struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			IsError bool `json:"isError"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal(data, &decoded))
+
+	if decoded.Error != nil {
+		return "", fmt.Errorf("%s", decoded.Error.Message)
+	}
+	var text string
+	if len(decoded.Result.Content) > 0 {
+		text = decoded.Result.Content[0].Text
+	}
+	if decoded.Result.IsError {
+		return "", fmt.Errorf("%s", text)
+	}
+	return text, nil
+}
+
+func TestSamplingDisabledConnection(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  prod-sf:
+    type: salesforce
+    host: https://example.my.salesforce.com
+    allow_sampling: false
+`)
+
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	_, err = callTool(t, s, "get_table_sample", map[string]interface{}{
+		"connection": "prod-sf",
+		"database":   "prod-sf",
+		"table":      "Account",
+	})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "data access disabled for this connection")
+
+	// Counting rows reads the data too
+	_, err = callTool(t, s, "count_rows", map[string]interface{}{
+		"connection": "prod-sf",
+		"table":      "Account",
+	})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "data access disabled for this connection")
+
+	_, err = callTool(t, s, "list_tables", map[string]interface{}{
+		"connection":     "prod-sf",
+		"include_counts": true,
+	})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "data access disabled for this connection")
+
+	_, err = callTool(t, s, "run_query", map[string]interface{}{
+		"connection": "prod-sf",
+		"query":      "SELECT Id FROM Account",
+	})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "data access disabled for this connection")
+
+	// Metadata tools keep working
+	text, err := callTool(t, s, "list_databases", map[string]interface{}{"connection": "prod-sf"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, text, `"databases":["prod-sf"]`)
+}
+
+func TestRunQuerySQLOnly(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  crm:
+    type: salesforce
+    host: https://example.my.salesforce.com
+`)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	_, err = callTool(t, s, "run_query", map[string]interface{}{"connection": "crm", "query": "SELECT Id FROM Account"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "run_query is not supported for salesforce connections")
+
+	_, err = callTool(t, s, "run_query", map[string]interface{}{"connection": "crm"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "query")
+}
+
+func TestListDatabasesSalesforceMatchesManager(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  crm:
+    type: salesforce
+    host: https://example.my.salesforce.com
+`)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	text, err := callTool(t, s, "list_databases", map[string]interface{}{"connection": "crm"})
+	testutil.AssertNoError(t, err)
+	var result struct {
+		Databases []string `json:"databases"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal([]byte(text), &result))
+
+	// internal/tools lists the manager's databases too, and resources use the same name
+	expected, err := s.dbManager.ListDatabasesSalesforce("crm")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, expected, result.Databases)
+	testutil.AssertEqual(t, []string{resourceDatabase("crm", s.config.Connections["crm"])}, result.Databases)
+}
+
+func TestResolveSchemaIsOptIn(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  warehouse:
+    type: postgres
+    host: localhost
+    port: 5432
+  orders-db:
+    type: mysql
+    host: localhost
+    port: 3306
+`)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	// Nothing is looked up unless resolve_table_schema is on, the connection
+	// is Postgres and no schema was passed
+	warehouse, _ := s.config.GetConnection("warehouse")
+	schema, err := s.resolveSchema(context.Background(), warehouse, "warehouse", "invoices", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "", schema)
+
+	s.config.Settings.ResolveTableSchema = true
+	schema, err = s.resolveSchema(context.Background(), warehouse, "warehouse", "invoices", "billing")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "billing", schema)
+
+	ordersDB, _ := s.config.GetConnection("orders-db")
+	schema, err = s.resolveSchema(context.Background(), ordersDB, "orders-db", "invoices", "")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "", schema)
+}
+
+func TestDescribeTableAnnotatesViews(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  warehouse:
+    type: postgres
+    host: localhost
+    port: 5432
+`)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	var withDefinitions []bool
+	s.describe = func(conn config.Connection, connectionName, databaseName, tableName, schema string, withDefinition bool) (tableDescription, error) {
+		withDefinitions = append(withDefinitions, withDefinition)
+		description := tableDescription{
+			Columns:    []database.ColumnInfo{{Name: "id", Type: "integer", IsPrimaryKey: true}},
+			ObjectType: database.ObjectTypeTable,
+		}
+		if tableName == "active_users" {
+			description.ObjectType = database.ObjectTypeView
+			if withDefinition {
+				description.Definition = "SELECT users.id FROM users WHERE users.active;"
+			}
+		}
+		return description, nil
+	}
+
+	var result struct {
+		ObjectType string                `json:"object_type"`
+		Definition *string               `json:"definition"`
+		Columns    []database.ColumnInfo `json:"columns"`
+	}
+	describe := func(args map[string]interface{}) {
+		t.Helper()
+		result.Definition = nil
+		text, err := callTool(t, s, "describe_table", args)
+		testutil.AssertNoError(t, err)
+		testutil.AssertNoError(t, json.Unmarshal([]byte(text), &result))
+	}
+
+	describe(map[string]interface{}{"connection": "warehouse", "database": "app", "table": "users"})
+	testutil.AssertEqual(t, "table", result.ObjectType)
+	testutil.AssertEqual(t, true, result.Columns[0].IsPrimaryKey)
+
+	// Primary key flags mean nothing on a view
+	describe(map[string]interface{}{"connection": "warehouse", "database": "app", "table": "active_users"})
+	testutil.AssertEqual(t, "view", result.ObjectType)
+	testutil.AssertEqual(t, false, result.Columns[0].IsPrimaryKey)
+	testutil.AssertEqual(t, true, result.Definition == nil)
+
+	describe(map[string]interface{}{"connection": "warehouse", "database": "app", "table": "active_users", "include_definition": true})
+	testutil.AssertEqual(t, "SELECT users.id FROM users WHERE users.active;", *result.Definition)
+	testutil.AssertEqual(t, []bool{false, false, true}, withDefinitions)
+}
+
+func TestRunDrainsHTTPCallsOnShutdown(t *testing.T) {
+	t.Setenv("HOME", testutil.TempDir(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.AssertNoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	s, err := NewServerWithFlags("http", address, "/mcp")
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.mcpServer.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		select {
+		case <-release:
+			return mcp.NewToolResultText("finished"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Run(ctx) }()
+
+	body := make(chan string, 1)
+	go func() {
+		var resp *http.Response
+		var err error
+		for i := 0; i < 100; i++ {
+			resp, err = http.Post("http://"+address+"/mcp", "application/json",
+				strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`))
+			if err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		body <- string(data)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The call never started")
+	}
+
+	// Shutting down waits for the call instead of cancelling it
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	testutil.AssertContains(t, <-body, "finished")
+	<-stopped
+}
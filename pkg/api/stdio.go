@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// serveStdio speaks JSON-RPC over stdin and the process's stdout until stdin
+// closes or ctx is cancelled. The real stdout carries protocol frames only:
+// while serving, os.Stdout points at stderr so a stray fmt.Print from any
+// package lands there, and the logger is moved off stdout if it was put on it.
+func (s *Server) serveStdio(ctx context.Context, stdin io.Reader) error {
+	protocol := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = protocol }()
+
+	if log.Writer() == io.Writer(protocol) {
+		log.SetOutput(os.Stderr)
+	}
+
+	stdio := server.NewStdioServer(s.mcpServer)
+	// Protocol errors go through the standard logger so --log-file and --log-level apply
+	stdio.SetErrorLogger(log.Default())
+	return stdio.Listen(ctx, stdin, protocol)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// swapFile points *target at a new temporary file for the rest of the test
+func swapFile(t *testing.T, target **os.File, name, contents string) *os.File {
+	t.Helper()
+	path := filepath.Join(testutil.TempDir(t), name)
+	testutil.AssertNoError(t, os.WriteFile(path, []byte(contents), 0600))
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND, 0600)
+	testutil.AssertNoError(t, err)
+
+	original := *target
+	*target = f
+	t.Cleanup(func() {
+		*target = original
+		f.Close()
+	})
+	return f
+}
+
+func TestStdioRunWritesOnlyJSONRPCToStdout(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+connections:
+  crm:
+    type: salesforce
+    host: https://crm.my.salesforce.com
+settings:
+  server:
+    transport: stdio
+`)
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	// A tool that misbehaves the way a stray debugging line would
+	s.mcpServer.AddTool(mcp.NewTool("noisy"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		fmt.Println("stray debugging output")
+		log.Printf("a log line from a tool")
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	requests := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"list_connections","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"noisy","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"list_databases","arguments":{"connection":"missing"}}}`,
+	}, "\n") + "\n"
+	swapFile(t, &os.Stdin, "stdin", requests)
+	stdout := swapFile(t, &os.Stdout, "stdout", "")
+	stderr := swapFile(t, &os.Stderr, "stderr", "")
+
+	// Run returns once stdin is exhausted
+	testutil.AssertNoError(t, s.Run(context.Background()))
+
+	written, err := os.ReadFile(stdout.Name())
+	testutil.AssertNoError(t, err)
+	scanner := bufio.NewScanner(strings.NewReader(string(written)))
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	var ids []float64
+	for scanner.Scan() {
+		var frame struct {
+			JSONRPC string      `json:"jsonrpc"`
+			ID      interface{} `json:"id"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			t.Fatalf("stdout line is not a JSON-RPC frame: %q", scanner.Text())
+		}
+		testutil.AssertEqual(t, "2.0", frame.JSONRPC)
+		id, _ := frame.ID.(float64)
+		ids = append(ids, id)
+	}
+	testutil.AssertEqual(t, 5, len(ids))
+	testutil.AssertEqual(t, float64(5), ids[4])
+
+	// The stray line went to stderr instead, and stdout is restored
+	diagnostics, err := os.ReadFile(stderr.Name())
+	testutil.AssertNoError(t, err)
+	testutil.AssertContains(t, string(diagnostics), "stray debugging output")
+	testutil.AssertEqual(t, stdout, os.Stdout)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// filterTools applies the enabled/disabled tool settings. Entries are tool
+// names or glob patterns such as "list_*". An empty enabled list enables every
+// tool. An entry that matches no tool is an error, so typos don't silently
+// leave a tool exposed.
+func filterTools(tools []server.ServerTool, enabled, disabled []string) ([]server.ServerTool, error) {
+	if err := checkToolPatterns(tools, "enabled_tools", enabled); err != nil {
+		return nil, err
+	}
+	if err := checkToolPatterns(tools, "disabled_tools", disabled); err != nil {
+		return nil, err
+	}
+
+	var filtered []server.ServerTool
+	for _, tool := range tools {
+		if len(enabled) > 0 && !matchesAny(tool.Tool.Name, enabled) {
+			continue
+		}
+		if matchesAny(tool.Tool.Name, disabled) {
+			continue
+		}
+		filtered = append(filtered, tool)
+	}
+	return filtered, nil
+}
+
+func checkToolPatterns(tools []server.ServerTool, setting string, patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s: invalid pattern '%s': %w", setting, pattern, err)
+		}
+		found := false
+		for _, tool := range tools {
+			if matchesAny(tool.Tool.Name, []string{pattern}) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: unknown tool '%s'", setting, pattern)
+		}
+	}
+	return nil
+}
+
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func sampleTools() []server.ServerTool {
+	var tools []server.ServerTool
+	for _, name := range []string{"list_connections", "list_tables", "describe_table", "get_table_sample", "get_pool_metrics"} {
+		tools = append(tools, server.ServerTool{Tool: mcp.NewTool(name)})
+	}
+	return tools
+}
+
+func toolNames(tools []server.ServerTool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Tool.Name)
+	}
+	return names
+}
+
+func TestFilterToolsDefaultsToAll(t *testing.T) {
+	filtered, err := filterTools(sampleTools(), nil, nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 5, len(filtered))
+}
+
+func TestFilterToolsEnabledPatterns(t *testing.T) {
+	filtered, err := filterTools(sampleTools(), []string{"list_*", "describe_*"}, nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "list_connections,list_tables,describe_table", strings.Join(toolNames(filtered), ","))
+}
+
+func TestFilterToolsDisabled(t *testing.T) {
+	filtered, err := filterTools(sampleTools(), nil, []string{"get_table_sample"})
+	testutil.AssertNoError(t, err)
+	for _, name := range toolNames(filtered) {
+		if name == "get_table_sample" {
+			t.Error("Expected get_table_sample to be disabled")
+		}
+	}
+	testutil.AssertEqual(t, 4, len(filtered))
+}
+
+func TestFilterToolsUnknownName(t *testing.T) {
+	_, err := filterTools(sampleTools(), []string{"list_tables", "drop_table"}, nil)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "enabled_tools: unknown tool 'drop_table'")
+
+	_, err = filterTools(sampleTools(), nil, []string{"get_table_samples"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "disabled_tools: unknown tool 'get_table_samples'")
+}
+
+// registeredTools lists tool names through the MCP tools/list method
+func registeredTools(t *testing.T, s *Server) []string {
+	t.Helper()
+	response := s.mcpServer.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	data, err := json.Marshal(response)
+	testutil.AssertNoError(t, err)
+
+	var decoded struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal(data, &decoded))
+
+	var names []string
+	for _, tool := range decoded.Result.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestDisabledToolNotRegistered(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+settings:
+  disabled_tools:
+    - get_table_sample
+`)
+
+	s, err := NewServer()
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	names := registeredTools(t, s)
+	for _, name := range names {
+		if name == "get_table_sample" {
+			t.Fatal("Expected get_table_sample not to be registered")
+		}
+	}
+	testutil.AssertContains(t, strings.Join(names, ","), "list_tables")
+}
+
+func TestUnknownToolRejectedAtStartup(t *testing.T) {
+	testutil.WriteConfigFile(t, `
+settings:
+  enabled_tools:
+    - list_tables
+    - run_anything
+`)
+
+	_, err := NewServer()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "unknown tool 'run_anything'")
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// wsPongWait is how long a client may go without answering a ping
+	wsPongWait = 60 * time.Second
+	// wsPingInterval must be shorter than wsPongWait
+	wsPingInterval = 30 * time.Second
+	// wsWriteWait bounds a single frame write
+	wsWriteWait = 10 * time.Second
+)
+
+// websocketHandler serves MCP over a persistent WebSocket, one JSON-RPC message per text frame
+type websocketHandler struct {
+	mcpServer *server.MCPServer
+	calls     *callRegistry
+	upgrader  websocket.Upgrader
+	// connections numbers connections, scoping their request IDs
+	connections atomic.Uint64
+	// shutdown is cancelled by closeAll. Hijacked connections outlive
+	// http.Server.Shutdown, so the server calls closeAll on shutdown to close
+	// them gracefully.
+	shutdown context.Context
+	closeAll context.CancelFunc
+}
+
+func newWebsocketHandler(mcpServer *server.MCPServer, calls *callRegistry) *websocketHandler {
+	shutdown, closeAll := context.WithCancel(context.Background())
+	return &websocketHandler{
+		mcpServer: mcpServer,
+		calls:     calls,
+		shutdown:  shutdown,
+		closeAll:  closeAll,
+	}
+}
+
+// wsConn serializes writes to a websocket connection, which gorilla requires
+type wsConn struct {
+	conn  *websocket.Conn
+	mutex sync.Mutex
+}
+
+func (c *wsConn) write(messageType int, data []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return c.conn.WriteMessage(messageType, data)
+}
+
+func (h *websocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ws := &wsConn{conn: conn}
+	// Request IDs are only unique per connection, so calls are cancelled from the same one
+	ctx, cancel := context.WithCancel(withCallScope(r.Context(), fmt.Sprintf("websocket-%d", h.connections.Add(1))))
+	defer cancel()
+	stop := context.AfterFunc(h.shutdown, cancel)
+	defer stop()
+
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	// Keep the connection alive and close it gracefully when the server shuts down
+	go func() {
+		ticker := time.NewTicker(wsPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				ws.write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := ws.write(websocket.PingMessage, nil); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	var inFlight sync.WaitGroup
+	defer inFlight.Wait()
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("WebSocket read error: %v", err)
+			}
+			return
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		// Handle each message concurrently so a slow tool call doesn't block others
+		inFlight.Add(1)
+		go func(data []byte) {
+			defer inFlight.Done()
+			h.handleMessage(ctx, ws, data)
+		}(data)
+	}
+}
+
+// handleMessage dispatches one JSON-RPC message and writes back the response, if any
+func (h *websocketHandler) handleMessage(ctx context.Context, ws *wsConn, data []byte) {
+	var message jsonRPCRequest
+	if err := json.Unmarshal(data, &message); err == nil && message.Method == string(mcp.MethodToolsCall) && len(message.ID) > 0 {
+		callCtx, done := h.calls.register(ctx, requestIDString(message.ID))
+		defer done()
+		ctx = context.WithValue(ctx, callContextKey{}, callCtx)
+	}
+
+	response := h.mcpServer.HandleMessage(ctx, data)
+	if response == nil {
+		// Notifications have no response
+		return
+	}
+
+	payload, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("Failed to marshal WebSocket response: %v", err)
+		return
+	}
+	if err := ws.write(websocket.TextMessage, payload); err != nil {
+		log.Printf("Failed to write WebSocket response: %v", err)
+	}
+}
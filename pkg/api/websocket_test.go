@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eliziario/simpledb-mcp/internal/testutil"
+	"github.com/gorilla/websocket"
+)
+
+func TestWebsocketListConnections(t *testing.T) {
+	// Point config loading at an empty home so the default config is used
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", testutil.TempDir(t))
+	defer os.Setenv("HOME", originalHome)
+
+	s, err := NewServerWithFlags("websocket", ":0", "/mcp")
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	ts := httptest.NewServer(s.stdHTTPServer.Handler)
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/mcp"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial websocket: %v", err)
+	}
+	defer conn.Close()
+
+	request := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_connections","arguments":{}}}`
+	testutil.AssertNoError(t, conn.WriteMessage(websocket.TextMessage, []byte(request)))
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	testutil.AssertNoError(t, err)
+
+	var response struct {
+		ID     int `json:"id"`
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal(data, &response))
+	testutil.AssertEqual(t, 1, response.ID)
+	if len(response.Result.Content) != 1 {
+		t.Fatalf("Expected one content item, got %s", string(data))
+	}
+
+	var result map[string]interface{}
+	testutil.AssertNoError(t, json.Unmarshal([]byte(response.Result.Content[0].Text), &result))
+	testutil.AssertEqual(t, float64(0), result["count"])
+}
+
+func TestWebsocketClosedOnShutdown(t *testing.T) {
+	t.Setenv("HOME", testutil.TempDir(t))
+	s, err := NewServerWithFlags("websocket", ":0", "/mcp")
+	testutil.AssertNoError(t, err)
+	defer s.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.AssertNoError(t, err)
+	go s.stdHTTPServer.Serve(listener)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/mcp", nil)
+	if err != nil {
+		t.Fatalf("Failed to dial websocket: %v", err)
+	}
+	defer conn.Close()
+
+	// Shutdown doesn't wait for hijacked connections, so the handler closes them
+	testutil.AssertNoError(t, s.stdHTTPServer.Shutdown(context.Background()))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("Expected a going-away close, got %v", err)
+	}
+}